@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,7 +17,9 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
 	"github.com/jasoncabot/dicewizard-characters/internal/models"
+	"github.com/jasoncabot/dicewizard-characters/internal/rules"
 	"github.com/jasoncabot/dicewizard-characters/internal/store"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -32,6 +35,7 @@ type Handler struct {
 	store      *store.Store
 	jwtSecret  []byte
 	assetsPath string
+	rng        dice.RNG
 }
 
 // NewHandler creates a new Handler
@@ -40,6 +44,7 @@ func NewHandler(s *store.Store, jwtSecret, assetsPath string) *Handler {
 		store:      s,
 		jwtSecret:  []byte(jwtSecret),
 		assetsPath: assetsPath,
+		rng:        dice.DefaultRNG(),
 	}
 }
 
@@ -303,6 +308,16 @@ func (h *Handler) UploadCampaignMap(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var sceneID *int64
+	if v := strings.TrimSpace(r.FormValue("sceneId")); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid scene id")
+			return
+		}
+		sceneID = &id
+	}
+
 	file, header, err := r.FormFile("map")
 	if err != nil {
 		respondError(w, http.StatusBadRequest, "Map file is required")
@@ -361,9 +376,17 @@ func (h *Handler) UploadCampaignMap(w http.ResponseWriter, r *http.Request) {
 		name = "Map"
 	}
 
-	created, err := h.store.CreateMapForCampaign(campaignID, userID, name, mapURL)
+	created, err := h.store.CreateMapForCampaign(campaignID, userID, sceneID, name, mapURL)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		os.Remove(filePath)
+		switch err {
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrSceneNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, err.Error())
+		}
 		return
 	}
 
@@ -453,6 +476,142 @@ func (h *Handler) UpdateTokenPosition(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, token)
 }
 
+// UpdateToken handles PUT /api/tokens/{id}
+func (h *Handler) UpdateToken(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	tokenID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid token id")
+		return
+	}
+
+	var req struct {
+		Label       string   `json:"label"`
+		ImageURL    string   `json:"imageUrl"`
+		SizeSquares int      `json:"sizeSquares"`
+		FacingDeg   int      `json:"facingDeg"`
+		Audience    []string `json:"audience"`
+		Tags        []string `json:"tags"`
+		Notes       string   `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	token, err := h.store.UpdateToken(tokenID, userID, req.Label, req.ImageURL, req.SizeSquares, req.FacingDeg, req.Audience, req.Tags, req.Notes)
+	if err != nil {
+		switch err {
+		case store.ErrTokenNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidToken, store.ErrInvalidTokenAudience:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, token)
+}
+
+// UpdateTokenLayer handles PUT /api/tokens/{id}/layer
+func (h *Handler) UpdateTokenLayer(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	tokenID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid token id")
+		return
+	}
+
+	var req struct {
+		Layer string `json:"layer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	token, err := h.store.UpdateTokenLayer(tokenID, userID, req.Layer)
+	if err != nil {
+		switch err {
+		case store.ErrTokenNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidTokenLayer:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, token)
+}
+
+// UpdateTokenVision handles PUT /api/tokens/{id}/vision
+func (h *Handler) UpdateTokenVision(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	tokenID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid token id")
+		return
+	}
+
+	var req struct {
+		VisionFt     int `json:"visionFt"`
+		DarkvisionFt int `json:"darkvisionFt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	token, err := h.store.UpdateTokenVision(tokenID, userID, req.VisionFt, req.DarkvisionFt)
+	if err != nil {
+		switch err {
+		case store.ErrTokenNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidVision:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, token)
+}
+
+// DeleteToken handles DELETE /api/tokens/{id}
+func (h *Handler) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	tokenID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid token id")
+		return
+	}
+
+	if err := h.store.DeleteToken(tokenID, userID); err != nil {
+		switch err {
+		case store.ErrTokenNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // UploadCampaignHandout handles POST /api/campaigns/{id}/handouts with file upload.
 func (h *Handler) UploadCampaignHandout(w http.ResponseWriter, r *http.Request) {
 	const maxUploadSize = int64(20 << 20) // 20MB
@@ -815,6 +974,7 @@ func (h *Handler) GetCharacter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", characterETag(character))
 	respondJSON(w, http.StatusOK, character)
 }
 
@@ -834,55 +994,227 @@ func (h *Handler) CreateCharacter(w http.ResponseWriter, r *http.Request) {
 
 	storeChar := req.ToStoreCharacter()
 	storeChar.UserID = userID
+	storeChar.AbilityScoreGeneration = req.AbilityScoreGeneration()
+	storeChar.CampaignID = req.CampaignID
+
+	levels, err := store.EncodeProficiencyLevels(req.ProficiencyLevels)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	storeChar.ProficiencyLevels = levels
+	if !setDamageTypes(w, storeChar, &req) {
+		return
+	}
 
 	if err := h.store.CreateCharacter(storeChar); err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		var scoreErr *store.AbilityScoreError
+		if errors.As(err, &scoreErr) {
+			respondJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error(), "fields": scoreErr.Fields})
+			return
+		}
+		switch err {
+		case store.ErrAbilityRollNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrAbilityRollUsed:
+			respondError(w, http.StatusConflict, err.Error())
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidClasses, store.ErrMulticlassPrerequisite,
+			store.ErrUnknownSpecies, store.ErrUnknownClass, store.ErrUnknownBackground,
+			store.ErrUnknownSubclass:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
+	w.Header().Set("ETag", characterETag(storeChar))
 	respondJSON(w, http.StatusCreated, storeChar)
 }
 
 // UpdateCharacter handles PUT /api/characters/{id}
 func (h *Handler) UpdateCharacter(w http.ResponseWriter, r *http.Request) {
-	userID := getUserID(r)
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid character id")
+	existing := h.loadCharacter(w, r)
+	if existing == nil || !checkIfMatch(w, r, existing) {
+		return
+	}
+
+	var req CreateCharacterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	h.saveCharacter(w, r, existing, &req)
+}
+
+// PatchCharacter handles PATCH /api/characters/{id} with a JSON Merge Patch (RFC 7396)
+// applied to the character's current fields, so omitted fields are left unchanged.
+func (h *Handler) PatchCharacter(w http.ResponseWriter, r *http.Request) {
+	existing := h.loadCharacter(w, r)
+	if existing == nil || !checkIfMatch(w, r, existing) {
+		return
+	}
+
+	var patch map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		respondError(w, http.StatusBadRequest, "Request body must be a JSON object")
 		return
 	}
 
-	existing, err := h.store.GetCharacter(id, userID)
+	var doc any
+	current, err := characterToRequest(existing)
+	if err == nil {
+		var data []byte
+		if data, err = json.Marshal(current); err == nil {
+			err = json.Unmarshal(data, &doc)
+		}
+	}
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	if existing == nil {
-		respondError(w, http.StatusNotFound, "Character not found")
+	merged, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	var req CreateCharacterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := json.Unmarshal(merged, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid patch: "+err.Error())
 		return
 	}
+	// Only resync items when the patch touches equipment, and let null clear the levels.
+	if _, ok := patch["equipment"]; !ok {
+		req.Equipment = nil
+	}
+	// Likewise only replace class levels the patch sends, so patching class or level alone
+	// still works.
+	if _, ok := patch["classes"]; !ok {
+		req.Classes = nil
+	}
+	if req.ProficiencyLevels == nil {
+		req.ProficiencyLevels = map[string]string{}
+	}
+
+	h.saveCharacter(w, r, existing, &req)
+}
 
+// saveCharacter writes req over existing for PUT and PATCH. When the request carried
+// If-Match, the store re-checks the version inside the update so a concurrent save
+// between our read and write still fails with 412.
+func (h *Handler) saveCharacter(w http.ResponseWriter, r *http.Request, existing *store.CharacterWithStats, req *CreateCharacterRequest) {
 	storeChar := req.ToStoreCharacter()
-	storeChar.ID = id
-	storeChar.UserID = userID
+	storeChar.ID = existing.ID
+	storeChar.UserID = existing.UserID
 	storeChar.CreatedAt = existing.CreatedAt
 	storeChar.AvatarUrl = existing.AvatarUrl
+	storeChar.HitDiceSpent = existing.HitDiceSpent
+	// Ability scores a request leaves out keep their values; any others must not change.
+	for _, score := range []struct {
+		sent         int
+		dst, current *int64
+	}{
+		{req.Strength, &storeChar.Strength, &existing.Strength},
+		{req.Dexterity, &storeChar.Dexterity, &existing.Dexterity},
+		{req.Constitution, &storeChar.Constitution, &existing.Constitution},
+		{req.Intelligence, &storeChar.Intelligence, &existing.Intelligence},
+		{req.Wisdom, &storeChar.Wisdom, &existing.Wisdom},
+		{req.Charisma, &storeChar.Charisma, &existing.Charisma},
+	} {
+		if score.sent == 0 {
+			*score.dst = *score.current
+		}
+	}
+	if req.ArmorClassOverride == nil {
+		storeChar.ArmorClassOverride = existing.ArmorClassOverride
+	}
+	if req.ArmorClassBonus == nil {
+		storeChar.ArmorClassBonus = existing.ArmorClassBonus
+	}
+	// Level and max hit points change through POST /api/characters/{id}/level-up.
+	if req.Level == 0 && req.Classes == nil {
+		storeChar.Level = existing.Level
+	}
+	if req.MaxHP == 0 {
+		storeChar.MaxHp = existing.MaxHp
+		if req.CurrentHP == nil {
+			storeChar.CurrentHp = existing.MaxHp
+		}
+	}
+	// Death saves only matter at 0 hit points, so an edit that heals the character clears them.
+	if storeChar.CurrentHp == 0 {
+		storeChar.DeathSaveSuccesses = existing.DeathSaveSuccesses
+		storeChar.DeathSaveFailures = existing.DeathSaveFailures
+	}
+	storeChar.DamageResistances = existing.DamageResistances
+	storeChar.DamageVulnerabilities = existing.DamageVulnerabilities
+	storeChar.DamageImmunities = existing.DamageImmunities
+	if !setDamageTypes(w, storeChar, req) {
+		return
+	}
+	storeChar.ProficiencyLevels = existing.ProficiencyLevels
+	if req.ProficiencyLevels != nil {
+		levels, err := store.EncodeProficiencyLevels(req.ProficiencyLevels)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		storeChar.ProficiencyLevels = levels
+	}
 
-	if err := h.store.UpdateCharacter(storeChar); err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+	var err error
+	if r.Header.Get("If-Match") != "" {
+		err = h.store.UpdateCharacterIfUnmodified(storeChar, existing.UpdatedAt)
+	} else {
+		err = h.store.UpdateCharacter(storeChar)
+	}
+	if err != nil {
+		switch err {
+		case store.ErrPreconditionFailed:
+			respondError(w, http.StatusPreconditionFailed, err.Error())
+		case store.ErrInvalidClasses, store.ErrClassesRequired, store.ErrMulticlassPrerequisite, store.ErrUnknownSubclass,
+			store.ErrAbilityScoresLocked, store.ErrLevelUpRequired,
+			store.ErrUnknownSpecies, store.ErrUnknownClass, store.ErrUnknownBackground:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
+	w.Header().Set("ETag", characterETag(storeChar))
 	respondJSON(w, http.StatusOK, storeChar)
 }
 
+// setDamageTypes validates and copies the damage type lists the request carries onto c,
+// leaving any list the request omitted as it is. It responds with 400 and returns false
+// on an unknown damage type.
+func setDamageTypes(w http.ResponseWriter, c *store.CharacterWithStats, req *CreateCharacterRequest) bool {
+	for _, field := range []struct {
+		types []string
+		dst   *string
+	}{
+		{req.DamageResistances, &c.DamageResistances},
+		{req.DamageVulnerabilities, &c.DamageVulnerabilities},
+		{req.DamageImmunities, &c.DamageImmunities},
+	} {
+		if field.types == nil && *field.dst != "" {
+			continue
+		}
+		encoded, err := store.EncodeDamageTypes(field.types)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return false
+		}
+		*field.dst = encoded
+	}
+	return true
+}
+
 // DeleteCharacter handles DELETE /api/characters/{id}
 func (h *Handler) DeleteCharacter(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
@@ -996,56 +1328,1786 @@ func (h *Handler) UploadCharacterAvatar(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Clean up previous avatar if present (only new uploads path is supported)
-	if character.AvatarUrl != "" && strings.HasPrefix(character.AvatarUrl, uploadMountPath+"/") {
-		oldPath := strings.TrimPrefix(character.AvatarUrl, uploadMountPath+"/")
-		if oldPath != "" {
-			clean := filepath.Clean(oldPath)
-			target := filepath.Join(h.assetsPath, clean)
-			if rel, err := filepath.Rel(h.assetsPath, target); err == nil && !strings.HasPrefix(rel, "..") {
-				_ = os.Remove(target)
-			}
-		}
+	if target, ok := h.uploadedFile(character.AvatarUrl); ok {
+		_ = os.Remove(target)
 	}
 
 	respondJSON(w, http.StatusOK, updated)
 }
 
-// Auth middleware
-func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			respondError(w, http.StatusUnauthorized, "Authorization header required")
-			return
-		}
+// Roll handlers
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			respondError(w, http.StatusUnauthorized, "Invalid authorization header")
-			return
-		}
+// CreateRoll handles POST /api/rolls
+func (h *Handler) CreateRoll(w http.ResponseWriter, r *http.Request) {
+	var req RollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
 
-		userID, err := h.validateJWT(parts[1])
-		if err != nil {
-			respondError(w, http.StatusUnauthorized, "Invalid token")
-			return
-		}
+	if strings.TrimSpace(req.Expression) == "" {
+		respondError(w, http.StatusBadRequest, "Expression is required")
+		return
+	}
 
-		ctx := context.WithValue(r.Context(), userIDKey, userID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+	result, err := dice.RollWithMode(req.Expression, dice.Mode(req.Mode), h.rng)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, RollResponse{
+		Label:  req.Label,
+		Result: result,
+		Detail: result.String(),
 	})
 }
 
-// Helper functions
+// CreateCampaignRoll handles POST /api/campaigns/{id}/rolls
+func (h *Handler) CreateCampaignRoll(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	idStr := chi.URLParam(r, "id")
+	campaignID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
 
-func extensionForAsset(contentType string) string {
-	switch contentType {
-	case "image/jpeg":
-		return ".jpg"
-	case "image/png":
-		return ".png"
-	case "image/webp":
-		return ".webp"
+	var req CampaignRollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if strings.TrimSpace(req.Expression) == "" {
+		respondError(w, http.StatusBadRequest, "Expression is required")
+		return
+	}
+
+	result, err := dice.RollWithMode(req.Expression, dice.Mode(req.Mode), h.rng)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	roll, err := h.store.CreateCampaignRoll(campaignID, userID, req.CharacterID, req.Label, req.Visibility, result)
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted, store.ErrCharacterNotOwned:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, roll)
+}
+
+// ListCampaignRolls handles GET /api/campaigns/{id}/rolls
+func (h *Handler) ListCampaignRolls(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	idStr := chi.URLParam(r, "id")
+	campaignID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	rolls, err := h.store.ListCampaignRolls(campaignID, userID, limit)
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rolls)
+}
+
+// ListCampaignRests handles GET /api/campaigns/{id}/rests
+func (h *Handler) ListCampaignRests(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	idStr := chi.URLParam(r, "id")
+	campaignID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	rests, err := h.store.ListCampaignRests(campaignID, userID, limit)
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rests)
+}
+
+// AuditCampaignRolls handles GET /api/campaigns/{id}/rolls/audit
+func (h *Handler) AuditCampaignRolls(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	idStr := chi.URLParam(r, "id")
+	campaignID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
+
+	audit, err := h.store.AuditCampaignRolls(campaignID, userID)
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, audit)
+}
+
+// RollCharacterCheck handles POST /api/characters/{id}/roll
+func (h *Handler) RollCharacterCheck(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid character id")
+		return
+	}
+
+	var req CharacterRollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	character, err := h.store.GetCharacter(id, userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if character == nil {
+		respondError(w, http.StatusNotFound, "Character not found")
+		return
+	}
+
+	modifier, err := character.CheckModifier(req.Kind, req.Name)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	expression := "1d20"
+	if modifier.Total != 0 {
+		expression += fmt.Sprintf("%+d", modifier.Total)
+	}
+	result, err := dice.RollWithMode(expression, dice.Mode(req.Mode), h.rng)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	label := checkLabel(modifier)
+	resp := CharacterRollResponse{
+		Modifier:    modifier,
+		Explanation: modifier.Explanation(),
+		Roll: RollResponse{
+			Label:  label,
+			Result: result,
+			Detail: result.String(),
+		},
+	}
+
+	if req.CampaignID != nil {
+		roll, err := h.store.CreateCampaignRoll(*req.CampaignID, userID, &character.ID, fmt.Sprintf("%s: %s", character.Name, label), req.Visibility, result)
+		if err != nil {
+			switch err {
+			case store.ErrNotCampaignMember, store.ErrNotPermitted, store.ErrCharacterNotOwned:
+				respondError(w, http.StatusForbidden, err.Error())
+			default:
+				respondError(w, http.StatusBadRequest, err.Error())
+			}
+			return
+		}
+		resp.CampaignRoll = roll
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// Spell handlers
+
+// GetCharacterSpells handles GET /api/characters/{id}/spells
+func (h *Handler) GetCharacterSpells(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	book, err := h.store.GetSpellbook(character)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, book)
+}
+
+// AddCharacterSpell handles POST /api/characters/{id}/spells
+func (h *Handler) AddCharacterSpell(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req CharacterSpellRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	spell := req.ToStoreSpell()
+	spell.CharacterID = character.ID
+	created, err := h.store.AddCharacterSpell(spell)
+	if err != nil {
+		switch err {
+		case store.ErrSpellExists:
+			respondError(w, http.StatusConflict, err.Error())
+		case store.ErrInvalidSpell:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, created)
+}
+
+// UpdateCharacterSpell handles PUT /api/characters/{id}/spells/{spellId}
+func (h *Handler) UpdateCharacterSpell(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	spellID, err := strconv.ParseInt(chi.URLParam(r, "spellId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid spell id")
+		return
+	}
+
+	var req CharacterSpellRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	spell := req.ToStoreSpell()
+	spell.ID = spellID
+	spell.CharacterID = character.ID
+	updated, err := h.store.UpdateCharacterSpell(spell)
+	if err != nil {
+		switch err {
+		case store.ErrSpellNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrSpellExists:
+			respondError(w, http.StatusConflict, err.Error())
+		case store.ErrInvalidSpell:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteCharacterSpell handles DELETE /api/characters/{id}/spells/{spellId}
+func (h *Handler) DeleteCharacterSpell(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	spellID, err := strconv.ParseInt(chi.URLParam(r, "spellId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid spell id")
+		return
+	}
+
+	if err := h.store.DeleteCharacterSpell(character.ID, spellID); err != nil {
+		if err == store.ErrSpellNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CastCharacterSpell handles POST /api/characters/{id}/spells/{spellId}/cast
+func (h *Handler) CastCharacterSpell(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	spellID, err := strconv.ParseInt(chi.URLParam(r, "spellId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid spell id")
+		return
+	}
+
+	var req CastSpellRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	cast, err := h.store.CastSpell(character, spellID, req.SlotLevel, req.Pact)
+	if err != nil {
+		switch err {
+		case store.ErrSpellNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNoSpellSlot:
+			respondError(w, http.StatusConflict, err.Error())
+		case store.ErrSpellNotPrepared, store.ErrInvalidSpellSlot:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, cast)
+}
+
+// UpdateCharacterSpellSlots handles PUT /api/characters/{id}/spells/slots
+func (h *Handler) UpdateCharacterSpellSlots(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req SpellSlotsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	book, err := h.store.SetSpellSlotsExpended(character, req.Kind, req.Level, req.Expended)
+	if err != nil {
+		if err == store.ErrInvalidSpellSlot {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, book)
+}
+
+// Inventory handlers
+
+// GetCharacterInventory handles GET /api/characters/{id}/items
+func (h *Handler) GetCharacterInventory(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	inv, err := h.store.GetInventory(character)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, inv)
+}
+
+// AddCharacterItem handles POST /api/characters/{id}/items
+func (h *Handler) AddCharacterItem(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req CharacterItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	item := req.ToStoreItem()
+	item.CharacterID = character.ID
+	created, err := h.store.AddCharacterItem(item)
+	if err != nil {
+		switch err {
+		case store.ErrInvalidItem, store.ErrInvalidArmor, store.ErrInvalidContainer, store.ErrAttunementLimit:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, created)
+}
+
+// UpdateCharacterItem handles PUT /api/characters/{id}/items/{itemId}
+func (h *Handler) UpdateCharacterItem(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	itemID, err := strconv.ParseInt(chi.URLParam(r, "itemId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid item id")
+		return
+	}
+
+	var req CharacterItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	item := req.ToStoreItem()
+	item.ID = itemID
+	item.CharacterID = character.ID
+	updated, err := h.store.UpdateCharacterItem(item)
+	if err != nil {
+		switch err {
+		case store.ErrItemNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrInvalidItem, store.ErrInvalidArmor, store.ErrInvalidContainer, store.ErrAttunementLimit:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteCharacterItem handles DELETE /api/characters/{id}/items/{itemId}
+func (h *Handler) DeleteCharacterItem(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	itemID, err := strconv.ParseInt(chi.URLParam(r, "itemId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid item id")
+		return
+	}
+
+	if err := h.store.DeleteCharacterItem(character.ID, itemID); err != nil {
+		if err == store.ErrItemNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateCharacterCoins handles PUT /api/characters/{id}/coins
+func (h *Handler) UpdateCharacterCoins(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req CoinsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	coins, err := h.store.SetCharacterCoins(store.CharacterCoin{
+		CharacterID: character.ID,
+		Cp:          int64(req.Cp),
+		Sp:          int64(req.Sp),
+		Ep:          int64(req.Ep),
+		Gp:          int64(req.Gp),
+		Pp:          int64(req.Pp),
+	})
+	if err != nil {
+		if err == store.ErrInvalidCoins {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, coins)
+}
+
+// Level-up handlers
+
+// LevelUpCharacter handles POST /api/characters/{id}/level-up
+func (h *Handler) LevelUpCharacter(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req LevelUpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.store.LevelUp(character, req.ToStoreChoice(), h.rng)
+	if err != nil {
+		switch err {
+		case store.ErrMaxLevel, store.ErrInvalidHitPointMethod, store.ErrAbilityIncreaseRequired,
+			store.ErrAbilityIncreaseNotAvailable, store.ErrInvalidAbilityIncrease, store.ErrInvalidLevelUpFeature,
+			store.ErrInvalidClasses, store.ErrMulticlassPrerequisite:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// GetCharacterLevelUps handles GET /api/characters/{id}/level-ups
+func (h *Handler) GetCharacterLevelUps(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	levelUps, err := h.store.ListLevelUps(character.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, levelUps)
+}
+
+// Ability score handlers
+
+// RollAbilityScores handles POST /api/characters/ability-rolls, rolling 4d6 drop lowest
+// six times for a new character's rolled ability scores.
+func (h *Handler) RollAbilityScores(w http.ResponseWriter, r *http.Request) {
+	roll, err := h.store.RollAbilityScores(getUserID(r), h.rng)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, roll)
+}
+
+// GetCharacterAbilityScores handles GET /api/characters/{id}/ability-scores
+func (h *Handler) GetCharacterAbilityScores(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	record, err := h.store.GetAbilityScores(character.ID)
+	if err != nil {
+		if err == store.ErrAbilityScoresNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, record)
+}
+
+// ListCampaignAbilityScores handles GET /api/campaigns/{id}/ability-scores
+func (h *Handler) ListCampaignAbilityScores(w http.ResponseWriter, r *http.Request) {
+	campaignID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
+
+	records, err := h.store.ListCampaignAbilityScores(campaignID, getUserID(r))
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, records)
+}
+
+// Export handlers
+
+// maxImportSize limits an imported character document, which may embed a 5MB avatar.
+const maxImportSize = int64(10 << 20) // 10MB
+
+// ExportCharacter handles GET /api/characters/{id}/export, returning a versioned JSON
+// document with the avatar embedded.
+func (h *Handler) ExportCharacter(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	doc, err := h.store.ExportCharacter(character)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if path, ok := h.uploadedFile(character.AvatarUrl); ok {
+		if data, err := os.ReadFile(path); err == nil {
+			doc.Avatar = &store.ExportedAvatar{ContentType: http.DetectContentType(data), Data: data}
+		}
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="character-%d.json"`, character.ID))
+	respondJSON(w, http.StatusOK, doc)
+}
+
+// ImportCharacter handles POST /api/characters/import, creating a character from an
+// export document. Documents from older schema versions are migrated first.
+func (h *Handler) ImportCharacter(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Export is too large")
+		return
+	}
+
+	doc, err := store.ParseCharacterExport(data)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	character, err := h.store.ImportCharacter(userID, doc)
+	if err != nil {
+		switch err {
+		case store.ErrInvalidClasses, store.ErrInvalidItem, store.ErrInvalidArmor, store.ErrInvalidContainer,
+			store.ErrAttunementLimit, store.ErrInvalidCoins, store.ErrInvalidSpell, store.ErrInvalidResource,
+			store.ErrInvalidAttack, store.ErrInvalidCondition, store.ErrInvalidImportedStats:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if doc.Avatar != nil && len(doc.Avatar.Data) > 0 {
+		if updated, err := h.saveImportedAvatar(character, doc.Avatar.Data); err == nil {
+			character = updated
+		}
+	}
+
+	w.Header().Set("ETag", characterETag(character))
+	respondJSON(w, http.StatusCreated, character)
+}
+
+// saveImportedAvatar writes an embedded avatar to the assets directory and sets it on
+// the character. Anything that is not a supported image is skipped.
+func (h *Handler) saveImportedAvatar(character *store.CharacterWithStats, data []byte) (*store.CharacterWithStats, error) {
+	contentType := http.DetectContentType(data)
+	ext := extensionForAsset(contentType)
+	if ext == "" || !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("unsupported avatar type %s", contentType)
+	}
+
+	fileName := fmt.Sprintf("char-%d-%d%s", character.ID, time.Now().UnixNano(), ext)
+	avatarDir := filepath.Join(h.assetsPath, "avatars")
+	if err := os.MkdirAll(avatarDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(avatarDir, fileName), data, 0644); err != nil {
+		return nil, err
+	}
+
+	avatarURL := fmt.Sprintf("%s/avatars/%s", uploadMountPath, fileName)
+	return h.store.UpdateCharacterAvatar(character.ID, character.UserID, avatarURL)
+}
+
+// Revision handlers
+
+// GetCharacterRevisions handles GET /api/characters/{id}/revisions
+func (h *Handler) GetCharacterRevisions(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	revisions, err := h.store.ListCharacterRevisions(character.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, revisions)
+}
+
+// DiffCharacterRevision handles GET /api/characters/{id}/revisions/{rev}/diff?against={rev|current}
+func (h *Handler) DiffCharacterRevision(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	rev, err := strconv.ParseInt(chi.URLParam(r, "rev"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid revision")
+		return
+	}
+
+	var against *int64
+	if v := r.URL.Query().Get("against"); v != "" && v != "current" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "against must be a revision number or \"current\"")
+			return
+		}
+		against = &n
+	}
+
+	diff, err := h.store.DiffCharacterRevision(character, rev, against)
+	if err != nil {
+		if err == store.ErrRevisionNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, diff)
+}
+
+// RestoreCharacterRevision handles POST /api/characters/{id}/revisions/{rev}/restore
+func (h *Handler) RestoreCharacterRevision(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	rev, err := strconv.ParseInt(chi.URLParam(r, "rev"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid revision")
+		return
+	}
+
+	if err := h.store.RestoreCharacterRevision(character, rev); err != nil {
+		if err == store.ErrRevisionNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, character)
+}
+
+// Rest handlers
+
+// RestCharacter handles POST /api/characters/{id}/rest
+func (h *Handler) RestCharacter(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req RestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.store.Rest(character, req.Type, req.HitDice, h.rng)
+	if err != nil {
+		switch err {
+		case store.ErrInvalidRestType, store.ErrNotEnoughHitDice, store.ErrLongRestAtZeroHp:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case store.ErrCharacterDead:
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// GetCharacterRests handles GET /api/characters/{id}/rests
+func (h *Handler) GetCharacterRests(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	rests, err := h.store.ListCharacterRests(character.ID, limit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rests)
+}
+
+// Hit point handlers
+
+// ApplyHitPoints handles POST /api/characters/{id}/hp
+func (h *Handler) ApplyHitPoints(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req HitPointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.store.ApplyHitPoints(character, req.ToStoreChange(), h.rng)
+	if err != nil {
+		switch err {
+		case store.ErrInvalidHitPointChange, store.ErrInvalidDamageType:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case store.ErrCharacterDead, store.ErrNotDying:
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Condition handlers
+
+// GetCharacterConditions handles GET /api/characters/{id}/conditions
+func (h *Handler) GetCharacterConditions(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	conditions, err := h.store.ListCharacterConditions(character.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, conditions)
+}
+
+// SetCharacterCondition handles POST /api/characters/{id}/conditions
+func (h *Handler) SetCharacterCondition(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req ConditionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cond := req.ToStoreCondition()
+	cond.CharacterID = character.ID
+	saved, err := h.store.SetCharacterCondition(cond)
+	if err != nil {
+		if err == store.ErrInvalidCondition {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, saved)
+}
+
+// DeleteCharacterCondition handles DELETE /api/characters/{id}/conditions/{conditionId}
+func (h *Handler) DeleteCharacterCondition(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	conditionID, err := strconv.ParseInt(chi.URLParam(r, "conditionId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid condition id")
+		return
+	}
+
+	if err := h.store.DeleteCharacterCondition(character.ID, conditionID); err != nil {
+		if err == store.ErrConditionNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Resource handlers
+
+// GetCharacterResources handles GET /api/characters/{id}/resources
+func (h *Handler) GetCharacterResources(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	resources, err := h.store.ListCharacterResources(character)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resources)
+}
+
+// AddCharacterResource handles POST /api/characters/{id}/resources
+func (h *Handler) AddCharacterResource(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req ResourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	added, err := h.store.AddCharacterResource(character, req.ToStoreResource())
+	if err != nil {
+		switch err {
+		case store.ErrInvalidResource:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case store.ErrResourceExists:
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, added)
+}
+
+// UpdateCharacterResource handles PUT /api/characters/{id}/resources/{resourceId}
+func (h *Handler) UpdateCharacterResource(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	resourceID, err := strconv.ParseInt(chi.URLParam(r, "resourceId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid resource id")
+		return
+	}
+
+	var req ResourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	res := req.ToStoreResource()
+	res.ID = resourceID
+	updated, err := h.store.UpdateCharacterResource(character, res)
+	if err != nil {
+		switch err {
+		case store.ErrInvalidResource:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case store.ErrResourceNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrResourceExists:
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteCharacterResource handles DELETE /api/characters/{id}/resources/{resourceId}
+func (h *Handler) DeleteCharacterResource(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	resourceID, err := strconv.ParseInt(chi.URLParam(r, "resourceId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid resource id")
+		return
+	}
+
+	if err := h.store.DeleteCharacterResource(character.ID, resourceID); err != nil {
+		if err == store.ErrResourceNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Attack handlers
+
+// GetCharacterAttacks handles GET /api/characters/{id}/attacks
+func (h *Handler) GetCharacterAttacks(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	attacks, err := h.store.ListCharacterAttacks(character)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, attacks)
+}
+
+// AddCharacterAttack handles POST /api/characters/{id}/attacks
+func (h *Handler) AddCharacterAttack(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	var req AttackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	added, err := h.store.AddCharacterAttack(character, req.ToStoreAttack())
+	if err != nil {
+		if err == store.ErrInvalidAttack {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, added)
+}
+
+// UpdateCharacterAttack handles PUT /api/characters/{id}/attacks/{attackId}
+func (h *Handler) UpdateCharacterAttack(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	attackID, err := strconv.ParseInt(chi.URLParam(r, "attackId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid attack id")
+		return
+	}
+
+	var req AttackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	attack := req.ToStoreAttack()
+	attack.ID = attackID
+	updated, err := h.store.UpdateCharacterAttack(character, attack)
+	if err != nil {
+		switch err {
+		case store.ErrInvalidAttack:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case store.ErrAttackNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteCharacterAttack handles DELETE /api/characters/{id}/attacks/{attackId}
+func (h *Handler) DeleteCharacterAttack(w http.ResponseWriter, r *http.Request) {
+	character := h.loadCharacter(w, r)
+	if character == nil {
+		return
+	}
+
+	attackID, err := strconv.ParseInt(chi.URLParam(r, "attackId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid attack id")
+		return
+	}
+
+	if err := h.store.DeleteCharacterAttack(character.ID, attackID); err != nil {
+		if err == store.ErrAttackNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Rules handlers
+
+// GetRules handles GET /api/rules/{kind}, listing the species, classes, subclasses,
+// backgrounds, items or spells the user's characters can be created with, including
+// those from their homebrew packs and, with ?campaignId=, that campaign's packs.
+func (h *Handler) GetRules(w http.ResponseWriter, r *http.Request) {
+	var campaignID *int64
+	if raw := r.URL.Query().Get("campaignId"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid campaign id")
+			return
+		}
+		campaignID = &id
+	}
+
+	catalog, err := h.store.Catalog(getUserID(r), campaignID)
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	switch chi.URLParam(r, "kind") {
+	case "species":
+		respondJSON(w, http.StatusOK, catalog.Species)
+	case "classes":
+		respondJSON(w, http.StatusOK, catalog.Classes)
+	case "subclasses":
+		respondJSON(w, http.StatusOK, catalog.Subclasses)
+	case "backgrounds":
+		respondJSON(w, http.StatusOK, catalog.Backgrounds)
+	case "items":
+		respondJSON(w, http.StatusOK, catalog.Items)
+	case "spells":
+		respondJSON(w, http.StatusOK, catalog.Spells)
+	default:
+		respondError(w, http.StatusNotFound, "Unknown rules category")
+	}
+}
+
+// Homebrew handlers
+
+// maxPackSize limits the size of an uploaded homebrew pack.
+const maxPackSize = int64(1 << 20) // 1MB
+
+// ListHomebrewPacks handles GET /api/homebrew
+func (h *Handler) ListHomebrewPacks(w http.ResponseWriter, r *http.Request) {
+	packs, err := h.store.ListHomebrewPacks(getUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, packs)
+}
+
+// UploadHomebrewPack handles POST /api/homebrew. The body is the pack as JSON or YAML.
+func (h *Handler) UploadHomebrewPack(w http.ResponseWriter, r *http.Request) {
+	h.saveHomebrewPack(w, r, nil)
+}
+
+// ListCampaignHomebrewPacks handles GET /api/campaigns/{id}/homebrew
+func (h *Handler) ListCampaignHomebrewPacks(w http.ResponseWriter, r *http.Request) {
+	campaignID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
+
+	packs, err := h.store.ListCampaignHomebrewPacks(campaignID, getUserID(r))
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, packs)
+}
+
+// UploadCampaignHomebrewPack handles POST /api/campaigns/{id}/homebrew. Only the
+// campaign's owner can upload packs for it.
+func (h *Handler) UploadCampaignHomebrewPack(w http.ResponseWriter, r *http.Request) {
+	campaignID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
+
+	h.saveHomebrewPack(w, r, &campaignID)
+}
+
+func (h *Handler) saveHomebrewPack(w http.ResponseWriter, r *http.Request, campaignID *int64) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPackSize))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Pack is too large")
+		return
+	}
+
+	pack, err := h.store.SaveHomebrewPack(getUserID(r), campaignID, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, rules.ErrInvalidPack):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case err == store.ErrPackVersion:
+			respondError(w, http.StatusConflict, err.Error())
+		case err == store.ErrNotCampaignMember, err == store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, pack)
+}
+
+// DeleteHomebrewPack handles DELETE /api/homebrew/{id}
+func (h *Handler) DeleteHomebrewPack(w http.ResponseWriter, r *http.Request) {
+	packID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid pack id")
+		return
+	}
+
+	if err := h.store.DeleteHomebrewPack(packID, getUserID(r)); err != nil {
+		switch err {
+		case store.ErrPackNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Scene handlers
+
+// ListScenes handles GET /api/campaigns/{id}/scenes
+func (h *Handler) ListScenes(w http.ResponseWriter, r *http.Request) {
+	campaignID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
+
+	scenes, err := h.store.ListScenes(campaignID, getUserID(r))
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, scenes)
+}
+
+// CreateScene handles POST /api/campaigns/{id}/scenes
+func (h *Handler) CreateScene(w http.ResponseWriter, r *http.Request) {
+	campaignID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
+
+	var req SceneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	scene, err := h.store.CreateScene(campaignID, getUserID(r), req.Name, req.Description)
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidScene:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case store.ErrCampaignNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, scene)
+}
+
+// UpdateScene handles PUT /api/campaigns/{id}/scenes/{sceneId}
+func (h *Handler) UpdateScene(w http.ResponseWriter, r *http.Request) {
+	campaignID, sceneID, ok := sceneRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req SceneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	scene, err := h.store.UpdateScene(campaignID, sceneID, getUserID(r), req.Name, req.Description)
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidScene:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case store.ErrSceneNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, scene)
+}
+
+// ReorderScenes handles PUT /api/campaigns/{id}/scenes/order
+func (h *Handler) ReorderScenes(w http.ResponseWriter, r *http.Request) {
+	campaignID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return
+	}
+
+	var req SceneOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	scenes, err := h.store.ReorderScenes(campaignID, getUserID(r), req.SceneIDs)
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidSceneOrder:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, scenes)
+}
+
+// ActivateScene handles POST /api/campaigns/{id}/scenes/{sceneId}/activate
+func (h *Handler) ActivateScene(w http.ResponseWriter, r *http.Request) {
+	campaignID, sceneID, ok := sceneRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	scene, err := h.store.ActivateScene(campaignID, sceneID, getUserID(r))
+	if err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrSceneNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, scene)
+}
+
+// DeleteScene handles DELETE /api/campaigns/{id}/scenes/{sceneId}
+func (h *Handler) DeleteScene(w http.ResponseWriter, r *http.Request) {
+	campaignID, sceneID, ok := sceneRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.store.DeleteScene(campaignID, sceneID, getUserID(r)); err != nil {
+		switch err {
+		case store.ErrNotCampaignMember, store.ErrNotPermitted:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrSceneNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func sceneRouteIDs(w http.ResponseWriter, r *http.Request) (campaignID, sceneID int64, ok bool) {
+	campaignID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign id")
+		return 0, 0, false
+	}
+	sceneID, err = strconv.ParseInt(chi.URLParam(r, "sceneId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid scene id")
+		return 0, 0, false
+	}
+	return campaignID, sceneID, true
+}
+
+// Fog handlers
+
+// RevealFog handles POST /api/maps/{id}/fog/reveal
+func (h *Handler) RevealFog(w http.ResponseWriter, r *http.Request) {
+	h.changeFog(w, r, h.store.RevealFog)
+}
+
+// HideFog handles POST /api/maps/{id}/fog/hide
+func (h *Handler) HideFog(w http.ResponseWriter, r *http.Request) {
+	h.changeFog(w, r, h.store.HideFog)
+}
+
+func (h *Handler) changeFog(w http.ResponseWriter, r *http.Request, change func(mapID, userID int64, change store.FogChange) (*models.Fog, error)) {
+	mapID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid map id")
+		return
+	}
+
+	var req FogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	fog, err := change(mapID, getUserID(r), req.ToStoreChange())
+	if err != nil {
+		switch err {
+		case store.ErrCampaignMapNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidFog, store.ErrFogTooDetailed:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case store.ErrFogModeMismatch:
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, fog)
+}
+
+// ResetFog handles POST /api/maps/{id}/fog/reset
+func (h *Handler) ResetFog(w http.ResponseWriter, r *http.Request) {
+	mapID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid map id")
+		return
+	}
+
+	var req FogResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	fog, err := h.store.ResetFog(mapID, getUserID(r), req.Mode)
+	if err != nil {
+		switch err {
+		case store.ErrCampaignMapNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidFogMode:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, fog)
+}
+
+// Wall and lighting handlers
+
+// ListWalls handles GET /api/maps/{id}/walls
+func (h *Handler) ListWalls(w http.ResponseWriter, r *http.Request) {
+	mapID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid map id")
+		return
+	}
+
+	walls, err := h.store.ListWalls(mapID, getUserID(r))
+	if err != nil {
+		switch err {
+		case store.ErrCampaignMapNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, walls)
+}
+
+// CreateWall handles POST /api/maps/{id}/walls
+func (h *Handler) CreateWall(w http.ResponseWriter, r *http.Request) {
+	mapID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid map id")
+		return
+	}
+
+	var req WallRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	wall, err := h.store.CreateWall(mapID, getUserID(r), req.ToModel())
+	if err != nil {
+		switch err {
+		case store.ErrCampaignMapNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidWall:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, wall)
+}
+
+// UpdateWall handles PUT /api/walls/{id}
+func (h *Handler) UpdateWall(w http.ResponseWriter, r *http.Request) {
+	wallID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid wall id")
+		return
+	}
+
+	var req WallRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	wall, err := h.store.UpdateWall(wallID, getUserID(r), req.ToModel())
+	if err != nil {
+		switch err {
+		case store.ErrWallNotFound, store.ErrCampaignMapNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidWall:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, wall)
+}
+
+// DeleteWall handles DELETE /api/walls/{id}
+func (h *Handler) DeleteWall(w http.ResponseWriter, r *http.Request) {
+	wallID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid wall id")
+		return
+	}
+
+	if err := h.store.DeleteWall(wallID, getUserID(r)); err != nil {
+		switch err {
+		case store.ErrWallNotFound, store.ErrCampaignMapNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateMapLighting handles PUT /api/maps/{id}/lighting
+func (h *Handler) UpdateMapLighting(w http.ResponseWriter, r *http.Request) {
+	mapID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid map id")
+		return
+	}
+
+	var req struct {
+		LightingMode string `json:"lightingMode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.store.UpdateMapLighting(mapID, getUserID(r), req.LightingMode); err != nil {
+		switch err {
+		case store.ErrCampaignMapNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case store.ErrNotPermitted, store.ErrNotCampaignMember:
+			respondError(w, http.StatusForbidden, err.Error())
+		case store.ErrInvalidLightingMode:
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"lightingMode": req.LightingMode})
+}
+
+// Auth middleware
+func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			respondError(w, http.StatusUnauthorized, "Authorization header required")
+			return
+		}
+
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			respondError(w, http.StatusUnauthorized, "Invalid authorization header")
+			return
+		}
+
+		userID, err := h.validateJWT(parts[1])
+		if err != nil {
+			respondError(w, http.StatusUnauthorized, "Invalid token")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDKey, userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Helper functions
+
+func extensionForAsset(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
 	case "image/gif":
 		return ".gif"
 	case "application/pdf":
@@ -1055,6 +3117,84 @@ func extensionForAsset(contentType string) string {
 	}
 }
 
+func checkLabel(m *store.CheckModifier) string {
+	switch m.Kind {
+	case store.CheckSkill:
+		return fmt.Sprintf("%s check", m.Name)
+	case store.CheckSave:
+		return fmt.Sprintf("%s saving throw", m.Name)
+	case store.CheckAbility:
+		return fmt.Sprintf("%s check", m.Name)
+	case store.CheckAttack:
+		return fmt.Sprintf("%s attack", m.Name)
+	default:
+		return "initiative"
+	}
+}
+
+// loadCharacter resolves the {id} URL parameter to one of the caller's characters.
+// It writes the error response and returns nil when the character can't be used.
+func (h *Handler) loadCharacter(w http.ResponseWriter, r *http.Request) *store.CharacterWithStats {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid character id")
+		return nil
+	}
+
+	character, err := h.store.GetCharacter(id, getUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return nil
+	}
+	if character == nil {
+		respondError(w, http.StatusNotFound, "Character not found")
+		return nil
+	}
+	return character
+}
+
+// uploadedFile maps an uploads URL to its file in the assets directory, refusing paths
+// that would escape it.
+func (h *Handler) uploadedFile(url string) (string, bool) {
+	if !strings.HasPrefix(url, uploadMountPath+"/") {
+		return "", false
+	}
+	rest := strings.TrimPrefix(url, uploadMountPath+"/")
+	if rest == "" {
+		return "", false
+	}
+	target := filepath.Join(h.assetsPath, filepath.Clean(rest))
+	if rel, err := filepath.Rel(h.assetsPath, target); err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return target, true
+}
+
+// characterETag is the character's version for If-Match preconditions. It is the quoted
+// updatedAt timestamp, so clients can also build it from the JSON body.
+func characterETag(c *store.CharacterWithStats) string {
+	return `"` + c.UpdatedAt.Format(time.RFC3339Nano) + `"`
+}
+
+// checkIfMatch enforces an If-Match header against the character's ETag, writing 412 and
+// returning false when none of the listed tags match. Weak tags compare by value.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, c *store.CharacterWithStats) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" || strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	etag := characterETag(c)
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	w.Header().Set("ETag", etag)
+	respondError(w, http.StatusPreconditionFailed, store.ErrPreconditionFailed.Error())
+	return false
+}
+
 func getUserID(r *http.Request) int64 {
 	if id, ok := r.Context().Value(userIDKey).(int64); ok {
 		return id
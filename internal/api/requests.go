@@ -3,6 +3,9 @@ package api
 import (
 	"encoding/json"
 
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+	"github.com/jasoncabot/dicewizard-characters/internal/geometry"
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
 	"github.com/jasoncabot/dicewizard-characters/internal/store"
 )
 
@@ -16,6 +19,15 @@ type CreateCharacterRequest struct {
 	Alignment        string `json:"alignment"`
 	ExperiencePoints int    `json:"experiencePoints"`
 
+	// Classes lists the level in each class, starting class first. When set it replaces
+	// class and level; when omitted, class and level describe a single class.
+	Classes []ClassLevelRequest `json:"classes"`
+
+	// CampaignID, on creation, is the campaign the character is being made for. The
+	// character joins the campaign, and its homebrew packs are available alongside the
+	// user's own.
+	CampaignID *int64 `json:"campaignId"`
+
 	Strength     int `json:"strength"`
 	Dexterity    int `json:"dexterity"`
 	Constitution int `json:"constitution"`
@@ -23,17 +35,40 @@ type CreateCharacterRequest struct {
 	Wisdom       int `json:"wisdom"`
 	Charisma     int `json:"charisma"`
 
-	MaxHP      int    `json:"maxHp"`
-	CurrentHP  int    `json:"currentHp"`
+	// On creation the scores above are the base scores generated with AbilityScoreMethod:
+	// "point-buy", "standard-array" or "rolled" (assigned from the server roll
+	// AbilityRollID). AbilityIncreases are the background's +2/+1 or
+	// +1/+1/+1, added by the server.
+	AbilityScoreMethod string         `json:"abilityScoreMethod"`
+	AbilityRollID      *int64         `json:"abilityRollId"`
+	AbilityIncreases   map[string]int `json:"abilityIncreases"`
+
+	MaxHP int `json:"maxHp"`
+	// CurrentHP is a pointer so that 0 (unconscious) differs from omitted, which means full HP.
+	CurrentHP  *int   `json:"currentHp"`
 	TempHP     int    `json:"tempHp"`
 	ArmorClass int    `json:"armorClass"`
 	Speed      int    `json:"speed"`
 	HitDice    string `json:"hitDice"`
 
+	// ArmorClass is only kept when ArmorClassOverride is set; otherwise it is worked out
+	// from equipped armor, adding ArmorClassBonus for rings, spells and the like. They are
+	// pointers so that an update leaving them out keeps the saved values.
+	ArmorClassOverride *bool `json:"armorClassOverride"`
+	ArmorClassBonus    *int  `json:"armorClassBonus"`
+
 	SkillProficiencies       []string `json:"skillProficiencies"`
 	SavingThrowProficiencies []string `json:"savingThrowProficiencies"`
 	Features                 []string `json:"features"`
 	Equipment                []string `json:"equipment"`
+
+	// ProficiencyLevels overrides the level for individual skills, e.g. {"stealth": "expertise"}.
+	ProficiencyLevels map[string]string `json:"proficiencyLevels"`
+
+	// Damage types applied by POST /api/characters/{id}/hp. Omitted lists are left unchanged on update.
+	DamageResistances     []string `json:"damageResistances"`
+	DamageVulnerabilities []string `json:"damageVulnerabilities"`
+	DamageImmunities      []string `json:"damageImmunities"`
 }
 
 // ToStoreCharacter converts a CreateCharacterRequest to a store.CharacterWithStats
@@ -54,7 +89,6 @@ func (r *CreateCharacterRequest) ToStoreCharacter() *store.CharacterWithStats {
 			Wisdom:                   int64(r.Wisdom),
 			Charisma:                 int64(r.Charisma),
 			MaxHp:                    int64(r.MaxHP),
-			CurrentHp:                int64(r.CurrentHP),
 			TempHp:                   int64(r.TempHP),
 			ArmorClass:               int64(r.ArmorClass),
 			Speed:                    int64(r.Speed),
@@ -62,8 +96,20 @@ func (r *CreateCharacterRequest) ToStoreCharacter() *store.CharacterWithStats {
 			SkillProficiencies:       sliceToJSON(r.SkillProficiencies),
 			SavingThrowProficiencies: sliceToJSON(r.SavingThrowProficiencies),
 			Features:                 sliceToJSON(r.Features),
-			Equipment:                sliceToJSON(r.Equipment),
 		},
+		Equipment: r.Equipment,
+	}
+	if r.ArmorClassOverride != nil {
+		c.ArmorClassOverride = *r.ArmorClassOverride
+	}
+	if r.ArmorClassBonus != nil {
+		c.ArmorClassBonus = int64(*r.ArmorClassBonus)
+	}
+	if r.Classes != nil {
+		c.Classes = make([]store.CharacterClass, 0, len(r.Classes))
+		for _, cl := range r.Classes {
+			c.Classes = append(c.Classes, store.CharacterClass{Class: cl.Class, Level: int64(cl.Level), Subclass: cl.Subclass})
+		}
 	}
 
 	// Set defaults
@@ -91,7 +137,9 @@ func (r *CreateCharacterRequest) ToStoreCharacter() *store.CharacterWithStats {
 	if c.MaxHp == 0 {
 		c.MaxHp = 10
 	}
-	if c.CurrentHp == 0 {
+	if r.CurrentHP != nil {
+		c.CurrentHp = int64(*r.CurrentHP)
+	} else {
 		c.CurrentHp = c.MaxHp
 	}
 	if c.ArmorClass == 0 {
@@ -107,6 +155,24 @@ func (r *CreateCharacterRequest) ToStoreCharacter() *store.CharacterWithStats {
 	return c
 }
 
+// AbilityScoreGeneration returns how the request says its base ability scores were
+// generated. Scores left out stay 0 so that they are reported as missing.
+func (r *CreateCharacterRequest) AbilityScoreGeneration() *store.AbilityScoreGeneration {
+	return &store.AbilityScoreGeneration{
+		Method: r.AbilityScoreMethod,
+		Base: map[string]int{
+			"strength":     r.Strength,
+			"dexterity":    r.Dexterity,
+			"constitution": r.Constitution,
+			"intelligence": r.Intelligence,
+			"wisdom":       r.Wisdom,
+			"charisma":     r.Charisma,
+		},
+		Increases: r.AbilityIncreases,
+		RollID:    r.AbilityRollID,
+	}
+}
+
 // CreateNoteRequest captures the payload for creating a note.
 type CreateNoteRequest struct {
 	EntityType string `json:"entityType"`
@@ -115,6 +181,375 @@ type CreateNoteRequest struct {
 	Body       string `json:"body"`
 }
 
+// RollRequest is the payload for rolling a dice expression on the server.
+type RollRequest struct {
+	Expression string `json:"expression"`
+	Mode       string `json:"mode,omitempty"`
+	Label      string `json:"label,omitempty"`
+}
+
+// CampaignRollRequest is the payload for rolling into a campaign's roll log.
+type CampaignRollRequest struct {
+	RollRequest
+	Visibility  string `json:"visibility"`
+	CharacterID *int64 `json:"characterId,omitempty"`
+}
+
+// CharacterRollRequest asks the server to roll a check for a character.
+type CharacterRollRequest struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Mode       string `json:"mode,omitempty"`
+	CampaignID *int64 `json:"campaignId,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// CharacterRollResponse returns a check roll with the modifier breakdown.
+type CharacterRollResponse struct {
+	Modifier     *store.CheckModifier `json:"modifier"`
+	Explanation  string               `json:"explanation"`
+	Roll         RollResponse         `json:"roll"`
+	CampaignRoll *models.CampaignRoll `json:"campaignRoll,omitempty"`
+}
+
+// RollResponse wraps a dice result with a human readable breakdown.
+type RollResponse struct {
+	Label string `json:"label,omitempty"`
+	*dice.Result
+	Detail string `json:"detail"`
+}
+
+// CharacterSpellRequest is the payload for adding or updating a character's spell.
+type CharacterSpellRequest struct {
+	Name     string `json:"name"`
+	Level    int    `json:"level"`
+	School   string `json:"school"`
+	Prepared bool   `json:"prepared"`
+	Notes    string `json:"notes"`
+}
+
+// ToStoreSpell converts the request into a store.CharacterSpell.
+func (r *CharacterSpellRequest) ToStoreSpell() store.CharacterSpell {
+	return store.CharacterSpell{
+		Name:     r.Name,
+		Level:    int64(r.Level),
+		School:   r.School,
+		Prepared: r.Prepared,
+		Notes:    r.Notes,
+	}
+}
+
+// CastSpellRequest chooses the slot to spend. SlotLevel defaults to the spell's level;
+// Pact spends a warlock pact magic slot instead.
+type CastSpellRequest struct {
+	SlotLevel int  `json:"slotLevel"`
+	Pact      bool `json:"pact"`
+}
+
+// SpellSlotsRequest sets how many slots of one level have been used.
+type SpellSlotsRequest struct {
+	Kind     string `json:"kind"`
+	Level    int    `json:"level"`
+	Expended int    `json:"expended"`
+}
+
+// CharacterItemRequest is the payload for adding or updating an inventory item.
+// Weight is in pounds and value in copper pieces, both per item.
+type CharacterItemRequest struct {
+	Name        string  `json:"name"`
+	Quantity    *int    `json:"quantity"`
+	Weight      float64 `json:"weight"`
+	ValueCp     int     `json:"valueCp"`
+	Equipped    bool    `json:"equipped"`
+	Attuned     bool    `json:"attuned"`
+	IsContainer bool    `json:"isContainer"`
+	ArmorType   string  `json:"armorType"`
+	ArmorClass  int     `json:"armorClass"`
+	ContainerID *int64  `json:"containerId"`
+	Notes       string  `json:"notes"`
+	Position    int     `json:"position"`
+}
+
+// ToStoreItem converts the request into a store.CharacterItem, defaulting quantity to 1.
+func (r *CharacterItemRequest) ToStoreItem() store.CharacterItem {
+	quantity := 1
+	if r.Quantity != nil {
+		quantity = *r.Quantity
+	}
+	return store.CharacterItem{
+		ContainerID: r.ContainerID,
+		Name:        r.Name,
+		Quantity:    int64(quantity),
+		Weight:      r.Weight,
+		ValueCp:     int64(r.ValueCp),
+		Equipped:    r.Equipped,
+		Attuned:     r.Attuned,
+		IsContainer: r.IsContainer,
+		ArmorType:   r.ArmorType,
+		ArmorClass:  int64(r.ArmorClass),
+		Notes:       r.Notes,
+		Position:    int64(r.Position),
+	}
+}
+
+// CoinsRequest sets the contents of a character's coin purse.
+type CoinsRequest struct {
+	Cp int `json:"cp"`
+	Sp int `json:"sp"`
+	Ep int `json:"ep"`
+	Gp int `json:"gp"`
+	Pp int `json:"pp"`
+}
+
+// ClassLevelRequest is a class, the character's level in it and, optionally, its subclass.
+type ClassLevelRequest struct {
+	Class    string `json:"class"`
+	Level    int    `json:"level"`
+	Subclass string `json:"subclass"`
+}
+
+// LevelUpRequest is the payload for POST /api/characters/{id}/level-up. Class defaults
+// to the starting class; naming a new class multiclasses into it.
+type LevelUpRequest struct {
+	Class            string         `json:"class"`
+	HitPoints        string         `json:"hitPoints"`
+	AbilityIncreases map[string]int `json:"abilityIncreases"`
+	Feat             string         `json:"feat"`
+	Features         []string       `json:"features"`
+}
+
+// ToStoreChoice converts the request into a store.LevelUpChoice.
+func (r *LevelUpRequest) ToStoreChoice() store.LevelUpChoice {
+	return store.LevelUpChoice{
+		Class:            r.Class,
+		HitPoints:        r.HitPoints,
+		AbilityIncreases: r.AbilityIncreases,
+		Feat:             r.Feat,
+		Features:         r.Features,
+	}
+}
+
+// RestRequest is the payload for POST /api/characters/{id}/rest. HitDice is the number
+// of hit dice to spend on a short rest.
+type RestRequest struct {
+	Type    string `json:"type"`
+	HitDice int    `json:"hitDice"`
+}
+
+// HitPointRequest is the payload for POST /api/characters/{id}/hp. Type is "damage",
+// "heal", "temp" or "death-save"; DamageType and Critical only apply to damage.
+type HitPointRequest struct {
+	Type       string `json:"type"`
+	Amount     int    `json:"amount"`
+	DamageType string `json:"damageType"`
+	Critical   bool   `json:"critical"`
+}
+
+// ToStoreChange converts a HitPointRequest to a store.HitPointChange
+func (r *HitPointRequest) ToStoreChange() store.HitPointChange {
+	return store.HitPointChange{
+		Type:       r.Type,
+		Amount:     r.Amount,
+		DamageType: r.DamageType,
+		Critical:   r.Critical,
+	}
+}
+
+// ConditionRequest is the payload for POST /api/characters/{id}/conditions. Level is
+// only used for exhaustion; Duration, when given, counts DurationUnit "rounds" or "minutes".
+type ConditionRequest struct {
+	Name         string `json:"name"`
+	Level        int    `json:"level"`
+	Source       string `json:"source"`
+	Duration     *int64 `json:"duration"`
+	DurationUnit string `json:"durationUnit"`
+}
+
+// ToStoreCondition converts the request into a store.CharacterCondition
+func (r *ConditionRequest) ToStoreCondition() store.CharacterCondition {
+	return store.CharacterCondition{
+		Name:         r.Name,
+		Level:        int64(r.Level),
+		Source:       r.Source,
+		Duration:     r.Duration,
+		DurationUnit: r.DurationUnit,
+	}
+}
+
+// ResourceRequest is the payload for adding or updating a class resource. MaxFormula is
+// a number or a formula such as "prof", "cha" or "level/2+1"; Reset is "short", "long"
+// or "dawn".
+type ResourceRequest struct {
+	Name       string `json:"name"`
+	MaxFormula string `json:"maxFormula"`
+	Used       int    `json:"used"`
+	Reset      string `json:"reset"`
+}
+
+// ToStoreResource converts the request into a store.CharacterResource
+func (r *ResourceRequest) ToStoreResource() store.CharacterResource {
+	return store.CharacterResource{
+		Name:       r.Name,
+		MaxFormula: r.MaxFormula,
+		Used:       int64(r.Used),
+		Reset:      r.Reset,
+	}
+}
+
+// AttackRequest is the payload for adding or updating an attack. Kind is "melee",
+// "ranged" or "spell"; Ability may be left empty to pick it from the kind and properties.
+// Proficient defaults to true.
+type AttackRequest struct {
+	Name          string   `json:"name"`
+	Kind          string   `json:"kind"`
+	Ability       string   `json:"ability"`
+	Proficient    *bool    `json:"proficient"`
+	DamageDice    string   `json:"damageDice"`
+	VersatileDice string   `json:"versatileDice"`
+	DamageType    string   `json:"damageType"`
+	Properties    []string `json:"properties"`
+	Bonus         int      `json:"bonus"`
+}
+
+// ToStoreAttack converts the request into a store.CharacterAttack
+func (r *AttackRequest) ToStoreAttack() store.CharacterAttack {
+	proficient := true
+	if r.Proficient != nil {
+		proficient = *r.Proficient
+	}
+	return store.CharacterAttack{
+		Name:          r.Name,
+		Kind:          r.Kind,
+		Ability:       r.Ability,
+		Proficient:    proficient,
+		DamageDice:    r.DamageDice,
+		VersatileDice: r.VersatileDice,
+		DamageType:    r.DamageType,
+		Properties:    sliceToJSON(r.Properties),
+		Bonus:         int64(r.Bonus),
+	}
+}
+
+// SceneRequest is the payload for creating or updating a campaign scene.
+type SceneRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SceneOrderRequest lists every scene of a campaign in the order they should appear.
+type SceneOrderRequest struct {
+	SceneIDs []int64 `json:"sceneIds"`
+}
+
+// FogRequest is an area of a map to reveal or hide: grid cells, or a polygon whose points
+// are in grid squares.
+type FogRequest struct {
+	Cells   []models.FogCell `json:"cells"`
+	Polygon []geometry.Point `json:"polygon"`
+}
+
+// ToStoreChange converts the request into a store.FogChange
+func (r *FogRequest) ToStoreChange() store.FogChange {
+	return store.FogChange{Cells: r.Cells, Polygon: r.Polygon}
+}
+
+// FogResetRequest picks the fog mode a reset map starts in; an empty mode removes the fog.
+type FogResetRequest struct {
+	Mode string `json:"mode"`
+}
+
+// WallRequest is a wall or door between two points in grid squares. Doors are "open",
+// "closed" or "locked"; a door without a state starts closed.
+type WallRequest struct {
+	Kind  string         `json:"kind"`
+	A     geometry.Point `json:"a"`
+	B     geometry.Point `json:"b"`
+	State string         `json:"state"`
+}
+
+// ToModel converts the request into a models.Wall
+func (r *WallRequest) ToModel() models.Wall {
+	return models.Wall{Kind: r.Kind, A: r.A, B: r.B, State: r.State}
+}
+
+// characterToRequest converts a stored character back into the request shape, which
+// PATCH uses as the document the merge patch applies to.
+func characterToRequest(c *store.CharacterWithStats) (*CreateCharacterRequest, error) {
+	req := &CreateCharacterRequest{
+		Name:                     c.Name,
+		Race:                     c.Race,
+		Class:                    c.Class,
+		Level:                    int(c.Level),
+		Background:               c.Background,
+		Alignment:                c.Alignment,
+		ExperiencePoints:         int(c.ExperiencePoints),
+		Strength:                 int(c.Strength),
+		Dexterity:                int(c.Dexterity),
+		Constitution:             int(c.Constitution),
+		Intelligence:             int(c.Intelligence),
+		Wisdom:                   int(c.Wisdom),
+		Charisma:                 int(c.Charisma),
+		MaxHP:                    int(c.MaxHp),
+		CurrentHP:                ptr(int(c.CurrentHp)),
+		TempHP:                   int(c.TempHp),
+		ArmorClass:               int(c.ArmorClass),
+		ArmorClassOverride:       ptr(c.ArmorClassOverride),
+		ArmorClassBonus:          ptr(int(c.ArmorClassBonus)),
+		Speed:                    int(c.Speed),
+		HitDice:                  c.HitDice,
+		SkillProficiencies:       jsonToSlice(c.SkillProficiencies),
+		SavingThrowProficiencies: jsonToSlice(c.SavingThrowProficiencies),
+		Features:                 jsonToSlice(c.Features),
+		Equipment:                c.Equipment,
+		ProficiencyLevels:        map[string]string{},
+		DamageResistances:        jsonToSlice(c.DamageResistances),
+		DamageVulnerabilities:    jsonToSlice(c.DamageVulnerabilities),
+		DamageImmunities:         jsonToSlice(c.DamageImmunities),
+	}
+	for _, cl := range c.Classes {
+		req.Classes = append(req.Classes, ClassLevelRequest{Class: cl.Class, Level: int(cl.Level), Subclass: cl.Subclass})
+	}
+	if c.ProficiencyLevels != "" {
+		if err := json.Unmarshal([]byte(c.ProficiencyLevels), &req.ProficiencyLevels); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// mergePatch applies an RFC 7396 JSON Merge Patch: objects merge recursively, null
+// removes a member, and anything else replaces the target.
+func mergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = map[string]any{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func jsonToSlice(s string) []string {
+	var values []string
+	if err := json.Unmarshal([]byte(s), &values); err != nil || values == nil {
+		return []string{}
+	}
+	return values
+}
+
 func sliceToJSON(s []string) string {
 	if s == nil {
 		return "[]"
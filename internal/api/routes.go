@@ -25,9 +25,9 @@ func NewRouter(h *Handler, frontendFS fs.FS, assetsPath string) *chi.Mux {
 	// CORS for development
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:5173", "http://localhost:8080"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -60,9 +60,45 @@ func NewRouter(h *Handler, frontendFS fs.FS, assetsPath string) *chi.Mux {
 			r.Use(h.AuthMiddleware)
 			r.Get("/", h.ListCharacters)
 			r.Post("/", h.CreateCharacter)
+			r.Post("/ability-rolls", h.RollAbilityScores)
+			r.Post("/import", h.ImportCharacter)
 			r.Get("/{id}", h.GetCharacter)
 			r.Put("/{id}", h.UpdateCharacter)
+			r.Patch("/{id}", h.PatchCharacter)
 			r.Post("/{id}/avatar", h.UploadCharacterAvatar)
+			r.Post("/{id}/roll", h.RollCharacterCheck)
+			r.Get("/{id}/spells", h.GetCharacterSpells)
+			r.Post("/{id}/spells", h.AddCharacterSpell)
+			r.Put("/{id}/spells/slots", h.UpdateCharacterSpellSlots)
+			r.Put("/{id}/spells/{spellId}", h.UpdateCharacterSpell)
+			r.Delete("/{id}/spells/{spellId}", h.DeleteCharacterSpell)
+			r.Post("/{id}/spells/{spellId}/cast", h.CastCharacterSpell)
+			r.Get("/{id}/items", h.GetCharacterInventory)
+			r.Post("/{id}/items", h.AddCharacterItem)
+			r.Put("/{id}/items/{itemId}", h.UpdateCharacterItem)
+			r.Delete("/{id}/items/{itemId}", h.DeleteCharacterItem)
+			r.Put("/{id}/coins", h.UpdateCharacterCoins)
+			r.Post("/{id}/level-up", h.LevelUpCharacter)
+			r.Get("/{id}/level-ups", h.GetCharacterLevelUps)
+			r.Get("/{id}/ability-scores", h.GetCharacterAbilityScores)
+			r.Get("/{id}/export", h.ExportCharacter)
+			r.Get("/{id}/revisions", h.GetCharacterRevisions)
+			r.Get("/{id}/revisions/{rev}/diff", h.DiffCharacterRevision)
+			r.Post("/{id}/revisions/{rev}/restore", h.RestoreCharacterRevision)
+			r.Post("/{id}/rest", h.RestCharacter)
+			r.Get("/{id}/rests", h.GetCharacterRests)
+			r.Post("/{id}/hp", h.ApplyHitPoints)
+			r.Get("/{id}/conditions", h.GetCharacterConditions)
+			r.Post("/{id}/conditions", h.SetCharacterCondition)
+			r.Delete("/{id}/conditions/{conditionId}", h.DeleteCharacterCondition)
+			r.Get("/{id}/resources", h.GetCharacterResources)
+			r.Post("/{id}/resources", h.AddCharacterResource)
+			r.Put("/{id}/resources/{resourceId}", h.UpdateCharacterResource)
+			r.Delete("/{id}/resources/{resourceId}", h.DeleteCharacterResource)
+			r.Get("/{id}/attacks", h.GetCharacterAttacks)
+			r.Post("/{id}/attacks", h.AddCharacterAttack)
+			r.Put("/{id}/attacks/{attackId}", h.UpdateCharacterAttack)
+			r.Delete("/{id}/attacks/{attackId}", h.DeleteCharacterAttack)
 			r.Delete("/{id}", h.DeleteCharacter)
 		})
 
@@ -80,20 +116,50 @@ func NewRouter(h *Handler, frontendFS fs.FS, assetsPath string) *chi.Mux {
 			r.Post("/{id}/maps", h.UploadCampaignMap)
 			r.Post("/{id}/handouts", h.UploadCampaignHandout)
 			r.Get("/{id}/members", h.ListCampaignMembers)
+			r.Get("/{id}/rolls", h.ListCampaignRolls)
+			r.Post("/{id}/rolls", h.CreateCampaignRoll)
+			r.Get("/{id}/rolls/audit", h.AuditCampaignRolls)
+			r.Get("/{id}/rests", h.ListCampaignRests)
 			r.Put("/{id}/members/{userId}/role", h.UpdateCampaignMemberRole)
 			r.Post("/{id}/members/{userId}/revoke", h.RevokeCampaignMember)
+			r.Get("/{id}/homebrew", h.ListCampaignHomebrewPacks)
+			r.Post("/{id}/homebrew", h.UploadCampaignHomebrewPack)
+			r.Get("/{id}/ability-scores", h.ListCampaignAbilityScores)
+			r.Get("/{id}/scenes", h.ListScenes)
+			r.Post("/{id}/scenes", h.CreateScene)
+			r.Put("/{id}/scenes/order", h.ReorderScenes)
+			r.Put("/{id}/scenes/{sceneId}", h.UpdateScene)
+			r.Post("/{id}/scenes/{sceneId}/activate", h.ActivateScene)
+			r.Delete("/{id}/scenes/{sceneId}", h.DeleteScene)
 		})
 
 		// Map-scoped routes
 		r.Route("/maps", func(r chi.Router) {
 			r.Use(h.AuthMiddleware)
 			r.Post("/{id}/tokens", h.CreateMapToken)
+			r.Post("/{id}/fog/reveal", h.RevealFog)
+			r.Post("/{id}/fog/hide", h.HideFog)
+			r.Post("/{id}/fog/reset", h.ResetFog)
+			r.Put("/{id}/lighting", h.UpdateMapLighting)
+			r.Get("/{id}/walls", h.ListWalls)
+			r.Post("/{id}/walls", h.CreateWall)
+		})
+
+		// Wall routes
+		r.Route("/walls", func(r chi.Router) {
+			r.Use(h.AuthMiddleware)
+			r.Put("/{id}", h.UpdateWall)
+			r.Delete("/{id}", h.DeleteWall)
 		})
 
 		// Token routes
 		r.Route("/tokens", func(r chi.Router) {
 			r.Use(h.AuthMiddleware)
+			r.Put("/{id}", h.UpdateToken)
 			r.Put("/{id}/position", h.UpdateTokenPosition)
+			r.Put("/{id}/layer", h.UpdateTokenLayer)
+			r.Put("/{id}/vision", h.UpdateTokenVision)
+			r.Delete("/{id}", h.DeleteToken)
 		})
 
 		// Public invite accept (auth required)
@@ -102,6 +168,26 @@ func NewRouter(h *Handler, frontendFS fs.FS, assetsPath string) *chi.Mux {
 			r.Post("/campaigns/invites/{code}/accept", h.AcceptCampaignInvite)
 		})
 
+		// Rules catalog routes
+		r.Route("/rules", func(r chi.Router) {
+			r.Use(h.AuthMiddleware)
+			r.Get("/{kind}", h.GetRules)
+		})
+
+		// Homebrew pack routes
+		r.Route("/homebrew", func(r chi.Router) {
+			r.Use(h.AuthMiddleware)
+			r.Get("/", h.ListHomebrewPacks)
+			r.Post("/", h.UploadHomebrewPack)
+			r.Delete("/{id}", h.DeleteHomebrewPack)
+		})
+
+		// Dice roll routes
+		r.Route("/rolls", func(r chi.Router) {
+			r.Use(h.AuthMiddleware)
+			r.Post("/", h.CreateRoll)
+		})
+
 		// Protected note routes
 		r.Route("/notes", func(r chi.Router) {
 			r.Use(h.AuthMiddleware)
@@ -0,0 +1,224 @@
+// Package dice parses and evaluates dice expressions such as "2d20kh1+5",
+// "4d6dl1" or "1d8+1d6+3".
+//
+// Supported notation per dice term:
+//
+//	NdS      roll N dice with S sides (N defaults to 1, S may be "%" for 100)
+//	khX klX  keep the highest/lowest X dice (k is shorthand for kh)
+//	dhX dlX  drop the highest/lowest X dice (d is shorthand for dl)
+//	!        explode: roll again whenever a die shows its maximum
+//	!>X !=X  explode on a custom target
+//	rX r<X   reroll dice matching the target until they no longer match
+//	roX      reroll matching dice once
+//
+// Terms are joined with + and -, and may be plain integer constants.
+package dice
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Limits that keep a single expression cheap to evaluate.
+const (
+	MaxTerms      = 20
+	MaxDice       = 100
+	MaxSides      = 1000
+	MaxExplosions = 100
+	maxRerolls    = 100
+)
+
+// ErrInvalidExpression is returned when an expression cannot be parsed.
+var ErrInvalidExpression = errors.New("invalid dice expression")
+
+// Mode selects whether d20 rolls are made with advantage or disadvantage.
+type Mode string
+
+const (
+	ModeNormal       Mode = ""
+	ModeAdvantage    Mode = "advantage"
+	ModeDisadvantage Mode = "disadvantage"
+)
+
+// RNG is the source of randomness used to roll dice. *rand.Rand satisfies it,
+// which lets tests inject a seeded generator.
+type RNG interface {
+	IntN(n int) int
+}
+
+// NewSeededRNG returns a deterministic RNG for tests and replays.
+func NewSeededRNG(seed uint64) RNG {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+type defaultRNG struct{}
+
+func (defaultRNG) IntN(n int) int { return rand.IntN(n) }
+
+// DefaultRNG returns the process-wide randomly seeded generator.
+func DefaultRNG() RNG {
+	return defaultRNG{}
+}
+
+// Die is a single physical die roll inside a term.
+type Die struct {
+	Sides    int  `json:"sides"`
+	Value    int  `json:"value"`
+	Dropped  bool `json:"dropped,omitempty"`
+	Exploded bool `json:"exploded,omitempty"`
+	Rerolled bool `json:"rerolled,omitempty"`
+}
+
+// TermResult is the outcome of one +/- separated term of an expression.
+type TermResult struct {
+	Notation string `json:"notation"`
+	Sign     int    `json:"sign"`
+	Dice     []Die  `json:"dice,omitempty"`
+	Subtotal int    `json:"subtotal"`
+}
+
+// Result is the evaluated expression with a per-die breakdown.
+type Result struct {
+	Expression string       `json:"expression"`
+	Mode       Mode         `json:"mode,omitempty"`
+	Terms      []TermResult `json:"terms"`
+	Total      int          `json:"total"`
+}
+
+// String renders the result as e.g. "2d20kh1 [17, 4d] + 5 = 22".
+func (r Result) String() string {
+	var b strings.Builder
+	for i, t := range r.Terms {
+		switch {
+		case i > 0 && t.Sign < 0:
+			b.WriteString(" - ")
+		case i > 0:
+			b.WriteString(" + ")
+		case t.Sign < 0:
+			b.WriteString("-")
+		}
+		b.WriteString(t.Notation)
+		if len(t.Dice) > 0 {
+			parts := make([]string, 0, len(t.Dice))
+			for _, d := range t.Dice {
+				s := strconv.Itoa(d.Value)
+				if d.Exploded {
+					s += "!"
+				}
+				if d.Dropped || d.Rerolled {
+					s += "d"
+				}
+				parts = append(parts, s)
+			}
+			b.WriteString(" [" + strings.Join(parts, ", ") + "]")
+		}
+	}
+	fmt.Fprintf(&b, " = %d", r.Total)
+	return b.String()
+}
+
+// Roll parses and evaluates expr using rng.
+func Roll(expr string, rng RNG) (*Result, error) {
+	return RollWithMode(expr, ModeNormal, rng)
+}
+
+// RollWithMode evaluates expr, turning every plain single d20 into 2d20kh1
+// (advantage) or 2d20kl1 (disadvantage) when a mode is given.
+func RollWithMode(expr string, mode Mode, rng RNG) (*Result, error) {
+	if mode != ModeNormal && mode != ModeAdvantage && mode != ModeDisadvantage {
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidExpression, mode)
+	}
+	e, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	e.applyMode(mode)
+	if rng == nil {
+		rng = DefaultRNG()
+	}
+	res := e.eval(rng)
+	res.Mode = mode
+	return res, nil
+}
+
+func (e *Expression) eval(rng RNG) *Result {
+	res := &Result{Expression: e.String(), Terms: make([]TermResult, 0, len(e.Terms))}
+	for _, t := range e.Terms {
+		tr := TermResult{Notation: t.String(), Sign: t.Sign}
+		if t.Sides == 0 {
+			tr.Subtotal = t.Constant
+		} else {
+			tr.Dice = t.roll(rng)
+			for _, d := range tr.Dice {
+				if !d.Dropped && !d.Rerolled {
+					tr.Subtotal += d.Value
+				}
+			}
+		}
+		res.Total += t.Sign * tr.Subtotal
+		res.Terms = append(res.Terms, tr)
+	}
+	return res
+}
+
+func (t Term) roll(rng RNG) []Die {
+	dice := make([]Die, 0, t.Count)
+	kept := make([]int, 0, t.Count)
+	explosions := 0
+
+	for i := 0; i < t.Count; i++ {
+		value := 1 + rng.IntN(t.Sides)
+		if t.Reroll != nil {
+			for n := 0; n < maxRerolls && t.Reroll.matches(value); n++ {
+				dice = append(dice, Die{Sides: t.Sides, Value: value, Rerolled: true})
+				value = 1 + rng.IntN(t.Sides)
+				if t.RerollOnce {
+					break
+				}
+			}
+		}
+		dice = append(dice, Die{Sides: t.Sides, Value: value})
+		kept = append(kept, len(dice)-1)
+
+		for t.Explode != nil && t.Explode.matches(value) && explosions < MaxExplosions {
+			dice[len(dice)-1].Exploded = true
+			explosions++
+			value = 1 + rng.IntN(t.Sides)
+			dice = append(dice, Die{Sides: t.Sides, Value: value})
+			kept = append(kept, len(dice)-1)
+		}
+	}
+
+	if t.Keep != nil {
+		// Order kept dice by value (stable on index) and drop the excluded ones.
+		order := append([]int(nil), kept...)
+		for i := 1; i < len(order); i++ {
+			for j := i; j > 0 && dice[order[j]].Value < dice[order[j-1]].Value; j-- {
+				order[j], order[j-1] = order[j-1], order[j]
+			}
+		}
+		n := t.Keep.Count
+		if n > len(order) {
+			n = len(order)
+		}
+		var drop []int
+		switch t.Keep.Kind {
+		case KeepHighest:
+			drop = order[:len(order)-n]
+		case KeepLowest:
+			drop = order[n:]
+		case DropHighest:
+			drop = order[len(order)-n:]
+		case DropLowest:
+			drop = order[:n]
+		}
+		for _, idx := range drop {
+			dice[idx].Dropped = true
+		}
+	}
+
+	return dice
+}
@@ -0,0 +1,122 @@
+package dice
+
+import (
+	"errors"
+	"testing"
+)
+
+// scriptedRNG returns values from a fixed list (as 1-based die faces).
+type scriptedRNG struct {
+	values []int
+	pos    int
+}
+
+func (s *scriptedRNG) IntN(n int) int {
+	v := s.values[s.pos%len(s.values)]
+	s.pos++
+	return (v - 1) % n
+}
+
+func TestParseCanonicalForm(t *testing.T) {
+	cases := map[string]string{
+		"2d20kh1+5":   "2d20kh1+5",
+		"4d6dl1":      "4d6dl1",
+		"4d6d1":       "4d6dl1",
+		"1d8+1d6+3":   "1d8+1d6+3",
+		"d20 - 2":     "1d20-2",
+		"3d6!":        "3d6!",
+		"3d6!>5":      "3d6!>5",
+		"2d6r<2":      "2d6r<2",
+		"1d20ro1":     "1d20ro1",
+		"d%":          "1d100",
+		"2D20KL1 + 4": "2d20kl1+4",
+	}
+	for in, want := range cases {
+		e, err := Parse(in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", in, err)
+		}
+		if got := e.String(); got != want {
+			t.Errorf("Parse(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseRejectsInvalid(t *testing.T) {
+	for _, in := range []string{"", "d", "2d", "1d20++1", "1d20x", "0d6", "1d0", "101d6", "2d6kh3", "1d6!<6", "1d6r<6", "1d6!!"} {
+		if _, err := Parse(in); !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidExpression", in, err)
+		}
+	}
+}
+
+func TestRollKeepHighest(t *testing.T) {
+	res, err := Roll("2d20kh1+5", &scriptedRNG{values: []int{4, 17}})
+	if err != nil {
+		t.Fatalf("roll: %v", err)
+	}
+	if res.Total != 22 {
+		t.Fatalf("total = %d, want 22", res.Total)
+	}
+	dice := res.Terms[0].Dice
+	if !dice[0].Dropped || dice[1].Dropped {
+		t.Fatalf("expected the 4 to be dropped, got %+v", dice)
+	}
+}
+
+func TestRollDropLowest(t *testing.T) {
+	res, err := Roll("4d6dl1", &scriptedRNG{values: []int{3, 1, 6, 5}})
+	if err != nil {
+		t.Fatalf("roll: %v", err)
+	}
+	if res.Total != 14 {
+		t.Fatalf("total = %d, want 14", res.Total)
+	}
+}
+
+func TestRollExplodingAndReroll(t *testing.T) {
+	res, err := Roll("2d6!", &scriptedRNG{values: []int{6, 6, 2, 3}})
+	if err != nil {
+		t.Fatalf("roll: %v", err)
+	}
+	if res.Total != 17 || len(res.Terms[0].Dice) != 4 {
+		t.Fatalf("unexpected explode result: %+v", res)
+	}
+
+	res, err = Roll("1d6r1", &scriptedRNG{values: []int{1, 1, 4}})
+	if err != nil {
+		t.Fatalf("roll: %v", err)
+	}
+	if res.Total != 4 || len(res.Terms[0].Dice) != 3 || !res.Terms[0].Dice[0].Rerolled {
+		t.Fatalf("unexpected reroll result: %+v", res)
+	}
+
+	res, err = Roll("1d6ro1", &scriptedRNG{values: []int{1, 1}})
+	if err != nil {
+		t.Fatalf("roll: %v", err)
+	}
+	if res.Total != 1 {
+		t.Fatalf("reroll once should keep the second 1, got %d", res.Total)
+	}
+}
+
+func TestRollWithModeAdvantage(t *testing.T) {
+	res, err := RollWithMode("1d20+3", ModeDisadvantage, &scriptedRNG{values: []int{15, 8}})
+	if err != nil {
+		t.Fatalf("roll: %v", err)
+	}
+	if res.Expression != "2d20kl1+3" || res.Total != 11 {
+		t.Fatalf("unexpected disadvantage result: %s total %d", res.Expression, res.Total)
+	}
+	if got := res.String(); got != "2d20kl1 [15d, 8] + 3 = 11" {
+		t.Fatalf("String() = %q", got)
+	}
+}
+
+func TestSeededRNGIsDeterministic(t *testing.T) {
+	a, _ := Roll("10d20", NewSeededRNG(42))
+	b, _ := Roll("10d20", NewSeededRNG(42))
+	if a.String() != b.String() {
+		t.Fatalf("seeded rolls differ: %s vs %s", a, b)
+	}
+}
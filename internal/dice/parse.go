@@ -0,0 +1,342 @@
+package dice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KeepKind selects which dice of a term contribute to its subtotal.
+type KeepKind string
+
+const (
+	KeepHighest KeepKind = "kh"
+	KeepLowest  KeepKind = "kl"
+	DropHighest KeepKind = "dh"
+	DropLowest  KeepKind = "dl"
+)
+
+// Keep is a keep/drop modifier such as kh1 or dl1.
+type Keep struct {
+	Kind  KeepKind
+	Count int
+}
+
+// Compare is a target used by explode and reroll modifiers.
+type Compare struct {
+	Op    byte // '=', '<' or '>'
+	Value int
+}
+
+func (c Compare) matches(v int) bool {
+	switch c.Op {
+	case '<':
+		return v <= c.Value
+	case '>':
+		return v >= c.Value
+	default:
+		return v == c.Value
+	}
+}
+
+func (c Compare) String() string {
+	if c.Op == '=' {
+		return strconv.Itoa(c.Value)
+	}
+	return string(c.Op) + strconv.Itoa(c.Value)
+}
+
+// Term is either a dice term (Sides > 0) or an integer constant.
+type Term struct {
+	Sign       int
+	Count      int
+	Sides      int
+	Constant   int
+	Keep       *Keep
+	Explode    *Compare
+	Reroll     *Compare
+	RerollOnce bool
+}
+
+// String renders the term without its sign.
+func (t Term) String() string {
+	if t.Sides == 0 {
+		return strconv.Itoa(t.Constant)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%dd%d", t.Count, t.Sides)
+	if t.Reroll != nil {
+		b.WriteString("r")
+		if t.RerollOnce {
+			b.WriteString("o")
+		}
+		b.WriteString(t.Reroll.String())
+	}
+	if t.Explode != nil {
+		b.WriteString("!")
+		if t.Explode.Op != '=' || t.Explode.Value != t.Sides {
+			if t.Explode.Op == '=' {
+				b.WriteString("=")
+			}
+			b.WriteString(t.Explode.String())
+		}
+	}
+	if t.Keep != nil {
+		fmt.Fprintf(&b, "%s%d", t.Keep.Kind, t.Keep.Count)
+	}
+	return b.String()
+}
+
+// Expression is a parsed sequence of signed terms.
+type Expression struct {
+	Terms []Term
+}
+
+// String renders the expression in canonical notation.
+func (e *Expression) String() string {
+	var b strings.Builder
+	for i, t := range e.Terms {
+		switch {
+		case t.Sign < 0:
+			b.WriteString("-")
+		case i > 0:
+			b.WriteString("+")
+		}
+		b.WriteString(t.String())
+	}
+	return b.String()
+}
+
+func (e *Expression) applyMode(mode Mode) {
+	if mode == ModeNormal {
+		return
+	}
+	for i := range e.Terms {
+		t := &e.Terms[i]
+		if t.Sides != 20 || t.Count != 1 || t.Keep != nil {
+			continue
+		}
+		t.Count = 2
+		if mode == ModeAdvantage {
+			t.Keep = &Keep{Kind: KeepHighest, Count: 1}
+		} else {
+			t.Keep = &Keep{Kind: KeepLowest, Count: 1}
+		}
+	}
+}
+
+// Parse parses a dice expression without rolling it.
+func Parse(expr string) (*Expression, error) {
+	p := &parser{src: strings.ToLower(strings.Join(strings.Fields(expr), ""))}
+	if p.src == "" {
+		return nil, fmt.Errorf("%w: expression is empty", ErrInvalidExpression)
+	}
+
+	e := &Expression{}
+	totalDice := 0
+	for !p.done() {
+		sign := 1
+		switch p.peek() {
+		case '+':
+			p.pos++
+		case '-':
+			sign = -1
+			p.pos++
+		default:
+			if len(e.Terms) > 0 {
+				return nil, p.errorf("expected + or -")
+			}
+		}
+
+		t, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		t.Sign = sign
+		totalDice += t.Count
+		if totalDice > MaxDice {
+			return nil, fmt.Errorf("%w: at most %d dice per roll", ErrInvalidExpression, MaxDice)
+		}
+		e.Terms = append(e.Terms, t)
+		if len(e.Terms) > MaxTerms {
+			return nil, fmt.Errorf("%w: at most %d terms per roll", ErrInvalidExpression, MaxTerms)
+		}
+	}
+
+	return e, nil
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at position %d", ErrInvalidExpression, fmt.Sprintf(format, args...), p.pos+1)
+}
+
+func (p *parser) number() (int, bool) {
+	start := p.pos
+	for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, false
+	}
+	n, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil || n > 1_000_000 {
+		p.pos = start
+		return 0, false
+	}
+	return n, true
+}
+
+func (p *parser) term() (Term, error) {
+	count, hasCount := p.number()
+	if p.peek() != 'd' {
+		if !hasCount {
+			return Term{}, p.errorf("expected a number or dice")
+		}
+		return Term{Constant: count}, nil
+	}
+	p.pos++
+
+	if !hasCount {
+		count = 1
+	}
+	if count < 1 || count > MaxDice {
+		return Term{}, p.errorf("dice count must be between 1 and %d", MaxDice)
+	}
+
+	var sides int
+	if p.peek() == '%' {
+		p.pos++
+		sides = 100
+	} else {
+		n, ok := p.number()
+		if !ok {
+			return Term{}, p.errorf("expected number of sides")
+		}
+		sides = n
+	}
+	if sides < 1 || sides > MaxSides {
+		return Term{}, p.errorf("dice must have between 1 and %d sides", MaxSides)
+	}
+
+	t := Term{Count: count, Sides: sides}
+	for !p.done() && p.peek() != '+' && p.peek() != '-' {
+		if err := p.modifier(&t); err != nil {
+			return Term{}, err
+		}
+	}
+	return t, nil
+}
+
+func (p *parser) modifier(t *Term) error {
+	switch p.peek() {
+	case '!':
+		if t.Explode != nil {
+			return p.errorf("duplicate explode modifier")
+		}
+		p.pos++
+		c, ok := p.compare()
+		if !ok {
+			c = Compare{Op: '=', Value: t.Sides}
+		}
+		if c.matches(1) && c.matches(t.Sides) {
+			return p.errorf("explode target would always match")
+		}
+		t.Explode = &c
+		return nil
+
+	case 'r':
+		if t.Reroll != nil {
+			return p.errorf("duplicate reroll modifier")
+		}
+		p.pos++
+		if p.peek() == 'o' {
+			p.pos++
+			t.RerollOnce = true
+		}
+		c, ok := p.compare()
+		if !ok {
+			return p.errorf("reroll needs a target")
+		}
+		if !t.RerollOnce && matchesAll(c, t.Sides) {
+			return p.errorf("reroll target would always match")
+		}
+		t.Reroll = &c
+		return nil
+
+	case 'k', 'd':
+		if t.Keep != nil {
+			return p.errorf("duplicate keep/drop modifier")
+		}
+		kind := p.peek()
+		p.pos++
+		var k KeepKind
+		switch {
+		case kind == 'k' && p.peek() == 'l':
+			p.pos++
+			k = KeepLowest
+		case kind == 'k':
+			if p.peek() == 'h' {
+				p.pos++
+			}
+			k = KeepHighest
+		case p.peek() == 'h':
+			p.pos++
+			k = DropHighest
+		default:
+			if p.peek() == 'l' {
+				p.pos++
+			}
+			k = DropLowest
+		}
+		n, ok := p.number()
+		if !ok {
+			n = 1
+		}
+		if n < 1 || n > t.Count {
+			return p.errorf("keep/drop count must be between 1 and %d", t.Count)
+		}
+		t.Keep = &Keep{Kind: k, Count: n}
+		return nil
+	}
+
+	return p.errorf("unexpected %q", p.peek())
+}
+
+func (p *parser) compare() (Compare, bool) {
+	start := p.pos
+	op := byte('=')
+	switch p.peek() {
+	case '<', '>', '=':
+		op = p.peek()
+		p.pos++
+	}
+	n, ok := p.number()
+	if !ok {
+		p.pos = start
+		return Compare{}, false
+	}
+	return Compare{Op: op, Value: n}, true
+}
+
+func matchesAll(c Compare, sides int) bool {
+	for v := 1; v <= sides; v++ {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	return true
+}
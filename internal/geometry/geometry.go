@@ -0,0 +1,302 @@
+// Package geometry provides the polygon operations behind fog of war and line of sight.
+// Coordinates are in grid squares, so a token at (2, 3) covers the square from (2, 3) to
+// (3, 4).
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// Point is a position on a map.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Ring is a closed polygon; its last point joins its first.
+type Ring []Point
+
+// Region is an area bounded by rings under the even-odd rule: a point is inside when it
+// is inside an odd number of rings, so a ring inside another one is a hole.
+type Region []Ring
+
+// precision is the grid coordinates are snapped to, which keeps points that are computed
+// twice from drifting apart.
+const precision = 1e6
+
+// offset is how far from an edge its sides are sampled when deciding whether the edge
+// bounds a combined region.
+const offset = 1e-5
+
+// Contains reports whether p is inside the region.
+func (r Region) Contains(p Point) bool {
+	inside := false
+	for _, ring := range r {
+		if ring.Contains(p) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Contains reports whether p is inside the ring by the even-odd rule.
+func (r Ring) Contains(p Point) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Area returns the ring's unsigned area.
+func (r Ring) Area() float64 {
+	sum := 0.0
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		sum += r[j].X*r[i].Y - r[i].X*r[j].Y
+	}
+	return math.Abs(sum) / 2
+}
+
+// Valid reports whether the ring has at least three points, finite coordinates and an
+// area.
+func (r Ring) Valid() bool {
+	if len(r) < 3 {
+		return false
+	}
+	for _, p := range r {
+		if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+			return false
+		}
+	}
+	return r.Area() > 0
+}
+
+// Union returns the area inside either region.
+func Union(a, b Region) Region {
+	return combine(a, b, func(inA, inB bool) bool { return inA || inB })
+}
+
+// Difference returns the area inside a but not b.
+func Difference(a, b Region) Region {
+	return combine(a, b, func(inA, inB bool) bool { return inA && !inB })
+}
+
+// Intersection returns the area inside both regions.
+func Intersection(a, b Region) Region {
+	return combine(a, b, func(inA, inB bool) bool { return inA && inB })
+}
+
+type segment struct {
+	a, b Point
+}
+
+// combine builds the region whose points satisfy keep. Every edge of both regions is
+// split where it meets another, and each piece is kept, facing so the region is on its
+// left, when keep holds on exactly one of its sides. Chaining the kept pieces gives rings
+// that bound the result under the even-odd rule whichever way they are joined.
+func combine(a, b Region, keep func(inA, inB bool) bool) Region {
+	pieces := split(append(edges(a), edges(b)...))
+
+	seen := make(map[[4]int64]bool, len(pieces))
+	var boundary []segment
+	for _, s := range pieces {
+		k := segmentKey(s)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+		length := math.Hypot(dx, dy)
+		mid := Point{(s.a.X + s.b.X) / 2, (s.a.Y + s.b.Y) / 2}
+		nx, ny := -dy/length*offset, dx/length*offset
+		left := Point{mid.X + nx, mid.Y + ny}
+		right := Point{mid.X - nx, mid.Y - ny}
+		inLeft := keep(a.Contains(left), b.Contains(left))
+		inRight := keep(a.Contains(right), b.Contains(right))
+		switch {
+		case inLeft && !inRight:
+			boundary = append(boundary, s)
+		case inRight && !inLeft:
+			boundary = append(boundary, segment{s.b, s.a})
+		}
+	}
+	return chain(boundary)
+}
+
+func edges(r Region) []segment {
+	var out []segment
+	for _, ring := range r {
+		for i := range ring {
+			s := segment{snap(ring[i]), snap(ring[(i+1)%len(ring)])}
+			if s.a != s.b {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// split cuts the segments wherever they cross, touch or overlap one another.
+func split(segs []segment) []segment {
+	cuts := make([][]float64, len(segs))
+	for i := range segs {
+		for j := i + 1; j < len(segs); j++ {
+			ti, tj := intersections(segs[i], segs[j])
+			cuts[i] = append(cuts[i], ti...)
+			cuts[j] = append(cuts[j], tj...)
+		}
+	}
+
+	var out []segment
+	for i, s := range segs {
+		ts := append([]float64{0, 1}, cuts[i]...)
+		sort.Float64s(ts)
+		prev := s.a
+		for _, t := range ts[1:] {
+			p := s.b
+			if t < 1 {
+				p = snap(Point{s.a.X + (s.b.X-s.a.X)*t, s.a.Y + (s.b.Y-s.a.Y)*t})
+			}
+			if p != prev {
+				out = append(out, segment{prev, p})
+				prev = p
+			}
+		}
+	}
+	return out
+}
+
+// intersections returns where along each segment, from 0 at its start to 1 at its end,
+// the other segment meets it, leaving out the ends themselves.
+func intersections(s1, s2 segment) (t1, t2 []float64) {
+	r := sub(s1.b, s1.a)
+	s := sub(s2.b, s2.a)
+	qp := sub(s2.a, s1.a)
+	rxs := cross(r, s)
+	lr, ls := math.Hypot(r.X, r.Y), math.Hypot(s.X, s.Y)
+
+	if math.Abs(rxs) <= 1e-12*lr*ls {
+		if math.Abs(cross(qp, r)) > 1e-9*lr {
+			return nil, nil
+		}
+		// Collinear: each segment is cut where the other one's ends lie on it.
+		rr, ss := dot(r, r), dot(s, s)
+		for _, t := range []float64{dot(qp, r) / rr, dot(sub(s2.b, s1.a), r) / rr} {
+			if inside(t) {
+				t1 = append(t1, t)
+			}
+		}
+		for _, u := range []float64{dot(sub(s1.a, s2.a), s) / ss, dot(sub(s1.b, s2.a), s) / ss} {
+			if inside(u) {
+				t2 = append(t2, u)
+			}
+		}
+		return t1, t2
+	}
+
+	t := cross(qp, s) / rxs
+	u := cross(qp, r) / rxs
+	const tol = 1e-12
+	if t < -tol || t > 1+tol || u < -tol || u > 1+tol {
+		return nil, nil
+	}
+	if inside(t) {
+		t1 = append(t1, t)
+	}
+	if inside(u) {
+		t2 = append(t2, u)
+	}
+	return t1, t2
+}
+
+func inside(t float64) bool {
+	return t > 1e-9 && t < 1-1e-9
+}
+
+// chain joins directed boundary pieces end to start into rings.
+func chain(boundary []segment) Region {
+	from := make(map[[2]int64][]int, len(boundary))
+	for i, s := range boundary {
+		k := pointKey(s.a)
+		from[k] = append(from[k], i)
+	}
+	used := make([]bool, len(boundary))
+
+	var region Region
+	for i := range boundary {
+		if used[i] {
+			continue
+		}
+		start := pointKey(boundary[i].a)
+		ring := Ring{boundary[i].a}
+		used[i] = true
+		end := boundary[i].b
+		closed := false
+		for {
+			k := pointKey(end)
+			if k == start {
+				closed = true
+				break
+			}
+			next := -1
+			for _, j := range from[k] {
+				if !used[j] {
+					next = j
+					break
+				}
+			}
+			if next < 0 {
+				break
+			}
+			used[next] = true
+			ring = append(ring, end)
+			end = boundary[next].b
+		}
+		if ring = simplify(ring); closed && ring.Valid() {
+			region = append(region, ring)
+		}
+	}
+	return region
+}
+
+// simplify drops points that lie on the straight line between their neighbours.
+func simplify(r Ring) Ring {
+	for changed := true; changed && len(r) >= 3; {
+		changed = false
+		for i := 0; i < len(r); i++ {
+			prev, next := r[(i+len(r)-1)%len(r)], r[(i+1)%len(r)]
+			a, b := sub(r[i], prev), sub(next, r[i])
+			if math.Abs(cross(a, b)) <= 1e-12*math.Hypot(a.X, a.Y)*math.Hypot(b.X, b.Y) && dot(a, b) > 0 {
+				r = append(r[:i:i], r[i+1:]...)
+				changed = true
+				break
+			}
+		}
+	}
+	return r
+}
+
+func snap(p Point) Point {
+	return Point{math.Round(p.X*precision) / precision, math.Round(p.Y*precision) / precision}
+}
+
+func pointKey(p Point) [2]int64 {
+	return [2]int64{int64(math.Round(p.X * precision)), int64(math.Round(p.Y * precision))}
+}
+
+func segmentKey(s segment) [4]int64 {
+	a, b := pointKey(s.a), pointKey(s.b)
+	if b[0] < a[0] || (b[0] == a[0] && b[1] < a[1]) {
+		a, b = b, a
+	}
+	return [4]int64{a[0], a[1], b[0], b[1]}
+}
+
+func sub(a, b Point) Point     { return Point{a.X - b.X, a.Y - b.Y} }
+func dot(a, b Point) float64   { return a.X*b.X + a.Y*b.Y }
+func cross(a, b Point) float64 { return a.X*b.Y - a.Y*b.X }
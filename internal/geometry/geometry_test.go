@@ -0,0 +1,97 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func square(x, y, size float64) Ring {
+	return Ring{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}}
+}
+
+// area sums the signed areas of the rings, which combine orients with the region on
+// their left so holes count against it.
+func area(r Region) float64 {
+	total := 0.0
+	for _, ring := range r {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			total += ring[j].X*ring[i].Y - ring[i].X*ring[j].Y
+		}
+	}
+	return total / 2
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestUnionOfOverlappingSquares(t *testing.T) {
+	got := Union(Region{square(0, 0, 2)}, Region{square(1, 1, 2)})
+	if len(got) != 1 || len(got[0]) != 8 || !near(area(got), 7) {
+		t.Fatalf("union = %v (area %v)", got, area(got))
+	}
+	for _, p := range []Point{{0.5, 0.5}, {1.5, 1.5}, {2.5, 2.5}} {
+		if !got.Contains(p) {
+			t.Errorf("union should contain %v", p)
+		}
+	}
+	if got.Contains(Point{2.5, 0.5}) {
+		t.Error("union should not contain (2.5, 0.5)")
+	}
+}
+
+func TestUnionOfTouchingSquaresMerges(t *testing.T) {
+	got := Union(Region{square(0, 0, 1)}, Region{square(1, 0, 1)})
+	if len(got) != 1 || len(got[0]) != 4 || !near(area(got), 2) {
+		t.Fatalf("union = %v", got)
+	}
+}
+
+func TestUnionOfSeparateSquaresKeepsBoth(t *testing.T) {
+	got := Union(Region{square(0, 0, 1)}, Region{square(5, 5, 1)})
+	if len(got) != 2 || !near(area(got), 2) {
+		t.Fatalf("union = %v", got)
+	}
+}
+
+func TestDifferenceCutsHole(t *testing.T) {
+	got := Difference(Region{square(0, 0, 4)}, Region{square(1, 1, 2)})
+	if len(got) != 2 || !near(area(got), 12) {
+		t.Fatalf("difference = %v (area %v)", got, area(got))
+	}
+	if got.Contains(Point{2, 2}) || !got.Contains(Point{0.5, 2}) {
+		t.Fatalf("hole not cut: %v", got)
+	}
+
+	// Filling the hole again leaves a single square.
+	filled := Union(got, Region{square(1, 1, 2)})
+	if len(filled) != 1 || !near(area(filled), 16) {
+		t.Fatalf("filled = %v", filled)
+	}
+}
+
+func TestDifferenceOfEverythingIsEmpty(t *testing.T) {
+	if got := Difference(Region{square(1, 1, 1)}, Region{square(0, 0, 3)}); len(got) != 0 {
+		t.Fatalf("difference = %v", got)
+	}
+}
+
+func TestIntersectionOfTriangleAndSquare(t *testing.T) {
+	triangle := Ring{{0, 0}, {4, 0}, {0, 4}}
+	got := Intersection(Region{triangle}, Region{square(0, 0, 2)})
+	if !near(area(got), 4) {
+		t.Fatalf("intersection = %v (area %v)", got, area(got))
+	}
+}
+
+func TestRingValid(t *testing.T) {
+	if (Ring{{0, 0}, {1, 1}}).Valid() || (Ring{{0, 0}, {1, 1}, {2, 2}}).Valid() {
+		t.Fatal("degenerate rings should not be valid")
+	}
+	if (Ring{{0, 0}, {1, 0}, {math.NaN(), 1}}).Valid() {
+		t.Fatal("NaN ring should not be valid")
+	}
+	if !square(0, 0, 1).Valid() {
+		t.Fatal("square should be valid")
+	}
+}
@@ -0,0 +1,100 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// Segment is a straight wall between two points.
+type Segment struct {
+	A Point `json:"a"`
+	B Point `json:"b"`
+}
+
+// Visibility returns the polygon that can be seen from origin when walls block sight,
+// bounded by a square reaching radius in each direction. Rays are cast towards every wall
+// end, and just either side of it, and stop at the nearest wall they hit.
+func Visibility(origin Point, walls []Segment, radius float64) Ring {
+	box := Rect(Point{origin.X - radius, origin.Y - radius}, Point{origin.X + radius, origin.Y + radius})
+	blockers := make([]Segment, 0, len(walls)+len(box))
+	for i := range box {
+		blockers = append(blockers, Segment{box[i], box[(i+1)%len(box)]})
+	}
+	for _, w := range walls {
+		if w.A != w.B {
+			blockers = append(blockers, w)
+		}
+	}
+
+	const nudge = 1e-5
+	angles := make([]float64, 0, len(blockers)*6)
+	for _, w := range blockers {
+		for _, p := range []Point{w.A, w.B} {
+			a := math.Atan2(p.Y-origin.Y, p.X-origin.X)
+			angles = append(angles, a-nudge, a, a+nudge)
+		}
+	}
+	sort.Float64s(angles)
+
+	ring := make(Ring, 0, len(angles))
+	for _, a := range angles {
+		dir := Point{math.Cos(a), math.Sin(a)}
+		nearest := math.Inf(1)
+		for _, w := range blockers {
+			if t, ok := raySegment(origin, dir, w); ok && t < nearest {
+				nearest = t
+			}
+		}
+		if math.IsInf(nearest, 1) {
+			continue
+		}
+		p := snap(Point{origin.X + dir.X*nearest, origin.Y + dir.Y*nearest})
+		if len(ring) == 0 || ring[len(ring)-1] != p {
+			ring = append(ring, p)
+		}
+	}
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		ring = ring[:len(ring)-1]
+	}
+	return simplify(ring)
+}
+
+// Sight returns the area seen from origin within radius, as a circle cut back by walls.
+func Sight(origin Point, walls []Segment, radius float64) Region {
+	if radius <= 0 {
+		return nil
+	}
+	return Intersection(Region{Visibility(origin, walls, radius)}, Region{Circle(origin, radius, 48)})
+}
+
+// Rect returns the rectangle with opposite corners min and max.
+func Rect(min, max Point) Ring {
+	return Ring{min, {max.X, min.Y}, max, {min.X, max.Y}}
+}
+
+// Circle approximates a circle with a regular polygon whose corners lie on it.
+func Circle(center Point, radius float64, sides int) Ring {
+	ring := make(Ring, sides)
+	for i := range ring {
+		a := 2 * math.Pi * float64(i) / float64(sides)
+		ring[i] = Point{center.X + radius*math.Cos(a), center.Y + radius*math.Sin(a)}
+	}
+	return ring
+}
+
+// raySegment returns how far along the ray from origin in direction dir it meets the
+// segment.
+func raySegment(origin, dir Point, s Segment) (float64, bool) {
+	e := sub(s.B, s.A)
+	denom := cross(dir, e)
+	if math.Abs(denom) < 1e-12 {
+		return 0, false
+	}
+	qp := sub(s.A, origin)
+	t := cross(qp, e) / denom
+	u := cross(qp, dir) / denom
+	if t < 1e-9 || u < -1e-9 || u > 1+1e-9 {
+		return 0, false
+	}
+	return t, true
+}
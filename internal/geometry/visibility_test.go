@@ -0,0 +1,50 @@
+package geometry
+
+import "testing"
+
+func TestVisibilityWithoutWallsIsTheBox(t *testing.T) {
+	got := Visibility(Point{5, 5}, nil, 3)
+	if len(got) != 4 || !near(got.Area(), 36) {
+		t.Fatalf("visibility = %v (area %v)", got, got.Area())
+	}
+}
+
+func TestVisibilityWallCastsShadow(t *testing.T) {
+	// A wall across x = 2 from y = -1 to y = 1 hides the points right behind it.
+	wall := Segment{Point{2, -1}, Point{2, 1}}
+	got := Visibility(Point{0, 0}, []Segment{wall}, 10)
+	if !got.Contains(Point{1, 0}) {
+		t.Error("point in front of the wall should be visible")
+	}
+	if got.Contains(Point{4, 0}) || got.Contains(Point{8, 1}) {
+		t.Error("points behind the wall should be hidden")
+	}
+	if !got.Contains(Point{4, 5}) || !got.Contains(Point{-5, 0}) {
+		t.Error("points around the wall should be visible")
+	}
+}
+
+func TestVisibilityInsideRoom(t *testing.T) {
+	room := Rect(Point{0, 0}, Point{4, 4})
+	var walls []Segment
+	for i := range room {
+		walls = append(walls, Segment{room[i], room[(i+1)%len(room)]})
+	}
+	got := Visibility(Point{1, 1}, walls, 20)
+	if !near(got.Area(), 16) {
+		t.Fatalf("room visibility = %v (area %v)", got, got.Area())
+	}
+	if got.Contains(Point{5, 1}) {
+		t.Error("point outside the room should be hidden")
+	}
+}
+
+func TestSightIsLimitedByRadius(t *testing.T) {
+	got := Sight(Point{0, 0}, nil, 2)
+	if !got.Contains(Point{1.5, 0}) || got.Contains(Point{1.8, 1.8}) {
+		t.Fatalf("sight = %v", got)
+	}
+	if Sight(Point{0, 0}, nil, 0) != nil {
+		t.Fatal("no radius should see nothing")
+	}
+}
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/geometry"
+)
 
 // Campaign visibility options
 const (
@@ -17,6 +22,13 @@ const (
 	CampaignStatusArchived   = "archived"
 )
 
+// Campaign roll visibility scopes
+const (
+	RollVisibilityPublic = "public"
+	RollVisibilityGMOnly = "gm-only"
+	RollVisibilitySelf   = "self"
+)
+
 // Campaign represents a shared world for multiple characters and scenes.
 type Campaign struct {
 	ID            int64     `json:"id"`
@@ -103,10 +115,61 @@ type Map struct {
 	WidthPx      *int      `json:"widthPx"`
 	HeightPx     *int      `json:"heightPx"`
 	LightingMode string    `json:"lightingMode"`
-	FogState     string    `json:"fogState"`
+	FogState     Fog       `json:"fogState"`
 	CreatedAt    time.Time `json:"createdAt"`
 }
 
+// Fog modes
+const (
+	FogModeGrid    = "grid"
+	FogModePolygon = "polygon"
+)
+
+// Fog is a map's fog of war. A map without a mode has no fog. Otherwise players only see
+// the revealed grid cells, or the revealed area in polygon mode, and the tokens in them.
+type Fog struct {
+	Mode     string          `json:"mode,omitempty"`
+	Cells    []FogCell       `json:"cells,omitempty"`
+	Revealed geometry.Region `json:"revealed,omitempty"`
+}
+
+// Lighting modes. With basic lighting players only see what their tokens can see.
+const (
+	LightingNone  = "none"
+	LightingBasic = "basic"
+)
+
+// Wall kinds and door states
+const (
+	WallKindWall = "wall"
+	WallKindDoor = "door"
+	DoorOpen     = "open"
+	DoorClosed   = "closed"
+	DoorLocked   = "locked"
+)
+
+// Wall is a wall or door on a map, from A to B in grid squares. Walls, and doors that are
+// closed or locked, block sight.
+type Wall struct {
+	ID    int64          `json:"id"`
+	MapID int64          `json:"mapId"`
+	Kind  string         `json:"kind"`
+	A     geometry.Point `json:"a"`
+	B     geometry.Point `json:"b"`
+	State string         `json:"state,omitempty"`
+}
+
+// BlocksSight reports whether the wall stops tokens seeing past it.
+func (w Wall) BlocksSight() bool {
+	return w.Kind == WallKindWall || w.State != DoorOpen
+}
+
+// FogCell is a grid square, counted from the map's top-left corner.
+type FogCell struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
 // Token represents a movable piece on the map.
 type Token struct {
 	ID          int64     `json:"id"`
@@ -124,6 +187,20 @@ type Token struct {
 	Layer       string    `json:"layer"`
 	CreatedBy   *int64    `json:"createdBy"`
 	CreatedAt   time.Time `json:"createdAt"`
+	// VisionFt and DarkvisionFt are how far the token sees on maps with basic lighting.
+	VisionFt     int `json:"visionFt"`
+	DarkvisionFt int `json:"darkvisionFt"`
+	// Conditions are those of the linked character, so the map shows who is prone or poisoned.
+	Conditions []Condition `json:"conditions"`
+}
+
+// Condition is a condition carried by a token's character.
+type Condition struct {
+	Name         string `json:"name"`
+	Level        int    `json:"level,omitempty"`
+	Source       string `json:"source,omitempty"`
+	Duration     *int64 `json:"duration,omitempty"`
+	DurationUnit string `json:"durationUnit,omitempty"`
 }
 
 // CreateCampaignRequest is the payload for creating a campaign.
@@ -150,6 +227,32 @@ type CampaignCharacterSummary struct {
 	OwnerUsername  string `json:"ownerUsername"`
 }
 
+// CampaignRoll is a server-side dice roll recorded in a campaign's roll log.
+type CampaignRoll struct {
+	ID          int64           `json:"id"`
+	CampaignID  int64           `json:"campaignId"`
+	UserID      int64           `json:"userId"`
+	Username    string          `json:"username"`
+	CharacterID *int64          `json:"characterId,omitempty"`
+	Label       string          `json:"label"`
+	Expression  string          `json:"expression"`
+	Result      json.RawMessage `json:"result"`
+	Total       int             `json:"total"`
+	Visibility  string          `json:"visibility"`
+	PrevHash    string          `json:"prevHash"`
+	Hash        string          `json:"hash"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// CampaignRollAudit reports whether a campaign's roll chain is intact.
+type CampaignRollAudit struct {
+	CampaignID int64  `json:"campaignId"`
+	Count      int    `json:"count"`
+	Valid      bool   `json:"valid"`
+	BrokenAtID *int64 `json:"brokenAtId,omitempty"`
+	LatestHash string `json:"latestHash"`
+}
+
 // CampaignHandout represents an uploaded file or note for a campaign.
 type CampaignHandout struct {
 	ID          int64     `json:"id"`
@@ -172,6 +275,9 @@ type CampaignDetail struct {
 type MapWithTokens struct {
 	Map
 	Tokens []Token `json:"tokens"`
+	// Vision is what the player's tokens can see on a map with basic lighting; it is only
+	// sent to players.
+	Vision geometry.Region `json:"vision,omitempty"`
 }
 
 // SceneWithMaps groups maps for a scene.
@@ -0,0 +1,194 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidPack is returned, with the reason appended, when a homebrew pack does not
+// match the pack schema.
+var ErrInvalidPack = errors.New("invalid homebrew pack")
+
+// Pack is a homebrew content pack. Uploading a pack again with the same name replaces it,
+// and needs a higher version.
+type Pack struct {
+	Name        string     `json:"name"`
+	Version     string     `json:"version"`
+	Description string     `json:"description,omitempty"`
+	Species     []Species  `json:"species,omitempty"`
+	Classes     []Class    `json:"classes,omitempty"`
+	Subclasses  []Subclass `json:"subclasses,omitempty"`
+	Items       []Item     `json:"items,omitempty"`
+	Spells      []Spell    `json:"spells,omitempty"`
+}
+
+// Values the pack schema accepts.
+var (
+	versionPattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)$`)
+	abilities      = []string{"strength", "dexterity", "constitution", "intelligence", "wisdom", "charisma"}
+	sizes          = []string{"Tiny", "Small", "Medium", "Large"}
+	hitDice        = []int{6, 8, 10, 12}
+	armorTypes     = []string{"light", "medium", "heavy", "shield"}
+	spellSchools   = []string{"abjuration", "conjuration", "divination", "enchantment", "evocation", "illusion", "necromancy", "transmutation"}
+)
+
+// ParsePack decodes a pack from JSON or YAML and checks it against the schema. Fields the
+// schema does not know are rejected so that typos are not silently dropped.
+func ParsePack(data []byte) (*Pack, error) {
+	// YAML is a superset of JSON, so both are read as YAML and then decoded strictly
+	// through JSON to share the struct definitions.
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPack, err)
+	}
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPack, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(normalized))
+	dec.DisallowUnknownFields()
+	var p Pack
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPack, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPack, err)
+	}
+	return &p, nil
+}
+
+// CompareVersions compares two pack versions of the form major.minor.patch, returning
+// -1, 0 or 1.
+func CompareVersions(a, b string) int {
+	am, bm := versionPattern.FindStringSubmatch(a), versionPattern.FindStringSubmatch(b)
+	for i := 1; i <= 3 && am != nil && bm != nil; i++ {
+		x, _ := strconv.Atoi(am[i])
+		y, _ := strconv.Atoi(bm[i])
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// validate normalises the pack's names and checks every option. Options may not share a
+// name with each other or with the SRD, and subclasses must extend a known class.
+func (p *Pack) validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	if !versionPattern.MatchString(p.Version) {
+		return errors.New("version must look like 1.0.0")
+	}
+	if len(p.Species)+len(p.Classes)+len(p.Subclasses)+len(p.Items)+len(p.Spells) == 0 {
+		return errors.New("pack has no content")
+	}
+
+	seen := map[string]bool{}
+	name := func(kind string, n *string, taken func(string) bool) error {
+		*n = strings.TrimSpace(*n)
+		key := kind + ":" + strings.ToLower(*n)
+		switch {
+		case *n == "":
+			return fmt.Errorf("%s needs a name", kind)
+		case seen[key] || taken(*n):
+			return fmt.Errorf("%s %q already exists", kind, *n)
+		}
+		seen[key] = true
+		return nil
+	}
+
+	for i := range p.Species {
+		s := &p.Species[i]
+		if err := name("species", &s.Name, func(n string) bool { _, ok := srd.LookupSpecies(n); return ok }); err != nil {
+			return err
+		}
+		if !slices.Contains(sizes, s.Size) || s.Speed <= 0 {
+			return fmt.Errorf("species %q needs a size of %s and a positive speed", s.Name, strings.Join(sizes, ", "))
+		}
+	}
+
+	for i := range p.Classes {
+		c := &p.Classes[i]
+		if err := name("class", &c.Name, func(n string) bool { _, ok := srd.LookupClass(n); return ok }); err != nil {
+			return err
+		}
+		if !slices.Contains(hitDice, c.HitDie) {
+			return fmt.Errorf("class %q needs a hit die of 6, 8, 10 or 12", c.Name)
+		}
+		if len(c.SavingThrows) != 2 || len(c.PrimaryAbilities) == 0 || c.Skills.Count < 0 {
+			return fmt.Errorf("class %q needs two saving throws, a primary ability and a skill count", c.Name)
+		}
+		for _, a := range append(append([]string{c.SpellcastingAbility}, c.SavingThrows...), c.PrimaryAbilities...) {
+			if a != "" && !slices.Contains(abilities, a) {
+				return fmt.Errorf("class %q: unknown ability %q", c.Name, a)
+			}
+		}
+		for _, a := range c.ArmorTraining {
+			if !slices.Contains(armorTypes, a) {
+				return fmt.Errorf("class %q: unknown armor %q", c.Name, a)
+			}
+		}
+		for _, lf := range c.Features {
+			if lf.Level < 1 || lf.Level > 20 || len(lf.Names) == 0 || slices.Contains(lf.Names, "") {
+				return fmt.Errorf("class %q: features need a level from 1 to 20 and names", c.Name)
+			}
+		}
+	}
+
+	for i := range p.Subclasses {
+		sc := &p.Subclasses[i]
+		sc.Class = strings.TrimSpace(sc.Class)
+		if err := name("subclass", &sc.Name, func(n string) bool { _, ok := srd.LookupSubclass(sc.Class, n); return ok }); err != nil {
+			return err
+		}
+		class, ok := srd.LookupClass(sc.Class)
+		if !ok {
+			class, ok = lookup(p.Classes, sc.Class, func(c Class) string { return c.Name })
+		}
+		if !ok {
+			return fmt.Errorf("subclass %q extends unknown class %q", sc.Name, sc.Class)
+		}
+		sc.Class = class.Name
+	}
+
+	for i := range p.Items {
+		item := &p.Items[i]
+		if err := name("item", &item.Name, func(string) bool { return false }); err != nil {
+			return err
+		}
+		if item.Weight < 0 || item.ValueCp < 0 {
+			return fmt.Errorf("item %q needs a non-negative weight and value", item.Name)
+		}
+		validArmor := item.ArmorClass == 0
+		if item.ArmorType != "" {
+			validArmor = slices.Contains(armorTypes, item.ArmorType) && item.ArmorClass > 0
+		}
+		if !validArmor {
+			return fmt.Errorf("item %q: armor needs a type of %s and a positive armor class", item.Name, strings.Join(armorTypes, ", "))
+		}
+	}
+
+	for i := range p.Spells {
+		spell := &p.Spells[i]
+		if err := name("spell", &spell.Name, func(string) bool { return false }); err != nil {
+			return err
+		}
+		if spell.Level < 0 || spell.Level > 9 || !slices.Contains(spellSchools, spell.School) {
+			return fmt.Errorf("spell %q needs a level from 0 to 9 and a school of magic", spell.Name)
+		}
+	}
+	return nil
+}
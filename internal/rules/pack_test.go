@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+)
+
+const testPack = `
+name: Wildlands
+version: 1.2.0
+species:
+  - name: Owlin
+    size: Medium
+    speed: 30
+    traits: [Darkvision, Flight, Silent Feathers]
+classes:
+  - name: Witch
+    hitDie: 6
+    primaryAbilities: [intelligence]
+    savingThrows: [intelligence, wisdom]
+    skills: {count: 2, from: [Arcana, Nature]}
+    spellcastingAbility: intelligence
+subclasses:
+  - {name: Coven of the Moon, class: witch, features: [Moon Hex]}
+  - {name: Path of the Storm, class: Barbarian, features: [Storm Aura]}
+spells:
+  - {name: Hex Bolt, level: 1, school: evocation}
+`
+
+func TestParsePack(t *testing.T) {
+	p, err := ParsePack([]byte(testPack))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if p.Subclasses[0].Class != "Witch" {
+		t.Fatalf("subclass class = %q", p.Subclasses[0].Class)
+	}
+
+	c := SRD().With(p)
+	if s, ok := c.LookupSpecies("owlin"); !ok || s.Source != "Wildlands" {
+		t.Fatalf("owlin = %+v, %v", s, ok)
+	}
+	if _, ok := c.LookupSubclass("Barbarian", "Path of the Storm"); !ok {
+		t.Fatal("expected the Path of the Storm")
+	}
+	if _, ok := SRD().LookupSpecies("Owlin"); ok {
+		t.Fatal("With modified the SRD")
+	}
+}
+
+func TestParsePackRejectsInvalid(t *testing.T) {
+	bad := map[string]string{
+		"not yaml":       "name: [",
+		"no version":     `{"name": "A", "species": [{"name": "X", "size": "Small", "speed": 30}]}`,
+		"empty":          `{"name": "A", "version": "1.0.0"}`,
+		"unknown field":  `{"name": "A", "version": "1.0.0", "species": [{"name": "X", "size": "Small", "speed": 30, "fly": 30}]}`,
+		"srd name":       `{"name": "A", "version": "1.0.0", "species": [{"name": "elf", "size": "Medium", "speed": 30}]}`,
+		"duplicate":      `{"name": "A", "version": "1.0.0", "spells": [{"name": "X", "level": 1, "school": "illusion"}, {"name": "x", "level": 2, "school": "illusion"}]}`,
+		"hit die":        `{"name": "A", "version": "1.0.0", "classes": [{"name": "X", "hitDie": 4, "primaryAbilities": ["wisdom"], "savingThrows": ["wisdom", "charisma"]}]}`,
+		"feature level":  `{"name": "A", "version": "1.0.0", "classes": [{"name": "X", "hitDie": 8, "primaryAbilities": ["wisdom"], "savingThrows": ["wisdom", "charisma"], "features": [{"level": 21, "names": ["Y"]}]}]}`,
+		"orphan":         `{"name": "A", "version": "1.0.0", "subclasses": [{"name": "X", "class": "Witch"}]}`,
+		"armor":          `{"name": "A", "version": "1.0.0", "items": [{"name": "X", "armorType": "cloth", "armorClass": 11}]}`,
+		"spell level":    `{"name": "A", "version": "1.0.0", "spells": [{"name": "X", "level": 10, "school": "illusion"}]}`,
+		"species speed":  `{"name": "A", "version": "1.0.0", "species": [{"name": "X", "size": "Medium"}]}`,
+		"unknown school": `{"name": "A", "version": "1.0.0", "spells": [{"name": "X", "level": 1, "school": "pyromancy"}]}`,
+	}
+	for name, data := range bad {
+		if _, err := ParsePack([]byte(data)); !errors.Is(err, ErrInvalidPack) {
+			t.Errorf("%s: expected ErrInvalidPack, got %v", name, err)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.10.0", "1.9.3", 1},
+		{"0.9.9", "1.0.0", -1},
+	}
+	for _, c := range cases {
+		if got := CompareVersions(c.a, c.b); got != c.want {
+			t.Errorf("CompareVersions(%s, %s) = %d", c.a, c.b, got)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Package rules holds the character creation options from the 2024 System Reference
+// Document: species, classes, subclasses and backgrounds, with the traits, proficiencies
+// and hit dice each one grants. Homebrew packs add their own options on top.
+//
+// The SRD data is embedded from JSON files in data/ and loaded once at start-up. Names
+// are looked up without regard to case.
+package rules
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//go:embed data/*.json
+var dataFS embed.FS
+
+// Species is a playable species (called race in older rules).
+type Species struct {
+	Name   string   `json:"name"`
+	Size   string   `json:"size"`
+	Speed  int      `json:"speed"`
+	Traits []string `json:"traits"`
+	Source string   `json:"source,omitempty"`
+}
+
+// SkillChoice is a number of skills to pick from a list. An empty list means any skill.
+type SkillChoice struct {
+	Count int      `json:"count"`
+	From  []string `json:"from"`
+}
+
+// LevelFeatures are the class features gained on reaching a level in a class.
+type LevelFeatures struct {
+	Level int      `json:"level"`
+	Names []string `json:"names"`
+}
+
+// Class is a character class, what it grants at 1st level and the features it gains as
+// it levels up.
+type Class struct {
+	Name                string          `json:"name"`
+	HitDie              int             `json:"hitDie"`
+	PrimaryAbilities    []string        `json:"primaryAbilities"`
+	SavingThrows        []string        `json:"savingThrows"`
+	Skills              SkillChoice     `json:"skills"`
+	ArmorTraining       []string        `json:"armorTraining"`
+	WeaponProficiencies []string        `json:"weaponProficiencies"`
+	SpellcastingAbility string          `json:"spellcastingAbility,omitempty"`
+	Features            []LevelFeatures `json:"features,omitempty"`
+	Source              string          `json:"source,omitempty"`
+}
+
+// FeaturesAt returns the class features gained on reaching level in the class.
+func (c Class) FeaturesAt(level int) []string {
+	var names []string
+	for _, lf := range c.Features {
+		if lf.Level == level {
+			names = append(names, lf.Names...)
+		}
+	}
+	return names
+}
+
+// Subclass is a specialisation of a class, such as a Barbarian's Path of the Berserker.
+type Subclass struct {
+	Name     string   `json:"name"`
+	Class    string   `json:"class"`
+	Features []string `json:"features"`
+	Source   string   `json:"source,omitempty"`
+}
+
+// Background is a character background. Its ability scores are the three that its +2/+1
+// or +1/+1/+1 increases can go to.
+type Background struct {
+	Name               string   `json:"name"`
+	AbilityScores      []string `json:"abilityScores"`
+	SkillProficiencies []string `json:"skillProficiencies"`
+	ToolProficiency    string   `json:"toolProficiency"`
+	Feat               string   `json:"feat"`
+	Source             string   `json:"source,omitempty"`
+}
+
+// Item is a piece of equipment or magic item. Weight is in pounds and value in copper
+// pieces; armor has an armor type and base armor class as on inventory items.
+type Item struct {
+	Name        string  `json:"name"`
+	Weight      float64 `json:"weight"`
+	ValueCp     int64   `json:"valueCp"`
+	ArmorType   string  `json:"armorType,omitempty"`
+	ArmorClass  int     `json:"armorClass,omitempty"`
+	Attunement  bool    `json:"attunement,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Source      string  `json:"source,omitempty"`
+}
+
+// Spell is a spell with the classes that can learn it. Level 0 is a cantrip.
+type Spell struct {
+	Name        string   `json:"name"`
+	Level       int      `json:"level"`
+	School      string   `json:"school"`
+	CastingTime string   `json:"castingTime,omitempty"`
+	Range       string   `json:"range,omitempty"`
+	Duration    string   `json:"duration,omitempty"`
+	Classes     []string `json:"classes,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Source      string   `json:"source,omitempty"`
+}
+
+// Catalog is a set of options characters can be created with. Source names the homebrew
+// pack an option came from and is empty for the SRD.
+type Catalog struct {
+	Species     []Species    `json:"species"`
+	Classes     []Class      `json:"classes"`
+	Subclasses  []Subclass   `json:"subclasses"`
+	Backgrounds []Background `json:"backgrounds"`
+	Items       []Item       `json:"items"`
+	Spells      []Spell      `json:"spells"`
+}
+
+var srd = &Catalog{
+	Species:     mustLoad[Species]("data/species.json"),
+	Classes:     mustLoad[Class]("data/classes.json"),
+	Subclasses:  mustLoad[Subclass]("data/subclasses.json"),
+	Backgrounds: mustLoad[Background]("data/backgrounds.json"),
+	Items:       []Item{},
+	Spells:      []Spell{},
+}
+
+// SRD returns the options from the System Reference Document. It must not be modified.
+func SRD() *Catalog {
+	return srd
+}
+
+// With returns a copy of the catalog with the options from each pack added, marked with
+// the pack's name as their source.
+func (c *Catalog) With(packs ...*Pack) *Catalog {
+	merged := &Catalog{
+		Species:     append([]Species{}, c.Species...),
+		Classes:     append([]Class{}, c.Classes...),
+		Subclasses:  append([]Subclass{}, c.Subclasses...),
+		Backgrounds: append([]Background{}, c.Backgrounds...),
+		Items:       append([]Item{}, c.Items...),
+		Spells:      append([]Spell{}, c.Spells...),
+	}
+	for _, p := range packs {
+		for _, s := range p.Species {
+			s.Source = p.Name
+			merged.Species = append(merged.Species, s)
+		}
+		for _, cl := range p.Classes {
+			cl.Source = p.Name
+			merged.Classes = append(merged.Classes, cl)
+		}
+		for _, sc := range p.Subclasses {
+			sc.Source = p.Name
+			merged.Subclasses = append(merged.Subclasses, sc)
+		}
+		for _, item := range p.Items {
+			item.Source = p.Name
+			merged.Items = append(merged.Items, item)
+		}
+		for _, spell := range p.Spells {
+			spell.Source = p.Name
+			merged.Spells = append(merged.Spells, spell)
+		}
+	}
+	return merged
+}
+
+// LookupSpecies finds a species by name.
+func (c *Catalog) LookupSpecies(name string) (Species, bool) {
+	return lookup(c.Species, name, func(s Species) string { return s.Name })
+}
+
+// LookupClass finds a class by name.
+func (c *Catalog) LookupClass(name string) (Class, bool) {
+	return lookup(c.Classes, name, func(cl Class) string { return cl.Name })
+}
+
+// LookupSubclass finds one of a class's subclasses by name.
+func (c *Catalog) LookupSubclass(class, name string) (Subclass, bool) {
+	for _, sc := range c.Subclasses {
+		if strings.EqualFold(sc.Class, strings.TrimSpace(class)) && strings.EqualFold(sc.Name, strings.TrimSpace(name)) {
+			return sc, true
+		}
+	}
+	return Subclass{}, false
+}
+
+// LookupBackground finds a background by name.
+func (c *Catalog) LookupBackground(name string) (Background, bool) {
+	return lookup(c.Backgrounds, name, func(b Background) string { return b.Name })
+}
+
+func lookup[T any](entries []T, name string, nameOf func(T) string) (T, bool) {
+	name = strings.TrimSpace(name)
+	for _, e := range entries {
+		if strings.EqualFold(nameOf(e), name) {
+			return e, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// mustLoad decodes one of the embedded data files, panicking if it is malformed as the
+// binary cannot work without it.
+func mustLoad[T any](path string) []T {
+	data, err := dataFS.ReadFile(path)
+	if err != nil {
+		panic(fmt.Sprintf("rules: %v", err))
+	}
+	var entries []T
+	if err := json.Unmarshal(data, &entries); err != nil {
+		panic(fmt.Sprintf("rules: failed to decode %s: %v", path, err))
+	}
+	return entries
+}
@@ -0,0 +1,50 @@
+package rules
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCatalogLoads(t *testing.T) {
+	c := SRD()
+	if len(c.Species) != 10 || len(c.Classes) != 12 || len(c.Subclasses) != 12 || len(c.Backgrounds) != 16 {
+		t.Fatalf("loaded %d species, %d classes, %d subclasses, %d backgrounds",
+			len(c.Species), len(c.Classes), len(c.Subclasses), len(c.Backgrounds))
+	}
+	for _, cl := range c.Classes {
+		if cl.HitDie < 6 || len(cl.SavingThrows) != 2 || cl.Skills.Count == 0 || len(cl.FeaturesAt(1)) == 0 {
+			t.Errorf("class %+v", cl)
+		}
+		for _, a := range append(cl.SavingThrows, cl.PrimaryAbilities...) {
+			if !slices.Contains(abilities, a) {
+				t.Errorf("%s: unknown ability %q", cl.Name, a)
+			}
+		}
+	}
+	for _, sc := range c.Subclasses {
+		if _, ok := c.LookupClass(sc.Class); !ok || len(sc.Features) == 0 {
+			t.Errorf("subclass %+v", sc)
+		}
+	}
+	for _, b := range c.Backgrounds {
+		if len(b.AbilityScores) != 3 || len(b.SkillProficiencies) != 2 || b.Feat == "" {
+			t.Errorf("background %+v", b)
+		}
+	}
+}
+
+func TestLookupIgnoresCase(t *testing.T) {
+	c := SRD()
+	if s, ok := c.LookupSpecies(" goliath "); !ok || s.Speed != 35 {
+		t.Fatalf("goliath = %+v, %v", s, ok)
+	}
+	if cl, ok := c.LookupClass("WIZARD"); !ok || cl.HitDie != 6 || cl.SpellcastingAbility != "intelligence" {
+		t.Fatalf("wizard = %+v, %v", cl, ok)
+	}
+	if sc, ok := c.LookupSubclass("fighter", "champion"); !ok || sc.Name != "Champion" {
+		t.Fatalf("champion = %+v, %v", sc, ok)
+	}
+	if _, ok := c.LookupBackground("Pirate"); ok {
+		t.Fatal("expected no Pirate background")
+	}
+}
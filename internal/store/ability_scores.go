@@ -0,0 +1,369 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+// Ability score generation methods. Each is recorded with the character so a GM can see
+// how its scores were made. Imported characters are recorded with the scores they were
+// imported with, as their generation is not known.
+const (
+	AbilityMethodPointBuy      = "point-buy"
+	AbilityMethodStandardArray = "standard-array"
+	AbilityMethodRolled        = "rolled"
+	AbilityMethodImported      = "imported"
+)
+
+// pointBuyBudget is the number of points a point buy character can spend.
+const pointBuyBudget = 27
+
+// pointBuyCosts is the cost of each score a point buy character can buy.
+var pointBuyCosts = map[int]int{8: 0, 9: 1, 10: 2, 11: 3, 12: 4, 13: 5, 14: 7, 15: 9}
+
+// standardArray is the set of scores assigned with the standard array method.
+var standardArray = []int{15, 14, 13, 12, 10, 8}
+
+// abilityRollExpression is rolled once per ability for rolled scores.
+const abilityRollExpression = "4d6dl1"
+
+// AbilityScoreGeneration is how a new character's ability scores were generated: the
+// base scores before any bonuses, the background's increases and, for rolled scores,
+// the server roll the base scores were assigned from.
+type AbilityScoreGeneration struct {
+	Method    string         `json:"method"`
+	Base      map[string]int `json:"base"`
+	Increases map[string]int `json:"increases"`
+	RollID    *int64         `json:"rollId,omitempty"`
+}
+
+// AbilityScoreError reports invalid ability scores by request field, such as "strength"
+// or "abilityIncreases.wisdom". Problems with the set as a whole use "abilityScores".
+type AbilityScoreError struct {
+	Fields map[string]string `json:"fields"`
+}
+
+func (e *AbilityScoreError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	problems := make([]string, 0, len(fields))
+	for _, field := range fields {
+		problems = append(problems, field+" "+e.Fields[field])
+	}
+	return "invalid ability scores: " + strings.Join(problems, "; ")
+}
+
+// AbilityRoll is a set of six 4d6-drop-lowest rolls made on the server. It is claimed by
+// the character created with it and cannot be used again.
+type AbilityRoll struct {
+	ID          int64         `json:"id"`
+	CharacterID *int64        `json:"characterId"`
+	Scores      []int         `json:"scores"`
+	Rolls       []dice.Result `json:"rolls"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+// AbilityScoreRecord is the audit of how a character's starting ability scores were
+// generated. Campaign listings also name the character and its owner.
+type AbilityScoreRecord struct {
+	CharacterID   int64  `json:"characterId"`
+	CharacterName string `json:"characterName,omitempty"`
+	OwnerUsername string `json:"ownerUsername,omitempty"`
+	AbilityScoreGeneration
+	Roll      *AbilityRoll `json:"roll,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+// RollAbilityScores rolls 4d6, dropping the lowest die, for each of the six abilities and
+// records the result so that a character can later be created from it. A user with a roll
+// no character has claimed yet gets that roll back, so scores can't be rerolled.
+func (s *Store) RollAbilityScores(userID int64, rng dice.RNG) (*AbilityRoll, error) {
+	ctx := context.Background()
+	existing, err := s.q.GetUnclaimedAbilityScoreRoll(ctx, userID)
+	if err == nil {
+		return abilityRoll(existing.ID, existing.CharacterID, existing.Rolls, existing.CreatedAt)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get rolls: %w", err)
+	}
+
+	rolls := make([]dice.Result, 0, len(Abilities))
+	for range Abilities {
+		result, err := dice.Roll(abilityRollExpression, rng)
+		if err != nil {
+			return nil, err
+		}
+		rolls = append(rolls, *result)
+	}
+	data, err := json.Marshal(rolls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rolls: %w", err)
+	}
+
+	row, err := s.q.InsertAbilityScoreRoll(ctx, InsertAbilityScoreRollParams{UserID: userID, Rolls: string(data)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record rolls: %w", err)
+	}
+	return abilityRoll(row.ID, row.CharacterID, row.Rolls, row.CreatedAt)
+}
+
+// GetAbilityScores returns how the character's starting ability scores were generated.
+// Characters created before generation was recorded return ErrAbilityScoresNotFound.
+func (s *Store) GetAbilityScores(characterID int64) (*AbilityScoreRecord, error) {
+	row, err := s.q.GetCharacterAbilityScores(context.Background(), characterID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAbilityScoresNotFound
+		}
+		return nil, fmt.Errorf("failed to get ability scores: %w", err)
+	}
+	return abilityScoreRecord(CharacterAbilityScore{
+		CharacterID: row.CharacterID,
+		Method:      row.Method,
+		Base:        row.Base,
+		Increases:   row.Increases,
+		RollID:      row.RollID,
+		CreatedAt:   row.CreatedAt,
+	}, row.Rolls, row.RolledAt)
+}
+
+// ListCampaignAbilityScores returns how every character in the campaign generated its
+// ability scores, for the campaign's members.
+func (s *Store) ListCampaignAbilityScores(campaignID, userID int64) ([]AbilityScoreRecord, error) {
+	_, status, err := s.getMembership(campaignID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "accepted" {
+		return nil, ErrNotPermitted
+	}
+
+	rows, err := s.q.ListCampaignAbilityScores(context.Background(), campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ability scores: %w", err)
+	}
+	records := make([]AbilityScoreRecord, 0, len(rows))
+	for _, row := range rows {
+		record, err := abilityScoreRecord(CharacterAbilityScore{
+			CharacterID: row.CharacterID,
+			Method:      row.Method,
+			Base:        row.Base,
+			Increases:   row.Increases,
+			RollID:      row.RollID,
+			CreatedAt:   row.CreatedAt,
+		}, row.Rolls, row.RolledAt)
+		if err != nil {
+			return nil, err
+		}
+		record.CharacterName = row.CharacterName
+		record.OwnerUsername = row.OwnerUsername
+		records = append(records, *record)
+	}
+	return records, nil
+}
+
+// loadAbilityRoll finds an unclaimed roll made by the user.
+func (s *Store) loadAbilityRoll(ctx context.Context, rollID, userID int64) (*AbilityRoll, error) {
+	row, err := s.q.GetAbilityScoreRoll(ctx, GetAbilityScoreRollParams{ID: rollID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAbilityRollNotFound
+		}
+		return nil, fmt.Errorf("failed to get ability roll: %w", err)
+	}
+	if row.CharacterID != nil {
+		return nil, ErrAbilityRollUsed
+	}
+	return abilityRoll(row.ID, row.CharacterID, row.Rolls, row.CreatedAt)
+}
+
+// saveAbilityScores records the generation of a new character's ability scores, claiming
+// the roll they came from.
+func saveAbilityScores(ctx context.Context, q *Queries, characterID int64, gen *AbilityScoreGeneration) error {
+	if gen.RollID != nil {
+		claimed, err := q.ClaimAbilityScoreRoll(ctx, ClaimAbilityScoreRollParams{CharacterID: &characterID, ID: *gen.RollID})
+		if err != nil {
+			return fmt.Errorf("failed to claim ability roll: %w", err)
+		}
+		if claimed == 0 {
+			return ErrAbilityRollUsed
+		}
+	}
+	base, err := json.Marshal(gen.Base)
+	if err != nil {
+		return fmt.Errorf("failed to encode ability scores: %w", err)
+	}
+	increases, err := json.Marshal(gen.Increases)
+	if err != nil {
+		return fmt.Errorf("failed to encode ability scores: %w", err)
+	}
+	if err := q.InsertCharacterAbilityScores(ctx, InsertCharacterAbilityScoresParams{
+		CharacterID: characterID,
+		Method:      gen.Method,
+		Base:        string(base),
+		Increases:   string(increases),
+		RollID:      gen.RollID,
+	}); err != nil {
+		return fmt.Errorf("failed to save ability scores: %w", err)
+	}
+	return nil
+}
+
+// applyAbilityScores checks the generated base scores against their method and the
+// increases against the abilities the background allows, then sets the character's
+// scores to their sum. 2024 species grant no ability score increases. roll is the
+// server roll for rolled scores.
+func (c *CharacterWithStats) applyAbilityScores(gen *AbilityScoreGeneration, allowed []string, roll *AbilityRoll) error {
+	fields := map[string]string{}
+
+	base := make(map[string]int, len(Abilities))
+	for name, score := range gen.Base {
+		if ability, ok := lookupAbility(name); ok {
+			base[ability] = score
+		}
+	}
+	scores := make([]int, 0, len(Abilities))
+	for _, ability := range Abilities {
+		score := base[ability]
+		switch {
+		case score == 0:
+			fields[ability] = "is required"
+		case gen.Method == AbilityMethodPointBuy && (score < 8 || score > 15):
+			fields[ability] = "must be from 8 to 15 for point buy"
+		}
+		scores = append(scores, score)
+	}
+
+	switch gen.Method {
+	case AbilityMethodPointBuy:
+		cost := 0
+		for _, score := range scores {
+			cost += pointBuyCosts[score]
+		}
+		if cost > pointBuyBudget {
+			fields["abilityScores"] = fmt.Sprintf("cost %d points; point buy has %d", cost, pointBuyBudget)
+		}
+	case AbilityMethodStandardArray:
+		if !sameScores(scores, standardArray) {
+			fields["abilityScores"] = "must use each of 15, 14, 13, 12, 10 and 8 once"
+		}
+	case AbilityMethodRolled:
+		if roll == nil {
+			fields["abilityRollId"] = "is required for rolled scores"
+		} else if !sameScores(scores, roll.Scores) {
+			fields["abilityScores"] = fmt.Sprintf("must use each rolled score once: %s", joinScores(roll.Scores))
+		}
+	default:
+		fields["abilityScoreMethod"] = "must be point-buy, standard-array or rolled"
+	}
+
+	increases := map[string]int{}
+	total := 0
+	for name, amount := range gen.Increases {
+		if amount == 0 {
+			continue
+		}
+		field := "abilityIncreases." + name
+		ability, ok := lookupAbility(name)
+		switch {
+		case !ok:
+			fields[field] = "is not an ability"
+		case !slices.Contains(allowed, ability):
+			fields[field] = "is not one of the background's abilities"
+		case amount < 0 || amount > 2:
+			fields[field] = "must be +1 or +2"
+		default:
+			increases[ability] += amount
+			total += amount
+		}
+	}
+	// A background gives +2 and +1 to two of its abilities or +1 to all three.
+	if len(allowed) > 0 {
+		amounts := make([]int, 0, len(increases))
+		for _, amount := range increases {
+			amounts = append(amounts, amount)
+		}
+		if total != 3 || (!sameScores(amounts, []int{2, 1}) && !sameScores(amounts, []int{1, 1, 1})) {
+			fields["abilityIncreases"] = "must add +2 and +1 to two of the background's abilities or +1 to all three"
+		}
+	}
+	for ability, amount := range increases {
+		if base[ability]+amount > maxAbilityScore {
+			fields["abilityIncreases."+ability] = fmt.Sprintf("cannot raise a score past %d", maxAbilityScore)
+		}
+	}
+
+	if len(fields) > 0 {
+		return &AbilityScoreError{Fields: fields}
+	}
+	gen.Base = base
+	gen.Increases = increases
+	for _, ability := range Abilities {
+		*c.abilityScore(ability) = int64(base[ability] + increases[ability])
+	}
+	return nil
+}
+
+// sameScores reports whether two lists hold the same scores in any order.
+func sameScores(a, b []int) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// joinScores lists scores highest first, e.g. "16, 14, 12".
+func joinScores(scores []int) string {
+	sorted := slices.Clone(scores)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	parts := make([]string, 0, len(sorted))
+	for _, score := range sorted {
+		parts = append(parts, fmt.Sprint(score))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func abilityRoll(id int64, characterID *int64, rolls string, createdAt time.Time) (*AbilityRoll, error) {
+	roll := &AbilityRoll{ID: id, CharacterID: characterID, CreatedAt: createdAt}
+	if err := json.Unmarshal([]byte(rolls), &roll.Rolls); err != nil {
+		return nil, fmt.Errorf("failed to decode ability roll: %w", err)
+	}
+	for _, result := range roll.Rolls {
+		roll.Scores = append(roll.Scores, result.Total)
+	}
+	return roll, nil
+}
+
+func abilityScoreRecord(row CharacterAbilityScore, rolls string, rolledAt *time.Time) (*AbilityScoreRecord, error) {
+	record := &AbilityScoreRecord{
+		CharacterID:            row.CharacterID,
+		AbilityScoreGeneration: AbilityScoreGeneration{Method: row.Method, RollID: row.RollID},
+		CreatedAt:              row.CreatedAt,
+	}
+	if err := json.Unmarshal([]byte(row.Base), &record.Base); err != nil {
+		return nil, fmt.Errorf("failed to decode ability scores: %w", err)
+	}
+	if err := json.Unmarshal([]byte(row.Increases), &record.Increases); err != nil {
+		return nil, fmt.Errorf("failed to decode ability scores: %w", err)
+	}
+	if row.RollID != nil && rolledAt != nil {
+		roll, err := abilityRoll(*row.RollID, &row.CharacterID, rolls, *rolledAt)
+		if err != nil {
+			return nil, err
+		}
+		record.Roll = roll
+	}
+	return record, nil
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+func scores(str, dex, con, intel, wis, cha int) map[string]int {
+	return map[string]int{"strength": str, "dexterity": dex, "constitution": con, "intelligence": intel, "wisdom": wis, "charisma": cha}
+}
+
+func TestAbilityScoreMethods(t *testing.T) {
+	cases := []struct {
+		name      string
+		gen       AbilityScoreGeneration
+		allowed   []string
+		badFields []string
+	}{
+		{"point buy", AbilityScoreGeneration{Method: AbilityMethodPointBuy, Base: scores(8, 15, 14, 13, 10, 8),
+			Increases: map[string]int{"dex": 2, "con": 1}}, []string{"dexterity", "constitution", "charisma"}, nil},
+		{"over budget", AbilityScoreGeneration{Method: AbilityMethodPointBuy, Base: scores(15, 15, 15, 9, 8, 8)},
+			nil, []string{"abilityScores"}},
+		{"point buy range", AbilityScoreGeneration{Method: AbilityMethodPointBuy, Base: scores(18, 8, 8, 8, 8, 0)},
+			nil, []string{"strength", "charisma"}},
+		{"standard array", AbilityScoreGeneration{Method: AbilityMethodStandardArray, Base: scores(8, 15, 14, 13, 12, 10),
+			Increases: map[string]int{"dexterity": 1, "wisdom": 1, "charisma": 1}}, []string{"dexterity", "wisdom", "charisma"}, nil},
+		{"not the array", AbilityScoreGeneration{Method: AbilityMethodStandardArray, Base: scores(15, 15, 13, 12, 10, 8)},
+			nil, []string{"abilityScores"}},
+		{"wrong background ability", AbilityScoreGeneration{Method: AbilityMethodStandardArray, Base: scores(8, 15, 14, 13, 12, 10),
+			Increases: map[string]int{"strength": 2, "dexterity": 1}}, []string{"dexterity", "wisdom", "charisma"},
+			[]string{"abilityIncreases.strength", "abilityIncreases"}},
+		{"increases required", AbilityScoreGeneration{Method: AbilityMethodStandardArray, Base: scores(8, 15, 14, 13, 12, 10)},
+			[]string{"dexterity", "wisdom", "charisma"}, []string{"abilityIncreases"}},
+		{"manual", AbilityScoreGeneration{Method: "manual", Base: scores(18, 18, 18, 3, 3, 3),
+			Increases: map[string]int{"strength": 2, "dexterity": 1}}, []string{"strength", "dexterity", "constitution"},
+			[]string{"abilityScoreMethod"}},
+		{"no method", AbilityScoreGeneration{Base: scores(10, 10, 10, 10, 10, 10)}, nil, []string{"abilityScoreMethod"}},
+		{"rolled without roll", AbilityScoreGeneration{Method: AbilityMethodRolled, Base: scores(10, 10, 10, 10, 10, 10)},
+			nil, []string{"abilityRollId"}},
+	}
+	for _, tc := range cases {
+		c := newTestCharacter()
+		err := c.applyAbilityScores(&tc.gen, tc.allowed, nil)
+		if len(tc.badFields) == 0 {
+			if err != nil {
+				t.Errorf("%s: %v", tc.name, err)
+			}
+			continue
+		}
+		var scoreErr *AbilityScoreError
+		if !errors.As(err, &scoreErr) || len(scoreErr.Fields) != len(tc.badFields) {
+			t.Errorf("%s: expected errors for %v, got %v", tc.name, tc.badFields, err)
+			continue
+		}
+		for _, field := range tc.badFields {
+			if scoreErr.Fields[field] == "" {
+				t.Errorf("%s: no error for %s in %v", tc.name, field, scoreErr.Fields)
+			}
+		}
+	}
+
+	c := newTestCharacter()
+	gen := AbilityScoreGeneration{Method: AbilityMethodPointBuy, Base: scores(8, 15, 14, 13, 10, 8), Increases: map[string]int{"dex": 2, "con": 1}}
+	if err := c.applyAbilityScores(&gen, []string{"dexterity", "constitution", "charisma"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.Dexterity != 17 || c.Constitution != 15 || c.Strength != 8 {
+		t.Fatalf("scores = %d %d %d", c.Strength, c.Dexterity, c.Constitution)
+	}
+}
+
+func TestRolledAbilityScores(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	gm, _ := s.CreateUser("gm", "hash")
+	user, _ := s.CreateUser("roller", "hash")
+	roll, err := s.RollAbilityScores(user.ID, dice.NewSeededRNG(3))
+	if err != nil {
+		t.Fatalf("roll: %v", err)
+	}
+	if len(roll.Scores) != 6 || len(roll.Rolls[0].Terms[0].Dice) != 4 {
+		t.Fatalf("roll = %+v", roll)
+	}
+	// Rolling again before a character uses the roll gives the same scores.
+	if reroll, err := s.RollAbilityScores(user.ID, dice.NewSeededRNG(4)); err != nil || reroll.ID != roll.ID {
+		t.Fatalf("reroll = %+v, %v", reroll, err)
+	}
+
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.AbilityScoreGeneration = &AbilityScoreGeneration{
+		Method: AbilityMethodRolled,
+		Base:   scores(roll.Scores[0], roll.Scores[1], roll.Scores[2], roll.Scores[3], roll.Scores[4], roll.Scores[5]),
+		RollID: &roll.ID,
+	}
+	// Three 18s are not what was rolled.
+	c.AbilityScoreGeneration.Base["strength"] = 18
+	c.AbilityScoreGeneration.Base["dexterity"] = 18
+	c.AbilityScoreGeneration.Base["constitution"] = 18
+	var scoreErr *AbilityScoreError
+	if err := s.CreateCharacter(c); !errors.As(err, &scoreErr) || scoreErr.Fields["abilityScores"] == "" {
+		t.Fatalf("expected the rolled scores to be enforced, got %v", err)
+	}
+
+	c.AbilityScoreGeneration.Base = scores(roll.Scores[5], roll.Scores[4], roll.Scores[3], roll.Scores[2], roll.Scores[1], roll.Scores[0])
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if c.Strength != int64(roll.Scores[5]) {
+		t.Fatalf("strength = %d, rolled %v", c.Strength, roll.Scores)
+	}
+
+	again := newTestCharacter()
+	again.UserID = user.ID
+	again.AbilityScoreGeneration = &AbilityScoreGeneration{Method: AbilityMethodRolled, Base: c.AbilityScoreGeneration.Base, RollID: &roll.ID}
+	if err := s.CreateCharacter(again); err != ErrAbilityRollUsed {
+		t.Fatalf("reused roll: expected ErrAbilityRollUsed, got %v", err)
+	}
+	// Once claimed, the next roll is a new one.
+	if next, err := s.RollAbilityScores(user.ID, dice.NewSeededRNG(4)); err != nil || next.ID == roll.ID {
+		t.Fatalf("next roll = %+v, %v", next, err)
+	}
+
+	// The GM sees the roll the character was created from.
+	camp, _ := s.CreateCampaign(gm.ID, "Audit", "", models.CampaignVisibilityPrivate, models.CampaignStatusInProgress)
+	if _, err := s.db.Exec(`INSERT INTO campaign_members (campaign_id, user_id, role, status) VALUES (?, ?, 'editor', 'accepted')`, camp.ID, user.ID); err != nil {
+		t.Fatalf("insert member: %v", err)
+	}
+	if _, err := s.AddCharacterToCampaign(camp.ID, c.ID, user.ID); err != nil {
+		t.Fatalf("add to campaign: %v", err)
+	}
+	records, err := s.ListCampaignAbilityScores(camp.ID, gm.ID)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("records = %+v, %v", records, err)
+	}
+	if r := records[0]; r.Method != AbilityMethodRolled || r.Roll == nil || r.Roll.ID != roll.ID || r.OwnerUsername != "roller" {
+		t.Fatalf("record = %+v", r)
+	}
+}
@@ -0,0 +1,123 @@
+package store
+
+import "strings"
+
+// Armor types for inventory items. Body armor limits how much Dexterity adds to Armor
+// Class; a shield adds its armor class on top.
+const (
+	ArmorLight  = "light"
+	ArmorMedium = "medium"
+	ArmorHeavy  = "heavy"
+	ArmorShield = "shield"
+)
+
+// ArmorTypes are the armor types an item can have.
+var ArmorTypes = []string{ArmorLight, ArmorMedium, ArmorHeavy, ArmorShield}
+
+// unarmoredAC is the base Armor Class without armor.
+const unarmoredAC = 10
+
+// unarmoredDefense is the ability each class adds to 10 + DEX when wearing no armor.
+// Monks also lose the benefit when carrying a shield.
+var unarmoredDefense = map[string]string{
+	"Barbarian": "constitution",
+	"Monk":      "wisdom",
+}
+
+// validateArmor normalises an item's armor type. Armor needs a positive armor class, and
+// other items have none.
+func validateArmor(item *CharacterItem) error {
+	item.ArmorType = strings.ToLower(strings.TrimSpace(item.ArmorType))
+	if item.ArmorType == "" {
+		if item.ArmorClass != 0 {
+			return ErrInvalidArmor
+		}
+		return nil
+	}
+	if !containsKey(ArmorTypes, item.ArmorType) || item.ArmorClass <= 0 {
+		return ErrInvalidArmor
+	}
+	return nil
+}
+
+// armorDexterity is how much of the Dexterity modifier counts towards Armor Class in the
+// given body armor.
+func armorDexterity(armorType string, dex int) int {
+	switch armorType {
+	case ArmorMedium:
+		return min(dex, 2)
+	case ArmorHeavy:
+		return 0
+	}
+	return dex
+}
+
+// applyArmorClass works out Armor Class from the equipped armor and shield, unarmored
+// defense and flat bonus, unless the character overrides it with the stored value.
+func (c *CharacterWithStats) applyArmorClass() {
+	if c.ArmorClassOverride {
+		c.ArmorClassBreakdown = []ModifierPart{{Source: "override", Value: int(c.ArmorClass)}}
+		return
+	}
+
+	var shield *CharacterItem
+	var armor []CharacterItem
+	for i, item := range c.items {
+		switch {
+		case !item.Equipped || item.ArmorType == "":
+		case item.ArmorType == ArmorShield:
+			if shield == nil || item.ArmorClass > shield.ArmorClass {
+				shield = &c.items[i]
+			}
+		default:
+			armor = append(armor, item)
+		}
+	}
+
+	// Each way of working out the base Armor Class is tried and the best one is used.
+	options := [][]ModifierPart{}
+	for _, item := range armor {
+		parts := []ModifierPart{{Source: item.Name, Value: int(item.ArmorClass)}}
+		if item.ArmorType != ArmorHeavy {
+			parts = append(parts, ModifierPart{Source: "DEX", Value: armorDexterity(item.ArmorType, c.DexterityModifier)})
+		}
+		options = append(options, parts)
+	}
+	if len(armor) == 0 {
+		options = append(options, []ModifierPart{{Source: "unarmored", Value: unarmoredAC}, {Source: "DEX", Value: c.DexterityModifier}})
+		for _, class := range c.classLevels() {
+			ability, ok := unarmoredDefense[class.Class]
+			if !ok || (class.Class == "Monk" && shield != nil) {
+				continue
+			}
+			options = append(options, []ModifierPart{
+				{Source: "unarmored defense", Value: unarmoredAC},
+				{Source: "DEX", Value: c.DexterityModifier},
+				{Source: abilityAbbreviations[ability], Value: c.AbilityModifier(ability)},
+			})
+		}
+	}
+
+	best, bestTotal := options[0], sumParts(options[0])
+	for _, parts := range options[1:] {
+		if total := sumParts(parts); total > bestTotal {
+			best, bestTotal = parts, total
+		}
+	}
+	if shield != nil {
+		best = append(best, ModifierPart{Source: shield.Name, Value: int(shield.ArmorClass)})
+	}
+	if c.ArmorClassBonus != 0 {
+		best = append(best, ModifierPart{Source: "bonus", Value: int(c.ArmorClassBonus)})
+	}
+	c.ArmorClassBreakdown = best
+	c.ArmorClass = int64(sumParts(best))
+}
+
+func sumParts(parts []ModifierPart) int {
+	total := 0
+	for _, p := range parts {
+		total += p.Value
+	}
+	return total
+}
@@ -0,0 +1,89 @@
+package store
+
+import "testing"
+
+func TestUnarmoredDefense(t *testing.T) {
+	c := newTestCharacter()
+	c.ComputeModifiers()
+	if c.ArmorClass != 13 || (CheckModifier{Parts: c.ArmorClassBreakdown}).Explanation() != "+10 unarmored, +3 DEX" {
+		t.Fatalf("rogue AC = %d %+v", c.ArmorClass, c.ArmorClassBreakdown)
+	}
+
+	// Barbarians add CON and may carry a shield.
+	c.Class = "Barbarian"
+	c.Constitution = 16
+	c.items = []CharacterItem{{Name: "Shield", Equipped: true, ArmorType: ArmorShield, ArmorClass: 2}}
+	c.ComputeModifiers()
+	if c.ArmorClass != 18 {
+		t.Fatalf("barbarian AC = %d %+v", c.ArmorClass, c.ArmorClassBreakdown)
+	}
+
+	// Monks add WIS, but not while carrying a shield.
+	c.Class = "Monk"
+	c.Wisdom = 16
+	c.ComputeModifiers()
+	if c.ArmorClass != 15 {
+		t.Fatalf("monk with shield AC = %d %+v", c.ArmorClass, c.ArmorClassBreakdown)
+	}
+	c.items = nil
+	c.ComputeModifiers()
+	if c.ArmorClass != 16 {
+		t.Fatalf("monk AC = %d %+v", c.ArmorClass, c.ArmorClassBreakdown)
+	}
+}
+
+func TestArmorClassFromEquippedArmor(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("armored", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	bad := []CharacterItem{
+		{CharacterID: c.ID, Name: "Robe", Quantity: 1, ArmorType: "cloth", ArmorClass: 11},
+		{CharacterID: c.ID, Name: "Shield", Quantity: 1, ArmorType: ArmorShield},
+		{CharacterID: c.ID, Name: "Cloak", Quantity: 1, ArmorClass: 1},
+	}
+	for _, item := range bad {
+		if _, err := s.AddCharacterItem(item); err != ErrInvalidArmor {
+			t.Fatalf("%+v: expected ErrInvalidArmor, got %v", item, err)
+		}
+	}
+
+	// Medium armor caps DEX at +2; unequipped plate makes no difference.
+	items := []CharacterItem{
+		{CharacterID: c.ID, Name: "Half Plate", Quantity: 1, Equipped: true, ArmorType: " Medium ", ArmorClass: 15},
+		{CharacterID: c.ID, Name: "Plate", Quantity: 1, ArmorType: ArmorHeavy, ArmorClass: 18},
+		{CharacterID: c.ID, Name: "Shield", Quantity: 1, Equipped: true, ArmorType: ArmorShield, ArmorClass: 2},
+	}
+	for _, item := range items {
+		if _, err := s.AddCharacterItem(item); err != nil {
+			t.Fatalf("add %s: %v", item.Name, err)
+		}
+	}
+	got, _ := s.GetCharacter(c.ID, user.ID)
+	got.ArmorClassBonus = 1
+	if err := s.UpdateCharacter(got); err != nil {
+		t.Fatalf("update character: %v", err)
+	}
+	got, _ = s.GetCharacter(c.ID, user.ID)
+	explanation := (CheckModifier{Parts: got.ArmorClassBreakdown}).Explanation()
+	if got.ArmorClass != 20 || explanation != "+15 Half Plate, +2 DEX, +2 Shield, +1 bonus" {
+		t.Fatalf("AC = %d (%s)", got.ArmorClass, explanation)
+	}
+
+	// The override keeps the number typed in.
+	got.ArmorClassOverride = true
+	got.ArmorClass = 12
+	if err := s.UpdateCharacter(got); err != nil {
+		t.Fatalf("update character: %v", err)
+	}
+	got, _ = s.GetCharacter(c.ID, user.ID)
+	if got.ArmorClass != 12 || len(got.ArmorClassBreakdown) != 1 || got.ArmorClassBreakdown[0].Source != "override" {
+		t.Fatalf("overridden AC = %d %+v", got.ArmorClass, got.ArmorClassBreakdown)
+	}
+}
@@ -0,0 +1,280 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+// Attack kinds.
+const (
+	AttackMelee  = "melee"
+	AttackRanged = "ranged"
+	AttackSpell  = "spell"
+)
+
+// Weapon properties that change how an attack is worked out. The others are kept for
+// reference.
+const (
+	PropertyFinesse   = "finesse"
+	PropertyThrown    = "thrown"
+	PropertyTwoHanded = "two-handed"
+	PropertyVersatile = "versatile"
+)
+
+// WeaponProperties are the properties a weapon attack can have.
+var WeaponProperties = []string{
+	"ammunition", PropertyFinesse, "heavy", "light", "loading", "reach", PropertyThrown,
+	PropertyTwoHanded, PropertyVersatile,
+}
+
+// Attack is a weapon or spell attack with its attack bonus and damage worked out for the
+// character.
+type Attack struct {
+	CharacterAttack
+	// AttackAbility is the ability the attack uses: the stored ability if set, otherwise
+	// the spellcasting ability for spells, Dexterity for ranged weapons, and Strength for
+	// melee weapons, or Dexterity if higher for finesse weapons.
+	AttackAbility string `json:"attackAbility"`
+	AttackBonus   int    `json:"attackBonus"`
+	// Damage adds the ability modifier to weapon damage; spell damage only adds the bonus.
+	Damage string `json:"damage"`
+	// VersatileDamage is the damage when a versatile weapon is used with two hands.
+	VersatileDamage string `json:"versatileDamage,omitempty"`
+}
+
+// ListCharacterAttacks returns the character's attacks in the order they were added.
+func (s *Store) ListCharacterAttacks(c *CharacterWithStats) ([]Attack, error) {
+	rows, err := s.q.ListCharacterAttacks(context.Background(), c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attacks: %w", err)
+	}
+	c.Attacks = newAttacks(rows)
+	c.applyAttacks()
+	return c.Attacks, nil
+}
+
+// AddCharacterAttack adds an attack to the character.
+func (s *Store) AddCharacterAttack(c *CharacterWithStats, attack CharacterAttack) (*Attack, error) {
+	if err := validateAttack(&attack); err != nil {
+		return nil, err
+	}
+
+	inserted, err := s.q.InsertCharacterAttack(context.Background(), InsertCharacterAttackParams{
+		CharacterID:   c.ID,
+		Name:          attack.Name,
+		Kind:          attack.Kind,
+		Ability:       attack.Ability,
+		Proficient:    attack.Proficient,
+		DamageDice:    attack.DamageDice,
+		VersatileDice: attack.VersatileDice,
+		DamageType:    attack.DamageType,
+		Properties:    attack.Properties,
+		Bonus:         attack.Bonus,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add attack: %w", err)
+	}
+	if err := markCharacterChanged(context.Background(), s.q, c.ID); err != nil {
+		return nil, err
+	}
+	return c.attack(inserted), nil
+}
+
+// UpdateCharacterAttack replaces an attack's details.
+func (s *Store) UpdateCharacterAttack(c *CharacterWithStats, attack CharacterAttack) (*Attack, error) {
+	if err := validateAttack(&attack); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.q.UpdateCharacterAttack(context.Background(), UpdateCharacterAttackParams{
+		Name:          attack.Name,
+		Kind:          attack.Kind,
+		Ability:       attack.Ability,
+		Proficient:    attack.Proficient,
+		DamageDice:    attack.DamageDice,
+		VersatileDice: attack.VersatileDice,
+		DamageType:    attack.DamageType,
+		Properties:    attack.Properties,
+		Bonus:         attack.Bonus,
+		ID:            attack.ID,
+		CharacterID:   c.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAttackNotFound
+		}
+		return nil, fmt.Errorf("failed to update attack: %w", err)
+	}
+	if err := markCharacterChanged(context.Background(), s.q, c.ID); err != nil {
+		return nil, err
+	}
+	return c.attack(updated), nil
+}
+
+// DeleteCharacterAttack removes an attack from the character.
+func (s *Store) DeleteCharacterAttack(characterID, attackID int64) error {
+	rows, err := s.q.DeleteCharacterAttack(context.Background(), DeleteCharacterAttackParams{ID: attackID, CharacterID: characterID})
+	if err != nil {
+		return fmt.Errorf("failed to delete attack: %w", err)
+	}
+	if rows == 0 {
+		return ErrAttackNotFound
+	}
+	return markCharacterChanged(context.Background(), s.q, characterID)
+}
+
+// validateAttack normalises the attack and checks its kind, ability, dice, damage type
+// and properties. Only weapons have properties, and a versatile weapon needs its
+// two-handed damage dice.
+func validateAttack(attack *CharacterAttack) error {
+	attack.Name = strings.TrimSpace(attack.Name)
+	attack.Kind = strings.ToLower(strings.TrimSpace(attack.Kind))
+	if attack.Name == "" || (attack.Kind != AttackMelee && attack.Kind != AttackRanged && attack.Kind != AttackSpell) {
+		return ErrInvalidAttack
+	}
+
+	if attack.Ability != "" {
+		ability, ok := lookupAbility(attack.Ability)
+		if !ok {
+			return ErrInvalidAttack
+		}
+		attack.Ability = ability
+	}
+
+	attack.DamageDice = strings.ReplaceAll(strings.ToLower(attack.DamageDice), " ", "")
+	attack.VersatileDice = strings.ReplaceAll(strings.ToLower(attack.VersatileDice), " ", "")
+	for _, expr := range []string{attack.DamageDice, attack.VersatileDice} {
+		if expr == "" {
+			continue
+		}
+		if _, err := dice.Parse(expr); err != nil {
+			return ErrInvalidAttack
+		}
+	}
+
+	if attack.DamageType != "" {
+		damageType, ok := lookupDamageType(attack.DamageType)
+		if !ok {
+			return ErrInvalidAttack
+		}
+		attack.DamageType = damageType
+	}
+
+	properties := []string{}
+	for _, p := range parseStringArray(attack.Properties) {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if !containsKey(WeaponProperties, p) || attack.Kind == AttackSpell {
+			return ErrInvalidAttack
+		}
+		if !containsKey(properties, p) {
+			properties = append(properties, p)
+		}
+	}
+	versatile := containsKey(properties, PropertyVersatile)
+	if versatile != (attack.VersatileDice != "") || (versatile && containsKey(properties, PropertyTwoHanded)) {
+		return ErrInvalidAttack
+	}
+	attack.Properties = marshalStringArray(properties)
+	return nil
+}
+
+// attackAbility picks the ability an attack uses.
+func (c *CharacterWithStats) attackAbility(attack CharacterAttack) string {
+	properties := parseStringArray(attack.Properties)
+	switch {
+	case attack.Ability != "":
+		return attack.Ability
+	case attack.Kind == AttackSpell:
+		if c.SpellcastingAbility != "" {
+			return c.SpellcastingAbility
+		}
+		return "intelligence"
+	case containsKey(properties, PropertyFinesse) && c.DexterityModifier > c.StrengthModifier:
+		return "dexterity"
+	case attack.Kind == AttackRanged && !containsKey(properties, PropertyThrown):
+		return "dexterity"
+	}
+	return "strength"
+}
+
+// attackModifier builds the attack roll bonus: the ability modifier, proficiency if
+// proficient, the magic bonus and any exhaustion penalty.
+func (c *CharacterWithStats) attackModifier(attack CharacterAttack) *CheckModifier {
+	m := &CheckModifier{Kind: CheckAttack, Name: attack.Name, Ability: c.attackAbility(attack)}
+	m.addAbility(c)
+	if attack.Proficient {
+		m.add("proficiency", c.ProficiencyBonus)
+	}
+	if attack.Bonus != 0 {
+		m.add("magic", int(attack.Bonus))
+	}
+	if c.Exhaustion > 0 {
+		m.add(ConditionExhaustion, -exhaustionPenalty(c.Exhaustion))
+	}
+	return m
+}
+
+// attack works out the attack bonus and damage for one attack.
+func (c *CharacterWithStats) attack(attack CharacterAttack) *Attack {
+	m := c.attackModifier(attack)
+	bonus := int(attack.Bonus)
+	if attack.Kind != AttackSpell {
+		bonus += c.AbilityModifier(m.Ability)
+	}
+	a := &Attack{
+		CharacterAttack: attack,
+		AttackAbility:   m.Ability,
+		AttackBonus:     m.Total,
+		Damage:          damageExpression(attack.DamageDice, bonus),
+	}
+	if attack.VersatileDice != "" {
+		a.VersatileDamage = damageExpression(attack.VersatileDice, bonus)
+	}
+	return a
+}
+
+// damageExpression adds a flat bonus to damage dice, e.g. "1d8+3". Without dice, as for
+// an unarmed strike, the damage is the bonus alone with a minimum of 1.
+func damageExpression(damageDice string, bonus int) string {
+	switch {
+	case damageDice == "":
+		return strconv.Itoa(max(1, bonus))
+	case bonus == 0:
+		return damageDice
+	}
+	return fmt.Sprintf("%s%+d", damageDice, bonus)
+}
+
+// applyAttacks works out the attack bonus and damage of each attack.
+func (c *CharacterWithStats) applyAttacks() {
+	if c.Attacks == nil {
+		c.Attacks = []Attack{}
+	}
+	for i := range c.Attacks {
+		c.Attacks[i] = *c.attack(c.Attacks[i].CharacterAttack)
+	}
+}
+
+func newAttacks(rows []CharacterAttack) []Attack {
+	attacks := make([]Attack, 0, len(rows))
+	for _, r := range rows {
+		attacks = append(attacks, Attack{CharacterAttack: r})
+	}
+	return attacks
+}
+
+// findAttack looks up one of the character's attacks by name, ignoring case.
+func (c *CharacterWithStats) findAttack(name string) (CharacterAttack, bool) {
+	for _, a := range c.Attacks {
+		if strings.EqualFold(a.Name, strings.TrimSpace(name)) {
+			return a.CharacterAttack, true
+		}
+	}
+	return CharacterAttack{}, false
+}
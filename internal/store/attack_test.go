@@ -0,0 +1,89 @@
+package store
+
+import "testing"
+
+func TestAttacksComputeBonusAndDamage(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("duelist", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	bad := []CharacterAttack{
+		{Name: "Sword", Kind: "slash", DamageDice: "1d8"},
+		{Name: "Sword", Kind: AttackMelee, DamageDice: "1d"},
+		{Name: "Sword", Kind: AttackMelee, DamageDice: "1d8", DamageType: "sonic"},
+		{Name: "Sword", Kind: AttackMelee, DamageDice: "1d8", Properties: `["sharp"]`},
+		{Name: "Longsword", Kind: AttackMelee, DamageDice: "1d8", Properties: `["versatile"]`},
+		{Name: "Greatsword", Kind: AttackMelee, DamageDice: "2d6", VersatileDice: "2d8", Properties: `["versatile","two-handed"]`},
+		{Name: "Fire Bolt", Kind: AttackSpell, DamageDice: "1d10", Properties: `["finesse"]`},
+	}
+	for _, a := range bad {
+		if _, err := s.AddCharacterAttack(c, a); err != ErrInvalidAttack {
+			t.Fatalf("%+v: expected ErrInvalidAttack, got %v", a, err)
+		}
+	}
+
+	// DEX +3 beats STR -1 for a finesse weapon; proficiency is +2.
+	rapier, err := s.AddCharacterAttack(c, CharacterAttack{Name: "Rapier", Kind: AttackMelee, Proficient: true, DamageDice: "1d8", DamageType: "Piercing", Properties: `["Finesse"]`})
+	if err != nil {
+		t.Fatalf("add rapier: %v", err)
+	}
+	if rapier.AttackAbility != "dexterity" || rapier.AttackBonus != 5 || rapier.Damage != "1d8+3" || rapier.DamageType != "piercing" {
+		t.Fatalf("rapier = %+v", rapier)
+	}
+
+	cases := []struct {
+		attack        CharacterAttack
+		ability       string
+		bonus         int
+		damage        string
+		twoHandDamage string
+	}{
+		{CharacterAttack{Name: "Longsword", Kind: AttackMelee, Proficient: true, DamageDice: "1d8", VersatileDice: "1d10", Properties: `["versatile"]`, Bonus: 1}, "strength", 2, "1d8", "1d10"},
+		{CharacterAttack{Name: "Shortbow", Kind: AttackRanged, Proficient: true, DamageDice: "1d6", Properties: `["ammunition"]`}, "dexterity", 5, "1d6+3", ""},
+		{CharacterAttack{Name: "Javelin", Kind: AttackRanged, DamageDice: "1d6", Properties: `["thrown"]`}, "strength", -1, "1d6-1", ""},
+		{CharacterAttack{Name: "Unarmed Strike", Kind: AttackMelee, Proficient: true}, "strength", 1, "1", ""},
+		{CharacterAttack{Name: "Shocking Grasp", Kind: AttackSpell, Ability: "int", Proficient: true, DamageDice: "1d8"}, "intelligence", 3, "1d8", ""},
+	}
+	for _, tc := range cases {
+		got, err := s.AddCharacterAttack(c, tc.attack)
+		if err != nil {
+			t.Fatalf("add %s: %v", tc.attack.Name, err)
+		}
+		if got.AttackAbility != tc.ability || got.AttackBonus != tc.bonus || got.Damage != tc.damage || got.VersatileDamage != tc.twoHandDamage {
+			t.Errorf("%s = %s %+d %s / %s", tc.attack.Name, got.AttackAbility, got.AttackBonus, got.Damage, got.VersatileDamage)
+		}
+	}
+
+	// Exhaustion lowers attack rolls, and the roll endpoint uses the same breakdown.
+	if _, err := s.SetCharacterCondition(CharacterCondition{CharacterID: c.ID, Name: ConditionExhaustion}); err != nil {
+		t.Fatalf("set exhaustion: %v", err)
+	}
+	got, _ := s.GetCharacter(c.ID, user.ID)
+	if len(got.Attacks) != 6 || got.Attacks[0].AttackBonus != 3 {
+		t.Fatalf("attacks = %+v", got.Attacks)
+	}
+	m, err := got.CheckModifier(CheckAttack, "rapier")
+	if err != nil || m.Total != 3 || m.Explanation() != "+3 DEX, +2 proficiency, -2 exhaustion" {
+		t.Fatalf("rapier modifier = %+v, %v", m, err)
+	}
+	if _, err := got.CheckModifier(CheckAttack, "Warhammer"); err == nil {
+		t.Fatal("expected an error for an unknown attack")
+	}
+
+	rapier.Bonus = 2
+	if _, err := s.UpdateCharacterAttack(c, rapier.CharacterAttack); err != nil {
+		t.Fatalf("update rapier: %v", err)
+	}
+	if err := s.DeleteCharacterAttack(c.ID, rapier.ID); err != nil {
+		t.Fatalf("delete rapier: %v", err)
+	}
+	if _, err := s.UpdateCharacterAttack(c, rapier.CharacterAttack); err != ErrAttackNotFound {
+		t.Fatalf("expected ErrAttackNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+// Token audience entries. A token's audience lists who besides the GM can see it: nobody
+// (gm-only), every player (all), or particular players by user or character ID, written
+// as "user:12" or "character:34".
+const (
+	AudienceGMOnly          = "gm-only"
+	AudienceAll             = "all"
+	audienceUserPrefix      = "user:"
+	audienceCharacterPrefix = "character:"
+)
+
+// normalizeAudience checks every audience entry and returns the audience to store. An
+// empty audience is gm-only, and gm-only cannot be combined with other entries.
+func normalizeAudience(audience []string) ([]string, error) {
+	if len(audience) == 0 {
+		return []string{AudienceGMOnly}, nil
+	}
+	seen := make(map[string]bool, len(audience))
+	normalized := make([]string, 0, len(audience))
+	for _, entry := range audience {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == AudienceGMOnly, entry == AudienceAll:
+		case strings.HasPrefix(entry, audienceUserPrefix):
+			if _, ok := audienceID(entry, audienceUserPrefix); !ok {
+				return nil, ErrInvalidTokenAudience
+			}
+		case strings.HasPrefix(entry, audienceCharacterPrefix):
+			if _, ok := audienceID(entry, audienceCharacterPrefix); !ok {
+				return nil, ErrInvalidTokenAudience
+			}
+		default:
+			return nil, ErrInvalidTokenAudience
+		}
+		if !seen[entry] {
+			seen[entry] = true
+			normalized = append(normalized, entry)
+		}
+	}
+	if seen[AudienceGMOnly] && len(normalized) > 1 {
+		return nil, ErrInvalidTokenAudience
+	}
+	return normalized, nil
+}
+
+func audienceID(entry, prefix string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(entry, prefix), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// tokenViewer is a player looking at a campaign's maps, with the characters they own.
+type tokenViewer struct {
+	userID       int64
+	characterIDs map[int64]bool
+}
+
+func (s *Store) loadTokenViewer(ctx context.Context, userID int64) (*tokenViewer, error) {
+	ids, err := s.q.ListCharacterIDsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list viewer characters: %w", err)
+	}
+	v := &tokenViewer{userID: userID, characterIDs: make(map[int64]bool, len(ids))}
+	for _, id := range ids {
+		v.characterIDs[id] = true
+	}
+	return v, nil
+}
+
+// owns reports whether the token stands for one of the viewer's characters.
+func (v *tokenViewer) owns(t *models.Token) bool {
+	return t.CharacterID != nil && v.characterIDs[*t.CharacterID]
+}
+
+// canSee reports whether the token's audience includes the viewer. Players always see the
+// tokens of their own characters.
+func (v *tokenViewer) canSee(t *models.Token) bool {
+	if v.owns(t) {
+		return true
+	}
+	for _, entry := range t.Audience {
+		switch {
+		case entry == AudienceAll:
+			return true
+		case strings.HasPrefix(entry, audienceUserPrefix):
+			if id, ok := audienceID(entry, audienceUserPrefix); ok && id == v.userID {
+				return true
+			}
+		case strings.HasPrefix(entry, audienceCharacterPrefix):
+			if id, ok := audienceID(entry, audienceCharacterPrefix); ok && v.characterIDs[id] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// forPlayer strips the fields only the GM should read from a token shown to a player.
+func forPlayer(t models.Token) models.Token {
+	t.Notes = ""
+	t.Audience = []string{}
+	return t
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+func TestNormalizeAudience(t *testing.T) {
+	cases := []struct {
+		in      []string
+		want    []string
+		invalid bool
+	}{
+		{in: nil, want: []string{"gm-only"}},
+		{in: []string{" All ", "all"}, want: []string{"all"}},
+		{in: []string{"user:3", "character:7"}, want: []string{"user:3", "character:7"}},
+		{in: []string{"gm-only", "user:3"}, invalid: true},
+		{in: []string{"user:abc"}, invalid: true},
+		{in: []string{"character:0"}, invalid: true},
+		{in: []string{"players"}, invalid: true},
+	}
+	for _, tc := range cases {
+		got, err := normalizeAudience(tc.in)
+		if tc.invalid {
+			if err != ErrInvalidTokenAudience {
+				t.Errorf("%v: expected ErrInvalidTokenAudience, got %v %v", tc.in, got, err)
+			}
+			continue
+		}
+		if err != nil || len(got) != len(tc.want) {
+			t.Errorf("%v: got %v %v, want %v", tc.in, got, err, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%v: got %v, want %v", tc.in, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestTokens_AudienceLimitsPlayerView(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	owner, _ := s.CreateUser("owner", "hash")
+	alice, _ := s.CreateUser("alice", "hash")
+	bob, _ := s.CreateUser("bob", "hash")
+	camp, err := s.CreateCampaign(owner.ID, "Quest", "", models.CampaignVisibilityPrivate, models.CampaignStatusNotStarted)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	for _, u := range []int64{alice.ID, bob.ID} {
+		if _, err := s.db.Exec(`INSERT INTO campaign_members (campaign_id, user_id, role, status) VALUES (?, ?, 'viewer', 'accepted')`, camp.ID, u); err != nil {
+			t.Fatalf("insert member: %v", err)
+		}
+	}
+	rogue := newTestCharacter()
+	rogue.UserID = alice.ID
+	if err := s.CreateCharacter(rogue); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	m, err := s.CreateMapForCampaign(camp.ID, owner.ID, nil, "Dungeon", "/uploads/dungeon.png")
+	if err != nil {
+		t.Fatalf("create map: %v", err)
+	}
+	add := func(label string, characterID *int64, audience ...string) *models.Token {
+		t.Helper()
+		token, err := s.CreateToken(m.ID, owner.ID, characterID, label, "", 1, 0, 0, 0, audience, nil, "")
+		if err != nil {
+			t.Fatalf("create %s: %v", label, err)
+		}
+		return token
+	}
+	add("Trap", nil)
+	door := add("Door", nil, "all")
+	if _, err := s.UpdateToken(door.ID, owner.ID, "Door", "", 1, 0, []string{"all"}, nil, "Trapped"); err != nil {
+		t.Fatalf("update door: %v", err)
+	}
+	add("Whisper", nil, fmt.Sprintf("user:%d", bob.ID))
+	add("Vision", nil, fmt.Sprintf("character:%d", rogue.ID))
+	add("Alice", &rogue.ID, "gm-only")
+	if _, err := s.CreateToken(m.ID, owner.ID, nil, "Bad", "", 1, 0, 0, 0, []string{"everyone"}, nil, ""); err != ErrInvalidTokenAudience {
+		t.Fatalf("expected ErrInvalidTokenAudience, got %v", err)
+	}
+
+	labels := func(userID int64) map[string]string {
+		t.Helper()
+		full, err := s.GetCampaignFull(camp.ID, userID)
+		if err != nil {
+			t.Fatalf("campaign full: %v", err)
+		}
+		seen := map[string]string{}
+		for _, tok := range full.Scenes[0].Maps[0].Tokens {
+			seen[tok.Label] = tok.Notes
+			if userID != owner.ID && len(tok.Audience) != 0 {
+				t.Fatalf("player payload should not include audience: %+v", tok)
+			}
+		}
+		return seen
+	}
+
+	if got := labels(owner.ID); len(got) != 5 || got["Door"] != "Trapped" {
+		t.Fatalf("owner should see every token: %v", got)
+	}
+	if got := labels(alice.ID); len(got) != 3 || got["Door"] != "" || !hasKeys(got, "Door", "Vision", "Alice") {
+		t.Fatalf("alice sees %v", got)
+	}
+	if got := labels(bob.ID); len(got) != 2 || !hasKeys(got, "Door", "Whisper") {
+		t.Fatalf("bob sees %v", got)
+	}
+}
+
+func hasKeys(m map[string]string, keys ...string) bool {
+	for _, k := range keys {
+		if _, ok := m[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
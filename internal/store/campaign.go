@@ -487,6 +487,23 @@ func (s *Store) ListCampaignDetails(userID int64) ([]*models.CampaignDetail, err
 		return nil, fmt.Errorf("failed to list campaign details: %w", err)
 	}
 
+	var characterIDs []int64
+	for _, r := range rows {
+		if r.CharacterID != 0 {
+			characterIDs = append(characterIDs, r.CharacterID)
+		}
+	}
+	classesByCharacter := make(map[int64][]CharacterClass)
+	if len(characterIDs) > 0 {
+		classes, err := s.q.ListCharacterClassesByCharacterIDs(ctx, characterIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list character classes: %w", err)
+		}
+		for _, cl := range classes {
+			classesByCharacter[cl.CharacterID] = append(classesByCharacter[cl.CharacterID], cl)
+		}
+	}
+
 	byID := make(map[int64]*models.CampaignDetail)
 	var result []*models.CampaignDetail
 
@@ -511,6 +528,10 @@ func (s *Store) ListCampaignDetails(userID int64) ([]*models.CampaignDetail, err
 		}
 
 		if r.LinkID != nil && r.CharacterID != 0 {
+			// Multiclass characters show every class, e.g. "Fighter 2 / Wizard 3".
+			if classes := classesByCharacter[r.CharacterID]; len(classes) > 1 {
+				r.CharacterClass = classLabel(classes)
+			}
 			detail.Characters = append(detail.Characters, models.CampaignCharacterSummary{
 				LinkID:         *r.LinkID,
 				CharacterID:    r.CharacterID,
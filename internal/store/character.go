@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // ListCharacters returns all characters for a user.
@@ -16,12 +18,73 @@ func (s *Store) ListCharacters(userID int64) ([]*CharacterWithStats, error) {
 		return nil, fmt.Errorf("failed to query characters: %w", err)
 	}
 
+	items, err := s.q.ListCharacterItemsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query items: %w", err)
+	}
+	itemsByCharacter := make(map[int64][]CharacterItem)
+	for _, item := range items {
+		itemsByCharacter[item.CharacterID] = append(itemsByCharacter[item.CharacterID], CharacterItem(item))
+	}
+
+	coins, err := s.q.ListCharacterCoinsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query coins: %w", err)
+	}
+	coinsByCharacter := make(map[int64]CharacterCoin, len(coins))
+	for _, c := range coins {
+		coinsByCharacter[c.CharacterID] = CharacterCoin(c)
+	}
+
+	conditions, err := s.q.ListCharacterConditionsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query conditions: %w", err)
+	}
+	conditionsByCharacter := make(map[int64][]CharacterCondition)
+	for _, cond := range conditions {
+		conditionsByCharacter[cond.CharacterID] = append(conditionsByCharacter[cond.CharacterID], CharacterCondition(cond))
+	}
+
+	classes, err := s.q.ListCharacterClassesByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query classes: %w", err)
+	}
+	classesByCharacter := make(map[int64][]CharacterClass)
+	for _, cl := range classes {
+		classesByCharacter[cl.CharacterID] = append(classesByCharacter[cl.CharacterID], CharacterClass(cl))
+	}
+
+	resources, err := s.q.ListCharacterResourcesByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query resources: %w", err)
+	}
+	resourcesByCharacter := make(map[int64][]CharacterResource)
+	for _, r := range resources {
+		resourcesByCharacter[r.CharacterID] = append(resourcesByCharacter[r.CharacterID], CharacterResource(r))
+	}
+
+	attacks, err := s.q.ListCharacterAttacksByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attacks: %w", err)
+	}
+	attacksByCharacter := make(map[int64][]CharacterAttack)
+	for _, a := range attacks {
+		attacksByCharacter[a.CharacterID] = append(attacksByCharacter[a.CharacterID], CharacterAttack(a))
+	}
+
 	result := make([]*CharacterWithStats, 0, len(chars))
 	for _, c := range chars {
 		model := &CharacterWithStats{
 			CharacterModel: toCharacterModel(c),
+			Conditions:     conditionsByCharacter[c.ID],
+			Classes:        classesByCharacter[c.ID],
+			Resources:      newClassResources(resourcesByCharacter[c.ID]),
+			Attacks:        newAttacks(attacksByCharacter[c.ID]),
+			items:          itemsByCharacter[c.ID],
 		}
+		model.Classes = model.classLevels()
 		model.ComputeModifiers()
+		model.applyInventory(itemsByCharacter[c.ID], coinsByCharacter[c.ID])
 		result = append(result, model)
 	}
 
@@ -43,18 +106,85 @@ func (s *Store) GetCharacter(id, userID int64) (*CharacterWithStats, error) {
 	model := &CharacterWithStats{
 		CharacterModel: c,
 	}
-	model.ComputeModifiers()
+	if err := s.attachDetails(ctx, model); err != nil {
+		return nil, err
+	}
 	return model, nil
 }
 
-// CreateCharacter creates a new character.
+// CreateCharacter creates a new character. Its options come from the SRD, the user's
+// packs and, when CampaignID is set, the packs of that campaign, which the character is
+// added to.
 func (s *Store) CreateCharacter(c *CharacterWithStats) error {
 	ctx := context.Background()
 
-	inserted, err := s.q.InsertCharacter(ctx, c.ToInsertParams())
+	catalog, err := s.characterCatalog(ctx, c)
+	if err != nil {
+		return err
+	}
+	// Ability scores are settled first as multiclass prerequisites depend on them.
+	gen := c.AbilityScoreGeneration
+	if gen != nil {
+		var roll *AbilityRoll
+		if gen.Method == AbilityMethodRolled && gen.RollID != nil {
+			if roll, err = s.loadAbilityRoll(ctx, *gen.RollID, c.UserID); err != nil {
+				return err
+			}
+		} else {
+			gen.RollID = nil
+		}
+		background, _ := catalog.LookupBackground(c.Background)
+		if err := c.applyAbilityScores(gen, background.AbilityScores, roll); err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	classes := c.Classes
+	if classes == nil {
+		classes = []CharacterClass{{Class: c.Class, Level: c.Level}}
+	}
+	if err := c.setClasses(classes, nil); err != nil {
+		return err
+	}
+	if err := c.applyCreationRules(catalog); err != nil {
+		return err
+	}
+
+	qtx := s.q.WithTx(tx)
+	inserted, err := qtx.InsertCharacter(ctx, c.ToInsertParams())
 	if err != nil {
 		return fmt.Errorf("failed to create character: %w", err)
 	}
+	if err := syncClasses(ctx, qtx, inserted.ID, c.Classes); err != nil {
+		return err
+	}
+	if err := syncEquipment(ctx, qtx, inserted.ID, c.Equipment); err != nil {
+		return err
+	}
+	if gen != nil {
+		if err := saveAbilityScores(ctx, qtx, inserted.ID, gen); err != nil {
+			return err
+		}
+	}
+	// Level-ups and edits find the campaign's packs through this link. Catalog has
+	// already checked that the user is an accepted member.
+	if c.CampaignID != nil {
+		if _, err := qtx.InsertCampaignCharacter(ctx, InsertCampaignCharacterParams{
+			CampaignID:  *c.CampaignID,
+			CharacterID: inserted.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to add character to campaign: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit character: %w", err)
+	}
 
 	// InsertCharacter returns Character (generated), which is different from CharacterModel (GetCharacterByIDAndUserRow)
 	// But we can map it.
@@ -70,28 +200,147 @@ func (s *Store) CreateCharacter(c *CharacterWithStats) error {
 
 	model := characterToModel(inserted)
 	c.CharacterModel = model
-	c.ComputeModifiers()
-	return nil
+	return s.attachDetails(ctx, c)
 }
 
-// UpdateCharacter updates an existing character.
+// UpdateCharacter updates an existing character, saving the previous version as a revision.
+// Its ability scores, level and max hit points must be left as they are.
 func (s *Store) UpdateCharacter(c *CharacterWithStats) error {
+	return s.updateCharacter(c, RevisionReasonUpdate, nil, nil)
+}
+
+// UpdateCharacterIfUnmodified updates the character only if it has not been saved since
+// updatedAt, returning ErrPreconditionFailed otherwise.
+func (s *Store) UpdateCharacterIfUnmodified(c *CharacterWithStats, updatedAt time.Time) error {
+	return s.updateCharacter(c, RevisionReasonUpdate, &updatedAt, nil)
+}
+
+// updateCharacter saves c over the stored character. When set, also runs in the same
+// transaction after the character is saved.
+func (s *Store) updateCharacter(c *CharacterWithStats, reason string, unmodifiedSince *time.Time, also func(ctx context.Context, q *Queries) error) error {
 	ctx := context.Background()
 
-	updated, err := s.q.UpdateCharacter(ctx, c.ToUpdateParams())
+	catalog, err := s.characterCatalog(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	current, err := saveRevision(ctx, qtx, c.ID, c.UserID, reason)
+	if err != nil {
+		return err
+	}
+	if unmodifiedSince != nil && !current.UpdatedAt.Equal(*unmodifiedSince) {
+		return ErrPreconditionFailed
+	}
+	// Ability scores are audited when the character is created, so edits can't change them.
+	// Levels and hit points come from LevelUp.
+	edit := reason == RevisionReasonUpdate
+	if edit {
+		stored := CharacterWithStats{CharacterModel: current}
+		for _, ability := range Abilities {
+			if *c.abilityScore(ability) != *stored.abilityScore(ability) {
+				return ErrAbilityScoresLocked
+			}
+		}
+		if c.MaxHp != current.MaxHp || (c.Classes == nil && c.Level != current.Level) {
+			return ErrLevelUpRequired
+		}
+	}
+	previous, err := qtx.ListCharacterClasses(ctx, c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list classes: %w", err)
+	}
+	// Without a class list the class levels are kept. A single-class character can also
+	// change class, but a multiclass character needs the full list to change its starting
+	// class.
+	classes := c.Classes
+	if classes == nil {
+		switch {
+		case len(previous) > 1 && !strings.EqualFold(c.Class, current.Class):
+			return ErrClassesRequired
+		case len(previous) > 0 && strings.EqualFold(c.Class, current.Class):
+			classes = append([]CharacterClass{}, previous...)
+		default:
+			classes = []CharacterClass{{Class: c.Class, Level: c.Level}}
+		}
+	}
+	if err := c.setClasses(classes, previous); err != nil {
+		return err
+	}
+	if edit && !sameLevels(c.Level, c.Classes, current.Level, previous) {
+		return ErrLevelUpRequired
+	}
+	if edit {
+		if err := c.checkChangedOptions(catalog, current, previous); err != nil {
+			return err
+		}
+	}
+	if err := c.checkSubclasses(catalog, previous); err != nil {
+		return err
+	}
+	c.applyClassRules(catalog)
+	updated, err := qtx.UpdateCharacter(ctx, c.ToUpdateParams())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return fmt.Errorf("character not found")
 		}
 		return fmt.Errorf("failed to update character: %w", err)
 	}
+	if err := syncClasses(ctx, qtx, updated.ID, c.Classes); err != nil {
+		return err
+	}
+	// A nil equipment list means the client didn't send one, so leave the items alone.
+	if c.Equipment != nil {
+		if err := syncEquipment(ctx, qtx, updated.ID, c.Equipment); err != nil {
+			return err
+		}
+	}
+	if also != nil {
+		if err := also(ctx, qtx); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit character: %w", err)
+	}
 
 	model := characterToModel(updated)
 	c.CharacterModel = model
-	c.ComputeModifiers()
+	return s.attachDetails(ctx, c)
+}
+
+// markCharacterChanged moves the character's updated_at on, and with it its ETag, when its
+// items, coins, conditions, resources, attacks, spells or spell slots change.
+func markCharacterChanged(ctx context.Context, q *Queries, characterID int64) error {
+	if err := q.TouchCharacter(ctx, characterID); err != nil {
+		return fmt.Errorf("failed to update character: %w", err)
+	}
 	return nil
 }
 
+// sameLevels reports whether a class list keeps the character's total level and the
+// level in each class it already had.
+func sameLevels(level int64, classes []CharacterClass, previousLevel int64, previous []CharacterClass) bool {
+	if level != previousLevel {
+		return false
+	}
+	for _, cl := range classes {
+		for _, p := range previous {
+			if strings.EqualFold(cl.Class, p.Class) && cl.Level != p.Level {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // DeleteCharacter deletes a character by ID for a specific user.
 func (s *Store) DeleteCharacter(id, userID int64) error {
 	ctx := context.Background()
@@ -125,10 +374,52 @@ func (s *Store) UpdateCharacterAvatar(id, userID int64, avatarURL string) (*Char
 	model := &CharacterWithStats{
 		CharacterModel: characterToModel(updated),
 	}
-	model.ComputeModifiers()
+	if err := s.attachDetails(ctx, model); err != nil {
+		return nil, err
+	}
 	return model, nil
 }
 
+// attachDetails loads the character's conditions, classes, resources, attacks, items and
+// coins, then computes the derived stats along with equipment and encumbrance.
+func (s *Store) attachDetails(ctx context.Context, c *CharacterWithStats) error {
+	conditions, err := s.q.ListCharacterConditions(ctx, c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list conditions: %w", err)
+	}
+	c.Conditions = conditions
+	classes, err := s.q.ListCharacterClasses(ctx, c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list classes: %w", err)
+	}
+	// Characters without class rows count as a single class at their level.
+	c.Classes = classes
+	c.Classes = c.classLevels()
+	resources, err := s.q.ListCharacterResources(ctx, c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list resources: %w", err)
+	}
+	c.Resources = newClassResources(resources)
+	attacks, err := s.q.ListCharacterAttacks(ctx, c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list attacks: %w", err)
+	}
+	c.Attacks = newAttacks(attacks)
+	items, err := s.q.ListCharacterItems(ctx, c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list items: %w", err)
+	}
+	c.items = items
+	c.ComputeModifiers()
+
+	coins, err := s.characterCoins(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	c.applyInventory(items, coins)
+	return nil
+}
+
 func (s *Store) characterOwnedByUser(characterID, userID int64) (bool, error) {
 	ctx := context.Background()
 
@@ -168,8 +459,16 @@ func characterToModel(c Character) CharacterModel {
 		SkillProficiencies:       nullJSONString(c.SkillProficiencies),
 		SavingThrowProficiencies: nullJSONString(c.SavingThrowProficiencies),
 		Features:                 nullJSONString(c.Features),
-		Equipment:                nullJSONString(c.Equipment),
 		AvatarUrl:                nullString(c.AvatarUrl),
+		ProficiencyLevels:        nullJSONObjectString(c.ProficiencyLevels),
+		HitDiceSpent:             nullInt64(c.HitDiceSpent),
+		DeathSaveSuccesses:       nullInt64(c.DeathSaveSuccesses),
+		DeathSaveFailures:        nullInt64(c.DeathSaveFailures),
+		DamageResistances:        nullJSONString(c.DamageResistances),
+		DamageVulnerabilities:    nullJSONString(c.DamageVulnerabilities),
+		DamageImmunities:         nullJSONString(c.DamageImmunities),
+		ArmorClassOverride:       nullBool(c.ArmorClassOverride),
+		ArmorClassBonus:          nullInt64(c.ArmorClassBonus),
 		CreatedAt:                c.CreatedAt,
 		UpdatedAt:                c.UpdatedAt,
 	}
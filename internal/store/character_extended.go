@@ -2,6 +2,8 @@ package store
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 )
 
 // CharacterModel is an alias for the generated row struct, which represents a character
@@ -13,15 +15,68 @@ type CharacterWithStats struct {
 	CharacterModel
 
 	// Computed fields (not stored in DB)
-	StrengthModifier     int `json:"strengthModifier"`
-	DexterityModifier    int `json:"dexterityModifier"`
-	ConstitutionModifier int `json:"constitutionModifier"`
-	IntelligenceModifier int `json:"intelligenceModifier"`
-	WisdomModifier       int `json:"wisdomModifier"`
-	CharismaModifier     int `json:"charismaModifier"`
-	ProficiencyBonus     int `json:"proficiencyBonus"`
-	Initiative           int `json:"initiative"`
-	PassivePerception    int `json:"passivePerception"`
+	StrengthModifier     int                         `json:"strengthModifier"`
+	DexterityModifier    int                         `json:"dexterityModifier"`
+	ConstitutionModifier int                         `json:"constitutionModifier"`
+	IntelligenceModifier int                         `json:"intelligenceModifier"`
+	WisdomModifier       int                         `json:"wisdomModifier"`
+	CharismaModifier     int                         `json:"charismaModifier"`
+	ProficiencyBonus     int                         `json:"proficiencyBonus"`
+	Initiative           int                         `json:"initiative"`
+	PassivePerception    int                         `json:"passivePerception"`
+	PassiveInvestigation int                         `json:"passiveInvestigation"`
+	PassiveInsight       int                         `json:"passiveInsight"`
+	SkillLevels          map[string]ProficiencyLevel `json:"skillLevels"`
+	SkillBonuses         map[string]int              `json:"skillBonuses"`
+	SavingThrows         map[string]int              `json:"savingThrows"`
+	SpellcastingAbility  string                      `json:"spellcastingAbility,omitempty"`
+	SpellSaveDC          *int                        `json:"spellSaveDc,omitempty"`
+	SpellAttackBonus     *int                        `json:"spellAttackBonus,omitempty"`
+	HitDiceRemaining     int                         `json:"hitDiceRemaining"`
+	Status               string                      `json:"status"`
+
+	// Spellcasting lists the stats of each spellcasting class, starting class first. The
+	// SpellcastingAbility, SpellSaveDC and SpellAttackBonus above are those of the first.
+	Spellcasting []ClassSpellcasting `json:"spellcasting"`
+
+	// Classes are the character's levels in each class, starting class first. Class and
+	// Level hold the starting class and the total level; ClassLabel names every class.
+	Classes    []CharacterClass `json:"classes"`
+	ClassLabel string           `json:"classLabel"`
+
+	// Resources are limited-use features such as Rage or Ki, with their maximum uses.
+	Resources []ClassResource `json:"resources"`
+
+	// Attacks are weapon and spell attacks with their attack bonus and damage.
+	Attacks []Attack `json:"attacks"`
+
+	// ArmorClassBreakdown explains ArmorClass, which is worked out from equipped armor
+	// unless ArmorClassOverride keeps the stored value.
+	ArmorClassBreakdown []ModifierPart `json:"armorClassBreakdown"`
+
+	// Conditions and their effects. CurrentSpeed is Speed after exhaustion and conditions.
+	Conditions   []CharacterCondition `json:"conditions"`
+	Exhaustion   int                  `json:"exhaustion"`
+	CurrentSpeed int                  `json:"currentSpeed"`
+
+	// Inventory summary, filled from character_items. Equipment lists item names for
+	// clients that still send and expect the old array of strings.
+	Equipment        []string `json:"equipment"`
+	CarriedWeight    float64  `json:"carriedWeight"`
+	CarryingCapacity int      `json:"carryingCapacity"`
+	Encumbrance      string   `json:"encumbrance"`
+
+	// AbilityScoreGeneration, when set on a new character, declares how its ability
+	// scores were generated. They are checked, the background's increases applied and
+	// the generation kept as an audit.
+	AbilityScoreGeneration *AbilityScoreGeneration `json:"-"`
+
+	// CampaignID, when set on a new character, is the campaign it is being made for. The
+	// character is added to the campaign, so it keeps the campaign's homebrew packs.
+	CampaignID *int64 `json:"-"`
+
+	// items are the character's inventory items, used to work out Armor Class.
+	items []CharacterItem
 }
 
 // ComputeModifiers calculates all derived stats
@@ -32,26 +87,78 @@ func (c *CharacterWithStats) ComputeModifiers() {
 	c.IntelligenceModifier = abilityModifier(int(c.Intelligence))
 	c.WisdomModifier = abilityModifier(int(c.Wisdom))
 	c.CharismaModifier = abilityModifier(int(c.Charisma))
-	c.ProficiencyBonus = proficiencyBonus(int(c.Level))
-	c.Initiative = c.DexterityModifier
-	c.PassivePerception = 10 + c.WisdomModifier
-
-	// Add proficiency to passive perception if proficient
-	var skills []string
-	if err := json.Unmarshal([]byte(c.SkillProficiencies), &skills); err == nil {
-		for _, skill := range skills {
-			if skill == "perception" {
-				c.PassivePerception += c.ProficiencyBonus
-				break
+	level := c.totalLevel()
+	c.ProficiencyBonus = proficiencyBonus(level)
+	c.HitDiceRemaining = max(0, level-int(c.HitDiceSpent))
+	c.ClassLabel = classLabel(c.classLevels())
+	c.applyConditions()
+	c.Status = hitPointStatus(c.CharacterModel)
+	if c.Exhaustion >= MaxExhaustion {
+		c.Status = StatusDead
+	}
+
+	c.SkillLevels = make(map[string]ProficiencyLevel, len(Skills))
+	c.SkillBonuses = make(map[string]int, len(Skills))
+	for skill := range Skills {
+		c.SkillLevels[skill] = c.SkillLevel(skill)
+		if m, err := c.CheckModifier(CheckSkill, skill); err == nil {
+			c.SkillBonuses[skill] = m.Total
+		}
+	}
+
+	c.SavingThrows = make(map[string]int, len(Abilities))
+	for _, ability := range Abilities {
+		if m, err := c.CheckModifier(CheckSave, ability); err == nil {
+			c.SavingThrows[ability] = m.Total
+		}
+	}
+
+	if m, err := c.CheckModifier(CheckInitiative, ""); err == nil {
+		c.Initiative = m.Total
+	}
+	c.PassivePerception = 10 + c.SkillBonuses["perception"]
+	c.PassiveInvestigation = 10 + c.SkillBonuses["investigation"]
+	c.PassiveInsight = 10 + c.SkillBonuses["insight"]
+
+	c.SpellcastingAbility = ""
+	c.SpellSaveDC = nil
+	c.SpellAttackBonus = nil
+	c.Spellcasting = []ClassSpellcasting{}
+	for _, cl := range c.classLevels() {
+		ability := cl.SpellcastingAbility
+		if ability == "" {
+			var ok bool
+			if ability, ok = SpellcastingAbilities[cl.Class]; !ok {
+				continue
 			}
 		}
+		mod := c.AbilityModifier(ability)
+		c.Spellcasting = append(c.Spellcasting, ClassSpellcasting{
+			Class:            cl.Class,
+			Ability:          ability,
+			SpellSaveDC:      8 + c.ProficiencyBonus + mod,
+			SpellAttackBonus: c.ProficiencyBonus + mod - exhaustionPenalty(c.Exhaustion),
+		})
+	}
+	if len(c.Spellcasting) > 0 {
+		first := c.Spellcasting[0]
+		c.SpellcastingAbility = first.Ability
+		c.SpellSaveDC = ptr(first.SpellSaveDC)
+		c.SpellAttackBonus = ptr(first.SpellAttackBonus)
 	}
+	c.applyArmorClass()
+	c.applyResources()
+	c.applyAttacks()
 }
 
 // abilityModifier calculates the modifier for an ability score
 // Formula: floor((score - 10) / 2)
 func abilityModifier(score int) int {
-	return (score - 10) / 2
+	diff := score - 10
+	if diff < 0 {
+		return (diff - 1) / 2
+	}
+	return diff / 2
 }
 
 // proficiencyBonus returns the proficiency bonus for a given level
@@ -81,6 +188,325 @@ var Skills = map[string]string{
 	"survival":       "wisdom",
 }
 
+// SpellcastingAbilities maps each SRD spellcasting class to the ability its spells key
+// off, for classes recorded without one.
+var SpellcastingAbilities = map[string]string{
+	"Bard":     "charisma",
+	"Cleric":   "wisdom",
+	"Druid":    "wisdom",
+	"Paladin":  "charisma",
+	"Ranger":   "wisdom",
+	"Sorcerer": "charisma",
+	"Warlock":  "charisma",
+	"Wizard":   "intelligence",
+}
+
+// ClassSpellcasting is the spellcasting ability, spell save DC and spell attack bonus of
+// one of the character's classes. Each class casts its spells with its own ability.
+type ClassSpellcasting struct {
+	Class            string `json:"class"`
+	Ability          string `json:"ability"`
+	SpellSaveDC      int    `json:"spellSaveDc"`
+	SpellAttackBonus int    `json:"spellAttackBonus"`
+}
+
+const jackOfAllTrades = "Jack of All Trades"
+
+// ProficiencyLevel describes how much of the proficiency bonus applies to a skill.
+type ProficiencyLevel string
+
+const (
+	ProficiencyNone       ProficiencyLevel = "none"
+	ProficiencyHalf       ProficiencyLevel = "half"
+	ProficiencyProficient ProficiencyLevel = "proficient"
+	ProficiencyExpertise  ProficiencyLevel = "expertise"
+)
+
+// Bonus returns the part of the proficiency bonus granted at this level (half rounds down).
+func (l ProficiencyLevel) Bonus(proficiencyBonus int) int {
+	switch l {
+	case ProficiencyHalf:
+		return proficiencyBonus / 2
+	case ProficiencyProficient:
+		return proficiencyBonus
+	case ProficiencyExpertise:
+		return proficiencyBonus * 2
+	}
+	return 0
+}
+
+func (l ProficiencyLevel) source() string {
+	switch l {
+	case ProficiencyHalf:
+		return "half proficiency"
+	case ProficiencyExpertise:
+		return "expertise"
+	}
+	return "proficiency"
+}
+
+func isValidProficiencyLevel(l ProficiencyLevel) bool {
+	switch l {
+	case ProficiencyNone, ProficiencyHalf, ProficiencyProficient, ProficiencyExpertise:
+		return true
+	}
+	return false
+}
+
+// EncodeProficiencyLevels validates per-skill proficiency levels and returns them as JSON
+// keyed by the canonical skill name.
+func EncodeProficiencyLevels(levels map[string]string) (string, error) {
+	out := make(map[string]ProficiencyLevel, len(levels))
+	for name, level := range levels {
+		skill, ok := lookupSkill(name)
+		if !ok {
+			return "", fmt.Errorf("%w: skill %q", ErrInvalidProficiencyLevel, name)
+		}
+		l := ProficiencyLevel(strings.ToLower(level))
+		if !isValidProficiencyLevel(l) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidProficiencyLevel, level)
+		}
+		out[skill] = l
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// SkillLevel resolves a skill's proficiency level. An explicit entry in proficiencyLevels wins
+// (with "none" still allowing Jack of All Trades), then the flat skillProficiencies list.
+func (c *CharacterWithStats) SkillLevel(skill string) ProficiencyLevel {
+	level, _ := c.skillProficiency(skill)
+	return level
+}
+
+// skillProficiency returns the level along with the label used in check explanations.
+func (c *CharacterWithStats) skillProficiency(skill string) (ProficiencyLevel, string) {
+	var levels map[string]ProficiencyLevel
+	if err := json.Unmarshal([]byte(c.ProficiencyLevels), &levels); err == nil {
+		for name, level := range levels {
+			if normalizeKey(name) != normalizeKey(skill) || !isValidProficiencyLevel(level) {
+				continue
+			}
+			if level == ProficiencyNone {
+				return c.untrainedProficiency()
+			}
+			return level, level.source()
+		}
+	}
+	if containsKey(parseStringArray(c.SkillProficiencies), skill) {
+		return ProficiencyProficient, ProficiencyProficient.source()
+	}
+	return c.untrainedProficiency()
+}
+
+func (c *CharacterWithStats) untrainedProficiency() (ProficiencyLevel, string) {
+	if c.HasJackOfAllTrades() {
+		return ProficiencyHalf, jackOfAllTrades
+	}
+	return ProficiencyNone, ""
+}
+
+// HasJackOfAllTrades reports whether the character adds half proficiency to checks it is
+// not proficient in: bards from 2nd level, or anyone with the feature listed.
+func (c *CharacterWithStats) HasJackOfAllTrades() bool {
+	if c.Class == "Bard" && c.Level >= 2 {
+		return true
+	}
+	return containsKey(parseStringArray(c.Features), jackOfAllTrades)
+}
+
+// Abilities lists the six ability scores in sheet order.
+var Abilities = []string{"strength", "dexterity", "constitution", "intelligence", "wisdom", "charisma"}
+
+var abilityAbbreviations = map[string]string{
+	"strength":     "STR",
+	"dexterity":    "DEX",
+	"constitution": "CON",
+	"intelligence": "INT",
+	"wisdom":       "WIS",
+	"charisma":     "CHA",
+}
+
+// Check kinds accepted by CheckModifier.
+const (
+	CheckSkill      = "skill"
+	CheckSave       = "save"
+	CheckAbility    = "ability"
+	CheckInitiative = "initiative"
+	CheckAttack     = "attack"
+)
+
+// ModifierPart is one labelled contribution to a check modifier, e.g. "+3 DEX".
+type ModifierPart struct {
+	Source string `json:"source"`
+	Value  int    `json:"value"`
+}
+
+// CheckModifier is the total bonus for a d20 check together with how it was built.
+type CheckModifier struct {
+	Kind    string         `json:"kind"`
+	Name    string         `json:"name"`
+	Ability string         `json:"ability"`
+	Parts   []ModifierPart `json:"parts"`
+	Total   int            `json:"total"`
+}
+
+// Explanation renders the parts as e.g. "+3 DEX, +2 proficiency".
+func (m CheckModifier) Explanation() string {
+	parts := make([]string, 0, len(m.Parts))
+	for _, p := range m.Parts {
+		parts = append(parts, fmt.Sprintf("%+d %s", p.Value, p.Source))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CheckModifier builds the modifier for a skill check, saving throw, raw ability check, initiative
+// or attack roll. Names are matched loosely so "Sleight of Hand", "sleightOfHand" and
+// "dex"/"dexterity" all resolve; attacks are matched by name.
+func (c *CharacterWithStats) CheckModifier(kind, name string) (*CheckModifier, error) {
+	m := &CheckModifier{Kind: kind}
+
+	switch kind {
+	case CheckSkill:
+		skill, ok := lookupSkill(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: skill %q", ErrUnknownCheck, name)
+		}
+		m.Name = skill
+		m.Ability = Skills[skill]
+		m.addAbility(c)
+		m.addProficiency(c, skill)
+	case CheckSave:
+		ability, ok := lookupAbility(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: saving throw %q", ErrUnknownCheck, name)
+		}
+		m.Name = ability
+		m.Ability = ability
+		m.addAbility(c)
+		if containsKey(parseStringArray(c.SavingThrowProficiencies), ability) {
+			m.add("proficiency", c.ProficiencyBonus)
+		}
+	case CheckAbility:
+		ability, ok := lookupAbility(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: ability %q", ErrUnknownCheck, name)
+		}
+		m.Name = ability
+		m.Ability = ability
+		m.addAbility(c)
+		m.addJackOfAllTrades(c)
+	case CheckInitiative:
+		m.Name = CheckInitiative
+		m.Ability = "dexterity"
+		m.addAbility(c)
+		m.addJackOfAllTrades(c)
+	case CheckAttack:
+		attack, ok := c.findAttack(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: attack %q", ErrUnknownCheck, name)
+		}
+		return c.attackModifier(attack), nil
+	default:
+		return nil, fmt.Errorf("%w: kind %q", ErrUnknownCheck, kind)
+	}
+	if c.Exhaustion > 0 {
+		m.add(ConditionExhaustion, -exhaustionPenalty(c.Exhaustion))
+	}
+
+	return m, nil
+}
+
+// exhaustionPenalty is the amount each d20 test is reduced by: 2 per level of exhaustion.
+func exhaustionPenalty(level int) int {
+	return 2 * level
+}
+
+func (m *CheckModifier) add(source string, value int) {
+	m.Parts = append(m.Parts, ModifierPart{Source: source, Value: value})
+	m.Total += value
+}
+
+func (m *CheckModifier) addAbility(c *CharacterWithStats) {
+	m.add(abilityAbbreviations[m.Ability], c.AbilityModifier(m.Ability))
+}
+
+func (m *CheckModifier) addProficiency(c *CharacterWithStats, skill string) {
+	level, source := c.skillProficiency(skill)
+	if bonus := level.Bonus(c.ProficiencyBonus); bonus != 0 {
+		m.add(source, bonus)
+	}
+}
+
+func (m *CheckModifier) addJackOfAllTrades(c *CharacterWithStats) {
+	if c.HasJackOfAllTrades() {
+		m.add(jackOfAllTrades, ProficiencyHalf.Bonus(c.ProficiencyBonus))
+	}
+}
+
+// AbilityModifier returns the computed modifier for a full ability name.
+func (c *CharacterWithStats) AbilityModifier(ability string) int {
+	switch ability {
+	case "strength":
+		return c.StrengthModifier
+	case "dexterity":
+		return c.DexterityModifier
+	case "constitution":
+		return c.ConstitutionModifier
+	case "intelligence":
+		return c.IntelligenceModifier
+	case "wisdom":
+		return c.WisdomModifier
+	case "charisma":
+		return c.CharismaModifier
+	}
+	return 0
+}
+
+// normalizeKey folds "Sleight of Hand", "sleight_of_hand" and "sleightOfHand" to the same key.
+func normalizeKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func lookupSkill(name string) (string, bool) {
+	key := normalizeKey(name)
+	for skill := range Skills {
+		if normalizeKey(skill) == key {
+			return skill, true
+		}
+	}
+	return "", false
+}
+
+func lookupAbility(name string) (string, bool) {
+	key := normalizeKey(name)
+	for _, ability := range Abilities {
+		if ability == key || strings.ToLower(abilityAbbreviations[ability]) == key {
+			return ability, true
+		}
+	}
+	return "", false
+}
+
+func containsKey(values []string, key string) bool {
+	key = normalizeKey(key)
+	for _, v := range values {
+		if normalizeKey(v) == key {
+			return true
+		}
+	}
+	return false
+}
+
 // Helper to convert ListCharactersByUserRow to CharacterModel
 func toCharacterModel(r ListCharactersByUserRow) CharacterModel {
 	return CharacterModel{
@@ -108,8 +534,16 @@ func toCharacterModel(r ListCharactersByUserRow) CharacterModel {
 		SkillProficiencies:       r.SkillProficiencies,
 		SavingThrowProficiencies: r.SavingThrowProficiencies,
 		Features:                 r.Features,
-		Equipment:                r.Equipment,
 		AvatarUrl:                r.AvatarUrl,
+		ProficiencyLevels:        r.ProficiencyLevels,
+		HitDiceSpent:             r.HitDiceSpent,
+		DeathSaveSuccesses:       r.DeathSaveSuccesses,
+		DeathSaveFailures:        r.DeathSaveFailures,
+		DamageResistances:        r.DamageResistances,
+		DamageVulnerabilities:    r.DamageVulnerabilities,
+		DamageImmunities:         r.DamageImmunities,
+		ArmorClassOverride:       r.ArmorClassOverride,
+		ArmorClassBonus:          r.ArmorClassBonus,
 		CreatedAt:                r.CreatedAt,
 		UpdatedAt:                r.UpdatedAt,
 	}
@@ -141,8 +575,16 @@ func (c *CharacterWithStats) ToInsertParams() InsertCharacterParams {
 		SkillProficiencies:       &c.SkillProficiencies,
 		SavingThrowProficiencies: &c.SavingThrowProficiencies,
 		Features:                 &c.Features,
-		Equipment:                &c.Equipment,
 		AvatarUrl:                &c.AvatarUrl,
+		ProficiencyLevels:        &c.ProficiencyLevels,
+		HitDiceSpent:             &c.HitDiceSpent,
+		DeathSaveSuccesses:       &c.DeathSaveSuccesses,
+		DeathSaveFailures:        &c.DeathSaveFailures,
+		DamageResistances:        &c.DamageResistances,
+		DamageVulnerabilities:    &c.DamageVulnerabilities,
+		DamageImmunities:         &c.DamageImmunities,
+		ArmorClassOverride:       &c.ArmorClassOverride,
+		ArmorClassBonus:          &c.ArmorClassBonus,
 	}
 }
 
@@ -171,7 +613,15 @@ func (c *CharacterWithStats) ToUpdateParams() UpdateCharacterParams {
 		SkillProficiencies:       &c.SkillProficiencies,
 		SavingThrowProficiencies: &c.SavingThrowProficiencies,
 		Features:                 &c.Features,
-		Equipment:                &c.Equipment,
+		ProficiencyLevels:        &c.ProficiencyLevels,
+		HitDiceSpent:             &c.HitDiceSpent,
+		DeathSaveSuccesses:       &c.DeathSaveSuccesses,
+		DeathSaveFailures:        &c.DeathSaveFailures,
+		DamageResistances:        &c.DamageResistances,
+		DamageVulnerabilities:    &c.DamageVulnerabilities,
+		DamageImmunities:         &c.DamageImmunities,
+		ArmorClassOverride:       &c.ArmorClassOverride,
+		ArmorClassBonus:          &c.ArmorClassBonus,
 		ID:                       c.ID,
 		UserID:                   c.UserID,
 	}
@@ -0,0 +1,157 @@
+package store
+
+import "testing"
+
+func newTestCharacter() *CharacterWithStats {
+	c := &CharacterWithStats{
+		CharacterModel: CharacterModel{
+			Name:     "Vex",
+			Race:     "Elf",
+			Class:    "Rogue",
+			Level:    1,
+			Strength: 8, Dexterity: 16, Constitution: 12, Intelligence: 13, Wisdom: 10, Charisma: 9,
+			MaxHp: 9, CurrentHp: 9, ArmorClass: 14, Speed: 30, HitDice: "1d8",
+			SkillProficiencies:       `["Stealth","Sleight of Hand"]`,
+			SavingThrowProficiencies: `["dexterity","intelligence"]`,
+			Features:                 "[]",
+		},
+	}
+	c.ComputeModifiers()
+	return c
+}
+
+func TestAbilityModifierRoundsDown(t *testing.T) {
+	cases := map[int]int{1: -5, 8: -1, 9: -1, 10: 0, 11: 0, 15: 2, 20: 5}
+	for score, want := range cases {
+		if got := abilityModifier(score); got != want {
+			t.Errorf("abilityModifier(%d) = %d, want %d", score, got, want)
+		}
+	}
+}
+
+func TestCheckModifier(t *testing.T) {
+	c := newTestCharacter()
+
+	cases := []struct {
+		kind, name  string
+		total       int
+		explanation string
+	}{
+		{CheckSkill, "stealth", 5, "+3 DEX, +2 proficiency"},
+		{CheckSkill, "sleightOfHand", 5, "+3 DEX, +2 proficiency"},
+		{CheckSkill, "Athletics", -1, "-1 STR"},
+		{CheckSave, "dex", 5, "+3 DEX, +2 proficiency"},
+		{CheckSave, "wisdom", 0, "+0 WIS"},
+		{CheckAbility, "intelligence", 1, "+1 INT"},
+		{CheckInitiative, "", 3, "+3 DEX"},
+	}
+	for _, tc := range cases {
+		m, err := c.CheckModifier(tc.kind, tc.name)
+		if err != nil {
+			t.Fatalf("%s %s: %v", tc.kind, tc.name, err)
+		}
+		if m.Total != tc.total || m.Explanation() != tc.explanation {
+			t.Errorf("%s %s = %d (%s), want %d (%s)", tc.kind, tc.name, m.Total, m.Explanation(), tc.total, tc.explanation)
+		}
+	}
+
+	if _, err := c.CheckModifier(CheckSkill, "basketweaving"); err == nil {
+		t.Fatalf("expected unknown skill to fail")
+	}
+	if _, err := c.CheckModifier("attack", "longsword"); err == nil {
+		t.Fatalf("expected unknown kind to fail")
+	}
+}
+
+func TestProficiencyLevels(t *testing.T) {
+	c := newTestCharacter()
+	c.Class = "Bard"
+	c.Level = 5
+	c.Charisma = 16
+	c.SkillProficiencies = `["Perception","Stealth","Insight"]`
+	levels, err := EncodeProficiencyLevels(map[string]string{"Stealth": "expertise", "insight": "none"})
+	if err != nil {
+		t.Fatalf("encode levels: %v", err)
+	}
+	c.ProficiencyLevels = levels
+	c.ComputeModifiers()
+
+	if c.SkillLevels["stealth"] != ProficiencyExpertise || c.SkillBonuses["stealth"] != 9 {
+		t.Fatalf("stealth = %s %+d, want expertise +9", c.SkillLevels["stealth"], c.SkillBonuses["stealth"])
+	}
+	// Jack of All Trades adds half proficiency (rounded down) to untrained skills and initiative.
+	if c.SkillLevels["athletics"] != ProficiencyHalf || c.SkillBonuses["athletics"] != 0 {
+		t.Fatalf("athletics = %s %+d, want half +0", c.SkillLevels["athletics"], c.SkillBonuses["athletics"])
+	}
+	if c.Initiative != 4 {
+		t.Fatalf("initiative = %d, want 4", c.Initiative)
+	}
+	// Insight is in the flat list but overridden to "none", leaving only Jack of All Trades.
+	if c.PassivePerception != 13 || c.PassiveInvestigation != 12 || c.PassiveInsight != 11 {
+		t.Fatalf("passives = %d/%d/%d, want 13/12/11", c.PassivePerception, c.PassiveInvestigation, c.PassiveInsight)
+	}
+	if c.SavingThrows["dexterity"] != 6 || c.SavingThrows["charisma"] != 3 {
+		t.Fatalf("saves = %v", c.SavingThrows)
+	}
+	if c.SpellcastingAbility != "charisma" || *c.SpellSaveDC != 14 || *c.SpellAttackBonus != 6 {
+		t.Fatalf("spellcasting = %s DC %d attack %+d", c.SpellcastingAbility, *c.SpellSaveDC, *c.SpellAttackBonus)
+	}
+
+	m, err := c.CheckModifier(CheckSkill, "arcana")
+	if err != nil {
+		t.Fatalf("arcana: %v", err)
+	}
+	if got := m.Explanation(); got != "+1 INT, +1 Jack of All Trades" {
+		t.Fatalf("arcana explanation = %q", got)
+	}
+
+	if _, err := EncodeProficiencyLevels(map[string]string{"stealth": "double"}); err == nil {
+		t.Fatalf("expected invalid level to fail")
+	}
+	if _, err := EncodeProficiencyLevels(map[string]string{"juggling": "proficient"}); err == nil {
+		t.Fatalf("expected unknown skill to fail")
+	}
+}
+
+func TestRogueHasNoSpellcasting(t *testing.T) {
+	c := newTestCharacter()
+	if c.SpellSaveDC != nil || c.SpellAttackBonus != nil || c.SpellcastingAbility != "" {
+		t.Fatalf("expected no spellcasting for a rogue, got %+v", c)
+	}
+	if c.PassivePerception != 10 || c.SkillBonuses["perception"] != 0 {
+		t.Fatalf("passive perception = %d", c.PassivePerception)
+	}
+}
+
+func TestUpdateCharacterIfUnmodified(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("twotabs", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	laptop, _ := s.GetCharacter(c.ID, user.ID)
+	phone, _ := s.GetCharacter(c.ID, user.ID)
+	seen := phone.UpdatedAt
+
+	laptop.Name = "Vex the Bold"
+	if err := s.UpdateCharacterIfUnmodified(laptop, seen); err != nil {
+		t.Fatalf("first update: %v", err)
+	}
+	if laptop.UpdatedAt.Equal(seen) {
+		t.Fatalf("updatedAt did not change on save")
+	}
+
+	phone.CurrentHp = 3
+	if err := s.UpdateCharacterIfUnmodified(phone, seen); err != ErrPreconditionFailed {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+	got, _ := s.GetCharacter(c.ID, user.ID)
+	if got.Name != "Vex the Bold" || got.CurrentHp != 9 {
+		t.Fatalf("stale save overwrote the sheet: %s hp %d", got.Name, got.CurrentHp)
+	}
+}
@@ -0,0 +1,262 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// multiclassMinimumScore is the ability score needed in each class's primary ability to
+// multiclass into or out of it.
+const multiclassMinimumScore = 13
+
+// multiclassPrerequisites lists the abilities each class needs at 13 or higher before a
+// character can multiclass. Every group must be met; any ability within a group will do.
+var multiclassPrerequisites = map[string][][]string{
+	"Barbarian": {{"strength"}},
+	"Bard":      {{"charisma"}},
+	"Cleric":    {{"wisdom"}},
+	"Druid":     {{"wisdom"}},
+	"Fighter":   {{"strength", "dexterity"}},
+	"Monk":      {{"dexterity"}, {"wisdom"}},
+	"Paladin":   {{"strength"}, {"charisma"}},
+	"Ranger":    {{"dexterity"}, {"wisdom"}},
+	"Rogue":     {{"dexterity"}},
+	"Sorcerer":  {{"charisma"}},
+	"Warlock":   {{"charisma"}},
+	"Wizard":    {{"intelligence"}},
+}
+
+// ClassLevel is a class and the character's level in it, as kept in revisions.
+type ClassLevel struct {
+	Class    string `json:"class"`
+	Level    int64  `json:"level"`
+	Subclass string `json:"subclass,omitempty"`
+}
+
+// MeetsMulticlassPrerequisites reports whether the character's ability scores allow a
+// multiclass character to have levels in class. Classes without known prerequisites,
+// such as homebrew classes, are always allowed.
+func (c *CharacterWithStats) MeetsMulticlassPrerequisites(class string) bool {
+	for _, group := range multiclassPrerequisites[class] {
+		met := false
+		for _, ability := range group {
+			if *c.abilityScore(ability) >= multiclassMinimumScore {
+				met = true
+				break
+			}
+		}
+		if !met {
+			return false
+		}
+	}
+	return true
+}
+
+// classLevels returns the character's levels in each class, treating a character
+// without class rows as a single class at its total level.
+func (c *CharacterWithStats) classLevels() []CharacterClass {
+	if len(c.Classes) > 0 {
+		return c.Classes
+	}
+	return []CharacterClass{{CharacterID: c.ID, Class: c.Class, Level: c.Level}}
+}
+
+// classLevelList returns the character's class levels without their row details.
+func (c *CharacterWithStats) classLevelList() []ClassLevel {
+	levels := make([]ClassLevel, 0, len(c.classLevels()))
+	for _, cl := range c.classLevels() {
+		levels = append(levels, ClassLevel{Class: cl.Class, Level: cl.Level, Subclass: cl.Subclass})
+	}
+	return levels
+}
+
+// classLevel returns the character's level in one class, or 0.
+func (c *CharacterWithStats) classLevel(class string) int {
+	for _, cl := range c.classLevels() {
+		if cl.Class == class {
+			return int(cl.Level)
+		}
+	}
+	return 0
+}
+
+// totalLevel is the character level: the sum of the levels in every class.
+func (c *CharacterWithStats) totalLevel() int {
+	total := 0
+	for _, cl := range c.classLevels() {
+		total += int(cl.Level)
+	}
+	return total
+}
+
+// setClasses validates class levels and makes them the character's classes, deriving
+// the starting class, the total level and the combined hit dice. Multiclass
+// prerequisites are checked whenever a class is added to those in previous.
+func (c *CharacterWithStats) setClasses(classes []CharacterClass, previous []CharacterClass) error {
+	normalized := make([]CharacterClass, 0, len(classes))
+	total := int64(0)
+	added := false
+	for i, cl := range classes {
+		name := canonicalClass(cl.Class)
+		if cl.Level < 1 || (name == "" && len(classes) > 1) {
+			return ErrInvalidClasses
+		}
+		for _, seen := range normalized {
+			if strings.EqualFold(seen.Class, name) {
+				return ErrInvalidClasses
+			}
+		}
+		entry := CharacterClass{
+			CharacterID:         c.ID,
+			Class:               name,
+			Level:               cl.Level,
+			Position:            int64(i),
+			Subclass:            strings.TrimSpace(cl.Subclass),
+			HitDie:              cl.HitDie,
+			SpellcastingAbility: cl.SpellcastingAbility,
+		}
+		known := false
+		for _, p := range previous {
+			if strings.EqualFold(p.Class, name) {
+				known = true
+				entry.HitDie, entry.SpellcastingAbility = p.HitDie, p.SpellcastingAbility
+			}
+		}
+		added = added || !known
+		normalized = append(normalized, entry)
+		total += cl.Level
+	}
+	if len(normalized) == 0 || total > MaxLevel {
+		return ErrInvalidClasses
+	}
+	if len(normalized) > 1 && added {
+		for _, cl := range normalized {
+			if !c.MeetsMulticlassPrerequisites(cl.Class) {
+				return ErrMulticlassPrerequisite
+			}
+		}
+	}
+
+	fallbackDie := c.HitDie()
+	c.Classes = normalized
+	c.Class = normalized[0].Class
+	c.Level = total
+	c.HitDice = hitDiceExpression(c.hitDicePool(fallbackDie))
+	return nil
+}
+
+// canonicalClass trims a class name and matches it to a known class regardless of case.
+func canonicalClass(name string) string {
+	name = strings.TrimSpace(name)
+	for class := range ClassHitDice {
+		if strings.EqualFold(class, name) {
+			return class
+		}
+	}
+	return name
+}
+
+// classHitDie returns the size of a class's hit die, as copied from the rules catalog or
+// else from the SRD. An unknown starting class uses the recorded hit dice; any other
+// unknown class uses a d8.
+func (c *CharacterWithStats) classHitDie(class string) int {
+	for _, cl := range c.classLevels() {
+		if cl.Class == class && cl.HitDie > 0 {
+			return int(cl.HitDie)
+		}
+	}
+	if die, ok := ClassHitDice[class]; ok {
+		return die
+	}
+	if class == c.Class {
+		return c.HitDie()
+	}
+	return 8
+}
+
+// hitDicePool lists every hit die the character has, one per level, largest first. Hit
+// dice are spent largest first, so the first HitDiceSpent entries are the spent ones.
+// fallbackDie is used for classes without a known hit die.
+func (c *CharacterWithStats) hitDicePool(fallbackDie int) []int {
+	var pool []int
+	for _, cl := range c.classLevels() {
+		die := int(cl.HitDie)
+		if die == 0 {
+			var ok bool
+			if die, ok = ClassHitDice[cl.Class]; !ok {
+				die = fallbackDie
+			}
+		}
+		for range cl.Level {
+			pool = append(pool, die)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(pool)))
+	return pool
+}
+
+// hitDiceExpression groups dice by size, largest first, e.g. "2d10+3d6".
+func hitDiceExpression(dice []int) string {
+	var parts []string
+	for i := 0; i < len(dice); {
+		j := i
+		for j < len(dice) && dice[j] == dice[i] {
+			j++
+		}
+		parts = append(parts, fmt.Sprintf("%dd%d", j-i, dice[i]))
+		i = j
+	}
+	return strings.Join(parts, "+")
+}
+
+// classLabel names a character's classes: the class alone for a single class, or each
+// class with its level, e.g. "Fighter 2 / Wizard 3".
+func classLabel(classes []CharacterClass) string {
+	if len(classes) == 1 {
+		return classes[0].Class
+	}
+	parts := make([]string, 0, len(classes))
+	for _, cl := range classes {
+		parts = append(parts, fmt.Sprintf("%s %d", cl.Class, cl.Level))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// syncClasses replaces the character's stored class levels when they differ from classes.
+func syncClasses(ctx context.Context, q *Queries, characterID int64, classes []CharacterClass) error {
+	existing, err := q.ListCharacterClasses(ctx, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to list classes: %w", err)
+	}
+	if len(existing) == len(classes) {
+		same := true
+		for i := range classes {
+			same = same && existing[i].Class == classes[i].Class && existing[i].Level == classes[i].Level &&
+				existing[i].Subclass == classes[i].Subclass && existing[i].HitDie == classes[i].HitDie &&
+				existing[i].SpellcastingAbility == classes[i].SpellcastingAbility
+		}
+		if same {
+			return nil
+		}
+	}
+
+	if err := q.DeleteCharacterClasses(ctx, characterID); err != nil {
+		return fmt.Errorf("failed to clear classes: %w", err)
+	}
+	for i, cl := range classes {
+		if err := q.InsertCharacterClass(ctx, InsertCharacterClassParams{
+			CharacterID:         characterID,
+			Class:               cl.Class,
+			Level:               cl.Level,
+			Position:            int64(i),
+			Subclass:            cl.Subclass,
+			HitDie:              cl.HitDie,
+			SpellcastingAbility: cl.SpellcastingAbility,
+		}); err != nil {
+			return fmt.Errorf("failed to save class: %w", err)
+		}
+	}
+	return nil
+}
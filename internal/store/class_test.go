@@ -0,0 +1,173 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+func TestMulticlassLevelUpAndDerivedStats(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("dabbler", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Class = "Paladin"
+	c.Level = 4
+	c.Strength = 15
+	c.Charisma = 13
+	c.Intelligence = 12
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	if len(c.Classes) != 1 || c.HitDice != "4d10" || c.ClassLabel != "Paladin" {
+		t.Fatalf("single class = %+v, %s", c.Classes, c.HitDice)
+	}
+
+	// Wizard needs Intelligence 13, so the first attempt is refused.
+	if _, err := s.LevelUp(c, LevelUpChoice{Class: "wizard"}, dice.NewSeededRNG(1)); err != ErrMulticlassPrerequisite {
+		t.Fatalf("expected ErrMulticlassPrerequisite, got %v", err)
+	}
+	// Edits can't raise ability scores.
+	c.Intelligence = 13
+	if err := s.UpdateCharacter(c); err != ErrAbilityScoresLocked {
+		t.Fatalf("expected ErrAbilityScoresLocked, got %v", err)
+	}
+	if _, err := s.db.Exec(`UPDATE characters SET intelligence = 13 WHERE id = ?`, c.ID); err != nil {
+		t.Fatalf("raise intelligence: %v", err)
+	}
+	c, _ = s.GetCharacter(c.ID, user.ID)
+	result, err := s.LevelUp(c, LevelUpChoice{Class: "wizard"}, dice.NewSeededRNG(1))
+	if err != nil {
+		t.Fatalf("multiclass level up: %v", err)
+	}
+	if result.HitDie != 6 || result.LevelUp.Class != "Wizard" {
+		t.Fatalf("level up = %+v", result)
+	}
+	// Total level 5 is an ability score improvement for a single class, but not for
+	// a first level of wizard.
+	got := result.Character
+	if got.Level != 5 || got.Class != "Paladin" || got.HitDice != "4d10+1d6" || got.ProficiencyBonus != 3 {
+		t.Fatalf("character = level %d %s %s +%d", got.Level, got.Class, got.HitDice, got.ProficiencyBonus)
+	}
+	if got.ClassLabel != "Paladin 4 / Wizard 1" {
+		t.Fatalf("label = %q", got.ClassLabel)
+	}
+
+	// Paladin 4 counts as caster level 2 and wizard 1 as 1, for four 1st and two 2nd level slots.
+	book, err := s.GetSpellbook(got)
+	if err != nil {
+		t.Fatalf("spellbook: %v", err)
+	}
+	if book.Slots[0].Max != 4 || book.Slots[1].Max != 2 || book.Slots[2].Max != 0 || book.PactSlots != nil {
+		t.Fatalf("slots = %+v", book.Slots[:3])
+	}
+
+	// Short rests spend the d10s before the d6.
+	rest, err := s.Rest(got, RestShort, 1, dice.NewSeededRNG(2))
+	if err != nil {
+		t.Fatalf("short rest: %v", err)
+	}
+	if rest.Roll.Expression != "1d10+1" {
+		t.Fatalf("rest roll = %s", rest.Roll.Expression)
+	}
+
+	campaign, err := s.CreateCampaign(user.ID, "Table", "", "private", "")
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	if _, err := s.AddCharacterToCampaign(campaign.ID, c.ID, user.ID); err != nil {
+		t.Fatalf("add character: %v", err)
+	}
+	details, err := s.ListCampaignDetails(user.ID)
+	if err != nil {
+		t.Fatalf("campaign details: %v", err)
+	}
+	if summary := details[0].Characters[0]; summary.CharacterClass != "Paladin 4 / Wizard 1" || summary.CharacterLevel != 5 {
+		t.Fatalf("summary = %+v", summary)
+	}
+}
+
+func TestSetClassesValidation(t *testing.T) {
+	c := newTestCharacter()
+	cases := []struct {
+		classes []CharacterClass
+		want    error
+	}{
+		{[]CharacterClass{{Class: "Rogue", Level: 0}}, ErrInvalidClasses},
+		{[]CharacterClass{{Class: "Rogue", Level: 3}, {Class: "rogue", Level: 1}}, ErrInvalidClasses},
+		{[]CharacterClass{{Class: "Rogue", Level: 15}, {Class: "Wizard", Level: 6}}, ErrInvalidClasses},
+		// Strength 8 rules out barbarian, even though Dexterity allows rogue.
+		{[]CharacterClass{{Class: "Rogue", Level: 3}, {Class: "Barbarian", Level: 1}}, ErrMulticlassPrerequisite},
+		{[]CharacterClass{{Class: "rogue", Level: 3}, {Class: "Wizard", Level: 2}}, nil},
+	}
+	for _, tc := range cases {
+		if err := c.setClasses(tc.classes, nil); err != tc.want {
+			t.Fatalf("%+v: expected %v, got %v", tc.classes, tc.want, err)
+		}
+	}
+	if c.Class != "Rogue" || c.Level != 5 || c.HitDice != "3d8+2d6" {
+		t.Fatalf("character = %s %d %s", c.Class, c.Level, c.HitDice)
+	}
+
+	// Classes the character already has are kept even without the prerequisites.
+	c.Intelligence = 8
+	if err := c.setClasses([]CharacterClass{{Class: "Rogue", Level: 3}, {Class: "Wizard", Level: 3}}, c.Classes); err != nil {
+		t.Fatalf("existing multiclass: %v", err)
+	}
+}
+
+func TestMulticlassSpellcastingAndLevelEdits(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("battlemage", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Strength = 13
+	c.Intelligence = 16
+	c.Classes = []CharacterClass{{Class: "Fighter", Level: 2}, {Class: "Wizard", Level: 3}}
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	// The wizard levels cast with Intelligence even though Fighter is the starting class.
+	if c.SpellcastingAbility != "intelligence" || c.SpellSaveDC == nil || *c.SpellSaveDC != 8+3+3 {
+		t.Fatalf("spellcasting = %q DC %v", c.SpellcastingAbility, c.SpellSaveDC)
+	}
+	if len(c.Spellcasting) != 1 || c.Spellcasting[0].Class != "Wizard" || c.Spellcasting[0].SpellAttackBonus != 6 {
+		t.Fatalf("class spellcasting = %+v", c.Spellcasting)
+	}
+
+	// Levels and hit points only change by levelling up.
+	c.Classes = nil
+	c.Level = 6
+	if err := s.UpdateCharacter(c); err != ErrLevelUpRequired {
+		t.Fatalf("level edit: expected ErrLevelUpRequired, got %v", err)
+	}
+	c.Level = 5
+	c.MaxHp++
+	if err := s.UpdateCharacter(c); err != ErrLevelUpRequired {
+		t.Fatalf("hit point edit: expected ErrLevelUpRequired, got %v", err)
+	}
+	c.MaxHp--
+	c.Classes = []CharacterClass{{Class: "Fighter", Level: 3}, {Class: "Wizard", Level: 2}}
+	if err := s.UpdateCharacter(c); err != ErrLevelUpRequired {
+		t.Fatalf("class level edit: expected ErrLevelUpRequired, got %v", err)
+	}
+	c.Classes = nil
+	c.Name = "Renamed"
+	if err := s.UpdateCharacter(c); err != nil {
+		t.Fatalf("update name: %v", err)
+	}
+	if c.ClassLabel != "Fighter 2 / Wizard 3" || c.HitDice != "2d10+3d6" {
+		t.Fatalf("after edit = %s, %s", c.ClassLabel, c.HitDice)
+	}
+
+	c.Classes = nil
+	c.Class = "Wizard"
+	if err := s.UpdateCharacter(c); err != ErrClassesRequired {
+		t.Fatalf("expected ErrClassesRequired, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+// ConditionExhaustion is the one condition with levels, from 1 to MaxExhaustion.
+const ConditionExhaustion = "exhaustion"
+
+// MaxExhaustion is the exhaustion level at which a character dies.
+const MaxExhaustion = 6
+
+// Condition duration units.
+const (
+	DurationRounds  = "rounds"
+	DurationMinutes = "minutes"
+)
+
+// Conditions are the conditions a character can carry.
+var Conditions = []string{
+	"blinded", "charmed", "deafened", ConditionExhaustion, "frightened", "grappled",
+	"incapacitated", "invisible", "paralyzed", "petrified", "poisoned", "prone",
+	"restrained", "stunned", "unconscious",
+}
+
+// speedZeroConditions leave a character unable to move.
+var speedZeroConditions = map[string]bool{
+	"grappled":    true,
+	"paralyzed":   true,
+	"petrified":   true,
+	"restrained":  true,
+	"unconscious": true,
+}
+
+// ListCharacterConditions returns the character's conditions in name order.
+func (s *Store) ListCharacterConditions(characterID int64) ([]CharacterCondition, error) {
+	conditions, err := s.q.ListCharacterConditions(context.Background(), characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conditions: %w", err)
+	}
+	if conditions == nil {
+		conditions = []CharacterCondition{}
+	}
+	return conditions, nil
+}
+
+// SetCharacterCondition applies a condition to the character, replacing the level,
+// source and duration if the character already has it. Exhaustion defaults to level 1.
+func (s *Store) SetCharacterCondition(cond CharacterCondition) (*CharacterCondition, error) {
+	if err := validateCondition(&cond); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.q.UpsertCharacterCondition(context.Background(), UpsertCharacterConditionParams{
+		CharacterID:  cond.CharacterID,
+		Name:         cond.Name,
+		Level:        cond.Level,
+		Source:       cond.Source,
+		Duration:     cond.Duration,
+		DurationUnit: cond.DurationUnit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save condition: %w", err)
+	}
+	if err := markCharacterChanged(context.Background(), s.q, cond.CharacterID); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteCharacterCondition removes a condition from the character.
+func (s *Store) DeleteCharacterCondition(characterID, conditionID int64) error {
+	rows, err := s.q.DeleteCharacterCondition(context.Background(), DeleteCharacterConditionParams{ID: conditionID, CharacterID: characterID})
+	if err != nil {
+		return fmt.Errorf("failed to delete condition: %w", err)
+	}
+	if rows == 0 {
+		return ErrConditionNotFound
+	}
+	return markCharacterChanged(context.Background(), s.q, characterID)
+}
+
+// validateCondition normalises the name and checks the level and duration.
+func validateCondition(cond *CharacterCondition) error {
+	cond.Name = strings.ToLower(strings.TrimSpace(cond.Name))
+	if !containsKey(Conditions, cond.Name) {
+		return ErrInvalidCondition
+	}
+	if cond.Name == ConditionExhaustion {
+		if cond.Level == 0 {
+			cond.Level = 1
+		}
+		if cond.Level < 1 || cond.Level > MaxExhaustion {
+			return ErrInvalidCondition
+		}
+	} else if cond.Level != 0 {
+		return ErrInvalidCondition
+	}
+
+	cond.Source = strings.TrimSpace(cond.Source)
+	cond.DurationUnit = strings.ToLower(cond.DurationUnit)
+	if cond.Duration == nil {
+		if cond.DurationUnit != "" {
+			return ErrInvalidCondition
+		}
+		return nil
+	}
+	if *cond.Duration <= 0 || (cond.DurationUnit != DurationRounds && cond.DurationUnit != DurationMinutes) {
+		return ErrInvalidCondition
+	}
+	return nil
+}
+
+// applyConditions works out the exhaustion level and speed from the character's
+// conditions. Each level of exhaustion reduces speed by 5 feet, and conditions such as
+// grappled or restrained reduce it to 0.
+func (c *CharacterWithStats) applyConditions() {
+	if c.Conditions == nil {
+		c.Conditions = []CharacterCondition{}
+	}
+
+	c.Exhaustion = 0
+	c.CurrentSpeed = int(c.Speed)
+	for _, cond := range c.Conditions {
+		if cond.Name == ConditionExhaustion {
+			c.Exhaustion = int(cond.Level)
+		}
+		if speedZeroConditions[cond.Name] {
+			c.CurrentSpeed = 0
+		}
+	}
+	c.CurrentSpeed = max(0, c.CurrentSpeed-5*c.Exhaustion)
+}
+
+// attachTokenConditions fills in each token's conditions from the character it links to.
+// Tokens without a character get an empty list.
+func (s *Store) attachTokenConditions(ctx context.Context, tokens ...*models.Token) error {
+	var characterIDs []int64
+	for _, t := range tokens {
+		if t.CharacterID != nil {
+			characterIDs = append(characterIDs, *t.CharacterID)
+		}
+	}
+
+	byCharacter := make(map[int64][]models.Condition)
+	if len(characterIDs) > 0 {
+		rows, err := s.q.ListCharacterConditionsByCharacterIDs(ctx, characterIDs)
+		if err != nil {
+			return fmt.Errorf("failed to list token conditions: %w", err)
+		}
+		for _, row := range rows {
+			byCharacter[row.CharacterID] = append(byCharacter[row.CharacterID], models.Condition{
+				Name:         row.Name,
+				Level:        int(row.Level),
+				Source:       row.Source,
+				Duration:     row.Duration,
+				DurationUnit: row.DurationUnit,
+			})
+		}
+	}
+
+	for _, t := range tokens {
+		t.Conditions = []models.Condition{}
+		if t.CharacterID != nil && byCharacter[*t.CharacterID] != nil {
+			t.Conditions = byCharacter[*t.CharacterID]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+func TestConditionsAffectDerivedStats(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("weary", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	stealth := c.SkillBonuses["stealth"]
+
+	bad := []CharacterCondition{
+		{Name: "sleepy"},
+		{Name: "exhaustion", Level: 7},
+		{Name: "poisoned", Level: 2},
+		{Name: "poisoned", Duration: ptr(int64(3))},
+		{Name: "poisoned", DurationUnit: DurationRounds},
+	}
+	for _, cond := range bad {
+		cond.CharacterID = c.ID
+		if _, err := s.SetCharacterCondition(cond); err != ErrInvalidCondition {
+			t.Fatalf("%+v: expected ErrInvalidCondition, got %v", cond, err)
+		}
+	}
+
+	poisoned, err := s.SetCharacterCondition(CharacterCondition{
+		CharacterID:  c.ID,
+		Name:         "Poisoned",
+		Source:       "Giant spider bite",
+		Duration:     ptr(int64(10)),
+		DurationUnit: DurationMinutes,
+	})
+	if err != nil {
+		t.Fatalf("set poisoned: %v", err)
+	}
+	if poisoned.Name != "poisoned" || *poisoned.Duration != 10 {
+		t.Fatalf("poisoned = %+v", poisoned)
+	}
+	if _, err := s.SetCharacterCondition(CharacterCondition{CharacterID: c.ID, Name: ConditionExhaustion}); err != nil {
+		t.Fatalf("set exhaustion: %v", err)
+	}
+	// Applying exhaustion again replaces the level rather than adding a second row.
+	if _, err := s.SetCharacterCondition(CharacterCondition{CharacterID: c.ID, Name: ConditionExhaustion, Level: 2}); err != nil {
+		t.Fatalf("raise exhaustion: %v", err)
+	}
+
+	got, err := s.GetCharacter(c.ID, user.ID)
+	if err != nil {
+		t.Fatalf("get character: %v", err)
+	}
+	if len(got.Conditions) != 2 || got.Exhaustion != 2 || got.CurrentSpeed != 20 {
+		t.Fatalf("conditions %+v, exhaustion %d, speed %d", got.Conditions, got.Exhaustion, got.CurrentSpeed)
+	}
+	if got.SkillBonuses["stealth"] != stealth-4 {
+		t.Fatalf("stealth = %d, want %d", got.SkillBonuses["stealth"], stealth-4)
+	}
+
+	grappled, err := s.SetCharacterCondition(CharacterCondition{CharacterID: c.ID, Name: "grappled"})
+	if err != nil {
+		t.Fatalf("set grappled: %v", err)
+	}
+	if got, _ = s.GetCharacter(c.ID, user.ID); got.CurrentSpeed != 0 {
+		t.Fatalf("grappled speed = %d", got.CurrentSpeed)
+	}
+	if err := s.DeleteCharacterCondition(c.ID, grappled.ID); err != nil {
+		t.Fatalf("delete condition: %v", err)
+	}
+	if err := s.DeleteCharacterCondition(c.ID, grappled.ID); err != ErrConditionNotFound {
+		t.Fatalf("expected ErrConditionNotFound, got %v", err)
+	}
+
+	// A long rest removes one level of exhaustion, and the last level removes the condition.
+	for _, want := range []int{1, 0} {
+		rest, err := s.Rest(got, RestLong, 0, dice.NewSeededRNG(1))
+		if err != nil {
+			t.Fatalf("long rest: %v", err)
+		}
+		got = rest.Character
+		if got.Exhaustion != want {
+			t.Fatalf("exhaustion after rest = %d, want %d", got.Exhaustion, want)
+		}
+	}
+	if len(got.Conditions) != 1 || got.CurrentSpeed != 30 {
+		t.Fatalf("conditions after rests = %+v", got.Conditions)
+	}
+
+	campaign, err := s.CreateCampaign(user.ID, "Table", "", "private", "")
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	sceneID, err := s.ensureDefaultScene(campaign.ID, user.ID)
+	if err != nil {
+		t.Fatalf("create scene: %v", err)
+	}
+	res, err := s.db.Exec(`INSERT INTO maps (scene_id, name, width_px, height_px) VALUES (?, 'Cave', 1000, 1000)`, sceneID)
+	if err != nil {
+		t.Fatalf("create map: %v", err)
+	}
+	mapID, _ := res.LastInsertId()
+	if _, err := s.db.Exec(`INSERT INTO tokens (map_id, character_id, label) VALUES (?, ?, 'Vex'), (?, NULL, 'Crate')`, mapID, c.ID, mapID); err != nil {
+		t.Fatalf("create tokens: %v", err)
+	}
+	full, err := s.GetCampaignFull(campaign.ID, user.ID)
+	if err != nil {
+		t.Fatalf("campaign full: %v", err)
+	}
+	tokens := full.Scenes[0].Maps[0].Tokens
+	if len(tokens) != 2 || len(tokens[0].Conditions) != 1 || tokens[0].Conditions[0].Source != "Giant spider bite" || len(tokens[1].Conditions) != 0 {
+		t.Fatalf("scene tokens = %+v", tokens)
+	}
+}
+
+func TestExhaustionSixIsDeath(t *testing.T) {
+	c := newTestCharacter()
+	c.Conditions = []CharacterCondition{{Name: ConditionExhaustion, Level: MaxExhaustion}}
+	c.ComputeModifiers()
+	if c.Status != StatusDead || c.CurrentSpeed != 0 {
+		t.Fatalf("status %s speed %d", c.Status, c.CurrentSpeed)
+	}
+}
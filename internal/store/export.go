@@ -0,0 +1,365 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExportSchemaVersion is the version of the character export document. Documents from
+// older versions are migrated when they are imported.
+const ExportSchemaVersion = 1
+
+// maxImportedAbilityScore is the highest ability score an imported character can have.
+const maxImportedAbilityScore = 30
+
+// CharacterExport is a self-contained character document for moving a character between
+// instances. Character follows CharacterModel; its IDs, owner and avatar URL are replaced
+// on import. Item IDs are only used to keep items in their containers.
+type CharacterExport struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	ExportedAt    time.Time            `json:"exportedAt"`
+	Character     CharacterModel       `json:"character"`
+	Classes       []ClassLevel         `json:"classes"`
+	Items         []CharacterItem      `json:"items"`
+	Coins         CharacterCoin        `json:"coins"`
+	Spells        []CharacterSpell     `json:"spells"`
+	Resources     []CharacterResource  `json:"resources"`
+	Attacks       []CharacterAttack    `json:"attacks"`
+	Conditions    []CharacterCondition `json:"conditions"`
+	Avatar        *ExportedAvatar      `json:"avatar,omitempty"`
+}
+
+// ExportedAvatar is an avatar image embedded in an export. Data is base64 in JSON.
+type ExportedAvatar struct {
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"data"`
+}
+
+// exportMigrations upgrade an export document, decoded as generic JSON, from the version
+// at its index to the next one.
+var exportMigrations = []func(doc map[string]any) error{
+	migrateExportV0,
+}
+
+// ExportCharacter builds the export document for a loaded character. The avatar is
+// left for the caller to embed as it lives outside the database.
+func (s *Store) ExportCharacter(c *CharacterWithStats) (*CharacterExport, error) {
+	ctx := context.Background()
+
+	spells, err := s.q.ListCharacterSpells(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spells: %w", err)
+	}
+	coins, err := s.characterCoins(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &CharacterExport{
+		SchemaVersion: ExportSchemaVersion,
+		ExportedAt:    time.Now().UTC(),
+		Character:     c.CharacterModel,
+		Classes:       c.classLevelList(),
+		Items:         c.items,
+		Coins:         coins,
+		Spells:        spells,
+		Resources:     make([]CharacterResource, 0, len(c.Resources)),
+		Attacks:       make([]CharacterAttack, 0, len(c.Attacks)),
+		Conditions:    c.Conditions,
+	}
+	for _, res := range c.Resources {
+		doc.Resources = append(doc.Resources, res.CharacterResource)
+	}
+	for _, attack := range c.Attacks {
+		doc.Attacks = append(doc.Attacks, attack.CharacterAttack)
+	}
+	if doc.Items == nil {
+		doc.Items = []CharacterItem{}
+	}
+	if doc.Spells == nil {
+		doc.Spells = []CharacterSpell{}
+	}
+	if doc.Conditions == nil {
+		doc.Conditions = []CharacterCondition{}
+	}
+	return doc, nil
+}
+
+// ParseCharacterExport decodes an export document, migrating it from older schema
+// versions. A document without a schema version is taken to be a character as returned
+// by GET /api/characters/{id}.
+func ParseCharacterExport(data []byte) (*CharacterExport, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+
+	version := 0
+	if v, ok := doc["schemaVersion"].(float64); ok {
+		version = int(v)
+	}
+	if version < 0 || version > ExportSchemaVersion {
+		return nil, ErrUnsupportedExportVersion
+	}
+	for ; version < ExportSchemaVersion; version++ {
+		if err := exportMigrations[version](doc); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+		}
+		doc["schemaVersion"] = version + 1
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	var export CharacterExport
+	if err := json.Unmarshal(migrated, &export); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	if strings.TrimSpace(export.Character.Name) == "" {
+		return nil, fmt.Errorf("%w: character needs a name", ErrInvalidExport)
+	}
+	return &export, nil
+}
+
+// migrateExportV0 wraps a character from GET /api/characters/{id} in a version 1 document.
+// Its equipment names become single items; coins and spells are not part of it.
+func migrateExportV0(doc map[string]any) error {
+	character := make(map[string]any, len(doc))
+	for k, v := range doc {
+		character[k] = v
+	}
+	doc["character"] = character
+
+	var items []any
+	equipment, _ := doc["equipment"].([]any)
+	for _, name := range equipment {
+		if name, ok := name.(string); ok {
+			items = append(items, map[string]any{"name": name, "quantity": 1})
+		}
+	}
+	doc["items"] = items
+	return nil
+}
+
+// ImportCharacter creates a character for the user from an export document. Everything
+// on the sheet is kept as exported, including homebrew species and classes this
+// instance does not know, as long as its scores, hit points and levels are in bounds;
+// items, spells, resources, attacks and conditions are checked as if they were added one
+// by one. The scores are recorded as imported so the GM's audit shows the character.
+func (s *Store) ImportCharacter(userID int64, doc *CharacterExport) (*CharacterWithStats, error) {
+	ctx := context.Background()
+
+	c := &CharacterWithStats{CharacterModel: doc.Character}
+	c.ID = 0
+	c.UserID = userID
+	c.AvatarUrl = ""
+	classes := make([]CharacterClass, 0, len(doc.Classes))
+	for _, cl := range doc.Classes {
+		classes = append(classes, CharacterClass{Class: cl.Class, Level: cl.Level, Subclass: cl.Subclass})
+	}
+	if len(classes) == 0 {
+		classes = []CharacterClass{{Class: c.Class, Level: c.Level}}
+	}
+	// Every class counts as already known so that multiclass prerequisites, met when the
+	// character took the classes, are not checked again.
+	if err := c.setClasses(classes, classes); err != nil {
+		return nil, err
+	}
+	base := make(map[string]int, len(Abilities))
+	for _, ability := range Abilities {
+		score := *c.abilityScore(ability)
+		if score < 1 || score > maxImportedAbilityScore {
+			return nil, ErrInvalidImportedStats
+		}
+		base[ability] = int(score)
+	}
+	if c.MaxHp < 1 || c.CurrentHp < 0 || c.CurrentHp > c.MaxHp || c.TempHp < 0 ||
+		c.HitDiceSpent < 0 || c.HitDiceSpent > c.Level {
+		return nil, ErrInvalidImportedStats
+	}
+	c.ComputeModifiers()
+
+	for i := range doc.Items {
+		item := &doc.Items[i]
+		item.Name = strings.TrimSpace(item.Name)
+		if item.Name == "" || item.Quantity < 0 || item.Weight < 0 || item.ValueCp < 0 {
+			return nil, ErrInvalidItem
+		}
+		if err := validateArmor(item); err != nil {
+			return nil, err
+		}
+	}
+	for i := range doc.Spells {
+		if err := validateSpell(&doc.Spells[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range doc.Resources {
+		if err := c.validateResource(&doc.Resources[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range doc.Attacks {
+		if err := validateAttack(&doc.Attacks[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range doc.Conditions {
+		if err := validateCondition(&doc.Conditions[i]); err != nil {
+			return nil, err
+		}
+	}
+	coins := doc.Coins
+	if coins.Cp < 0 || coins.Sp < 0 || coins.Ep < 0 || coins.Gp < 0 || coins.Pp < 0 {
+		return nil, ErrInvalidCoins
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	inserted, err := qtx.InsertCharacter(ctx, c.ToInsertParams())
+	if err != nil {
+		return nil, fmt.Errorf("failed to import character: %w", err)
+	}
+	id := inserted.ID
+	if err := syncClasses(ctx, qtx, id, c.Classes); err != nil {
+		return nil, err
+	}
+	if err := saveAbilityScores(ctx, qtx, id, &AbilityScoreGeneration{
+		Method:    AbilityMethodImported,
+		Base:      base,
+		Increases: map[string]int{},
+	}); err != nil {
+		return nil, err
+	}
+	if err := importItems(ctx, qtx, id, doc.Items); err != nil {
+		return nil, err
+	}
+	if err := qtx.UpsertCharacterCoins(ctx, UpsertCharacterCoinsParams{
+		CharacterID: id, Cp: coins.Cp, Sp: coins.Sp, Ep: coins.Ep, Gp: coins.Gp, Pp: coins.Pp,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to import coins: %w", err)
+	}
+	for _, spell := range doc.Spells {
+		if _, err := qtx.InsertCharacterSpell(ctx, InsertCharacterSpellParams{
+			CharacterID: id,
+			Name:        spell.Name,
+			Level:       spell.Level,
+			School:      spell.School,
+			Prepared:    spell.Prepared,
+			Notes:       spell.Notes,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to import spell: %w", err)
+		}
+	}
+	for _, res := range doc.Resources {
+		if _, err := qtx.InsertCharacterResource(ctx, InsertCharacterResourceParams{
+			CharacterID: id,
+			Name:        res.Name,
+			MaxFormula:  res.MaxFormula,
+			Used:        res.Used,
+			Reset:       res.Reset,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to import resource: %w", err)
+		}
+	}
+	for _, attack := range doc.Attacks {
+		if _, err := qtx.InsertCharacterAttack(ctx, InsertCharacterAttackParams{
+			CharacterID:   id,
+			Name:          attack.Name,
+			Kind:          attack.Kind,
+			Ability:       attack.Ability,
+			Proficient:    attack.Proficient,
+			DamageDice:    attack.DamageDice,
+			VersatileDice: attack.VersatileDice,
+			DamageType:    attack.DamageType,
+			Properties:    attack.Properties,
+			Bonus:         attack.Bonus,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to import attack: %w", err)
+		}
+	}
+	for _, cond := range doc.Conditions {
+		if _, err := qtx.UpsertCharacterCondition(ctx, UpsertCharacterConditionParams{
+			CharacterID:  id,
+			Name:         cond.Name,
+			Level:        cond.Level,
+			Source:       cond.Source,
+			Duration:     cond.Duration,
+			DurationUnit: cond.DurationUnit,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to import condition: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+
+	c.CharacterModel = characterToModel(inserted)
+	if err := s.attachDetails(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// importItems inserts exported items, containers before their contents, mapping the
+// exported container IDs to the new ones. Items whose container is missing or not a
+// container, including containers inside each other, are rejected.
+func importItems(ctx context.Context, q *Queries, characterID int64, items []CharacterItem) error {
+	newIDs := make(map[int64]int64, len(items))
+	attuned := 0
+	pending := items
+	for len(pending) > 0 {
+		var waiting []CharacterItem
+		for _, item := range pending {
+			var containerID *int64
+			if item.ContainerID != nil {
+				id, ok := newIDs[*item.ContainerID]
+				if !ok {
+					waiting = append(waiting, item)
+					continue
+				}
+				containerID = &id
+			}
+			if item.Attuned {
+				if attuned++; attuned > MaxAttunedItems {
+					return ErrAttunementLimit
+				}
+			}
+			inserted, err := q.InsertCharacterItem(ctx, InsertCharacterItemParams{
+				CharacterID: characterID,
+				ContainerID: containerID,
+				Name:        item.Name,
+				Quantity:    item.Quantity,
+				Weight:      item.Weight,
+				ValueCp:     item.ValueCp,
+				Equipped:    item.Equipped,
+				Attuned:     item.Attuned,
+				IsContainer: item.IsContainer,
+				ArmorType:   item.ArmorType,
+				ArmorClass:  item.ArmorClass,
+				Notes:       item.Notes,
+				Position:    item.Position,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to import item: %w", err)
+			}
+			if item.IsContainer {
+				newIDs[item.ID] = inserted.ID
+			}
+		}
+		if len(waiting) == len(pending) {
+			return ErrInvalidContainer
+		}
+		pending = waiting
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExportImportCharacter(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	from, _ := s.CreateUser("from", "hash")
+	to, _ := s.CreateUser("to", "hash")
+	c := newTestCharacter()
+	c.UserID = from.ID
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	pack, err := s.AddCharacterItem(CharacterItem{CharacterID: c.ID, Name: "Backpack", Quantity: 1, IsContainer: true})
+	if err != nil {
+		t.Fatalf("add backpack: %v", err)
+	}
+	if _, err := s.AddCharacterItem(CharacterItem{CharacterID: c.ID, ContainerID: &pack.ID, Name: "Rope", Quantity: 1, Weight: 5}); err != nil {
+		t.Fatalf("add rope: %v", err)
+	}
+	if _, err := s.AddCharacterSpell(CharacterSpell{CharacterID: c.ID, Name: "Minor Illusion", Level: 0}); err != nil {
+		t.Fatalf("add spell: %v", err)
+	}
+	if _, err := s.SetCharacterCoins(CharacterCoin{CharacterID: c.ID, Gp: 15}); err != nil {
+		t.Fatalf("set coins: %v", err)
+	}
+	c, _ = s.GetCharacter(c.ID, from.ID)
+	if _, err := s.AddCharacterResource(c, CharacterResource{Name: "Luck", MaxFormula: "prof", Reset: ResetLongRest}); err != nil {
+		t.Fatalf("add resource: %v", err)
+	}
+	c, _ = s.GetCharacter(c.ID, from.ID)
+
+	doc, err := s.ExportCharacter(c)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	data, _ := json.Marshal(doc)
+	parsed, err := ParseCharacterExport(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	imported, err := s.ImportCharacter(to.ID, parsed)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if imported.ID == c.ID || imported.UserID != to.ID || imported.Name != c.Name || imported.Dexterity != c.Dexterity ||
+		imported.Features != c.Features || len(imported.Resources) != 1 || imported.CarriedWeight != c.CarriedWeight {
+		t.Fatalf("imported = %+v", imported)
+	}
+	items, err := s.GetInventory(imported)
+	if err != nil {
+		t.Fatalf("inventory: %v", err)
+	}
+	if len(items.Items) != 2 || items.Coins.Gp != 15 {
+		t.Fatalf("items = %+v", items)
+	}
+	for _, item := range items.Items {
+		if item.Name == "Rope" && (item.ContainerID == nil || *item.ContainerID == pack.ID) {
+			t.Fatalf("rope container = %v", item.ContainerID)
+		}
+	}
+
+	record, err := s.GetAbilityScores(imported.ID)
+	if err != nil || record.Method != AbilityMethodImported || record.Base["dexterity"] != int(c.Dexterity) {
+		t.Fatalf("ability scores = %+v, %v", record, err)
+	}
+
+	// Scores, hit points and hit dice out of bounds are rejected.
+	for name, change := range map[string]func(*CharacterExport){
+		"score":      func(d *CharacterExport) { d.Character.Strength = 31 },
+		"zero score": func(d *CharacterExport) { d.Character.Wisdom = 0 },
+		"max hp":     func(d *CharacterExport) { d.Character.MaxHp = 0 },
+		"current hp": func(d *CharacterExport) { d.Character.CurrentHp = d.Character.MaxHp + 1 },
+		"temp hp":    func(d *CharacterExport) { d.Character.TempHp = -1 },
+		"hit dice":   func(d *CharacterExport) { d.Character.HitDiceSpent = 2 },
+	} {
+		bad, _ := ParseCharacterExport(data)
+		change(bad)
+		if _, err := s.ImportCharacter(to.ID, bad); err != ErrInvalidImportedStats {
+			t.Errorf("%s: expected ErrInvalidImportedStats, got %v", name, err)
+		}
+	}
+}
+
+func TestParseCharacterExportVersions(t *testing.T) {
+	// A character as returned by the API, before export documents were versioned.
+	v0 := `{"name": "Old Timer", "race": "Human", "class": "Fighter", "level": 2, "strength": 16,
+		"classes": [{"class": "Fighter", "level": 2}], "equipment": ["Longsword", "Shield"]}`
+	doc, err := ParseCharacterExport([]byte(v0))
+	if err != nil {
+		t.Fatalf("parse v0: %v", err)
+	}
+	if doc.SchemaVersion != ExportSchemaVersion || doc.Character.Name != "Old Timer" || doc.Character.Strength != 16 ||
+		len(doc.Items) != 2 || doc.Items[1].Name != "Shield" || doc.Classes[0].Level != 2 {
+		t.Fatalf("migrated = %+v", doc)
+	}
+
+	if _, err := ParseCharacterExport([]byte(`{"schemaVersion": 99, "character": {"name": "Future"}}`)); err != ErrUnsupportedExportVersion {
+		t.Fatalf("expected ErrUnsupportedExportVersion, got %v", err)
+	}
+	if _, err := ParseCharacterExport([]byte(`{"schemaVersion": 1, "character": {}}`)); err == nil {
+		t.Fatal("expected an error for a character without a name")
+	}
+}
@@ -0,0 +1,221 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/geometry"
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+// maxFogCells and maxFogPoints limit the size of a single fog change. maxFogRegionPoints
+// limits the revealed polygons kept for a map, as merging a change costs the square of
+// their points.
+const (
+	maxFogCells        = 10000
+	maxFogPoints       = 1000
+	maxFogRegionPoints = 2000
+)
+
+// FogChange is an area of a map to reveal or hide: either grid cells or a polygon, in
+// grid squares.
+type FogChange struct {
+	Cells   []models.FogCell
+	Polygon geometry.Ring
+}
+
+// mode returns the fog mode the change applies to, checking its cells or polygon.
+func (c FogChange) mode() (string, error) {
+	switch {
+	case len(c.Cells) > 0 && len(c.Polygon) == 0:
+		if len(c.Cells) > maxFogCells {
+			return "", ErrInvalidFog
+		}
+		for _, cell := range c.Cells {
+			if cell.X < 0 || cell.Y < 0 {
+				return "", ErrInvalidFog
+			}
+		}
+		return models.FogModeGrid, nil
+	case len(c.Polygon) > 0 && len(c.Cells) == 0:
+		if len(c.Polygon) > maxFogPoints || !c.Polygon.Valid() {
+			return "", ErrInvalidFog
+		}
+		return models.FogModePolygon, nil
+	default:
+		return "", ErrInvalidFog
+	}
+}
+
+// RevealFog uncovers an area of a map for players. Revealing on a map without fog
+// covers the rest of the map.
+func (s *Store) RevealFog(mapID, userID int64, change FogChange) (*models.Fog, error) {
+	return s.changeFog(mapID, userID, change, true)
+}
+
+// HideFog covers an area of a map again. Hiding on a map without fog covers all of it.
+func (s *Store) HideFog(mapID, userID int64, change FogChange) (*models.Fog, error) {
+	return s.changeFog(mapID, userID, change, false)
+}
+
+// ResetFog covers the whole map in fog of the given mode, or removes the fog when the
+// mode is empty.
+func (s *Store) ResetFog(mapID, userID int64, mode string) (*models.Fog, error) {
+	if mode != "" && mode != models.FogModeGrid && mode != models.FogModePolygon {
+		return nil, ErrInvalidFogMode
+	}
+	if err := s.authorizeMapEdit(mapID, userID); err != nil {
+		return nil, err
+	}
+
+	fog := models.Fog{Mode: mode}
+	if err := s.q.UpdateMapFogState(context.Background(), UpdateMapFogStateParams{FogState: marshalFog(fog), ID: mapID}); err != nil {
+		return nil, fmt.Errorf("failed to reset fog: %w", err)
+	}
+	return &fog, nil
+}
+
+func (s *Store) changeFog(mapID, userID int64, change FogChange, reveal bool) (*models.Fog, error) {
+	mode, err := change.mode()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeMapEdit(mapID, userID); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	raw, err := qtx.GetMapFogState(ctx, mapID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCampaignMapNotFound
+		}
+		return nil, fmt.Errorf("failed to get fog: %w", err)
+	}
+	fog := parseFog(raw)
+	if fog.Mode == "" {
+		fog.Mode = mode
+	} else if fog.Mode != mode {
+		return nil, ErrFogModeMismatch
+	}
+
+	if mode == models.FogModeGrid {
+		revealed := make(map[models.FogCell]bool, len(fog.Cells)+len(change.Cells))
+		for _, cell := range fog.Cells {
+			revealed[cell] = true
+		}
+		for _, cell := range change.Cells {
+			if reveal {
+				revealed[cell] = true
+			} else {
+				delete(revealed, cell)
+			}
+		}
+		fog.Cells = make([]models.FogCell, 0, len(revealed))
+		for cell := range revealed {
+			fog.Cells = append(fog.Cells, cell)
+		}
+		sort.Slice(fog.Cells, func(i, j int) bool {
+			a, b := fog.Cells[i], fog.Cells[j]
+			return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+		})
+	} else {
+		area := geometry.Region{change.Polygon}
+		if reveal {
+			fog.Revealed = geometry.Union(fog.Revealed, area)
+		} else {
+			fog.Revealed = geometry.Difference(fog.Revealed, area)
+		}
+		points := 0
+		for _, ring := range fog.Revealed {
+			points += len(ring)
+		}
+		if points > maxFogRegionPoints {
+			return nil, ErrFogTooDetailed
+		}
+	}
+
+	if err := qtx.UpdateMapFogState(ctx, UpdateMapFogStateParams{FogState: marshalFog(fog), ID: mapID}); err != nil {
+		return nil, fmt.Errorf("failed to update fog: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit fog: %w", err)
+	}
+	return &fog, nil
+}
+
+// authorizeMapEdit checks that the map exists and that the actor is an accepted owner or
+// editor of its campaign.
+func (s *Store) authorizeMapEdit(mapID, userID int64) error {
+	campaignID, err := s.getCampaignIDByMap(mapID)
+	if err != nil {
+		return err
+	}
+	return s.requireCampaignEditor(campaignID, userID)
+}
+
+func parseFog(raw string) models.Fog {
+	var fog models.Fog
+	if err := json.Unmarshal([]byte(raw), &fog); err != nil {
+		return models.Fog{}
+	}
+	return fog
+}
+
+func marshalFog(fog models.Fog) string {
+	data, err := json.Marshal(fog)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// fogMask answers whether a map's fog leaves a token visible to players.
+type fogMask struct {
+	fog   models.Fog
+	cells map[models.FogCell]bool
+}
+
+func newFogMask(fog models.Fog) *fogMask {
+	m := &fogMask{fog: fog}
+	if fog.Mode == models.FogModeGrid {
+		m.cells = make(map[models.FogCell]bool, len(fog.Cells))
+		for _, cell := range fog.Cells {
+			m.cells[cell] = true
+		}
+	}
+	return m
+}
+
+// reveals reports whether any square the token covers is revealed. In polygon mode a
+// square is revealed when its centre is.
+func (m *fogMask) reveals(t *models.Token) bool {
+	if m.fog.Mode != models.FogModeGrid && m.fog.Mode != models.FogModePolygon {
+		return true
+	}
+	size := max(t.SizeSquares, 1)
+	for dy := 0; dy < size; dy++ {
+		for dx := 0; dx < size; dx++ {
+			x, y := t.PositionX+dx, t.PositionY+dy
+			if m.fog.Mode == models.FogModeGrid {
+				if m.cells[models.FogCell{X: x, Y: y}] {
+					return true
+				}
+			} else if m.fog.Revealed.Contains(geometry.Point{X: float64(x) + 0.5, Y: float64(y) + 0.5}) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/geometry"
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+func setupFogMap(t *testing.T, s *Store) (owner, player *models.User, camp *models.Campaign, m *models.Map) {
+	t.Helper()
+	owner, _ = s.CreateUser("owner", "hash")
+	player, _ = s.CreateUser("player", "hash")
+	camp, err := s.CreateCampaign(owner.ID, "Quest", "", models.CampaignVisibilityPrivate, models.CampaignStatusNotStarted)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO campaign_members (campaign_id, user_id, role, status) VALUES (?, ?, 'viewer', 'accepted')`, camp.ID, player.ID); err != nil {
+		t.Fatalf("insert player: %v", err)
+	}
+	m, err = s.CreateMapForCampaign(camp.ID, owner.ID, nil, "Dungeon", "/uploads/dungeon.png")
+	if err != nil {
+		t.Fatalf("create map: %v", err)
+	}
+	return owner, player, camp, m
+}
+
+func playerTokenLabels(t *testing.T, s *Store, campaignID, userID int64) []string {
+	t.Helper()
+	full, err := s.GetCampaignFull(campaignID, userID)
+	if err != nil {
+		t.Fatalf("campaign full: %v", err)
+	}
+	var labels []string
+	for _, tok := range full.Scenes[0].Maps[0].Tokens {
+		labels = append(labels, tok.Label)
+	}
+	return labels
+}
+
+func TestFog_GridRevealHideReset(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	owner, player, camp, m := setupFogMap(t, s)
+
+	if _, err := s.CreateToken(m.ID, owner.ID, nil, "Goblin", "", 1, 3, 4, 0, []string{"all"}, nil, ""); err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 1 {
+		t.Fatalf("without fog the player should see the goblin: %v", got)
+	}
+
+	if _, err := s.RevealFog(m.ID, player.ID, FogChange{Cells: []models.FogCell{{X: 0, Y: 0}}}); err != ErrNotPermitted {
+		t.Fatalf("player expected ErrNotPermitted, got %v", err)
+	}
+	if _, err := s.RevealFog(m.ID, owner.ID, FogChange{}); err != ErrInvalidFog {
+		t.Fatalf("expected ErrInvalidFog, got %v", err)
+	}
+	fog, err := s.RevealFog(m.ID, owner.ID, FogChange{Cells: []models.FogCell{{X: 1, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 0}}})
+	if err != nil {
+		t.Fatalf("reveal: %v", err)
+	}
+	if fog.Mode != models.FogModeGrid || len(fog.Cells) != 2 || fog.Cells[0] != (models.FogCell{X: 0, Y: 0}) {
+		t.Fatalf("unexpected fog: %+v", fog)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 0 {
+		t.Fatalf("fogged goblin should be hidden: %v", got)
+	}
+
+	if _, err := s.RevealFog(m.ID, owner.ID, FogChange{Cells: []models.FogCell{{X: 3, Y: 4}}}); err != nil {
+		t.Fatalf("reveal goblin: %v", err)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 1 {
+		t.Fatalf("revealed goblin should be visible: %v", got)
+	}
+
+	fog, err = s.HideFog(m.ID, owner.ID, FogChange{Cells: []models.FogCell{{X: 3, Y: 4}, {X: 1, Y: 0}}})
+	if err != nil {
+		t.Fatalf("hide: %v", err)
+	}
+	if len(fog.Cells) != 1 {
+		t.Fatalf("unexpected fog after hide: %+v", fog)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, owner.ID); len(got) != 1 {
+		t.Fatalf("GM should still see the goblin: %v", got)
+	}
+
+	triangle := geometry.Ring{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}
+	if _, err := s.RevealFog(m.ID, owner.ID, FogChange{Polygon: triangle}); err != ErrFogModeMismatch {
+		t.Fatalf("expected ErrFogModeMismatch, got %v", err)
+	}
+	if _, err := s.ResetFog(m.ID, owner.ID, "hex"); err != ErrInvalidFogMode {
+		t.Fatalf("expected ErrInvalidFogMode, got %v", err)
+	}
+	if _, err := s.ResetFog(m.ID, owner.ID, ""); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 1 {
+		t.Fatalf("removing the fog should show the goblin: %v", got)
+	}
+}
+
+func TestFog_PolygonsMergeAndHideTokens(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	owner, player, camp, m := setupFogMap(t, s)
+
+	hero := newTestCharacter()
+	hero.UserID = player.ID
+	if err := s.CreateCharacter(hero); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	if _, err := s.CreateToken(m.ID, owner.ID, nil, "Chest", "", 1, 1, 1, 0, []string{"all"}, nil, ""); err != nil {
+		t.Fatalf("create chest: %v", err)
+	}
+	if _, err := s.CreateToken(m.ID, owner.ID, nil, "Ogre", "", 2, 5, 5, 0, []string{"all"}, nil, ""); err != nil {
+		t.Fatalf("create ogre: %v", err)
+	}
+	if _, err := s.CreateToken(m.ID, owner.ID, &hero.ID, "Hero", "", 1, 9, 9, 0, nil, nil, ""); err != nil {
+		t.Fatalf("create hero: %v", err)
+	}
+
+	if _, err := s.ResetFog(m.ID, owner.ID, models.FogModePolygon); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 1 || got[0] != "Hero" {
+		t.Fatalf("only the player's own token should show in full fog: %v", got)
+	}
+
+	if _, err := s.RevealFog(m.ID, owner.ID, FogChange{Polygon: geometry.Ring{{X: 0, Y: 0}, {X: 3, Y: 0}, {X: 3, Y: 3}, {X: 0, Y: 3}}}); err != nil {
+		t.Fatalf("reveal room: %v", err)
+	}
+	// A corridor overlapping the room and reaching the ogre's far square merges into one area.
+	fog, err := s.RevealFog(m.ID, owner.ID, FogChange{Polygon: geometry.Ring{{X: 2, Y: 2}, {X: 7, Y: 2}, {X: 7, Y: 7}, {X: 6, Y: 7}, {X: 6, Y: 3}, {X: 2, Y: 3}}})
+	if err != nil {
+		t.Fatalf("reveal corridor: %v", err)
+	}
+	if len(fog.Revealed) != 1 {
+		t.Fatalf("overlapping reveals should merge: %+v", fog.Revealed)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 3 {
+		t.Fatalf("chest and ogre should be revealed: %v", got)
+	}
+
+	if _, err := s.HideFog(m.ID, owner.ID, FogChange{Polygon: geometry.Ring{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}}); err != nil {
+		t.Fatalf("hide: %v", err)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 2 || got[0] != "Ogre" {
+		t.Fatalf("chest should be hidden again: %v", got)
+	}
+}
+
+func TestFog_RevealedPointsAreCapped(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	owner, _, _, m := setupFogMap(t, s)
+
+	if _, err := s.ResetFog(m.ID, owner.ID, models.FogModePolygon); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	for i, x := range []float64{5, 15} {
+		if _, err := s.RevealFog(m.ID, owner.ID, FogChange{Polygon: geometry.Circle(geometry.Point{X: x, Y: 5}, 3, 999)}); err != nil {
+			t.Fatalf("reveal %d: %v", i, err)
+		}
+	}
+	// A third detailed circle would take the map past the limit, so it is refused.
+	if _, err := s.RevealFog(m.ID, owner.ID, FogChange{Polygon: geometry.Circle(geometry.Point{X: 25, Y: 5}, 3, 999)}); err != ErrFogTooDetailed {
+		t.Fatalf("expected ErrFogTooDetailed, got %v", err)
+	}
+	fog, err := s.HideFog(m.ID, owner.ID, FogChange{Polygon: geometry.Ring{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}})
+	if err != nil || len(fog.Revealed) != 2 {
+		t.Fatalf("fog after refused reveal = %d rings, %v", len(fog.Revealed), err)
+	}
+}
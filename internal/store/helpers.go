@@ -61,6 +61,13 @@ func nullJSONString(ns *string) string {
 	return "[]"
 }
 
+func nullJSONObjectString(ns *string) string {
+	if ns != nil && *ns != "" {
+		return *ns
+	}
+	return "{}"
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -78,3 +85,18 @@ func nullInt64(ni *int64) int64 {
 	}
 	return 0
 }
+
+func nullBool(nb *bool) bool {
+	if nb != nil {
+		return *nb
+	}
+	return false
+}
+
+func intPtr(ni *int64) *int {
+	if ni == nil {
+		return nil
+	}
+	v := int(*ni)
+	return &v
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/rules"
+)
+
+// SaveHomebrewPack validates a JSON or YAML pack and saves it for the user, or for the
+// campaign when campaignID is set, which only the campaign's owner may do. Saving a pack
+// with the name of one already there replaces it if the version is higher.
+func (s *Store) SaveHomebrewPack(userID int64, campaignID *int64, data []byte) (*HomebrewPack, error) {
+	ctx := context.Background()
+
+	if campaignID != nil {
+		role, status, err := s.getMembership(*campaignID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if status != "accepted" || role != "owner" {
+			return nil, ErrNotPermitted
+		}
+	}
+
+	pack, err := rules.ParsePack(data)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(pack)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pack: %w", err)
+	}
+
+	var existing HomebrewPack
+	if campaignID != nil {
+		existing, err = s.q.GetCampaignHomebrewPackByName(ctx, GetCampaignHomebrewPackByNameParams{CampaignID: campaignID, Name: pack.Name})
+	} else {
+		existing, err = s.q.GetUserHomebrewPackByName(ctx, GetUserHomebrewPackByNameParams{UserID: userID, Name: pack.Name})
+	}
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		inserted, err := s.q.InsertHomebrewPack(ctx, InsertHomebrewPackParams{
+			UserID:     userID,
+			CampaignID: campaignID,
+			Name:       pack.Name,
+			Version:    pack.Version,
+			Content:    string(content),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save pack: %w", err)
+		}
+		return &inserted, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get pack: %w", err)
+	}
+
+	if rules.CompareVersions(pack.Version, existing.Version) <= 0 {
+		return nil, ErrPackVersion
+	}
+	updated, err := s.q.UpdateHomebrewPack(ctx, UpdateHomebrewPackParams{
+		UserID:  userID,
+		Version: pack.Version,
+		Content: string(content),
+		ID:      existing.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save pack: %w", err)
+	}
+	return &updated, nil
+}
+
+// ListHomebrewPacks returns the packs the user has uploaded for their own characters.
+func (s *Store) ListHomebrewPacks(userID int64) ([]HomebrewPack, error) {
+	packs, err := s.q.ListUserHomebrewPacks(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packs: %w", err)
+	}
+	if packs == nil {
+		packs = []HomebrewPack{}
+	}
+	return packs, nil
+}
+
+// ListCampaignHomebrewPacks returns a campaign's packs to any member of the campaign.
+func (s *Store) ListCampaignHomebrewPacks(campaignID, userID int64) ([]HomebrewPack, error) {
+	_, status, err := s.getMembership(campaignID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "accepted" {
+		return nil, ErrNotPermitted
+	}
+
+	packs, err := s.q.ListCampaignHomebrewPacks(context.Background(), &campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packs: %w", err)
+	}
+	if packs == nil {
+		packs = []HomebrewPack{}
+	}
+	return packs, nil
+}
+
+// DeleteHomebrewPack removes a pack. User packs can only be removed by their uploader
+// and campaign packs by the campaign's owner. Characters keep the options they chose.
+func (s *Store) DeleteHomebrewPack(packID, userID int64) error {
+	ctx := context.Background()
+
+	pack, err := s.q.GetHomebrewPack(ctx, packID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPackNotFound
+		}
+		return fmt.Errorf("failed to get pack: %w", err)
+	}
+	if pack.CampaignID != nil {
+		role, status, err := s.getMembership(*pack.CampaignID, userID)
+		if errors.Is(err, ErrNotCampaignMember) {
+			return ErrPackNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if status != "accepted" || role != "owner" {
+			return ErrNotPermitted
+		}
+	} else if pack.UserID != userID {
+		return ErrPackNotFound
+	}
+
+	if _, err := s.q.DeleteHomebrewPack(ctx, packID); err != nil {
+		return fmt.Errorf("failed to delete pack: %w", err)
+	}
+	return nil
+}
+
+// Catalog returns the options a character can be created with: the SRD plus the user's
+// own packs and, when campaignID is set, the packs of that campaign, which the user must
+// have joined.
+func (s *Store) Catalog(userID int64, campaignID *int64) (*rules.Catalog, error) {
+	var campaignIDs []int64
+	if campaignID != nil {
+		_, status, err := s.getMembership(*campaignID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if status != "accepted" {
+			return nil, ErrNotPermitted
+		}
+		campaignIDs = append(campaignIDs, *campaignID)
+	}
+	return s.loadCatalog(context.Background(), userID, campaignIDs)
+}
+
+// characterCatalog returns the options open to a character: the SRD, its owner's packs
+// and the packs of the campaigns it plays in, or of the campaign it is being made for.
+func (s *Store) characterCatalog(ctx context.Context, c *CharacterWithStats) (*rules.Catalog, error) {
+	if c.ID == 0 {
+		return s.Catalog(c.UserID, c.CampaignID)
+	}
+	campaignIDs, err := s.q.ListCharacterCampaignIDs(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list character campaigns: %w", err)
+	}
+	return s.loadCatalog(ctx, c.UserID, campaignIDs)
+}
+
+func (s *Store) loadCatalog(ctx context.Context, userID int64, campaignIDs []int64) (*rules.Catalog, error) {
+	rows, err := s.q.ListCatalogHomebrewPacks(ctx, ListCatalogHomebrewPacksParams{UserID: userID, CampaignIds: campaignIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packs: %w", err)
+	}
+	packs := make([]*rules.Pack, 0, len(rows))
+	for _, row := range rows {
+		var pack rules.Pack
+		if err := json.Unmarshal([]byte(row.Content), &pack); err != nil {
+			return nil, fmt.Errorf("failed to decode pack %q: %w", row.Name, err)
+		}
+		packs = append(packs, &pack)
+	}
+	return rules.SRD().With(packs...), nil
+}
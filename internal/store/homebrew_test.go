@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+	"github.com/jasoncabot/dicewizard-characters/internal/rules"
+)
+
+const witchPack = `
+name: Witchery
+version: 1.0.0
+classes:
+  - name: Witch
+    hitDie: 6
+    primaryAbilities: [intelligence]
+    savingThrows: [intelligence, wisdom]
+    skills: {count: 2, from: [Arcana, Nature]}
+subclasses:
+  - {name: Coven of the Moon, class: Witch, features: [Moon Hex]}
+`
+
+func TestHomebrewPacks(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	gm, _ := s.CreateUser("gm", "hash")
+	player, _ := s.CreateUser("player", "hash")
+	stranger, _ := s.CreateUser("stranger", "hash")
+	camp, err := s.CreateCampaign(gm.ID, "Homebrew", "", models.CampaignVisibilityPrivate, models.CampaignStatusInProgress)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO campaign_members (campaign_id, user_id, role, status) VALUES (?, ?, 'viewer', 'accepted')`, camp.ID, player.ID); err != nil {
+		t.Fatalf("insert player: %v", err)
+	}
+
+	if _, err := s.SaveHomebrewPack(player.ID, &camp.ID, []byte(witchPack)); err != ErrNotPermitted {
+		t.Fatalf("player upload: expected ErrNotPermitted, got %v", err)
+	}
+	if _, err := s.SaveHomebrewPack(gm.ID, &camp.ID, []byte("name: Broken")); !errors.Is(err, rules.ErrInvalidPack) {
+		t.Fatalf("expected ErrInvalidPack, got %v", err)
+	}
+	pack, err := s.SaveHomebrewPack(gm.ID, &camp.ID, []byte(witchPack))
+	if err != nil {
+		t.Fatalf("save pack: %v", err)
+	}
+	if _, err := s.SaveHomebrewPack(gm.ID, &camp.ID, []byte(witchPack)); err != ErrPackVersion {
+		t.Fatalf("same version: expected ErrPackVersion, got %v", err)
+	}
+	if packs, err := s.ListCampaignHomebrewPacks(camp.ID, player.ID); err != nil || len(packs) != 1 {
+		t.Fatalf("campaign packs = %+v, %v", packs, err)
+	}
+
+	// Campaign members can create characters for the campaign with the pack's class and
+	// subclass, but not characters made for no campaign.
+	c := newTestCharacter()
+	c.UserID = player.ID
+	c.Class = "witch"
+	c.Classes = []CharacterClass{{Class: "witch", Level: 3, Subclass: "coven of the moon"}}
+	if err := s.CreateCharacter(c); err != ErrUnknownClass {
+		t.Fatalf("no campaign: expected ErrUnknownClass, got %v", err)
+	}
+	c.CampaignID = &camp.ID
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create witch: %v", err)
+	}
+	if c.Class != "Witch" || c.HitDice != "3d6" || c.Classes[0].Subclass != "Coven of the Moon" ||
+		!containsKey(parseStringArray(c.Features), "Moon Hex") {
+		t.Fatalf("witch = %s %s %+v %s", c.Class, c.HitDice, c.Classes, c.Features)
+	}
+	// The character joins the campaign, so later level-ups still see its packs.
+	if ids, err := s.q.ListCharacterCampaignIDs(context.Background(), c.ID); err != nil || len(ids) != 1 || ids[0] != camp.ID {
+		t.Fatalf("campaigns = %v, %v", ids, err)
+	}
+
+	// Other users can't.
+	other := newTestCharacter()
+	other.UserID = stranger.ID
+	other.Class = "Witch"
+	if err := s.CreateCharacter(other); err != ErrUnknownClass {
+		t.Fatalf("stranger: expected ErrUnknownClass, got %v", err)
+	}
+	other.CampaignID = &camp.ID
+	if err := s.CreateCharacter(other); err != ErrNotCampaignMember {
+		t.Fatalf("stranger: expected ErrNotCampaignMember, got %v", err)
+	}
+	other.CampaignID = nil
+	other.Class = "Rogue"
+	other.Classes = []CharacterClass{{Class: "Rogue", Level: 3, Subclass: "Coven of the Moon"}}
+	if err := s.CreateCharacter(other); err != ErrUnknownSubclass {
+		t.Fatalf("wrong subclass: expected ErrUnknownSubclass, got %v", err)
+	}
+
+	// A character keeps its subclass after the pack is removed.
+	if err := s.DeleteHomebrewPack(pack.ID, player.ID); err != ErrNotPermitted {
+		t.Fatalf("player delete: expected ErrNotPermitted, got %v", err)
+	}
+	if err := s.DeleteHomebrewPack(pack.ID, gm.ID); err != nil {
+		t.Fatalf("delete pack: %v", err)
+	}
+	got, _ := s.GetCharacter(c.ID, player.ID)
+	got.Name = "Agnes"
+	if err := s.UpdateCharacter(got); err != nil {
+		t.Fatalf("update after delete: %v", err)
+	}
+	if got.Classes[0].Subclass != "Coven of the Moon" {
+		t.Fatalf("subclass = %q", got.Classes[0].Subclass)
+	}
+}
+
+func TestUserHomebrewPackVersions(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("brewer", "hash")
+	other, _ := s.CreateUser("other", "hash")
+	pack, err := s.SaveHomebrewPack(user.ID, nil, []byte(witchPack))
+	if err != nil {
+		t.Fatalf("save pack: %v", err)
+	}
+	updated, err := s.SaveHomebrewPack(user.ID, nil, []byte(`{"name": "Witchery", "version": "1.1.0", "spells": [{"name": "Hex Bolt", "level": 1, "school": "evocation"}]}`))
+	if err != nil || updated.ID != pack.ID || updated.Version != "1.1.0" {
+		t.Fatalf("update pack = %+v, %v", updated, err)
+	}
+	catalog, err := s.Catalog(user.ID, nil)
+	if err != nil {
+		t.Fatalf("catalog: %v", err)
+	}
+	if _, ok := catalog.LookupClass("Witch"); ok || len(catalog.Spells) != 1 || catalog.Spells[0].Source != "Witchery" {
+		t.Fatalf("catalog classes %d, spells %+v", len(catalog.Classes), catalog.Spells)
+	}
+
+	if err := s.DeleteHomebrewPack(pack.ID, other.ID); err != ErrPackNotFound {
+		t.Fatalf("other delete: expected ErrPackNotFound, got %v", err)
+	}
+	if packs, _ := s.ListHomebrewPacks(other.ID); len(packs) != 0 {
+		t.Fatalf("other packs = %+v", packs)
+	}
+}
+
+const hexerPack = `
+name: Hexcraft
+version: 1.0.0
+classes:
+  - name: Hexer
+    hitDie: 10
+    primaryAbilities: [intelligence]
+    savingThrows: [intelligence, charisma]
+    skills: {count: 2, from: [Arcana, Deception]}
+    spellcastingAbility: intelligence
+`
+
+func TestHomebrewClassAsSecondClass(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("brewer", "hash")
+	if _, err := s.SaveHomebrewPack(user.ID, nil, []byte(hexerPack)); err != nil {
+		t.Fatalf("save pack: %v", err)
+	}
+
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Classes = []CharacterClass{{Class: "Rogue", Level: 3}, {Class: "hexer", Level: 2}}
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// The pack's d10 and Intelligence are used, not a d8 and no spellcasting.
+	if c.HitDice != "2d10+3d8" || len(c.Spellcasting) != 1 || c.Spellcasting[0].Class != "Hexer" ||
+		c.Spellcasting[0].Ability != "intelligence" || c.Spellcasting[0].SpellSaveDC != 12 {
+		t.Fatalf("hexer = %s %+v", c.HitDice, c.Spellcasting)
+	}
+
+	got, _ := s.GetCharacter(c.ID, user.ID)
+	result, err := s.LevelUp(got, LevelUpChoice{Class: "Hexer"}, dice.NewSeededRNG(1))
+	if err != nil {
+		t.Fatalf("level up: %v", err)
+	}
+	if result.HitDie != 10 || result.Character.HitDice != "3d10+3d8" || result.Character.Classes[1].HitDie != 10 {
+		t.Fatalf("level up = d%d %s %+v", result.HitDie, result.Character.HitDice, result.Character.Classes)
+	}
+}
@@ -0,0 +1,266 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+// Hit point change types.
+const (
+	HitPointDamage    = "damage"
+	HitPointHeal      = "heal"
+	HitPointTemp      = "temp"
+	HitPointDeathSave = "death-save"
+)
+
+// Character states derived from hit points and death saving throws.
+const (
+	StatusConscious = "conscious"
+	StatusDying     = "dying"
+	StatusStable    = "stable"
+	StatusDead      = "dead"
+)
+
+// Defenses that changed the damage taken.
+const (
+	DefenseResistance    = "resistance"
+	DefenseVulnerability = "vulnerability"
+	DefenseImmunity      = "immunity"
+)
+
+// DamageTypes are the damage types a character can resist, be vulnerable to or be immune to.
+var DamageTypes = []string{
+	"acid", "bludgeoning", "cold", "fire", "force", "lightning", "necrotic",
+	"piercing", "poison", "psychic", "radiant", "slashing", "thunder",
+}
+
+// HitPointChange is damage taken, healing received, temporary hit points gained or a
+// death saving throw.
+type HitPointChange struct {
+	Type       string
+	Amount     int
+	DamageType string
+	// Critical damage from a critical hit costs a character at 0 hit points two death saves.
+	Critical bool
+}
+
+// HitPointResult is the character after a hit point change and how the change was applied.
+type HitPointResult struct {
+	Character *CharacterWithStats `json:"character"`
+	// Amount is the damage or healing actually applied, after defenses and caps.
+	Amount         int          `json:"amount"`
+	Defenses       []string     `json:"defenses,omitempty"`
+	TempHpAbsorbed int          `json:"tempHpAbsorbed"`
+	InstantDeath   bool         `json:"instantDeath,omitempty"`
+	Roll           *dice.Result `json:"roll,omitempty"`
+}
+
+// hitPointStatus reports whether a character is conscious, dying, stable or dead. Three
+// death save successes stabilise a character at 0 hit points; three failures kill it.
+func hitPointStatus(c CharacterModel) string {
+	switch {
+	case c.DeathSaveFailures >= 3:
+		return StatusDead
+	case c.CurrentHp > 0:
+		return StatusConscious
+	case c.DeathSaveSuccesses >= 3:
+		return StatusStable
+	}
+	return StatusDying
+}
+
+// EncodeDamageTypes validates a list of damage types and returns it as a JSON array of
+// lower-case names without duplicates.
+func EncodeDamageTypes(types []string) (string, error) {
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		name, ok := lookupDamageType(t)
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrInvalidDamageType, t)
+		}
+		if !containsKey(out, name) {
+			out = append(out, name)
+		}
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func lookupDamageType(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, t := range DamageTypes {
+		if t == name {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// ApplyHitPoints applies a hit point change using the 5e rules. Damage is modified by
+// the character's immunities, resistances (halved, rounded down) and vulnerabilities
+// (doubled), then comes out of temporary hit points before current hit points. Damage
+// that leaves at least the character's hit point maximum over after reaching 0 kills
+// outright; damage taken at 0 hit points is a failed death save, or two for a critical
+// hit. Any healing brings a dying or stable character back and clears its death saves.
+// Temporary hit points don't stack: the character keeps the higher of old and new.
+// The change is worked out from the hit points stored when it is applied, so changes
+// made at the same time don't overwrite each other.
+func (s *Store) ApplyHitPoints(c *CharacterWithStats, change HitPointChange, rng dice.RNG) (*HitPointResult, error) {
+	if change.Amount < 0 {
+		return nil, ErrInvalidHitPointChange
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	stored, err := qtx.GetCharacterByIDAndUser(ctx, GetCharacterByIDAndUserParams{ID: c.ID, UserID: c.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hit points: %w", err)
+	}
+	c.MaxHp = stored.MaxHp
+	c.CurrentHp = stored.CurrentHp
+	c.TempHp = stored.TempHp
+	c.HitDiceSpent = stored.HitDiceSpent
+	c.DeathSaveSuccesses = stored.DeathSaveSuccesses
+	c.DeathSaveFailures = stored.DeathSaveFailures
+	if c.Exhaustion < MaxExhaustion {
+		c.Status = hitPointStatus(c.CharacterModel)
+	}
+	if c.Status == StatusDead {
+		return nil, ErrCharacterDead
+	}
+	result := &HitPointResult{}
+
+	switch change.Type {
+	case HitPointDamage:
+		amount := change.Amount
+		if change.DamageType != "" {
+			damageType, ok := lookupDamageType(change.DamageType)
+			if !ok {
+				return nil, ErrInvalidDamageType
+			}
+			switch {
+			case containsKey(parseStringArray(c.DamageImmunities), damageType):
+				amount = 0
+				result.Defenses = append(result.Defenses, DefenseImmunity)
+			default:
+				if containsKey(parseStringArray(c.DamageResistances), damageType) {
+					amount /= 2
+					result.Defenses = append(result.Defenses, DefenseResistance)
+				}
+				if containsKey(parseStringArray(c.DamageVulnerabilities), damageType) {
+					amount *= 2
+					result.Defenses = append(result.Defenses, DefenseVulnerability)
+				}
+			}
+		}
+		result.Amount = amount
+
+		absorbed := min(int64(amount), c.TempHp)
+		c.TempHp -= absorbed
+		result.TempHpAbsorbed = int(absorbed)
+		remaining := int64(amount) - absorbed
+		if remaining == 0 {
+			break
+		}
+
+		if c.CurrentHp == 0 {
+			if remaining >= c.MaxHp {
+				c.DeathSaveFailures = 3
+				result.InstantDeath = true
+				break
+			}
+			// A stable character that takes damage starts dying again.
+			if c.DeathSaveSuccesses >= 3 {
+				c.DeathSaveSuccesses = 0
+			}
+			failures := int64(1)
+			if change.Critical {
+				failures = 2
+			}
+			c.DeathSaveFailures = min(3, c.DeathSaveFailures+failures)
+			break
+		}
+
+		overflow := remaining - c.CurrentHp
+		c.CurrentHp = max(0, -overflow)
+		if c.CurrentHp == 0 {
+			c.DeathSaveSuccesses = 0
+			c.DeathSaveFailures = 0
+			if overflow >= c.MaxHp {
+				c.DeathSaveFailures = 3
+				result.InstantDeath = true
+			}
+		}
+	case HitPointHeal:
+		healed := min(c.MaxHp, c.CurrentHp+int64(change.Amount))
+		result.Amount = int(healed - c.CurrentHp)
+		c.CurrentHp = healed
+		if c.CurrentHp > 0 {
+			c.DeathSaveSuccesses = 0
+			c.DeathSaveFailures = 0
+		}
+	case HitPointTemp:
+		result.Amount = change.Amount
+		c.TempHp = max(c.TempHp, int64(change.Amount))
+	case HitPointDeathSave:
+		if c.Status != StatusDying {
+			return nil, ErrNotDying
+		}
+		roll, err := dice.Roll("1d20", rng)
+		if err != nil {
+			return nil, fmt.Errorf("failed to roll death save: %w", err)
+		}
+		result.Roll = roll
+		switch {
+		case roll.Total == 20:
+			// A natural 20 brings the character back with 1 hit point.
+			c.CurrentHp = 1
+			c.DeathSaveSuccesses = 0
+			c.DeathSaveFailures = 0
+			result.Amount = 1
+		case roll.Total == 1:
+			c.DeathSaveFailures = min(3, c.DeathSaveFailures+2)
+		case roll.Total >= 10:
+			c.DeathSaveSuccesses++
+		default:
+			c.DeathSaveFailures++
+		}
+	default:
+		return nil, ErrInvalidHitPointChange
+	}
+
+	if err := qtx.UpdateCharacterHitPoints(ctx, UpdateCharacterHitPointsParams{
+		CurrentHp:          c.CurrentHp,
+		TempHp:             &c.TempHp,
+		HitDiceSpent:       &c.HitDiceSpent,
+		DeathSaveSuccesses: &c.DeathSaveSuccesses,
+		DeathSaveFailures:  &c.DeathSaveFailures,
+		ID:                 c.ID,
+		UserID:             c.UserID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update hit points: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit hit points: %w", err)
+	}
+
+	updated, err := s.GetCharacter(c.ID, c.UserID)
+	if err != nil {
+		return nil, err
+	}
+	result.Character = updated
+	return result, nil
+}
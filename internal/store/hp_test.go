@@ -0,0 +1,176 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+func TestApplyHitPointsDamageAndHealing(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("tank", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.MaxHp = 20
+	c.CurrentHp = 20
+	c.TempHp = 5
+	c.DamageResistances = `["fire"]`
+	c.DamageVulnerabilities = `["cold"]`
+	c.DamageImmunities = `["poison"]`
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	rng := dice.NewSeededRNG(1)
+
+	if _, err := s.ApplyHitPoints(c, HitPointChange{Type: HitPointDamage, Amount: 3, DamageType: "sonic"}, rng); err != ErrInvalidDamageType {
+		t.Fatalf("expected ErrInvalidDamageType, got %v", err)
+	}
+	if _, err := s.ApplyHitPoints(c, HitPointChange{Type: HitPointHeal, Amount: -3}, rng); err != ErrInvalidHitPointChange {
+		t.Fatalf("expected ErrInvalidHitPointChange, got %v", err)
+	}
+	if _, err := s.ApplyHitPoints(c, HitPointChange{Type: HitPointDeathSave}, rng); err != ErrNotDying {
+		t.Fatalf("expected ErrNotDying, got %v", err)
+	}
+
+	// 13 fire is halved to 6: 5 from temporary hit points, 1 from current.
+	result, err := s.ApplyHitPoints(c, HitPointChange{Type: HitPointDamage, Amount: 13, DamageType: "Fire"}, rng)
+	if err != nil {
+		t.Fatalf("fire damage: %v", err)
+	}
+	if result.Amount != 6 || result.TempHpAbsorbed != 5 || result.Character.TempHp != 0 || result.Character.CurrentHp != 19 {
+		t.Fatalf("fire damage = %+v, hp %d temp %d", result, result.Character.CurrentHp, result.Character.TempHp)
+	}
+
+	result, err = s.ApplyHitPoints(result.Character, HitPointChange{Type: HitPointDamage, Amount: 50, DamageType: "poison"}, rng)
+	if err != nil || result.Amount != 0 || result.Character.CurrentHp != 19 || result.Defenses[0] != DefenseImmunity {
+		t.Fatalf("poison damage = %+v, %v", result, err)
+	}
+
+	// 8 cold doubles to 16, leaving 3.
+	result, err = s.ApplyHitPoints(result.Character, HitPointChange{Type: HitPointDamage, Amount: 8, DamageType: "cold"}, rng)
+	if err != nil || result.Character.CurrentHp != 3 {
+		t.Fatalf("cold damage = %+v, %v", result, err)
+	}
+
+	result, err = s.ApplyHitPoints(result.Character, HitPointChange{Type: HitPointTemp, Amount: 4}, rng)
+	if err != nil {
+		t.Fatalf("temp hp: %v", err)
+	}
+	result, err = s.ApplyHitPoints(result.Character, HitPointChange{Type: HitPointTemp, Amount: 2}, rng)
+	if err != nil || result.Character.TempHp != 4 {
+		t.Fatalf("temporary hit points should not stack: %+v, %v", result, err)
+	}
+
+	// Dropping to 0 leaves the character dying; damage while down costs death saves.
+	result, err = s.ApplyHitPoints(result.Character, HitPointChange{Type: HitPointDamage, Amount: 10}, rng)
+	if err != nil || result.Character.CurrentHp != 0 || result.Character.Status != StatusDying {
+		t.Fatalf("knocked out = %+v, %v", result, err)
+	}
+	result, err = s.ApplyHitPoints(result.Character, HitPointChange{Type: HitPointDamage, Amount: 2, Critical: true}, rng)
+	if err != nil || result.Character.DeathSaveFailures != 2 || result.Character.Status != StatusDying {
+		t.Fatalf("critical hit while down = %+v, %v", result.Character, err)
+	}
+
+	result, err = s.ApplyHitPoints(result.Character, HitPointChange{Type: HitPointHeal, Amount: 30}, rng)
+	if err != nil {
+		t.Fatalf("heal: %v", err)
+	}
+	got := result.Character
+	if result.Amount != 20 || got.CurrentHp != 20 || got.DeathSaveFailures != 0 || got.Status != StatusConscious {
+		t.Fatalf("healed = %+v, hp %d failures %d", result, got.CurrentHp, got.DeathSaveFailures)
+	}
+}
+
+func TestApplyHitPointsDeathSavesAndMassiveDamage(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("doomed", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.MaxHp = 12
+	c.CurrentHp = 0
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	rng := dice.NewSeededRNG(3)
+	var result *HitPointResult
+	var err error
+	for saves := 0; saves < 5; saves++ {
+		result, err = s.ApplyHitPoints(c, HitPointChange{Type: HitPointDeathSave}, rng)
+		if err != nil {
+			break
+		}
+		c = result.Character
+		roll := result.Roll.Total
+		if roll == 20 && (c.CurrentHp != 1 || c.Status != StatusConscious) {
+			t.Fatalf("natural 20 should restore 1 hp: %+v", c)
+		}
+		if c.Status != StatusDying {
+			break
+		}
+	}
+	if err != nil && err != ErrNotDying {
+		t.Fatalf("death save: %v", err)
+	}
+	if c.Status == StatusDying {
+		t.Fatalf("five death saves should settle the character, got %d/%d", c.DeathSaveSuccesses, c.DeathSaveFailures)
+	}
+
+	fresh := newTestCharacter()
+	fresh.UserID = user.ID
+	fresh.MaxHp = 12
+	fresh.CurrentHp = 5
+	if err := s.CreateCharacter(fresh); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	// 17 damage takes 5 to reach 0 and leaves 12, the hit point maximum.
+	result, err = s.ApplyHitPoints(fresh, HitPointChange{Type: HitPointDamage, Amount: 17}, rng)
+	if err != nil || !result.InstantDeath || result.Character.Status != StatusDead {
+		t.Fatalf("massive damage = %+v, %v", result, err)
+	}
+	if _, err := s.ApplyHitPoints(result.Character, HitPointChange{Type: HitPointHeal, Amount: 5}, rng); err != ErrCharacterDead {
+		t.Fatalf("expected ErrCharacterDead, got %v", err)
+	}
+	if _, err := s.Rest(result.Character, RestLong, 0, rng); err != ErrCharacterDead {
+		t.Fatalf("expected ErrCharacterDead from rest, got %v", err)
+	}
+}
+
+func TestApplyHitPointsUsesStoredHitPoints(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("brawler", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.MaxHp = 20
+	c.CurrentHp = 20
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	first, _ := s.GetCharacter(c.ID, user.ID)
+	second, _ := s.GetCharacter(c.ID, user.ID)
+
+	// Both requests loaded the character at 20 hit points, but neither hit is lost.
+	if _, err := s.ApplyHitPoints(first, HitPointChange{Type: HitPointDamage, Amount: 3}, nil); err != nil {
+		t.Fatalf("first hit: %v", err)
+	}
+	result, err := s.ApplyHitPoints(second, HitPointChange{Type: HitPointDamage, Amount: 4}, nil)
+	if err != nil || result.Character.CurrentHp != 13 {
+		t.Fatalf("after both hits = %+v, %v", result, err)
+	}
+}
+
+func TestEncodeDamageTypes(t *testing.T) {
+	encoded, err := EncodeDamageTypes([]string{"Fire", " cold", "fire"})
+	if err != nil || encoded != `["fire","cold"]` {
+		t.Fatalf("encoded = %s, %v", encoded, err)
+	}
+	if _, err := EncodeDamageTypes([]string{"sonic"}); err == nil {
+		t.Fatal("expected an error for an unknown damage type")
+	}
+}
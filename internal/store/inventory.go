@@ -0,0 +1,296 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Encumbrance levels, using the variant rule thresholds of 5x and 10x Strength.
+const (
+	EncumbranceNone         = "unencumbered"
+	EncumbranceEncumbered   = "encumbered"
+	EncumbranceHeavy        = "heavily encumbered"
+	EncumbranceOverCapacity = "over capacity"
+)
+
+// MaxAttunedItems is the number of magic items a character can be attuned to at once.
+const MaxAttunedItems = 3
+
+// coinsPerPound is how many coins of any denomination weigh one pound.
+const coinsPerPound = 50
+
+// Inventory is a character's items and coin purse with the derived totals.
+type Inventory struct {
+	Items            []CharacterItem `json:"items"`
+	Coins            CharacterCoin   `json:"coins"`
+	TotalValueCp     int64           `json:"totalValueCp"`
+	CarriedWeight    float64         `json:"carriedWeight"`
+	CarryingCapacity int             `json:"carryingCapacity"`
+	Encumbrance      string          `json:"encumbrance"`
+}
+
+// GetInventory returns the character's items, coins and encumbrance.
+func (s *Store) GetInventory(c *CharacterWithStats) (*Inventory, error) {
+	ctx := context.Background()
+
+	items, err := s.q.ListCharacterItems(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+	if items == nil {
+		items = []CharacterItem{}
+	}
+
+	coins, err := s.characterCoins(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.applyInventory(items, coins)
+
+	inv := &Inventory{
+		Items:            items,
+		Coins:            coins,
+		CarriedWeight:    c.CarriedWeight,
+		CarryingCapacity: c.CarryingCapacity,
+		Encumbrance:      c.Encumbrance,
+	}
+	inv.TotalValueCp = coinValueCp(coins)
+	for _, item := range items {
+		inv.TotalValueCp += item.ValueCp * item.Quantity
+	}
+	return inv, nil
+}
+
+// AddCharacterItem adds an item to the character's inventory.
+func (s *Store) AddCharacterItem(item CharacterItem) (*CharacterItem, error) {
+	ctx := context.Background()
+
+	if err := s.validateItem(ctx, &item); err != nil {
+		return nil, err
+	}
+
+	inserted, err := s.q.InsertCharacterItem(ctx, InsertCharacterItemParams{
+		CharacterID: item.CharacterID,
+		ContainerID: item.ContainerID,
+		Name:        item.Name,
+		Quantity:    item.Quantity,
+		Weight:      item.Weight,
+		ValueCp:     item.ValueCp,
+		Equipped:    item.Equipped,
+		Attuned:     item.Attuned,
+		IsContainer: item.IsContainer,
+		ArmorType:   item.ArmorType,
+		ArmorClass:  item.ArmorClass,
+		Notes:       item.Notes,
+		Position:    item.Position,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add item: %w", err)
+	}
+	if err := markCharacterChanged(ctx, s.q, item.CharacterID); err != nil {
+		return nil, err
+	}
+	return &inserted, nil
+}
+
+// UpdateCharacterItem replaces an item's details.
+func (s *Store) UpdateCharacterItem(item CharacterItem) (*CharacterItem, error) {
+	ctx := context.Background()
+
+	if _, err := s.q.GetCharacterItem(ctx, GetCharacterItemParams{ID: item.ID, CharacterID: item.CharacterID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+	if err := s.validateItem(ctx, &item); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.q.UpdateCharacterItem(ctx, UpdateCharacterItemParams{
+		ContainerID: item.ContainerID,
+		Name:        item.Name,
+		Quantity:    item.Quantity,
+		Weight:      item.Weight,
+		ValueCp:     item.ValueCp,
+		Equipped:    item.Equipped,
+		Attuned:     item.Attuned,
+		IsContainer: item.IsContainer,
+		ArmorType:   item.ArmorType,
+		ArmorClass:  item.ArmorClass,
+		Notes:       item.Notes,
+		Position:    item.Position,
+		ID:          item.ID,
+		CharacterID: item.CharacterID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+	if err := markCharacterChanged(ctx, s.q, item.CharacterID); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteCharacterItem removes an item. Anything stored inside it is moved out of the container.
+func (s *Store) DeleteCharacterItem(characterID, itemID int64) error {
+	ctx := context.Background()
+
+	rows, err := s.q.DeleteCharacterItem(ctx, DeleteCharacterItemParams{ID: itemID, CharacterID: characterID})
+	if err != nil {
+		return fmt.Errorf("failed to delete item: %w", err)
+	}
+	if rows == 0 {
+		return ErrItemNotFound
+	}
+	return markCharacterChanged(ctx, s.q, characterID)
+}
+
+// SetCharacterCoins replaces the character's coin purse.
+func (s *Store) SetCharacterCoins(coins CharacterCoin) (*CharacterCoin, error) {
+	if coins.Cp < 0 || coins.Sp < 0 || coins.Ep < 0 || coins.Gp < 0 || coins.Pp < 0 {
+		return nil, ErrInvalidCoins
+	}
+
+	ctx := context.Background()
+	if err := s.q.UpsertCharacterCoins(ctx, UpsertCharacterCoinsParams(coins)); err != nil {
+		return nil, fmt.Errorf("failed to update coins: %w", err)
+	}
+	if err := markCharacterChanged(ctx, s.q, coins.CharacterID); err != nil {
+		return nil, err
+	}
+	return &coins, nil
+}
+
+func (s *Store) characterCoins(ctx context.Context, characterID int64) (CharacterCoin, error) {
+	coins, err := s.q.GetCharacterCoins(ctx, characterID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return CharacterCoin{CharacterID: characterID}, nil
+		}
+		return CharacterCoin{}, fmt.Errorf("failed to get coins: %w", err)
+	}
+	return coins, nil
+}
+
+// validateItem checks quantities, armor, attunement slots and that any container is one of
+// the character's containers without creating a loop.
+func (s *Store) validateItem(ctx context.Context, item *CharacterItem) error {
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" || item.Quantity < 0 || item.Weight < 0 || item.ValueCp < 0 {
+		return ErrInvalidItem
+	}
+	if err := validateArmor(item); err != nil {
+		return err
+	}
+
+	items, err := s.q.ListCharacterItems(ctx, item.CharacterID)
+	if err != nil {
+		return fmt.Errorf("failed to list items: %w", err)
+	}
+	byID := make(map[int64]CharacterItem, len(items))
+	attuned := 0
+	for _, other := range items {
+		byID[other.ID] = other
+		if other.Attuned && other.ID != item.ID {
+			attuned++
+		}
+	}
+
+	if item.Attuned && attuned >= MaxAttunedItems {
+		return ErrAttunementLimit
+	}
+
+	for parent := item.ContainerID; parent != nil; {
+		container, ok := byID[*parent]
+		if !ok || !container.IsContainer || container.ID == item.ID {
+			return ErrInvalidContainer
+		}
+		parent = container.ContainerID
+	}
+	return nil
+}
+
+// applyInventory fills the equipment names and encumbrance from the character's items.
+func (c *CharacterWithStats) applyInventory(items []CharacterItem, coins CharacterCoin) {
+	c.Equipment = make([]string, 0, len(items))
+	weight := float64(coins.Cp+coins.Sp+coins.Ep+coins.Gp+coins.Pp) / coinsPerPound
+	for _, item := range items {
+		c.Equipment = append(c.Equipment, item.Name)
+		weight += item.Weight * float64(item.Quantity)
+	}
+
+	c.CarriedWeight = math.Round(weight*100) / 100
+	c.CarryingCapacity = int(c.Strength) * 15
+	c.Encumbrance = encumbrance(c.CarriedWeight, int(c.Strength))
+}
+
+func encumbrance(weight float64, strength int) string {
+	switch {
+	case weight > float64(strength*15):
+		return EncumbranceOverCapacity
+	case weight > float64(strength*10):
+		return EncumbranceHeavy
+	case weight > float64(strength*5):
+		return EncumbranceEncumbered
+	}
+	return EncumbranceNone
+}
+
+func coinValueCp(coins CharacterCoin) int64 {
+	return coins.Cp + coins.Sp*10 + coins.Ep*50 + coins.Gp*100 + coins.Pp*1000
+}
+
+// syncEquipment makes the character's items match a plain list of names, as sent by
+// clients that only know about the legacy equipment array. Items whose names are still
+// listed keep their details; repeated names add to the quantity of new items.
+func syncEquipment(ctx context.Context, q *Queries, characterID int64, names []string) error {
+	existing, err := q.ListCharacterItems(ctx, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to list items: %w", err)
+	}
+
+	wanted := make(map[string]int, len(names))
+	order := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if wanted[name] == 0 {
+			order = append(order, name)
+		}
+		wanted[name]++
+	}
+
+	kept := make(map[string]bool, len(existing))
+	for _, item := range existing {
+		if wanted[item.Name] > 0 {
+			kept[item.Name] = true
+			continue
+		}
+		if _, err := q.DeleteCharacterItem(ctx, DeleteCharacterItemParams{ID: item.ID, CharacterID: characterID}); err != nil {
+			return fmt.Errorf("failed to remove item: %w", err)
+		}
+	}
+
+	for i, name := range order {
+		if kept[name] {
+			continue
+		}
+		if _, err := q.InsertCharacterItem(ctx, InsertCharacterItemParams{
+			CharacterID: characterID,
+			Name:        name,
+			Quantity:    int64(wanted[name]),
+			Position:    int64(len(existing) + i),
+		}); err != nil {
+			return fmt.Errorf("failed to add item: %w", err)
+		}
+	}
+	return nil
+}
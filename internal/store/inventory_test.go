@@ -0,0 +1,203 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+)
+
+func TestEquipmentRoundTripKeepsItemDetails(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("packer", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Equipment = []string{"Rope", "Torch", "Torch"}
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	got, err := s.GetCharacter(c.ID, user.ID)
+	if err != nil {
+		t.Fatalf("get character: %v", err)
+	}
+	if len(got.Equipment) != 2 || got.Equipment[0] != "Rope" || got.Equipment[1] != "Torch" {
+		t.Fatalf("equipment = %v, want [Rope Torch]", got.Equipment)
+	}
+
+	inv, err := s.GetInventory(got)
+	if err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	rope := inv.Items[0]
+	if inv.Items[1].Quantity != 2 {
+		t.Fatalf("torch quantity = %d, want 2", inv.Items[1].Quantity)
+	}
+	rope.Weight = 10
+	rope.Notes = "50 feet, hempen"
+	if _, err := s.UpdateCharacterItem(rope); err != nil {
+		t.Fatalf("update rope: %v", err)
+	}
+
+	// A legacy client resending names must not wipe the details of items it still lists.
+	got.Equipment = []string{"Rope", "Bedroll"}
+	if err := s.UpdateCharacter(got); err != nil {
+		t.Fatalf("update character: %v", err)
+	}
+	inv, err = s.GetInventory(got)
+	if err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if len(inv.Items) != 2 || inv.Items[0].Notes != "50 feet, hempen" || inv.Items[1].Name != "Bedroll" {
+		t.Fatalf("items after sync = %+v", inv.Items)
+	}
+
+	// Omitting equipment leaves the inventory alone.
+	got.Equipment = nil
+	if err := s.UpdateCharacter(got); err != nil {
+		t.Fatalf("update character: %v", err)
+	}
+	if got, _ = s.GetCharacter(c.ID, user.ID); len(got.Equipment) != 2 {
+		t.Fatalf("equipment = %v, want 2 items", got.Equipment)
+	}
+}
+
+func TestInventoryEncumbranceAndLimits(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("mule", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	pack, err := s.AddCharacterItem(CharacterItem{CharacterID: c.ID, Name: "Backpack", Quantity: 1, Weight: 5, IsContainer: true})
+	if err != nil {
+		t.Fatalf("add backpack: %v", err)
+	}
+	if _, err := s.AddCharacterItem(CharacterItem{CharacterID: c.ID, Name: "Iron Pot", Quantity: 2, Weight: 10, ValueCp: 200, ContainerID: &pack.ID}); err != nil {
+		t.Fatalf("add pot: %v", err)
+	}
+	if _, err := s.SetCharacterCoins(CharacterCoin{CharacterID: c.ID, Gp: 40, Sp: 10}); err != nil {
+		t.Fatalf("set coins: %v", err)
+	}
+
+	// Strength 8: encumbered above 40 lb, heavily above 80 lb, capacity 120 lb.
+	inv, err := s.GetInventory(c)
+	if err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if inv.CarriedWeight != 26 || inv.CarryingCapacity != 120 || inv.Encumbrance != EncumbranceNone {
+		t.Fatalf("inventory = %.2f/%d %s, want 26/120 unencumbered", inv.CarriedWeight, inv.CarryingCapacity, inv.Encumbrance)
+	}
+	if inv.TotalValueCp != 4500 {
+		t.Fatalf("total value = %d cp, want 4500", inv.TotalValueCp)
+	}
+
+	if _, err := s.AddCharacterItem(CharacterItem{CharacterID: c.ID, Name: "Anvil", Quantity: 1, Weight: 60}); err != nil {
+		t.Fatalf("add anvil: %v", err)
+	}
+	if inv, _ = s.GetInventory(c); inv.Encumbrance != EncumbranceHeavy {
+		t.Fatalf("encumbrance = %s, want %s", inv.Encumbrance, EncumbranceHeavy)
+	}
+
+	for i, name := range []string{"Ring", "Cloak", "Amulet"} {
+		if _, err := s.AddCharacterItem(CharacterItem{CharacterID: c.ID, Name: name, Quantity: 1, Attuned: true, Position: int64(i)}); err != nil {
+			t.Fatalf("attune %s: %v", name, err)
+		}
+	}
+	if _, err := s.AddCharacterItem(CharacterItem{CharacterID: c.ID, Name: "Boots", Quantity: 1, Attuned: true}); err != ErrAttunementLimit {
+		t.Fatalf("expected ErrAttunementLimit, got %v", err)
+	}
+
+	pot := inv.Items[1]
+	if _, err := s.AddCharacterItem(CharacterItem{CharacterID: c.ID, Name: "Spoon", Quantity: 1, ContainerID: &pot.ID}); err != ErrInvalidContainer {
+		t.Fatalf("expected ErrInvalidContainer for non-container, got %v", err)
+	}
+	pack.ContainerID = &pack.ID
+	if _, err := s.UpdateCharacterItem(*pack); err != ErrInvalidContainer {
+		t.Fatalf("expected ErrInvalidContainer for self-containment, got %v", err)
+	}
+	if _, err := s.SetCharacterCoins(CharacterCoin{CharacterID: c.ID, Gp: -1}); err != ErrInvalidCoins {
+		t.Fatalf("expected ErrInvalidCoins, got %v", err)
+	}
+
+	if err := s.DeleteCharacterItem(c.ID, pack.ID); err != nil {
+		t.Fatalf("delete backpack: %v", err)
+	}
+	if err := s.DeleteCharacterItem(c.ID, pack.ID); err != ErrItemNotFound {
+		t.Fatalf("expected ErrItemNotFound, got %v", err)
+	}
+}
+
+func TestMigrationConvertsLegacyEquipment(t *testing.T) {
+	s, err := NewFromPath(filepath.Join(t.TempDir(), "legacy.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		t.Fatalf("set dialect: %v", err)
+	}
+	goose.SetBaseFS(Migrations)
+	if err := goose.UpTo(s.DB(), "migrations", 14); err != nil {
+		t.Fatalf("migrate to 14: %v", err)
+	}
+
+	user, _ := s.CreateUser("veteran", "hash")
+	if _, err := s.DB().Exec(`INSERT INTO characters (user_id, name, equipment) VALUES (?, 'Old Timer', ?)`,
+		user.ID, `["Longsword", "15 gp", "Shield", "3 SP", "2 gems worth 50 gp", "1,000 gp", "10 gp"]`); err != nil {
+		t.Fatalf("insert legacy character: %v", err)
+	}
+
+	if err := goose.Up(s.DB(), "migrations"); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	chars, err := s.ListCharacters(user.ID)
+	if err != nil || len(chars) != 1 {
+		t.Fatalf("list characters: %v (%d)", err, len(chars))
+	}
+	inv, err := s.GetInventory(chars[0])
+	if err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	// Entries that aren't just a number of coins stay as items.
+	if len(inv.Items) != 4 || inv.Items[0].Name != "Longsword" || inv.Items[1].Name != "Shield" ||
+		inv.Items[2].Name != "2 gems worth 50 gp" || inv.Items[3].Name != "1,000 gp" {
+		t.Fatalf("items = %+v", inv.Items)
+	}
+	if inv.Coins.Gp != 25 || inv.Coins.Sp != 3 {
+		t.Fatalf("coins = %+v, want 25 gp 3 sp", inv.Coins)
+	}
+}
+
+func TestInventoryChangesMoveUpdatedAt(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("hoarder", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	// Backdate the character so the change can't land in the same millisecond.
+	if _, err := s.db.Exec(`UPDATE characters SET updated_at = '2020-01-01 00:00:00.000' WHERE id = ?`, c.ID); err != nil {
+		t.Fatalf("backdate character: %v", err)
+	}
+	before, _ := s.GetCharacter(c.ID, user.ID)
+
+	if _, err := s.SetCharacterCoins(CharacterCoin{CharacterID: c.ID, Gp: 5}); err != nil {
+		t.Fatalf("set coins: %v", err)
+	}
+	after, _ := s.GetCharacter(c.ID, user.ID)
+	if !after.UpdatedAt.After(before.UpdatedAt) {
+		t.Fatalf("updated_at = %v, was %v", after.UpdatedAt, before.UpdatedAt)
+	}
+}
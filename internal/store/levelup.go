@@ -0,0 +1,330 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+	"github.com/jasoncabot/dicewizard-characters/internal/rules"
+)
+
+// Hit point methods for a level-up.
+const (
+	HitPointsAverage = "average"
+	HitPointsRoll    = "roll"
+)
+
+// MaxLevel is the highest character level.
+const MaxLevel = 20
+
+// maxAbilityScore is the cap an ability score improvement cannot raise a score past.
+const maxAbilityScore = 20
+
+// ClassHitDice maps each class to the size of its hit die.
+var ClassHitDice = classHitDice()
+
+// abilityScoreImprovementLevels lists the levels at which every class gains an ability
+// score improvement (or a feat instead). Fighters and rogues get extra ones.
+var abilityScoreImprovementLevels = []int{4, 8, 12, 16, 19}
+
+var extraAbilityScoreImprovementLevels = map[string][]int{
+	"Fighter": {6, 14},
+	"Rogue":   {10},
+}
+
+// LevelUpChoice is what the player picks when gaining a level.
+type LevelUpChoice struct {
+	// Class is the class gaining the level, which defaults to the starting class. A new
+	// class starts a multiclass at level 1 if the character meets its prerequisites.
+	Class string
+	// HitPoints is HitPointsAverage (the default) or HitPointsRoll.
+	HitPoints string
+	// AbilityIncreases adds +2 to one ability or +1 to two, keyed by ability name.
+	AbilityIncreases map[string]int
+	// Feat is taken instead of ability increases.
+	Feat string
+	// Features are the class features gained at the new level.
+	Features []string
+}
+
+// LevelUpResult is the updated character and the recorded level-up.
+type LevelUpResult struct {
+	Character *CharacterWithStats `json:"character"`
+	LevelUp   CharacterLevelUp    `json:"levelUp"`
+	HitDie    int                 `json:"hitDie"`
+}
+
+// HitDie returns the size of the starting class's hit die, from the class or else the
+// first of the recorded hit dice.
+func (c *CharacterWithStats) HitDie() int {
+	if die, ok := ClassHitDice[c.Class]; ok {
+		return die
+	}
+	first, _, _ := strings.Cut(strings.ToLower(c.HitDice), "+")
+	if _, sides, ok := strings.Cut(first, "d"); ok {
+		if die, err := strconv.Atoi(sides); err == nil && die > 0 {
+			return die
+		}
+	}
+	return 8
+}
+
+// GrantsAbilityScoreImprovement reports whether reaching level gives the class an ability
+// score improvement or feat.
+func GrantsAbilityScoreImprovement(class string, level int) bool {
+	for _, l := range abilityScoreImprovementLevels {
+		if l == level {
+			return true
+		}
+	}
+	for _, l := range extraAbilityScoreImprovementLevels[class] {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// LevelUp advances the character by one level in the chosen class. Hit points are the
+// class's hit die average (rounded up) or a server roll, plus the Constitution modifier,
+// with a minimum of 1. Raising Constitution also raises hit points retroactively for
+// every earlier level. Ability score improvements follow the level in that class.
+func (s *Store) LevelUp(c *CharacterWithStats, choice LevelUpChoice, rng dice.RNG) (*LevelUpResult, error) {
+	if c.totalLevel() >= MaxLevel {
+		return nil, ErrMaxLevel
+	}
+	newLevel := c.totalLevel() + 1
+
+	class := c.Class
+	if choice.Class != "" {
+		class = canonicalClass(choice.Class)
+	}
+	previous := c.classLevels()
+	classes := make([]CharacterClass, 0, len(previous)+1)
+	classLevel := 1
+	for _, cl := range previous {
+		if cl.Class == class {
+			cl.Level++
+			classLevel = int(cl.Level)
+		}
+		classes = append(classes, cl)
+	}
+	if classLevel == 1 {
+		classes = append(classes, CharacterClass{Class: class, Level: 1})
+	}
+
+	method := choice.HitPoints
+	if method == "" {
+		method = HitPointsAverage
+	}
+	if method != HitPointsAverage && method != HitPointsRoll {
+		return nil, ErrInvalidHitPointMethod
+	}
+
+	increases, err := validateLevelUpChoice(c, class, classLevel, choice)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := s.characterCatalog(context.Background(), c)
+	if err != nil {
+		return nil, err
+	}
+	classFeatures, err := checkLevelUpFeatures(catalog, c, class, classLevel, choice.Features)
+	if err != nil {
+		return nil, err
+	}
+
+	features := parseStringArray(c.Features)
+	gained := make([]string, 0, len(classFeatures)+1)
+	if feat := strings.TrimSpace(choice.Feat); feat != "" {
+		gained = append(gained, feat)
+	}
+	gained = append(gained, classFeatures...)
+	for _, f := range gained {
+		if !containsKey(features, f) {
+			features = append(features, f)
+		}
+	}
+
+	if err := c.setClasses(classes, previous); err != nil {
+		return nil, err
+	}
+	c.applyClassRules(catalog)
+	die := c.classHitDie(class)
+	hpRoll := die/2 + 1
+	if method == HitPointsRoll {
+		result, err := dice.Roll(fmt.Sprintf("1d%d", die), rng)
+		if err != nil {
+			return nil, fmt.Errorf("failed to roll hit points: %w", err)
+		}
+		hpRoll = result.Total
+	}
+
+	oldConMod := c.ConstitutionModifier
+	for ability, amount := range increases {
+		*c.abilityScore(ability) += int64(amount)
+	}
+	c.Features = marshalStringArray(features)
+	c.ComputeModifiers()
+
+	hpGain := max(1, hpRoll+c.ConstitutionModifier) + (c.ConstitutionModifier-oldConMod)*(newLevel-1)
+	c.MaxHp += int64(hpGain)
+	c.CurrentHp += int64(hpGain)
+
+	increasesJSON, err := json.Marshal(increases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ability increases: %w", err)
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	if _, err := saveRevision(ctx, qtx, c.ID, c.UserID, RevisionReasonLevelUp); err != nil {
+		return nil, err
+	}
+	updated, err := qtx.UpdateCharacter(ctx, c.ToUpdateParams())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update character: %w", err)
+	}
+	if err := syncClasses(ctx, qtx, c.ID, c.Classes); err != nil {
+		return nil, err
+	}
+	record, err := qtx.InsertCharacterLevelUp(ctx, InsertCharacterLevelUpParams{
+		CharacterID:      c.ID,
+		Level:            int64(newLevel),
+		HpMethod:         method,
+		HpRoll:           int64(hpRoll),
+		HpGain:           int64(hpGain),
+		AbilityIncreases: string(increasesJSON),
+		Feat:             strings.TrimSpace(choice.Feat),
+		Features:         marshalStringArray(gained),
+		Class:            class,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record level up: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit level up: %w", err)
+	}
+
+	c.CharacterModel = characterToModel(updated)
+	if err := s.attachDetails(ctx, c); err != nil {
+		return nil, err
+	}
+	return &LevelUpResult{Character: c, LevelUp: record, HitDie: die}, nil
+}
+
+// ListLevelUps returns the character's recorded level-ups, oldest first.
+func (s *Store) ListLevelUps(characterID int64) ([]CharacterLevelUp, error) {
+	levelUps, err := s.q.ListCharacterLevelUps(context.Background(), characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list level ups: %w", err)
+	}
+	if levelUps == nil {
+		levelUps = []CharacterLevelUp{}
+	}
+	return levelUps, nil
+}
+
+// validateLevelUpChoice checks the ability score improvement or feat against the new
+// level in the class and returns the increases keyed by full ability name.
+func validateLevelUpChoice(c *CharacterWithStats, class string, classLevel int, choice LevelUpChoice) (map[string]int, error) {
+	increases := make(map[string]int, len(choice.AbilityIncreases))
+	total := 0
+	for name, amount := range choice.AbilityIncreases {
+		if amount == 0 {
+			continue
+		}
+		ability, ok := lookupAbility(name)
+		if !ok || amount < 0 {
+			return nil, ErrInvalidAbilityIncrease
+		}
+		increases[ability] += amount
+		total += amount
+	}
+	hasFeat := strings.TrimSpace(choice.Feat) != ""
+
+	if !GrantsAbilityScoreImprovement(class, classLevel) {
+		if total > 0 || hasFeat {
+			return nil, ErrAbilityIncreaseNotAvailable
+		}
+		return increases, nil
+	}
+
+	switch {
+	case hasFeat && total > 0, !hasFeat && total == 0:
+		return nil, ErrAbilityIncreaseRequired
+	case hasFeat:
+		return increases, nil
+	}
+	if total != 2 || len(increases) > 2 {
+		return nil, ErrInvalidAbilityIncrease
+	}
+	for ability, amount := range increases {
+		if int(*c.abilityScore(ability))+amount > maxAbilityScore {
+			return nil, ErrInvalidAbilityIncrease
+		}
+	}
+	return increases, nil
+}
+
+// checkLevelUpFeatures checks each chosen feature against the rules catalog and returns
+// them with their catalog names. A feature must be one the class gains at the new level
+// in it, or one of the features of the character's subclass in that class, which the
+// catalog does not tie to levels. Classes the catalog lists no features for, as homebrew
+// packs may leave them out, take features as given.
+func checkLevelUpFeatures(catalog *rules.Catalog, c *CharacterWithStats, class string, classLevel int, chosen []string) ([]string, error) {
+	var features []string
+	for _, f := range chosen {
+		if f = strings.TrimSpace(f); f != "" {
+			features = append(features, f)
+		}
+	}
+	entry, ok := catalog.LookupClass(class)
+	if !ok || len(entry.Features) == 0 {
+		return features, nil
+	}
+
+	allowed := entry.FeaturesAt(classLevel)
+	for _, cl := range c.classLevels() {
+		if subclass, ok := catalog.LookupSubclass(class, cl.Subclass); ok && cl.Class == class {
+			allowed = append(allowed, subclass.Features...)
+		}
+	}
+	for i, f := range features {
+		j := slices.IndexFunc(allowed, func(a string) bool { return normalizeKey(a) == normalizeKey(f) })
+		if j < 0 {
+			return nil, ErrInvalidLevelUpFeature
+		}
+		features[i] = allowed[j]
+	}
+	return features, nil
+}
+
+// abilityScore returns a pointer to the stored score for a full ability name.
+func (c *CharacterWithStats) abilityScore(ability string) *int64 {
+	switch ability {
+	case "strength":
+		return &c.Strength
+	case "dexterity":
+		return &c.Dexterity
+	case "constitution":
+		return &c.Constitution
+	case "intelligence":
+		return &c.Intelligence
+	case "wisdom":
+		return &c.Wisdom
+	case "charisma":
+		return &c.Charisma
+	}
+	return nil
+}
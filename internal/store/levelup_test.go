@@ -0,0 +1,110 @@
+package store
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+func TestLevelUpAppliesHitPointsAndImprovements(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("climber", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Level = 3
+	c.HitDice = "3d8"
+	c.Constitution = 13
+	c.MaxHp = 21
+	c.CurrentHp = 15
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	// Level 4 is a rogue ability score improvement, so a choice is required.
+	if _, err := s.LevelUp(c, LevelUpChoice{}, dice.NewSeededRNG(1)); err != ErrAbilityIncreaseRequired {
+		t.Fatalf("expected ErrAbilityIncreaseRequired, got %v", err)
+	}
+	bad := []map[string]int{
+		{"dex": 3},
+		{"str": 1, "dex": 1, "con": 1},
+		{"luck": 2},
+	}
+	for _, increases := range bad {
+		if _, err := s.LevelUp(c, LevelUpChoice{AbilityIncreases: increases}, dice.NewSeededRNG(1)); err != ErrInvalidAbilityIncrease {
+			t.Fatalf("%v: expected ErrInvalidAbilityIncrease, got %v", increases, err)
+		}
+	}
+	if _, err := s.LevelUp(c, LevelUpChoice{HitPoints: "max"}, dice.NewSeededRNG(1)); err != ErrInvalidHitPointMethod {
+		t.Fatalf("expected ErrInvalidHitPointMethod, got %v", err)
+	}
+
+	// Steady Aim is a 3rd level rogue feature, so it cannot be taken at 4th level.
+	if _, err := s.LevelUp(c, LevelUpChoice{
+		AbilityIncreases: map[string]int{"dexterity": 2},
+		Features:         []string{"Steady Aim"},
+	}, dice.NewSeededRNG(1)); err != ErrInvalidLevelUpFeature {
+		t.Fatalf("expected ErrInvalidLevelUpFeature, got %v", err)
+	}
+
+	// +1 CON takes the modifier from +1 to +2, adding 1 hit point for each earlier level.
+	result, err := s.LevelUp(c, LevelUpChoice{
+		AbilityIncreases: map[string]int{"dexterity": 1, "con": 1},
+	}, dice.NewSeededRNG(1))
+	if err != nil {
+		t.Fatalf("level up: %v", err)
+	}
+	got := result.Character
+	if got.Level != 4 || got.HitDice != "4d8" || got.Dexterity != 17 || got.Constitution != 14 {
+		t.Fatalf("character = level %d %s DEX %d CON %d", got.Level, got.HitDice, got.Dexterity, got.Constitution)
+	}
+	if got.MaxHp != 21+5+2+3 || got.CurrentHp != 15+5+2+3 {
+		t.Fatalf("hp = %d/%d, want 25/31", got.CurrentHp, got.MaxHp)
+	}
+	if result.LevelUp.HpRoll != 5 || result.LevelUp.HpGain != 10 || result.LevelUp.HpMethod != HitPointsAverage {
+		t.Fatalf("level up record = %+v", result.LevelUp)
+	}
+
+	// Level 5 has no improvement; the proficiency bonus goes up and hit points are rolled.
+	if _, err := s.LevelUp(got, LevelUpChoice{Feat: "Alert"}, dice.NewSeededRNG(1)); err != ErrAbilityIncreaseNotAvailable {
+		t.Fatalf("expected ErrAbilityIncreaseNotAvailable, got %v", err)
+	}
+	result, err = s.LevelUp(got, LevelUpChoice{HitPoints: HitPointsRoll, Features: []string{"uncanny dodge"}}, dice.NewSeededRNG(7))
+	if err != nil {
+		t.Fatalf("level up: %v", err)
+	}
+	if !slices.Contains(parseStringArray(result.Character.Features), "Uncanny Dodge") {
+		t.Fatalf("features = %s", result.Character.Features)
+	}
+	roll := result.LevelUp.HpRoll
+	if roll < 1 || roll > 8 || result.LevelUp.HpGain != roll+2 {
+		t.Fatalf("rolled %d for a gain of %d", roll, result.LevelUp.HpGain)
+	}
+	if result.Character.ProficiencyBonus != 3 {
+		t.Fatalf("proficiency bonus = %d, want 3", result.Character.ProficiencyBonus)
+	}
+
+	levelUps, err := s.ListLevelUps(c.ID)
+	if err != nil {
+		t.Fatalf("list level ups: %v", err)
+	}
+	if len(levelUps) != 2 || levelUps[0].AbilityIncreases != `{"constitution":1,"dexterity":1}` {
+		t.Fatalf("level ups = %+v", levelUps)
+	}
+}
+
+func TestLevelUpStopsAtMaxLevel(t *testing.T) {
+	c := newTestCharacter()
+	c.Level = MaxLevel
+
+	s := setupTestStore(t)
+	defer s.Close()
+	if _, err := s.LevelUp(c, LevelUpChoice{}, dice.NewSeededRNG(1)); err != ErrMaxLevel {
+		t.Fatalf("expected ErrMaxLevel, got %v", err)
+	}
+	if !GrantsAbilityScoreImprovement("Fighter", 6) || GrantsAbilityScoreImprovement("Wizard", 6) {
+		t.Fatalf("fighters should get an extra improvement at 6th level")
+	}
+}
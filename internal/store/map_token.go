@@ -5,12 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/jasoncabot/dicewizard-characters/internal/geometry"
 	"github.com/jasoncabot/dicewizard-characters/internal/models"
 )
 
-// CreateMapForCampaign inserts a map under the campaign's default scene, creating the scene if needed.
-func (s *Store) CreateMapForCampaign(campaignID, userID int64, name, baseImageURL string) (*models.Map, error) {
+// CreateMapForCampaign inserts a map under the given scene or, without one, the campaign's
+// default scene, creating the scene if needed.
+func (s *Store) CreateMapForCampaign(campaignID, userID int64, sceneID *int64, name, baseImageURL string) (*models.Map, error) {
 	role, status, err := s.getMembership(campaignID, userID)
 	if err != nil {
 		return nil, err
@@ -19,15 +22,26 @@ func (s *Store) CreateMapForCampaign(campaignID, userID int64, name, baseImageUR
 		return nil, ErrNotPermitted
 	}
 
-	defaultSceneID, err := s.ensureDefaultScene(campaignID, userID)
-	if err != nil {
-		return nil, err
-	}
-
 	ctx := context.Background()
 
+	var targetSceneID int64
+	if sceneID != nil {
+		if _, err := s.q.GetScene(ctx, GetSceneParams{ID: *sceneID, CampaignID: campaignID}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrSceneNotFound
+			}
+			return nil, fmt.Errorf("failed to get scene: %w", err)
+		}
+		targetSceneID = *sceneID
+	} else {
+		targetSceneID, err = s.ensureDefaultScene(campaignID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	m, err := s.q.CreateMap(ctx, CreateMapParams{
-		SceneID:      defaultSceneID,
+		SceneID:      targetSceneID,
 		Name:         name,
 		BaseImageUrl: &baseImageURL,
 	})
@@ -41,8 +55,10 @@ func (s *Store) CreateMapForCampaign(campaignID, userID int64, name, baseImageUR
 		Name:         m.Name,
 		BaseImageURL: m.BaseImageUrl,
 		GridSizeFt:   int(m.GridSizeFt),
+		WidthPx:      intPtr(m.WidthPx),
+		HeightPx:     intPtr(m.HeightPx),
 		LightingMode: m.LightingMode,
-		FogState:     m.FogState,
+		FogState:     parseFog(m.FogState),
 		CreatedAt:    m.CreatedAt,
 	}, nil
 }
@@ -68,7 +84,14 @@ func (s *Store) CreateToken(mapID, userID int64, characterID *int64, label, imag
 	if layer == "" {
 		layer = "token"
 	}
+	if !validTokenLayers[layer] {
+		return nil, ErrInvalidTokenLayer
+	}
 
+	audience, err = normalizeAudience(audience)
+	if err != nil {
+		return nil, err
+	}
 	audienceJSON := marshalStringArray(audience)
 	tagsJSON := marshalStringArray(tags)
 
@@ -92,50 +115,139 @@ func (s *Store) CreateToken(mapID, userID int64, characterID *int64, label, imag
 		return nil, fmt.Errorf("failed to create token: %w", err)
 	}
 
-	return &models.Token{
-		ID:          t.ID,
-		MapID:       t.MapID,
-		CharacterID: t.CharacterID,
-		Label:       t.Label,
-		ImageURL:    t.ImageUrl,
-		SizeSquares: int(t.SizeSquares),
-		PositionX:   int(t.PositionX),
-		PositionY:   int(t.PositionY),
-		FacingDeg:   int(t.FacingDeg),
-		Audience:    audience,
-		Tags:        tags,
-		Notes:       "",
-		Layer:       t.Layer,
-		CreatedBy:   t.CreatedBy,
-		CreatedAt:   t.CreatedAt,
-	}, nil
+	token := &models.Token{
+		ID:           t.ID,
+		MapID:        t.MapID,
+		CharacterID:  t.CharacterID,
+		Label:        t.Label,
+		ImageURL:     t.ImageUrl,
+		SizeSquares:  int(t.SizeSquares),
+		PositionX:    int(t.PositionX),
+		PositionY:    int(t.PositionY),
+		FacingDeg:    int(t.FacingDeg),
+		VisionFt:     int(t.VisionFt),
+		DarkvisionFt: int(t.DarkvisionFt),
+		Audience:     audience,
+		Tags:         tags,
+		Notes:        "",
+		Layer:        t.Layer,
+		CreatedBy:    t.CreatedBy,
+		CreatedAt:    t.CreatedAt,
+	}
+	if err := s.attachTokenConditions(ctx, token); err != nil {
+		return nil, err
+	}
+	return token, nil
 }
 
 // UpdateTokenPosition moves a token if the actor can edit the campaign.
 func (s *Store) UpdateTokenPosition(tokenID, userID int64, positionX, positionY int) (*models.Token, error) {
-	campaignID, _, err := s.getCampaignIDByToken(tokenID)
-	if err != nil {
+	if err := s.authorizeTokenEdit(tokenID, userID); err != nil {
 		return nil, err
 	}
 
-	role, status, err := s.getMembership(campaignID, userID)
+	ctx := context.Background()
+	err := s.q.UpdateTokenPosition(ctx, UpdateTokenPositionParams{
+		PositionX: int64(positionX),
+		PositionY: int64(positionY),
+		ID:        tokenID,
+	})
 	if err != nil {
+		return nil, fmt.Errorf("failed to update token: %w", err)
+	}
+
+	return s.getToken(ctx, tokenID)
+}
+
+// UpdateToken replaces a token's label, image, size, facing, audience, tags and notes if
+// the actor can edit the campaign. Position and layer have their own updates.
+func (s *Store) UpdateToken(tokenID, userID int64, label, imageURL string, sizeSquares, facingDeg int, audience, tags []string, notes string) (*models.Token, error) {
+	if err := s.authorizeTokenEdit(tokenID, userID); err != nil {
 		return nil, err
 	}
-	if status != "accepted" || (role != "owner" && role != "editor") {
-		return nil, ErrNotPermitted
+
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return nil, ErrInvalidToken
+	}
+	if sizeSquares <= 0 {
+		sizeSquares = 1
+	}
+	audience, err := normalizeAudience(audience)
+	if err != nil {
+		return nil, err
 	}
 
 	ctx := context.Background()
-	err = s.q.UpdateTokenPosition(ctx, UpdateTokenPositionParams{
-		PositionX: int64(positionX),
-		PositionY: int64(positionY),
-		ID:        tokenID,
+	err = s.q.UpdateToken(ctx, UpdateTokenParams{
+		Label:       label,
+		ImageUrl:    &imageURL,
+		SizeSquares: int64(sizeSquares),
+		FacingDeg:   int64(facingDeg),
+		Audience:    marshalStringArray(audience),
+		Tags:        marshalStringArray(tags),
+		Notes:       &notes,
+		ID:          tokenID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to update token: %w", err)
 	}
 
+	return s.getToken(ctx, tokenID)
+}
+
+// UpdateTokenLayer moves a token to the map, object, token or gm layer if the actor can
+// edit the campaign.
+func (s *Store) UpdateTokenLayer(tokenID, userID int64, layer string) (*models.Token, error) {
+	if err := s.authorizeTokenEdit(tokenID, userID); err != nil {
+		return nil, err
+	}
+	if !validTokenLayers[layer] {
+		return nil, ErrInvalidTokenLayer
+	}
+
+	ctx := context.Background()
+	if err := s.q.UpdateTokenLayer(ctx, UpdateTokenLayerParams{Layer: layer, ID: tokenID}); err != nil {
+		return nil, fmt.Errorf("failed to update token layer: %w", err)
+	}
+
+	return s.getToken(ctx, tokenID)
+}
+
+// DeleteToken removes a token from its map if the actor can edit the campaign.
+func (s *Store) DeleteToken(tokenID, userID int64) error {
+	if err := s.authorizeTokenEdit(tokenID, userID); err != nil {
+		return err
+	}
+
+	if err := s.q.DeleteToken(context.Background(), tokenID); err != nil {
+		return fmt.Errorf("failed to delete token: %w", err)
+	}
+	return nil
+}
+
+// validTokenLayers mirrors the CHECK constraint on tokens.layer.
+var validTokenLayers = map[string]bool{"map": true, "object": true, "token": true, "gm": true}
+
+// authorizeTokenEdit checks that the token exists and that the actor is an accepted owner
+// or editor of its campaign.
+func (s *Store) authorizeTokenEdit(tokenID, userID int64) error {
+	campaignID, _, err := s.getCampaignIDByToken(tokenID)
+	if err != nil {
+		return err
+	}
+
+	role, status, err := s.getMembership(campaignID, userID)
+	if err != nil {
+		return err
+	}
+	if status != "accepted" || (role != "owner" && role != "editor") {
+		return ErrNotPermitted
+	}
+	return nil
+}
+
+func (s *Store) getToken(ctx context.Context, tokenID int64) (*models.Token, error) {
 	t, err := s.q.GetTokenByID(ctx, tokenID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -144,22 +256,29 @@ func (s *Store) UpdateTokenPosition(tokenID, userID int64, positionX, positionY
 		return nil, fmt.Errorf("failed to fetch token: %w", err)
 	}
 
-	return &models.Token{
-		ID:          t.ID,
-		MapID:       t.MapID,
-		CharacterID: int64ToPtrOrNil(t.CharacterID),
-		Label:       t.Label,
-		ImageURL:    nullString(t.ImageUrl),
-		SizeSquares: int(t.SizeSquares),
-		PositionX:   int(t.PositionX),
-		PositionY:   int(t.PositionY),
-		FacingDeg:   int(t.FacingDeg),
-		Audience:    parseStringArray(t.Audience),
-		Tags:        parseStringArray(t.Tags),
-		Notes:       t.Notes,
-		CreatedBy:   int64ToPtrOrNil(t.CreatedBy),
-		CreatedAt:   t.CreatedAt,
-	}, nil
+	token := &models.Token{
+		ID:           t.ID,
+		MapID:        t.MapID,
+		CharacterID:  int64ToPtrOrNil(t.CharacterID),
+		Label:        t.Label,
+		ImageURL:     nullString(t.ImageUrl),
+		SizeSquares:  int(t.SizeSquares),
+		PositionX:    int(t.PositionX),
+		PositionY:    int(t.PositionY),
+		FacingDeg:    int(t.FacingDeg),
+		VisionFt:     int(t.VisionFt),
+		DarkvisionFt: int(t.DarkvisionFt),
+		Audience:     parseStringArray(t.Audience),
+		Tags:         parseStringArray(t.Tags),
+		Notes:        t.Notes,
+		Layer:        t.Layer,
+		CreatedBy:    int64ToPtrOrNil(t.CreatedBy),
+		CreatedAt:    t.CreatedAt,
+	}
+	if err := s.attachTokenConditions(ctx, token); err != nil {
+		return nil, err
+	}
+	return token, nil
 }
 
 // GetCampaignFull aggregates a campaign, members, characters, scenes/maps/tokens, and handouts in one payload.
@@ -192,7 +311,7 @@ func (s *Store) GetCampaignFull(campaignID, userID int64) (*models.CampaignFull,
 		}
 	}
 
-	scenes, err := s.listScenesWithMapsAndTokens(campaignID, role == "owner" || role == "editor", campaign.ActiveSceneID)
+	scenes, err := s.listScenesWithMapsAndTokens(campaignID, userID, role == "owner" || role == "editor", campaign.ActiveSceneID)
 	if err != nil {
 		return nil, err
 	}
@@ -222,7 +341,11 @@ func (s *Store) GetCampaignFull(campaignID, userID int64) (*models.CampaignFull,
 	}, nil
 }
 
-func (s *Store) listScenesWithMapsAndTokens(campaignID int64, isGM bool, activeSceneID *int64) ([]models.SceneWithMaps, error) {
+// listScenesWithMapsAndTokens loads the campaign's scenes with their maps and tokens. Players
+// only get the active scene, and only their own tokens and those whose audience includes
+// them outside the fog and, on maps with basic lighting, within their tokens' sight,
+// without the GM's notes.
+func (s *Store) listScenesWithMapsAndTokens(campaignID, userID int64, isGM bool, activeSceneID *int64) ([]models.SceneWithMaps, error) {
 	ctx := context.Background()
 
 	sceneRows, err := s.q.ListScenes(ctx, campaignID)
@@ -237,18 +360,8 @@ func (s *Store) listScenesWithMapsAndTokens(campaignID int64, isGM bool, activeS
 			continue
 		}
 		scenes = append(scenes, models.SceneWithMaps{
-			Scene: models.Scene{
-				ID:          r.ID,
-				CampaignID:  r.CampaignID,
-				Name:        r.Name,
-				Description: r.Description,
-				Ordering:    int(r.Ordering),
-				IsActive:    r.IsActive,
-				CreatedBy:   r.CreatedBy,
-				CreatedAt:   r.CreatedAt,
-				UpdatedAt:   r.UpdatedAt,
-			},
-			Maps: []models.MapWithTokens{},
+			Scene: sceneToModel(r),
+			Maps:  []models.MapWithTokens{},
 		})
 		sceneIDs = append(sceneIDs, r.ID)
 	}
@@ -264,7 +377,10 @@ func (s *Store) listScenesWithMapsAndTokens(campaignID int64, isGM bool, activeS
 
 	mapByScene := make(map[int64][]models.MapWithTokens)
 	mapIDs := make([]int64, 0, len(mapRows))
+	fogByMap := make(map[int64]*fogMask, len(mapRows))
 	for _, m := range mapRows {
+		fog := parseFog(m.FogState)
+		fogByMap[m.ID] = newFogMask(fog)
 		mapByScene[m.SceneID] = append(mapByScene[m.SceneID], models.MapWithTokens{
 			Map: models.Map{
 				ID:           m.ID,
@@ -272,10 +388,10 @@ func (s *Store) listScenesWithMapsAndTokens(campaignID int64, isGM bool, activeS
 				Name:         m.Name,
 				BaseImageURL: m.BaseImageUrl,
 				GridSizeFt:   int(m.GridSizeFt),
-				WidthPx:      ptr(int(m.WidthPx)),
-				HeightPx:     ptr(int(m.HeightPx)),
+				WidthPx:      intPtr(m.WidthPx),
+				HeightPx:     intPtr(m.HeightPx),
 				LightingMode: m.LightingMode,
-				FogState:     m.FogState,
+				FogState:     fog,
 				CreatedAt:    m.CreatedAt,
 			},
 			Tokens: []models.Token{},
@@ -285,6 +401,7 @@ func (s *Store) listScenesWithMapsAndTokens(campaignID int64, isGM bool, activeS
 
 	if len(mapIDs) > 0 {
 		tokensByMap := make(map[int64][]models.Token)
+		visionByMap := make(map[int64]geometry.Region)
 
 		if isGM {
 			tokenRows, err := s.q.ListTokensByMapIDs(ctx, mapIDs)
@@ -293,52 +410,94 @@ func (s *Store) listScenesWithMapsAndTokens(campaignID int64, isGM bool, activeS
 			}
 			for _, t := range tokenRows {
 				tokensByMap[t.MapID] = append(tokensByMap[t.MapID], models.Token{
-					ID:          t.ID,
-					MapID:       t.MapID,
-					CharacterID: t.CharacterID,
-					Label:       t.Label,
-					ImageURL:    t.ImageUrl,
-					SizeSquares: int(t.SizeSquares),
-					PositionX:   int(t.PositionX),
-					PositionY:   int(t.PositionY),
-					FacingDeg:   int(t.FacingDeg),
-					Audience:    parseStringArray(t.Audience),
-					Tags:        parseStringArray(t.Tags),
-					Notes:       t.Notes,
-					Layer:       t.Layer,
-					CreatedBy:   t.CreatedBy,
-					CreatedAt:   t.CreatedAt,
+					ID:           t.ID,
+					MapID:        t.MapID,
+					CharacterID:  t.CharacterID,
+					Label:        t.Label,
+					ImageURL:     t.ImageUrl,
+					SizeSquares:  int(t.SizeSquares),
+					PositionX:    int(t.PositionX),
+					PositionY:    int(t.PositionY),
+					FacingDeg:    int(t.FacingDeg),
+					VisionFt:     int(t.VisionFt),
+					DarkvisionFt: int(t.DarkvisionFt),
+					Audience:     parseStringArray(t.Audience),
+					Tags:         parseStringArray(t.Tags),
+					Notes:        t.Notes,
+					Layer:        t.Layer,
+					CreatedBy:    t.CreatedBy,
+					CreatedAt:    t.CreatedAt,
 				})
 			}
 		} else {
+			viewer, err := s.loadTokenViewer(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
 			tokenRows, err := s.q.ListTokensByMapIDsForPlayer(ctx, mapIDs)
 			if err != nil {
 				return nil, fmt.Errorf("failed to list tokens: %w", err)
 			}
 			for _, t := range tokenRows {
-				tokensByMap[t.MapID] = append(tokensByMap[t.MapID], models.Token{
-					ID:          t.ID,
-					MapID:       t.MapID,
-					CharacterID: t.CharacterID,
-					Label:       t.Label,
-					ImageURL:    t.ImageUrl,
-					SizeSquares: int(t.SizeSquares),
-					PositionX:   int(t.PositionX),
-					PositionY:   int(t.PositionY),
-					FacingDeg:   int(t.FacingDeg),
-					Audience:    parseStringArray(t.Audience),
-					Tags:        parseStringArray(t.Tags),
-					Notes:       t.Notes,
-					Layer:       t.Layer,
-					CreatedBy:   t.CreatedBy,
-					CreatedAt:   t.CreatedAt,
-				})
+				token := models.Token{
+					ID:           t.ID,
+					MapID:        t.MapID,
+					CharacterID:  t.CharacterID,
+					Label:        t.Label,
+					ImageURL:     t.ImageUrl,
+					SizeSquares:  int(t.SizeSquares),
+					PositionX:    int(t.PositionX),
+					PositionY:    int(t.PositionY),
+					FacingDeg:    int(t.FacingDeg),
+					VisionFt:     int(t.VisionFt),
+					DarkvisionFt: int(t.DarkvisionFt),
+					Audience:     parseStringArray(t.Audience),
+					Tags:         parseStringArray(t.Tags),
+					Notes:        t.Notes,
+					Layer:        t.Layer,
+					CreatedBy:    t.CreatedBy,
+					CreatedAt:    t.CreatedAt,
+				}
+				if viewer.owns(&token) || (viewer.canSee(&token) && fogByMap[t.MapID].reveals(&token)) {
+					tokensByMap[t.MapID] = append(tokensByMap[t.MapID], forPlayer(token))
+				}
 			}
+
+			// With basic lighting, players only see their own tokens and what those tokens
+			// can see.
+			for _, m := range mapRows {
+				if m.LightingMode != models.LightingBasic {
+					continue
+				}
+				sight, err := s.loadPlayerSight(ctx, m.ID, m.GridSizeFt, viewer, tokensByMap[m.ID])
+				if err != nil {
+					return nil, err
+				}
+				visible := []models.Token{}
+				for _, token := range tokensByMap[m.ID] {
+					if viewer.owns(&token) || sight.sees(&token) {
+						visible = append(visible, token)
+					}
+				}
+				tokensByMap[m.ID] = visible
+				visionByMap[m.ID] = sight.area
+			}
+		}
+
+		var tokens []*models.Token
+		for _, mapTokens := range tokensByMap {
+			for i := range mapTokens {
+				tokens = append(tokens, &mapTokens[i])
+			}
+		}
+		if err := s.attachTokenConditions(ctx, tokens...); err != nil {
+			return nil, err
 		}
 
 		for sceneID, maps := range mapByScene {
 			for i := range maps {
 				maps[i].Tokens = tokensByMap[maps[i].ID]
+				maps[i].Vision = visionByMap[maps[i].ID]
 			}
 			mapByScene[sceneID] = maps
 		}
@@ -361,7 +520,14 @@ func (s *Store) ensureDefaultScene(campaignID, userID int64) (int64, error) {
 		return 0, fmt.Errorf("failed to check default scene: %w", err)
 	}
 
-	scene, err := s.q.CreateScene(ctx, CreateSceneParams{
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	scene, err := qtx.CreateScene(ctx, CreateSceneParams{
 		CampaignID:  campaignID,
 		Name:        "Table",
 		Description: ptr(""),
@@ -372,6 +538,12 @@ func (s *Store) ensureDefaultScene(campaignID, userID int64) (int64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("failed to create default scene: %w", err)
 	}
+	if err := activateScene(ctx, qtx, campaignID, &scene.ID); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit default scene: %w", err)
+	}
 	return scene.ID, nil
 }
 
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+func TestTokens_Lifecycle(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	owner, _ := s.CreateUser("owner", "hash")
+	viewer, _ := s.CreateUser("viewer", "hash")
+	camp, err := s.CreateCampaign(owner.ID, "Quest", "", models.CampaignVisibilityPrivate, models.CampaignStatusNotStarted)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO campaign_members (campaign_id, user_id, role, status) VALUES (?, ?, 'viewer', 'accepted')`, camp.ID, viewer.ID); err != nil {
+		t.Fatalf("insert viewer: %v", err)
+	}
+
+	m, err := s.CreateMapForCampaign(camp.ID, owner.ID, nil, "Dungeon", "/uploads/dungeon.png")
+	if err != nil {
+		t.Fatalf("create map: %v", err)
+	}
+	if _, err := s.CreateToken(m.ID, owner.ID, nil, "Goblin", "", 1, 0, 0, 0, nil, nil, "floor"); err != ErrInvalidTokenLayer {
+		t.Fatalf("expected ErrInvalidTokenLayer, got %v", err)
+	}
+	token, err := s.CreateToken(m.ID, owner.ID, nil, "Goblin", "/uploads/goblin.png", 1, 2, 3, 90, []string{"gm-only"}, []string{"enemy"}, "")
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if token.Label != "Goblin" || token.ImageURL != "/uploads/goblin.png" || token.PositionX != 2 || token.PositionY != 3 || token.Layer != "token" {
+		t.Fatalf("unexpected token: %+v", token)
+	}
+
+	moved, err := s.UpdateTokenPosition(token.ID, owner.ID, 5, 6)
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if moved.PositionX != 5 || moved.PositionY != 6 || moved.Layer != "token" {
+		t.Fatalf("unexpected move: %+v", moved)
+	}
+
+	if _, err := s.UpdateToken(token.ID, viewer.ID, "Hobgoblin", "", 1, 0, nil, nil, ""); err != ErrNotPermitted {
+		t.Fatalf("viewer expected ErrNotPermitted, got %v", err)
+	}
+	if _, err := s.UpdateToken(token.ID, owner.ID, " ", "", 1, 0, nil, nil, ""); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+	updated, err := s.UpdateToken(token.ID, owner.ID, "Hobgoblin", "/uploads/hob.png", 2, 180, []string{"all"}, []string{"boss"}, "Flees at half HP")
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Label != "Hobgoblin" || updated.ImageURL != "/uploads/hob.png" || updated.SizeSquares != 2 || updated.FacingDeg != 180 ||
+		updated.Notes != "Flees at half HP" || len(updated.Tags) != 1 || updated.Tags[0] != "boss" || updated.Audience[0] != "all" {
+		t.Fatalf("unexpected update: %+v", updated)
+	}
+	if updated.PositionX != 5 {
+		t.Fatalf("update should keep position: %+v", updated)
+	}
+
+	if _, err := s.UpdateTokenLayer(token.ID, owner.ID, "sky"); err != ErrInvalidTokenLayer {
+		t.Fatalf("expected ErrInvalidTokenLayer, got %v", err)
+	}
+	hidden, err := s.UpdateTokenLayer(token.ID, owner.ID, "gm")
+	if err != nil {
+		t.Fatalf("layer: %v", err)
+	}
+	if hidden.Layer != "gm" {
+		t.Fatalf("unexpected layer: %+v", hidden)
+	}
+
+	full, err := s.GetCampaignFull(camp.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("campaign full: %v", err)
+	}
+	if len(full.Scenes) != 1 || len(full.Scenes[0].Maps) != 1 || len(full.Scenes[0].Maps[0].Tokens) != 1 {
+		t.Fatalf("owner should see the token: %+v", full.Scenes)
+	}
+
+	if err := s.DeleteToken(token.ID, viewer.ID); err != ErrNotPermitted {
+		t.Fatalf("viewer expected ErrNotPermitted, got %v", err)
+	}
+	if err := s.DeleteToken(token.ID, owner.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.DeleteToken(token.ID, owner.ID); err != ErrTokenNotFound {
+		t.Fatalf("expected ErrTokenNotFound, got %v", err)
+	}
+}
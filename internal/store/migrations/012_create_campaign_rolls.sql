@@ -0,0 +1,24 @@
+-- +goose Up
+-- Server-side dice rolls made inside a campaign. Each row carries a hash that
+-- chains it to the previous roll in the same campaign so edits are detectable.
+CREATE TABLE IF NOT EXISTS campaign_rolls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    character_id INTEGER,
+    label TEXT NOT NULL DEFAULT '',
+    expression TEXT NOT NULL,
+    result TEXT NOT NULL DEFAULT '{}',
+    total INTEGER NOT NULL,
+    visibility TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('public','gm-only','self')),
+    prev_hash TEXT NOT NULL DEFAULT '',
+    hash TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_campaign_rolls_campaign ON campaign_rolls(campaign_id, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS campaign_rolls;
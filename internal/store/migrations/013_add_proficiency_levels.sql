@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE characters ADD COLUMN proficiency_levels TEXT DEFAULT '{}';
+UPDATE characters SET proficiency_levels = '{}' WHERE proficiency_levels IS NULL;
+
+-- +goose Down
+ALTER TABLE characters DROP COLUMN proficiency_levels;
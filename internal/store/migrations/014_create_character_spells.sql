@@ -0,0 +1,30 @@
+-- +goose Up
+-- Spells a character knows or has prepared.
+CREATE TABLE IF NOT EXISTS character_spells (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    level INTEGER NOT NULL DEFAULT 0 CHECK (level BETWEEN 0 AND 9),
+    school TEXT NOT NULL DEFAULT '',
+    prepared BOOLEAN NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE,
+    UNIQUE (character_id, name)
+);
+CREATE INDEX IF NOT EXISTS idx_character_spells_character ON character_spells(character_id, level);
+
+-- Expended spell slots. The maximum per level is derived from class and level,
+-- so only the spent count is stored. Pact magic slots are tracked separately.
+CREATE TABLE IF NOT EXISTS character_spell_slots (
+    character_id INTEGER NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'spell' CHECK (kind IN ('spell','pact')),
+    slot_level INTEGER NOT NULL CHECK (slot_level BETWEEN 1 AND 9),
+    expended INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (character_id, kind, slot_level),
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS character_spell_slots;
+DROP TABLE IF EXISTS character_spells;
@@ -0,0 +1,82 @@
+-- +goose Up
+-- Structured inventory replacing the free-text characters.equipment array.
+CREATE TABLE IF NOT EXISTS character_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    container_id INTEGER,
+    name TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    weight REAL NOT NULL DEFAULT 0 CHECK (weight >= 0),
+    value_cp INTEGER NOT NULL DEFAULT 0 CHECK (value_cp >= 0),
+    equipped BOOLEAN NOT NULL DEFAULT 0,
+    attuned BOOLEAN NOT NULL DEFAULT 0,
+    is_container BOOLEAN NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE,
+    FOREIGN KEY (container_id) REFERENCES character_items(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_character_items_character ON character_items(character_id, position);
+
+CREATE TABLE IF NOT EXISTS character_coins (
+    character_id INTEGER PRIMARY KEY,
+    cp INTEGER NOT NULL DEFAULT 0 CHECK (cp >= 0),
+    sp INTEGER NOT NULL DEFAULT 0 CHECK (sp >= 0),
+    ep INTEGER NOT NULL DEFAULT 0 CHECK (ep >= 0),
+    gp INTEGER NOT NULL DEFAULT 0 CHECK (gp >= 0),
+    pp INTEGER NOT NULL DEFAULT 0 CHECK (pp >= 0),
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+-- Coin entries such as "15 gp" move into the purse; everything else becomes an item. Only
+-- a plain run of digits before the denomination counts as coins, so "1,000 gp" and
+-- "2 gems worth 50 gp" are kept as items rather than cast to 1 gp and 2 gp.
+WITH entries AS (
+    SELECT c.id AS character_id, j.key AS position, TRIM(j.value) AS name,
+           LOWER(TRIM(j.value)) AS entry
+    FROM characters c, json_each(CASE WHEN json_valid(c.equipment) THEN c.equipment ELSE '[]' END) j
+    WHERE j.type = 'text' AND TRIM(j.value) != ''
+), coins AS (
+    SELECT character_id, SUBSTR(entry, -2) AS denomination,
+           CAST(SUBSTR(entry, 1, LENGTH(entry) - 3) AS INTEGER) AS amount
+    FROM entries
+    WHERE (entry GLOB '*[0-9] [cseg]p' OR entry GLOB '*[0-9] pp')
+      AND SUBSTR(entry, 1, LENGTH(entry) - 3) NOT GLOB '*[^0-9]*'
+      AND LENGTH(entry) <= 15
+)
+INSERT INTO character_coins (character_id, cp, sp, ep, gp, pp)
+SELECT character_id,
+       SUM(CASE WHEN denomination = 'cp' THEN amount ELSE 0 END),
+       SUM(CASE WHEN denomination = 'sp' THEN amount ELSE 0 END),
+       SUM(CASE WHEN denomination = 'ep' THEN amount ELSE 0 END),
+       SUM(CASE WHEN denomination = 'gp' THEN amount ELSE 0 END),
+       SUM(CASE WHEN denomination = 'pp' THEN amount ELSE 0 END)
+FROM coins
+GROUP BY character_id;
+
+WITH entries AS (
+    SELECT c.id AS character_id, j.key AS position, TRIM(j.value) AS name,
+           LOWER(TRIM(j.value)) AS entry
+    FROM characters c, json_each(CASE WHEN json_valid(c.equipment) THEN c.equipment ELSE '[]' END) j
+    WHERE j.type = 'text' AND TRIM(j.value) != ''
+)
+INSERT INTO character_items (character_id, name, quantity, position)
+SELECT character_id, name, 1, position
+FROM entries
+WHERE NOT ((entry GLOB '*[0-9] [cseg]p' OR entry GLOB '*[0-9] pp')
+           AND SUBSTR(entry, 1, LENGTH(entry) - 3) NOT GLOB '*[^0-9]*'
+           AND LENGTH(entry) <= 15);
+
+ALTER TABLE characters DROP COLUMN equipment;
+
+-- +goose Down
+ALTER TABLE characters ADD COLUMN equipment TEXT DEFAULT '[]';
+UPDATE characters SET equipment = COALESCE((
+    SELECT json_group_array(name) FROM (
+        SELECT name FROM character_items WHERE character_id = characters.id ORDER BY position, id
+    )
+), '[]');
+DROP TABLE IF EXISTS character_coins;
+DROP TABLE IF EXISTS character_items;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- One row per level gained through the level-up workflow, recording the choices made.
+CREATE TABLE IF NOT EXISTS character_level_ups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    level INTEGER NOT NULL CHECK (level BETWEEN 2 AND 20),
+    hp_method TEXT NOT NULL CHECK (hp_method IN ('average','roll')),
+    hp_roll INTEGER NOT NULL,
+    hp_gain INTEGER NOT NULL,
+    ability_increases TEXT NOT NULL DEFAULT '{}',
+    feat TEXT NOT NULL DEFAULT '',
+    features TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_character_level_ups_character ON character_level_ups(character_id, level);
+
+-- +goose Down
+DROP TABLE IF EXISTS character_level_ups;
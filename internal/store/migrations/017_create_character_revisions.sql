@@ -0,0 +1,15 @@
+-- +goose Up
+-- Snapshots of a character sheet taken before each overwrite, so a bad save can be undone.
+CREATE TABLE IF NOT EXISTS character_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT 'update',
+    snapshot TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE,
+    UNIQUE (character_id, revision)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS character_revisions;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Hit dice spent since the last long rest; the total is the character's level.
+ALTER TABLE characters ADD COLUMN hit_dice_spent INTEGER DEFAULT 0;
+
+-- Short and long rests taken, so the GM can see who rested and what they recovered.
+CREATE TABLE IF NOT EXISTS character_rests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    rest_type TEXT NOT NULL CHECK (rest_type IN ('short','long')),
+    hit_dice_spent INTEGER NOT NULL DEFAULT 0,
+    hit_dice_recovered INTEGER NOT NULL DEFAULT 0,
+    hp_before INTEGER NOT NULL,
+    hp_after INTEGER NOT NULL,
+    roll TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_character_rests_character ON character_rests(character_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS character_rests;
+ALTER TABLE characters DROP COLUMN hit_dice_spent;
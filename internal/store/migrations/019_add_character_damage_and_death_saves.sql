@@ -0,0 +1,16 @@
+-- +goose Up
+-- Death saving throws made while at 0 hit points; three of either ends the dying state.
+ALTER TABLE characters ADD COLUMN death_save_successes INTEGER DEFAULT 0;
+ALTER TABLE characters ADD COLUMN death_save_failures INTEGER DEFAULT 0;
+
+-- Damage types, stored as JSON arrays, applied when damage is taken through the hp endpoint.
+ALTER TABLE characters ADD COLUMN damage_resistances TEXT DEFAULT '[]';
+ALTER TABLE characters ADD COLUMN damage_vulnerabilities TEXT DEFAULT '[]';
+ALTER TABLE characters ADD COLUMN damage_immunities TEXT DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE characters DROP COLUMN damage_immunities;
+ALTER TABLE characters DROP COLUMN damage_vulnerabilities;
+ALTER TABLE characters DROP COLUMN damage_resistances;
+ALTER TABLE characters DROP COLUMN death_save_failures;
+ALTER TABLE characters DROP COLUMN death_save_successes;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Conditions affecting a character, one row per condition. Exhaustion carries its level
+-- (1-6); the others use level 0. Duration is optional and counts rounds or minutes.
+CREATE TABLE IF NOT EXISTS character_conditions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    level INTEGER NOT NULL DEFAULT 0,
+    source TEXT NOT NULL DEFAULT '',
+    duration INTEGER,
+    duration_unit TEXT NOT NULL DEFAULT '' CHECK (duration_unit IN ('', 'rounds', 'minutes')),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (character_id, name),
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS character_conditions;
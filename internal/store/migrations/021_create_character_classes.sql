@@ -0,0 +1,24 @@
+-- +goose Up
+-- Levels in each class, for multiclass characters. characters.class stays the starting
+-- class and characters.level the total of these levels.
+CREATE TABLE IF NOT EXISTS character_classes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    class TEXT NOT NULL,
+    level INTEGER NOT NULL CHECK (level BETWEEN 1 AND 20),
+    position INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (character_id, class),
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+INSERT INTO character_classes (character_id, class, level, position)
+SELECT id, class, MIN(MAX(level, 1), 20), 0 FROM characters;
+
+-- The class that gained each recorded level.
+ALTER TABLE character_level_ups ADD COLUMN class TEXT NOT NULL DEFAULT '';
+UPDATE character_level_ups
+SET class = (SELECT c.class FROM characters c WHERE c.id = character_level_ups.character_id);
+
+-- +goose Down
+ALTER TABLE character_level_ups DROP COLUMN class;
+DROP TABLE IF EXISTS character_classes;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Limited-use features such as Rage, Ki or Channel Divinity. The maximum is a formula
+-- worked out from the character's level and abilities; used counts the uses spent since
+-- the resource last reset on a short rest, a long rest or at dawn.
+CREATE TABLE IF NOT EXISTS character_resources (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    max_formula TEXT NOT NULL,
+    used INTEGER NOT NULL DEFAULT 0,
+    reset TEXT NOT NULL CHECK (reset IN ('short', 'long', 'dawn')),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (character_id, name),
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS character_resources;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Weapon and spell attacks. The attack bonus and damage are worked out from the
+-- character's ability modifiers and proficiency bonus; ability is left empty to pick it
+-- from the kind and properties (e.g. finesse). properties is a JSON array of weapon
+-- properties and bonus is a magic bonus added to both attack and damage rolls.
+CREATE TABLE IF NOT EXISTS character_attacks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('melee', 'ranged', 'spell')),
+    ability TEXT NOT NULL DEFAULT '',
+    proficient BOOLEAN NOT NULL DEFAULT 1,
+    damage_dice TEXT NOT NULL DEFAULT '',
+    versatile_dice TEXT NOT NULL DEFAULT '',
+    damage_type TEXT NOT NULL DEFAULT '',
+    properties TEXT NOT NULL DEFAULT '[]',
+    bonus INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_character_attacks_character ON character_attacks(character_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_character_attacks_character;
+DROP TABLE IF EXISTS character_attacks;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Armor Class is worked out from equipped armor and shields. armor_type is light, medium,
+-- heavy or shield; armor_class is the armor's base AC or the shield's bonus.
+ALTER TABLE character_items ADD COLUMN armor_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE character_items ADD COLUMN armor_class INTEGER NOT NULL DEFAULT 0;
+
+-- Flat bonuses such as a Ring of Protection, and an override that keeps the typed-in
+-- armor_class. Existing characters keep their typed-in AC until the override is cleared.
+ALTER TABLE characters ADD COLUMN armor_class_override BOOLEAN DEFAULT 0;
+ALTER TABLE characters ADD COLUMN armor_class_bonus INTEGER DEFAULT 0;
+UPDATE characters SET armor_class_override = 1;
+
+-- +goose Down
+ALTER TABLE characters DROP COLUMN armor_class_bonus;
+ALTER TABLE characters DROP COLUMN armor_class_override;
+ALTER TABLE character_items DROP COLUMN armor_class;
+ALTER TABLE character_items DROP COLUMN armor_type;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Homebrew content packs. A pack belongs to the user who uploaded it, or to a campaign
+-- when campaign_id is set, and is unique by name within that scope. content is the
+-- validated pack as JSON.
+CREATE TABLE IF NOT EXISTS homebrew_packs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    campaign_id INTEGER,
+    name TEXT NOT NULL,
+    version TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_homebrew_packs_user_name ON homebrew_packs(user_id, name) WHERE campaign_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_homebrew_packs_campaign_name ON homebrew_packs(campaign_id, name) WHERE campaign_id IS NOT NULL;
+
+-- The subclass chosen in each class, from the rules catalog or a homebrew pack.
+ALTER TABLE character_classes ADD COLUMN subclass TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE character_classes DROP COLUMN subclass;
+DROP INDEX IF EXISTS idx_homebrew_packs_campaign_name;
+DROP INDEX IF EXISTS idx_homebrew_packs_user_name;
+DROP TABLE IF EXISTS homebrew_packs;
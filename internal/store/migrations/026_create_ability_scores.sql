@@ -0,0 +1,30 @@
+-- +goose Up
+-- Six 4d6-drop-lowest rolls made on the server. A roll is claimed by the character
+-- created with it and cannot be used again.
+CREATE TABLE IF NOT EXISTS ability_score_rolls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    character_id INTEGER,
+    rolls TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_ability_score_rolls_user ON ability_score_rolls(user_id);
+
+-- How a character's starting ability scores were generated, kept as an audit.
+CREATE TABLE IF NOT EXISTS character_ability_scores (
+    character_id INTEGER PRIMARY KEY,
+    method TEXT NOT NULL CHECK (method IN ('point-buy','standard-array','rolled','manual')),
+    base TEXT NOT NULL DEFAULT '{}',
+    increases TEXT NOT NULL DEFAULT '{}',
+    roll_id INTEGER,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE,
+    FOREIGN KEY (roll_id) REFERENCES ability_score_rolls(id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS character_ability_scores;
+DROP TABLE IF EXISTS ability_score_rolls;
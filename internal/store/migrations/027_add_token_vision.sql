@@ -0,0 +1,13 @@
+-- +goose Up
+-- How far a token sees, in feet: vision_ft is the light it carries or the range of its
+-- sight, darkvision_ft its darkvision. Maps in basic lighting use the farther of the two.
+ALTER TABLE tokens ADD COLUMN vision_ft INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tokens ADD COLUMN darkvision_ft INTEGER NOT NULL DEFAULT 0;
+
+-- Walls and doors are kept as layers of type 'wall' or 'door'.
+CREATE INDEX IF NOT EXISTS idx_layers_map ON layers(map_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_layers_map;
+ALTER TABLE tokens DROP COLUMN darkvision_ft;
+ALTER TABLE tokens DROP COLUMN vision_ft;
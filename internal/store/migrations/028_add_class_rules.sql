@@ -0,0 +1,9 @@
+-- +goose Up
+-- The hit die and spellcasting ability of each class a character has, copied from the
+-- rules catalog so that homebrew classes keep them. 0 and '' mean the SRD values apply.
+ALTER TABLE character_classes ADD COLUMN hit_die INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE character_classes ADD COLUMN spellcasting_ability TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE character_classes DROP COLUMN spellcasting_ability;
+ALTER TABLE character_classes DROP COLUMN hit_die;
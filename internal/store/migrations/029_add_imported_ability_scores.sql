@@ -0,0 +1,41 @@
+-- +goose Up
+-- Imported characters record their scores with the method 'imported'. SQLite can't
+-- change a CHECK constraint, so the table is rebuilt. 'manual' is kept for characters
+-- created before it was removed.
+CREATE TABLE character_ability_scores_new (
+    character_id INTEGER PRIMARY KEY,
+    method TEXT NOT NULL CHECK (method IN ('point-buy','standard-array','rolled','manual','imported')),
+    base TEXT NOT NULL DEFAULT '{}',
+    increases TEXT NOT NULL DEFAULT '{}',
+    roll_id INTEGER,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE,
+    FOREIGN KEY (roll_id) REFERENCES ability_score_rolls(id)
+);
+
+INSERT INTO character_ability_scores_new (character_id, method, base, increases, roll_id, created_at)
+SELECT character_id, method, base, increases, roll_id, created_at
+FROM character_ability_scores;
+
+DROP TABLE character_ability_scores;
+ALTER TABLE character_ability_scores_new RENAME TO character_ability_scores;
+
+-- +goose Down
+CREATE TABLE character_ability_scores_old (
+    character_id INTEGER PRIMARY KEY,
+    method TEXT NOT NULL CHECK (method IN ('point-buy','standard-array','rolled','manual')),
+    base TEXT NOT NULL DEFAULT '{}',
+    increases TEXT NOT NULL DEFAULT '{}',
+    roll_id INTEGER,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE,
+    FOREIGN KEY (roll_id) REFERENCES ability_score_rolls(id)
+);
+
+INSERT INTO character_ability_scores_old (character_id, method, base, increases, roll_id, created_at)
+SELECT character_id, method, base, increases, roll_id, created_at
+FROM character_ability_scores
+WHERE method != 'imported';
+
+DROP TABLE character_ability_scores;
+ALTER TABLE character_ability_scores_old RENAME TO character_ability_scores;
@@ -8,6 +8,14 @@ import (
 	"time"
 )
 
+type AbilityScoreRoll struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"userId"`
+	CharacterID *int64    `json:"characterId"`
+	Rolls       string    `json:"rolls"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
 type Campaign struct {
 	ID            int64     `json:"id"`
 	OwnerID       int64     `json:"ownerId"`
@@ -61,6 +69,21 @@ type CampaignMember struct {
 	CreatedAt  time.Time `json:"createdAt"`
 }
 
+type CampaignRoll struct {
+	ID          int64     `json:"id"`
+	CampaignID  int64     `json:"campaignId"`
+	UserID      int64     `json:"userId"`
+	CharacterID *int64    `json:"characterId"`
+	Label       string    `json:"label"`
+	Expression  string    `json:"expression"`
+	Result      string    `json:"result"`
+	Total       int64     `json:"total"`
+	Visibility  string    `json:"visibility"`
+	PrevHash    string    `json:"prevHash"`
+	Hash        string    `json:"hash"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
 type Character struct {
 	ID                       int64     `json:"id"`
 	UserID                   int64     `json:"userId"`
@@ -86,12 +109,168 @@ type Character struct {
 	SkillProficiencies       *string   `json:"skillProficiencies"`
 	SavingThrowProficiencies *string   `json:"savingThrowProficiencies"`
 	Features                 *string   `json:"features"`
-	Equipment                *string   `json:"equipment"`
 	AvatarUrl                *string   `json:"avatarUrl"`
+	ProficiencyLevels        *string   `json:"proficiencyLevels"`
+	HitDiceSpent             *int64    `json:"hitDiceSpent"`
+	DeathSaveSuccesses       *int64    `json:"deathSaveSuccesses"`
+	DeathSaveFailures        *int64    `json:"deathSaveFailures"`
+	DamageResistances        *string   `json:"damageResistances"`
+	DamageVulnerabilities    *string   `json:"damageVulnerabilities"`
+	DamageImmunities         *string   `json:"damageImmunities"`
+	ArmorClassOverride       *bool     `json:"armorClassOverride"`
+	ArmorClassBonus          *int64    `json:"armorClassBonus"`
 	CreatedAt                time.Time `json:"createdAt"`
 	UpdatedAt                time.Time `json:"updatedAt"`
 }
 
+type CharacterAbilityScore struct {
+	CharacterID int64     `json:"characterId"`
+	Method      string    `json:"method"`
+	Base        string    `json:"base"`
+	Increases   string    `json:"increases"`
+	RollID      *int64    `json:"rollId"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type CharacterAttack struct {
+	ID            int64     `json:"id"`
+	CharacterID   int64     `json:"characterId"`
+	Name          string    `json:"name"`
+	Kind          string    `json:"kind"`
+	Ability       string    `json:"ability"`
+	Proficient    bool      `json:"proficient"`
+	DamageDice    string    `json:"damageDice"`
+	VersatileDice string    `json:"versatileDice"`
+	DamageType    string    `json:"damageType"`
+	Properties    string    `json:"properties"`
+	Bonus         int64     `json:"bonus"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type CharacterClass struct {
+	ID                  int64  `json:"id"`
+	CharacterID         int64  `json:"characterId"`
+	Class               string `json:"class"`
+	Level               int64  `json:"level"`
+	Position            int64  `json:"position"`
+	Subclass            string `json:"subclass"`
+	HitDie              int64  `json:"hitDie"`
+	SpellcastingAbility string `json:"spellcastingAbility"`
+}
+
+type CharacterCoin struct {
+	CharacterID int64 `json:"characterId"`
+	Cp          int64 `json:"cp"`
+	Sp          int64 `json:"sp"`
+	Ep          int64 `json:"ep"`
+	Gp          int64 `json:"gp"`
+	Pp          int64 `json:"pp"`
+}
+
+type CharacterCondition struct {
+	ID           int64     `json:"id"`
+	CharacterID  int64     `json:"characterId"`
+	Name         string    `json:"name"`
+	Level        int64     `json:"level"`
+	Source       string    `json:"source"`
+	Duration     *int64    `json:"duration"`
+	DurationUnit string    `json:"durationUnit"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type CharacterItem struct {
+	ID          int64     `json:"id"`
+	CharacterID int64     `json:"characterId"`
+	ContainerID *int64    `json:"containerId"`
+	Name        string    `json:"name"`
+	Quantity    int64     `json:"quantity"`
+	Weight      float64   `json:"weight"`
+	ValueCp     int64     `json:"valueCp"`
+	Equipped    bool      `json:"equipped"`
+	Attuned     bool      `json:"attuned"`
+	IsContainer bool      `json:"isContainer"`
+	ArmorType   string    `json:"armorType"`
+	ArmorClass  int64     `json:"armorClass"`
+	Notes       string    `json:"notes"`
+	Position    int64     `json:"position"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type CharacterLevelUp struct {
+	ID               int64     `json:"id"`
+	CharacterID      int64     `json:"characterId"`
+	Level            int64     `json:"level"`
+	HpMethod         string    `json:"hpMethod"`
+	HpRoll           int64     `json:"hpRoll"`
+	HpGain           int64     `json:"hpGain"`
+	AbilityIncreases string    `json:"abilityIncreases"`
+	Feat             string    `json:"feat"`
+	Features         string    `json:"features"`
+	CreatedAt        time.Time `json:"createdAt"`
+	Class            string    `json:"class"`
+}
+
+type CharacterResource struct {
+	ID          int64     `json:"id"`
+	CharacterID int64     `json:"characterId"`
+	Name        string    `json:"name"`
+	MaxFormula  string    `json:"maxFormula"`
+	Used        int64     `json:"used"`
+	Reset       string    `json:"reset"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type CharacterRest struct {
+	ID               int64     `json:"id"`
+	CharacterID      int64     `json:"characterId"`
+	RestType         string    `json:"restType"`
+	HitDiceSpent     int64     `json:"hitDiceSpent"`
+	HitDiceRecovered int64     `json:"hitDiceRecovered"`
+	HpBefore         int64     `json:"hpBefore"`
+	HpAfter          int64     `json:"hpAfter"`
+	Roll             string    `json:"roll"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+type CharacterRevision struct {
+	ID          int64     `json:"id"`
+	CharacterID int64     `json:"characterId"`
+	Revision    int64     `json:"revision"`
+	Reason      string    `json:"reason"`
+	Snapshot    string    `json:"snapshot"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type CharacterSpell struct {
+	ID          int64     `json:"id"`
+	CharacterID int64     `json:"characterId"`
+	Name        string    `json:"name"`
+	Level       int64     `json:"level"`
+	School      string    `json:"school"`
+	Prepared    bool      `json:"prepared"`
+	Notes       string    `json:"notes"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type CharacterSpellSlot struct {
+	CharacterID int64  `json:"characterId"`
+	Kind        string `json:"kind"`
+	SlotLevel   int64  `json:"slotLevel"`
+	Expended    int64  `json:"expended"`
+}
+
+type HomebrewPack struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"userId"`
+	CampaignID *int64    `json:"campaignId"`
+	Name       string    `json:"name"`
+	Version    string    `json:"version"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
 type Layer struct {
 	ID     int64  `json:"id"`
 	MapID  int64  `json:"mapId"`
@@ -143,21 +322,23 @@ type Scene struct {
 }
 
 type Token struct {
-	ID          int64     `json:"id"`
-	MapID       int64     `json:"mapId"`
-	CharacterID *int64    `json:"characterId"`
-	Label       string    `json:"label"`
-	ImageUrl    *string   `json:"imageUrl"`
-	SizeSquares int64     `json:"sizeSquares"`
-	PositionX   int64     `json:"positionX"`
-	PositionY   int64     `json:"positionY"`
-	FacingDeg   int64     `json:"facingDeg"`
-	Audience    string    `json:"audience"`
-	Tags        string    `json:"tags"`
-	Notes       *string   `json:"notes"`
-	Layer       string    `json:"layer"`
-	CreatedBy   *int64    `json:"createdBy"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID           int64     `json:"id"`
+	MapID        int64     `json:"mapId"`
+	CharacterID  *int64    `json:"characterId"`
+	Label        string    `json:"label"`
+	ImageUrl     *string   `json:"imageUrl"`
+	SizeSquares  int64     `json:"sizeSquares"`
+	PositionX    int64     `json:"positionX"`
+	PositionY    int64     `json:"positionY"`
+	FacingDeg    int64     `json:"facingDeg"`
+	Audience     string    `json:"audience"`
+	Tags         string    `json:"tags"`
+	Notes        *string   `json:"notes"`
+	Layer        string    `json:"layer"`
+	CreatedBy    *int64    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	VisionFt     int64     `json:"visionFt"`
+	DarkvisionFt int64     `json:"darkvisionFt"`
 }
 
 type User struct {
@@ -19,8 +19,8 @@ WHERE id = ?;
 SELECT id, user_id, name, race, class, level, COALESCE(background, '') as background, COALESCE(alignment, '') as alignment, COALESCE(experience_points, 0) as experience_points,
        strength, dexterity, constitution, intelligence, wisdom, charisma,
        max_hp, current_hp, COALESCE(temp_hp, 0) as temp_hp, armor_class, COALESCE(speed, 0) as speed, COALESCE(hit_dice, '') as hit_dice,
-       COALESCE(skill_proficiencies, '[]') as skill_proficiencies, COALESCE(saving_throw_proficiencies, '[]') as saving_throw_proficiencies, COALESCE(features, '[]') as features, COALESCE(equipment, '[]') as equipment,
-       COALESCE(avatar_url, '') as avatar_url, created_at, updated_at
+       COALESCE(skill_proficiencies, '[]') as skill_proficiencies, COALESCE(saving_throw_proficiencies, '[]') as saving_throw_proficiencies, COALESCE(features, '[]') as features,
+       COALESCE(avatar_url, '') as avatar_url, COALESCE(proficiency_levels, '{}') as proficiency_levels, COALESCE(hit_dice_spent, 0) as hit_dice_spent, COALESCE(death_save_successes, 0) as death_save_successes, COALESCE(death_save_failures, 0) as death_save_failures, COALESCE(damage_resistances, '[]') as damage_resistances, COALESCE(damage_vulnerabilities, '[]') as damage_vulnerabilities, COALESCE(damage_immunities, '[]') as damage_immunities, COALESCE(armor_class_override, 0) as armor_class_override, COALESCE(armor_class_bonus, 0) as armor_class_bonus, created_at, updated_at
 FROM characters
 WHERE user_id = ?
 ORDER BY updated_at DESC;
@@ -29,8 +29,8 @@ ORDER BY updated_at DESC;
 SELECT id, user_id, name, race, class, level, COALESCE(background, '') as background, COALESCE(alignment, '') as alignment, COALESCE(experience_points, 0) as experience_points,
        strength, dexterity, constitution, intelligence, wisdom, charisma,
        max_hp, current_hp, COALESCE(temp_hp, 0) as temp_hp, armor_class, COALESCE(speed, 0) as speed, COALESCE(hit_dice, '') as hit_dice,
-       COALESCE(skill_proficiencies, '[]') as skill_proficiencies, COALESCE(saving_throw_proficiencies, '[]') as saving_throw_proficiencies, COALESCE(features, '[]') as features, COALESCE(equipment, '[]') as equipment,
-       COALESCE(avatar_url, '') as avatar_url, created_at, updated_at
+       COALESCE(skill_proficiencies, '[]') as skill_proficiencies, COALESCE(saving_throw_proficiencies, '[]') as saving_throw_proficiencies, COALESCE(features, '[]') as features,
+       COALESCE(avatar_url, '') as avatar_url, COALESCE(proficiency_levels, '{}') as proficiency_levels, COALESCE(hit_dice_spent, 0) as hit_dice_spent, COALESCE(death_save_successes, 0) as death_save_successes, COALESCE(death_save_failures, 0) as death_save_failures, COALESCE(damage_resistances, '[]') as damage_resistances, COALESCE(damage_vulnerabilities, '[]') as damage_vulnerabilities, COALESCE(damage_immunities, '[]') as damage_immunities, COALESCE(armor_class_override, 0) as armor_class_override, COALESCE(armor_class_bonus, 0) as armor_class_bonus, created_at, updated_at
 FROM characters
 WHERE id = ? AND user_id = ?;
 
@@ -39,41 +39,55 @@ INSERT INTO characters (
     user_id, name, race, class, level, background, alignment, experience_points,
     strength, dexterity, constitution, intelligence, wisdom, charisma,
     max_hp, current_hp, temp_hp, armor_class, speed, hit_dice,
-    skill_proficiencies, saving_throw_proficiencies, features, equipment,
-    avatar_url
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    skill_proficiencies, saving_throw_proficiencies, features,
+    avatar_url, proficiency_levels, hit_dice_spent, death_save_successes, death_save_failures, damage_resistances, damage_vulnerabilities, damage_immunities, armor_class_override, armor_class_bonus
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING id, user_id, name, race, class, level, background, alignment, experience_points,
           strength, dexterity, constitution, intelligence, wisdom, charisma,
           max_hp, current_hp, temp_hp, armor_class, speed, hit_dice,
-          skill_proficiencies, saving_throw_proficiencies, features, equipment,
-          avatar_url, created_at, updated_at;
+          skill_proficiencies, saving_throw_proficiencies, features,
+          avatar_url, proficiency_levels, hit_dice_spent, death_save_successes, death_save_failures, damage_resistances, damage_vulnerabilities, damage_immunities, armor_class_override, armor_class_bonus, created_at, updated_at;
 
 -- name: UpdateCharacter :one
 UPDATE characters SET
     name = ?, race = ?, class = ?, level = ?, background = ?, alignment = ?, experience_points = ?,
     strength = ?, dexterity = ?, constitution = ?, intelligence = ?, wisdom = ?, charisma = ?,
     max_hp = ?, current_hp = ?, temp_hp = ?, armor_class = ?, speed = ?, hit_dice = ?,
-    skill_proficiencies = ?, saving_throw_proficiencies = ?, features = ?, equipment = ?,
-    updated_at = CURRENT_TIMESTAMP
+    skill_proficiencies = ?, saving_throw_proficiencies = ?, features = ?,
+    proficiency_levels = ?,
+    hit_dice_spent = ?,
+    death_save_successes = ?,
+    death_save_failures = ?,
+    damage_resistances = ?,
+    damage_vulnerabilities = ?,
+    damage_immunities = ?,
+    armor_class_override = ?,
+    armor_class_bonus = ?,
+    updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
 WHERE id = ? AND user_id = ?
 RETURNING id, user_id, name, race, class, level, background, alignment, experience_points,
           strength, dexterity, constitution, intelligence, wisdom, charisma,
           max_hp, current_hp, temp_hp, armor_class, speed, hit_dice,
-          skill_proficiencies, saving_throw_proficiencies, features, equipment,
-          avatar_url, created_at, updated_at;
+          skill_proficiencies, saving_throw_proficiencies, features,
+          avatar_url, proficiency_levels, hit_dice_spent, death_save_successes, death_save_failures, damage_resistances, damage_vulnerabilities, damage_immunities, armor_class_override, armor_class_bonus, created_at, updated_at;
 
 -- name: DeleteCharacter :execrows
 DELETE FROM characters WHERE id = ? AND user_id = ?;
 
+-- name: TouchCharacter :exec
+UPDATE characters
+SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+WHERE id = ?;
+
 -- name: UpdateCharacterAvatar :one
 UPDATE characters
-SET avatar_url = ?, updated_at = CURRENT_TIMESTAMP
+SET avatar_url = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
 WHERE id = ? AND user_id = ?
 RETURNING id, user_id, name, race, class, level, background, alignment, experience_points,
           strength, dexterity, constitution, intelligence, wisdom, charisma,
           max_hp, current_hp, temp_hp, armor_class, speed, hit_dice,
-          skill_proficiencies, saving_throw_proficiencies, features, equipment,
-          avatar_url, created_at, updated_at;
+          skill_proficiencies, saving_throw_proficiencies, features,
+          avatar_url, proficiency_levels, hit_dice_spent, death_save_successes, death_save_failures, damage_resistances, damage_vulnerabilities, damage_immunities, armor_class_override, armor_class_bonus, created_at, updated_at;
 
 -- Campaign queries
 -- name: InsertCampaign :one
@@ -205,8 +219,20 @@ UPDATE tokens
 SET position_x = ?, position_y = ?
 WHERE id = ?;
 
+-- name: UpdateToken :exec
+UPDATE tokens
+SET label = ?, image_url = ?, size_squares = ?, facing_deg = ?, audience = ?, tags = ?, notes = ?
+WHERE id = ?;
+
+-- name: ListCharacterIDsByUser :many
+SELECT id FROM characters WHERE user_id = ?;
+
+-- name: DeleteToken :exec
+DELETE FROM tokens
+WHERE id = ?;
+
 -- name: GetTokenByID :one
-SELECT id, map_id, COALESCE(character_id, 0) as character_id, label, image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, COALESCE(created_by, 0) as created_by, created_at
+SELECT id, map_id, COALESCE(character_id, 0) as character_id, label, image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, COALESCE(created_by, 0) as created_by, created_at, vision_ft, darkvision_ft
 FROM tokens
 WHERE id = ?;
 
@@ -244,12 +270,12 @@ SELECT 1 FROM campaign_invites WHERE code = ?;
 -- name: CreateMap :one
 INSERT INTO maps (scene_id, name, base_image_url)
 VALUES (?, ?, ?)
-RETURNING id, scene_id, name, COALESCE(base_image_url, '') as base_image_url, grid_size_ft, CAST(width_px AS INTEGER) as width_px, CAST(height_px AS INTEGER) as height_px, lighting_mode, fog_state, created_at;
+RETURNING id, scene_id, name, COALESCE(base_image_url, '') as base_image_url, grid_size_ft, width_px, height_px, lighting_mode, fog_state, created_at;
 
 -- name: CreateToken :one
 INSERT INTO tokens (map_id, character_id, label, image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, notes, created_by)
-VALUES (?, sqlc.narg('character_id'), ?, CAST(sqlc.arg('image_url') AS TEXT), ?, ?, ?, ?, ?, ?, ?, '', sqlc.narg('created_by'))
-RETURNING id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at;
+VALUES (?, ?, ?, CAST(? AS TEXT), ?, ?, ?, ?, ?, ?, ?, '', ?)
+RETURNING id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at, vision_ft, darkvision_ft;
 
 -- name: ListScenes :many
 SELECT id, campaign_id, name, COALESCE(description, '') as description, ordering, is_active, created_by, created_at, updated_at
@@ -258,19 +284,19 @@ WHERE campaign_id = ?
 ORDER BY ordering ASC, id ASC;
 
 -- name: ListMapsBySceneIDs :many
-SELECT id, scene_id, name, COALESCE(base_image_url, '') as base_image_url, grid_size_ft, CAST(width_px AS INTEGER) as width_px, CAST(height_px AS INTEGER) as height_px, lighting_mode, fog_state, created_at
+SELECT id, scene_id, name, COALESCE(base_image_url, '') as base_image_url, grid_size_ft, width_px, height_px, lighting_mode, fog_state, created_at
 FROM maps
 WHERE scene_id IN (sqlc.slice('scene_ids'))
 ORDER BY id ASC;
 
 -- name: ListTokensByMapIDs :many
-SELECT id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at
+SELECT id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at, vision_ft, darkvision_ft
 FROM tokens
 WHERE map_id IN (sqlc.slice('map_ids'))
 ORDER BY id ASC;
 
 -- name: ListTokensByMapIDsForPlayer :many
-SELECT id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at
+SELECT id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at, vision_ft, darkvision_ft
 FROM tokens
 WHERE map_id IN (sqlc.slice('map_ids'))
   AND layer != 'gm'
@@ -294,8 +320,480 @@ INSERT INTO scenes (campaign_id, name, description, ordering, is_active, created
 VALUES (?, ?, ?, ?, ?, ?)
 RETURNING id, campaign_id, name, COALESCE(description, '') as description, ordering, is_active, created_by, created_at, updated_at;
 
+-- name: GetScene :one
+SELECT id, campaign_id, name, COALESCE(description, '') as description, ordering, is_active, created_by, created_at, updated_at
+FROM scenes
+WHERE id = ? AND campaign_id = ?;
+
+-- name: GetNextSceneOrdering :one
+SELECT CAST(COALESCE(MAX(ordering) + 1, 0) AS INTEGER) AS next_ordering
+FROM scenes
+WHERE campaign_id = ?;
+
+-- name: UpdateScene :one
+UPDATE scenes
+SET name = ?, description = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND campaign_id = ?
+RETURNING id, campaign_id, name, COALESCE(description, '') as description, ordering, is_active, created_by, created_at, updated_at;
+
+-- name: UpdateSceneOrdering :exec
+UPDATE scenes
+SET ordering = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND campaign_id = ?;
+
+-- name: SetActiveScene :exec
+UPDATE scenes
+SET is_active = (id = ?)
+WHERE campaign_id = ?;
+
+-- name: DeleteScene :exec
+DELETE FROM scenes
+WHERE id = ? AND campaign_id = ?;
+
+-- name: GetMapFogState :one
+SELECT fog_state FROM maps WHERE id = ?;
+
+-- name: UpdateMapFogState :exec
+UPDATE maps
+SET fog_state = ?
+WHERE id = ?;
+
+-- name: UpdateMapLighting :exec
+UPDATE maps
+SET lighting_mode = ?
+WHERE id = ?;
+
+-- name: UpdateTokenVision :exec
+UPDATE tokens
+SET vision_ft = ?, darkvision_ft = ?
+WHERE id = ?;
+
+-- Wall and door queries
+-- name: ListMapWalls :many
+SELECT id, map_id, type, z_index, data
+FROM layers
+WHERE map_id = ? AND type IN ('wall', 'door')
+ORDER BY id ASC;
+
+-- name: GetLayer :one
+SELECT id, map_id, type, z_index, data
+FROM layers
+WHERE id = ?;
+
+-- name: InsertLayer :one
+INSERT INTO layers (map_id, type, z_index, data)
+VALUES (?, ?, ?, ?)
+RETURNING id, map_id, type, z_index, data;
+
+-- name: UpdateLayer :one
+UPDATE layers
+SET type = ?, data = ?
+WHERE id = ?
+RETURNING id, map_id, type, z_index, data;
+
+-- name: DeleteLayer :exec
+DELETE FROM layers
+WHERE id = ?;
+
 -- name: GetCampaignIDByMap :one
 SELECT sc.campaign_id
 FROM maps m
 JOIN scenes sc ON sc.id = m.scene_id
 WHERE m.id = ?;
+
+-- Campaign roll queries
+-- name: InsertCampaignRoll :one
+INSERT INTO campaign_rolls (campaign_id, user_id, character_id, label, expression, result, total, visibility, prev_hash, hash)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, campaign_id, user_id, character_id, label, expression, result, total, visibility, prev_hash, hash, created_at;
+
+-- name: GetLatestCampaignRollHash :one
+SELECT hash
+FROM campaign_rolls
+WHERE campaign_id = ?
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListCampaignRolls :many
+SELECT r.id, r.campaign_id, r.user_id, u.username, r.character_id, r.label, r.expression, r.result, r.total, r.visibility, r.prev_hash, r.hash, r.created_at
+FROM campaign_rolls r
+JOIN users u ON u.id = r.user_id
+WHERE r.campaign_id = ?
+  AND (r.user_id = ? OR r.visibility IN (sqlc.slice('visibilities')))
+ORDER BY r.id DESC
+LIMIT ?;
+
+-- name: ListCampaignRollsForAudit :many
+SELECT id, campaign_id, user_id, character_id, label, expression, result, total, visibility, prev_hash, hash, created_at
+FROM campaign_rolls
+WHERE campaign_id = ?
+ORDER BY id ASC;
+
+-- Character spell queries
+-- name: ListCharacterSpells :many
+SELECT id, character_id, name, level, school, prepared, notes, created_at
+FROM character_spells
+WHERE character_id = ?
+ORDER BY level, name;
+
+-- name: GetCharacterSpell :one
+SELECT id, character_id, name, level, school, prepared, notes, created_at
+FROM character_spells
+WHERE id = ? AND character_id = ?;
+
+-- name: InsertCharacterSpell :one
+INSERT INTO character_spells (character_id, name, level, school, prepared, notes)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, name, level, school, prepared, notes, created_at;
+
+-- name: UpdateCharacterSpell :one
+UPDATE character_spells
+SET name = ?, level = ?, school = ?, prepared = ?, notes = ?
+WHERE id = ? AND character_id = ?
+RETURNING id, character_id, name, level, school, prepared, notes, created_at;
+
+-- name: DeleteCharacterSpell :execrows
+DELETE FROM character_spells WHERE id = ? AND character_id = ?;
+
+-- name: ListCharacterSpellSlots :many
+SELECT character_id, kind, slot_level, expended
+FROM character_spell_slots
+WHERE character_id = ?
+ORDER BY kind, slot_level;
+
+-- name: UpsertCharacterSpellSlot :exec
+INSERT INTO character_spell_slots (character_id, kind, slot_level, expended)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (character_id, kind, slot_level) DO UPDATE SET expended = excluded.expended;
+
+-- Character inventory queries
+-- name: ListCharacterItems :many
+SELECT id, character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position, created_at, updated_at
+FROM character_items
+WHERE character_id = ?
+ORDER BY position, id;
+
+-- name: ListCharacterItemsByUser :many
+SELECT i.id, i.character_id, i.container_id, i.name, i.quantity, i.weight, i.value_cp, i.equipped, i.attuned, i.is_container, i.armor_type, i.armor_class, i.notes, i.position, i.created_at, i.updated_at
+FROM character_items i
+JOIN characters ch ON ch.id = i.character_id
+WHERE ch.user_id = ?
+ORDER BY i.character_id, i.position, i.id;
+
+-- name: GetCharacterItem :one
+SELECT id, character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position, created_at, updated_at
+FROM character_items
+WHERE id = ? AND character_id = ?;
+
+-- name: InsertCharacterItem :one
+INSERT INTO character_items (character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position, created_at, updated_at;
+
+-- name: UpdateCharacterItem :one
+UPDATE character_items
+SET container_id = ?, name = ?, quantity = ?, weight = ?, value_cp = ?, equipped = ?, attuned = ?, is_container = ?, armor_type = ?, armor_class = ?, notes = ?, position = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND character_id = ?
+RETURNING id, character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position, created_at, updated_at;
+
+-- name: DeleteCharacterItem :execrows
+DELETE FROM character_items WHERE id = ? AND character_id = ?;
+
+-- name: GetCharacterCoins :one
+SELECT character_id, cp, sp, ep, gp, pp
+FROM character_coins
+WHERE character_id = ?;
+
+-- name: ListCharacterCoinsByUser :many
+SELECT co.character_id, co.cp, co.sp, co.ep, co.gp, co.pp
+FROM character_coins co
+JOIN characters ch ON ch.id = co.character_id
+WHERE ch.user_id = ?;
+
+-- name: UpsertCharacterCoins :exec
+INSERT INTO character_coins (character_id, cp, sp, ep, gp, pp)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (character_id) DO UPDATE SET cp = excluded.cp, sp = excluded.sp, ep = excluded.ep, gp = excluded.gp, pp = excluded.pp;
+
+-- Character level-up queries
+-- name: ListCharacterLevelUps :many
+SELECT id, character_id, level, hp_method, hp_roll, hp_gain, ability_increases, feat, features, created_at, class
+FROM character_level_ups
+WHERE character_id = ?
+ORDER BY level, id;
+
+-- name: InsertCharacterLevelUp :one
+INSERT INTO character_level_ups (character_id, level, hp_method, hp_roll, hp_gain, ability_increases, feat, features, class)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, level, hp_method, hp_roll, hp_gain, ability_increases, feat, features, created_at, class;
+
+-- Character revision queries
+-- name: ListCharacterRevisions :many
+SELECT id, character_id, revision, reason, created_at
+FROM character_revisions
+WHERE character_id = ?
+ORDER BY revision DESC;
+
+-- name: GetCharacterRevision :one
+SELECT id, character_id, revision, reason, snapshot, created_at
+FROM character_revisions
+WHERE character_id = ? AND revision = ?;
+
+-- name: InsertCharacterRevision :one
+INSERT INTO character_revisions (character_id, revision, reason, snapshot)
+SELECT ?, COALESCE(MAX(r.revision), 0) + 1, ?, ?
+FROM character_revisions r
+WHERE r.character_id = ?
+RETURNING id, character_id, revision, reason, snapshot, created_at;
+
+-- Character rest queries
+-- name: UpdateCharacterHitPoints :exec
+UPDATE characters
+SET current_hp = ?, temp_hp = ?, hit_dice_spent = ?, death_save_successes = ?, death_save_failures = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+WHERE id = ? AND user_id = ?;
+
+-- name: ClearCharacterSpellSlots :exec
+DELETE FROM character_spell_slots WHERE character_id = ? AND kind = ?;
+
+-- name: InsertCharacterRest :one
+INSERT INTO character_rests (character_id, rest_type, hit_dice_spent, hit_dice_recovered, hp_before, hp_after, roll)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, rest_type, hit_dice_spent, hit_dice_recovered, hp_before, hp_after, roll, created_at;
+
+-- name: ListCharacterRests :many
+SELECT id, character_id, rest_type, hit_dice_spent, hit_dice_recovered, hp_before, hp_after, roll, created_at
+FROM character_rests
+WHERE character_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
+
+-- name: ListCampaignRests :many
+SELECT r.id, r.character_id, ch.name AS character_name, u.username AS owner_username, r.rest_type, r.hit_dice_spent, r.hit_dice_recovered, r.hp_before, r.hp_after, r.created_at
+FROM character_rests r
+JOIN campaign_characters cc ON cc.character_id = r.character_id
+JOIN characters ch ON ch.id = r.character_id
+JOIN users u ON u.id = ch.user_id
+WHERE cc.campaign_id = ?
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT ?;
+
+-- Character condition queries
+-- name: ListCharacterConditions :many
+SELECT id, character_id, name, level, source, duration, duration_unit, created_at
+FROM character_conditions
+WHERE character_id = ?
+ORDER BY name ASC;
+
+-- name: ListCharacterConditionsByUser :many
+SELECT cc.id, cc.character_id, cc.name, cc.level, cc.source, cc.duration, cc.duration_unit, cc.created_at
+FROM character_conditions cc
+JOIN characters c ON c.id = cc.character_id
+WHERE c.user_id = ?
+ORDER BY cc.character_id ASC, cc.name ASC;
+
+-- name: ListCharacterConditionsByCharacterIDs :many
+SELECT id, character_id, name, level, source, duration, duration_unit, created_at
+FROM character_conditions
+WHERE character_id IN (sqlc.slice('character_ids'))
+ORDER BY character_id ASC, name ASC;
+
+-- name: UpsertCharacterCondition :one
+INSERT INTO character_conditions (character_id, name, level, source, duration, duration_unit)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (character_id, name) DO UPDATE
+SET level = excluded.level, source = excluded.source, duration = excluded.duration, duration_unit = excluded.duration_unit, created_at = CURRENT_TIMESTAMP
+RETURNING id, character_id, name, level, source, duration, duration_unit, created_at;
+
+-- name: DeleteCharacterCondition :execrows
+DELETE FROM character_conditions WHERE id = ? AND character_id = ?;
+
+-- name: ReduceCharacterExhaustion :exec
+UPDATE character_conditions
+SET level = level - 1
+WHERE character_id = ? AND name = 'exhaustion';
+
+-- name: DeleteRecoveredExhaustion :exec
+DELETE FROM character_conditions WHERE character_id = ? AND name = 'exhaustion' AND level <= 0;
+
+-- Character class queries
+-- name: ListCharacterClasses :many
+SELECT id, character_id, class, level, position, subclass, hit_die, spellcasting_ability
+FROM character_classes
+WHERE character_id = ?
+ORDER BY position ASC, id ASC;
+
+-- name: ListCharacterClassesByUser :many
+SELECT cl.id, cl.character_id, cl.class, cl.level, cl.position, cl.subclass, cl.hit_die, cl.spellcasting_ability
+FROM character_classes cl
+JOIN characters c ON c.id = cl.character_id
+WHERE c.user_id = ?
+ORDER BY cl.character_id ASC, cl.position ASC, cl.id ASC;
+
+-- name: ListCharacterClassesByCharacterIDs :many
+SELECT id, character_id, class, level, position, subclass, hit_die, spellcasting_ability
+FROM character_classes
+WHERE character_id IN (sqlc.slice('character_ids'))
+ORDER BY character_id ASC, position ASC, id ASC;
+
+-- name: DeleteCharacterClasses :exec
+DELETE FROM character_classes WHERE character_id = ?;
+
+-- name: InsertCharacterClass :exec
+INSERT INTO character_classes (character_id, class, level, position, subclass, hit_die, spellcasting_ability)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- Character resource queries
+-- name: ListCharacterResources :many
+SELECT id, character_id, name, max_formula, used, reset, created_at
+FROM character_resources
+WHERE character_id = ?
+ORDER BY id ASC;
+
+-- name: ListCharacterResourcesByUser :many
+SELECT cr.id, cr.character_id, cr.name, cr.max_formula, cr.used, cr.reset, cr.created_at
+FROM character_resources cr
+JOIN characters c ON c.id = cr.character_id
+WHERE c.user_id = ?
+ORDER BY cr.character_id ASC, cr.id ASC;
+
+-- name: InsertCharacterResource :one
+INSERT INTO character_resources (character_id, name, max_formula, used, reset)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, character_id, name, max_formula, used, reset, created_at;
+
+-- name: UpdateCharacterResource :one
+UPDATE character_resources
+SET name = ?, max_formula = ?, used = ?, reset = ?
+WHERE id = ? AND character_id = ?
+RETURNING id, character_id, name, max_formula, used, reset, created_at;
+
+-- name: DeleteCharacterResource :execrows
+DELETE FROM character_resources WHERE id = ? AND character_id = ?;
+
+-- name: ResetCharacterResources :exec
+UPDATE character_resources
+SET used = 0
+WHERE character_id = ? AND reset = ?;
+
+-- Character attack queries
+-- name: ListCharacterAttacks :many
+SELECT id, character_id, name, kind, ability, proficient, damage_dice, versatile_dice, damage_type, properties, bonus, created_at
+FROM character_attacks
+WHERE character_id = ?
+ORDER BY id ASC;
+
+-- name: ListCharacterAttacksByUser :many
+SELECT ca.id, ca.character_id, ca.name, ca.kind, ca.ability, ca.proficient, ca.damage_dice, ca.versatile_dice, ca.damage_type, ca.properties, ca.bonus, ca.created_at
+FROM character_attacks ca
+JOIN characters c ON c.id = ca.character_id
+WHERE c.user_id = ?
+ORDER BY ca.character_id ASC, ca.id ASC;
+
+-- name: InsertCharacterAttack :one
+INSERT INTO character_attacks (character_id, name, kind, ability, proficient, damage_dice, versatile_dice, damage_type, properties, bonus)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, name, kind, ability, proficient, damage_dice, versatile_dice, damage_type, properties, bonus, created_at;
+
+-- name: UpdateCharacterAttack :one
+UPDATE character_attacks
+SET name = ?, kind = ?, ability = ?, proficient = ?, damage_dice = ?, versatile_dice = ?, damage_type = ?, properties = ?, bonus = ?
+WHERE id = ? AND character_id = ?
+RETURNING id, character_id, name, kind, ability, proficient, damage_dice, versatile_dice, damage_type, properties, bonus, created_at;
+
+-- name: DeleteCharacterAttack :execrows
+DELETE FROM character_attacks WHERE id = ? AND character_id = ?;
+
+-- Homebrew pack queries
+-- name: ListUserHomebrewPacks :many
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE user_id = ? AND campaign_id IS NULL
+ORDER BY name ASC;
+
+-- name: ListCampaignHomebrewPacks :many
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE campaign_id = ?
+ORDER BY name ASC;
+
+-- name: ListCatalogHomebrewPacks :many
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE (campaign_id IS NULL AND user_id = ?)
+   OR campaign_id IN (sqlc.slice('campaign_ids'))
+ORDER BY name ASC, id ASC;
+
+-- name: ListCharacterCampaignIDs :many
+SELECT campaign_id FROM campaign_characters WHERE character_id = ? ORDER BY campaign_id ASC;
+
+-- name: GetHomebrewPack :one
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE id = ?;
+
+-- name: GetUserHomebrewPackByName :one
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE user_id = ? AND campaign_id IS NULL AND name = ?;
+
+-- name: GetCampaignHomebrewPackByName :one
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE campaign_id = ? AND name = ?;
+
+-- name: InsertHomebrewPack :one
+INSERT INTO homebrew_packs (user_id, campaign_id, name, version, content)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, user_id, campaign_id, name, version, content, created_at, updated_at;
+
+-- name: UpdateHomebrewPack :one
+UPDATE homebrew_packs
+SET user_id = ?, version = ?, content = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, user_id, campaign_id, name, version, content, created_at, updated_at;
+
+-- name: DeleteHomebrewPack :execrows
+DELETE FROM homebrew_packs WHERE id = ?;
+
+-- Ability score generation queries
+-- name: InsertAbilityScoreRoll :one
+INSERT INTO ability_score_rolls (user_id, rolls)
+VALUES (?, ?)
+RETURNING id, user_id, character_id, rolls, created_at;
+
+-- name: GetAbilityScoreRoll :one
+SELECT id, user_id, character_id, rolls, created_at
+FROM ability_score_rolls
+WHERE id = ? AND user_id = ?;
+
+-- name: GetUnclaimedAbilityScoreRoll :one
+SELECT id, user_id, character_id, rolls, created_at
+FROM ability_score_rolls
+WHERE user_id = ? AND character_id IS NULL
+ORDER BY id ASC
+LIMIT 1;
+
+-- name: ClaimAbilityScoreRoll :execrows
+UPDATE ability_score_rolls
+SET character_id = ?
+WHERE id = ? AND character_id IS NULL;
+
+-- name: InsertCharacterAbilityScores :exec
+INSERT INTO character_ability_scores (character_id, method, base, increases, roll_id)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetCharacterAbilityScores :one
+SELECT a.character_id, a.method, a.base, a.increases, a.roll_id, COALESCE(r.rolls, '') AS rolls, r.created_at AS rolled_at, a.created_at
+FROM character_ability_scores a
+LEFT JOIN ability_score_rolls r ON r.id = a.roll_id
+WHERE a.character_id = ?;
+
+-- name: ListCampaignAbilityScores :many
+SELECT a.character_id, ch.name AS character_name, u.username AS owner_username, a.method, a.base, a.increases, a.roll_id, COALESCE(r.rolls, '') AS rolls, r.created_at AS rolled_at, a.created_at
+FROM character_ability_scores a
+JOIN campaign_characters cc ON cc.character_id = a.character_id
+JOIN characters ch ON ch.id = a.character_id
+JOIN users u ON u.id = ch.user_id
+LEFT JOIN ability_score_rolls r ON r.id = a.roll_id
+WHERE cc.campaign_id = ?
+ORDER BY ch.name ASC, a.character_id ASC;
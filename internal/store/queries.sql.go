@@ -22,6 +22,39 @@ func (q *Queries) CheckInviteCodeExists(ctx context.Context, code string) (int64
 	return column_1, err
 }
 
+const claimAbilityScoreRoll = `-- name: ClaimAbilityScoreRoll :execrows
+UPDATE ability_score_rolls
+SET character_id = ?
+WHERE id = ? AND character_id IS NULL
+`
+
+type ClaimAbilityScoreRollParams struct {
+	CharacterID *int64 `json:"characterId"`
+	ID          int64  `json:"id"`
+}
+
+func (q *Queries) ClaimAbilityScoreRoll(ctx context.Context, arg ClaimAbilityScoreRollParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimAbilityScoreRoll, arg.CharacterID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const clearCharacterSpellSlots = `-- name: ClearCharacterSpellSlots :exec
+DELETE FROM character_spell_slots WHERE character_id = ? AND kind = ?
+`
+
+type ClearCharacterSpellSlotsParams struct {
+	CharacterID int64  `json:"characterId"`
+	Kind        string `json:"kind"`
+}
+
+func (q *Queries) ClearCharacterSpellSlots(ctx context.Context, arg ClearCharacterSpellSlotsParams) error {
+	_, err := q.db.ExecContext(ctx, clearCharacterSpellSlots, arg.CharacterID, arg.Kind)
+	return err
+}
+
 const createCampaignHandout = `-- name: CreateCampaignHandout :one
 INSERT INTO campaign_handouts (campaign_id, title, description, file_path, created_by)
 VALUES (?, ?, ?, ?, ?)
@@ -72,7 +105,7 @@ func (q *Queries) CreateCampaignHandout(ctx context.Context, arg CreateCampaignH
 const createMap = `-- name: CreateMap :one
 INSERT INTO maps (scene_id, name, base_image_url)
 VALUES (?, ?, ?)
-RETURNING id, scene_id, name, COALESCE(base_image_url, '') as base_image_url, grid_size_ft, CAST(width_px AS INTEGER) as width_px, CAST(height_px AS INTEGER) as height_px, lighting_mode, fog_state, created_at
+RETURNING id, scene_id, name, COALESCE(base_image_url, '') as base_image_url, grid_size_ft, width_px, height_px, lighting_mode, fog_state, created_at
 `
 
 type CreateMapParams struct {
@@ -87,8 +120,8 @@ type CreateMapRow struct {
 	Name         string    `json:"name"`
 	BaseImageUrl string    `json:"baseImageUrl"`
 	GridSizeFt   int64     `json:"gridSizeFt"`
-	WidthPx      int64     `json:"widthPx"`
-	HeightPx     int64     `json:"heightPx"`
+	WidthPx      *int64    `json:"widthPx"`
+	HeightPx     *int64    `json:"heightPx"`
 	LightingMode string    `json:"lightingMode"`
 	FogState     string    `json:"fogState"`
 	CreatedAt    time.Time `json:"createdAt"`
@@ -166,8 +199,8 @@ func (q *Queries) CreateScene(ctx context.Context, arg CreateSceneParams) (Creat
 
 const createToken = `-- name: CreateToken :one
 INSERT INTO tokens (map_id, character_id, label, image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, notes, created_by)
-VALUES (?, ?10, ?, CAST(?11 AS TEXT), ?, ?, ?, ?, ?, ?, ?, '', ?12)
-RETURNING id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at
+VALUES (?, ?, ?, CAST(? AS TEXT), ?, ?, ?, ?, ?, ?, ?, '', ?)
+RETURNING id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at, vision_ft, darkvision_ft
 `
 
 type CreateTokenParams struct {
@@ -186,21 +219,23 @@ type CreateTokenParams struct {
 }
 
 type CreateTokenRow struct {
-	ID          int64     `json:"id"`
-	MapID       int64     `json:"mapId"`
-	CharacterID *int64    `json:"characterId"`
-	Label       string    `json:"label"`
-	ImageUrl    string    `json:"imageUrl"`
-	SizeSquares int64     `json:"sizeSquares"`
-	PositionX   int64     `json:"positionX"`
-	PositionY   int64     `json:"positionY"`
-	FacingDeg   int64     `json:"facingDeg"`
-	Audience    string    `json:"audience"`
-	Layer       string    `json:"layer"`
-	Tags        string    `json:"tags"`
-	Notes       string    `json:"notes"`
-	CreatedBy   *int64    `json:"createdBy"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID           int64     `json:"id"`
+	MapID        int64     `json:"mapId"`
+	CharacterID  *int64    `json:"characterId"`
+	Label        string    `json:"label"`
+	ImageUrl     string    `json:"imageUrl"`
+	SizeSquares  int64     `json:"sizeSquares"`
+	PositionX    int64     `json:"positionX"`
+	PositionY    int64     `json:"positionY"`
+	FacingDeg    int64     `json:"facingDeg"`
+	Audience     string    `json:"audience"`
+	Layer        string    `json:"layer"`
+	Tags         string    `json:"tags"`
+	Notes        string    `json:"notes"`
+	CreatedBy    *int64    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	VisionFt     int64     `json:"visionFt"`
+	DarkvisionFt int64     `json:"darkvisionFt"`
 }
 
 func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (CreateTokenRow, error) {
@@ -235,6 +270,8 @@ func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (Creat
 		&i.Notes,
 		&i.CreatedBy,
 		&i.CreatedAt,
+		&i.VisionFt,
+		&i.DarkvisionFt,
 	)
 	return i, err
 }
@@ -280,6 +317,180 @@ func (q *Queries) DeleteCharacter(ctx context.Context, arg DeleteCharacterParams
 	return result.RowsAffected()
 }
 
+const deleteCharacterAttack = `-- name: DeleteCharacterAttack :execrows
+DELETE FROM character_attacks WHERE id = ? AND character_id = ?
+`
+
+type DeleteCharacterAttackParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"characterId"`
+}
+
+func (q *Queries) DeleteCharacterAttack(ctx context.Context, arg DeleteCharacterAttackParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCharacterAttack, arg.ID, arg.CharacterID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCharacterClasses = `-- name: DeleteCharacterClasses :exec
+DELETE FROM character_classes WHERE character_id = ?
+`
+
+func (q *Queries) DeleteCharacterClasses(ctx context.Context, characterID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCharacterClasses, characterID)
+	return err
+}
+
+const deleteCharacterCondition = `-- name: DeleteCharacterCondition :execrows
+DELETE FROM character_conditions WHERE id = ? AND character_id = ?
+`
+
+type DeleteCharacterConditionParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"characterId"`
+}
+
+func (q *Queries) DeleteCharacterCondition(ctx context.Context, arg DeleteCharacterConditionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCharacterCondition, arg.ID, arg.CharacterID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCharacterItem = `-- name: DeleteCharacterItem :execrows
+DELETE FROM character_items WHERE id = ? AND character_id = ?
+`
+
+type DeleteCharacterItemParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"characterId"`
+}
+
+func (q *Queries) DeleteCharacterItem(ctx context.Context, arg DeleteCharacterItemParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCharacterItem, arg.ID, arg.CharacterID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCharacterResource = `-- name: DeleteCharacterResource :execrows
+DELETE FROM character_resources WHERE id = ? AND character_id = ?
+`
+
+type DeleteCharacterResourceParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"characterId"`
+}
+
+func (q *Queries) DeleteCharacterResource(ctx context.Context, arg DeleteCharacterResourceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCharacterResource, arg.ID, arg.CharacterID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCharacterSpell = `-- name: DeleteCharacterSpell :execrows
+DELETE FROM character_spells WHERE id = ? AND character_id = ?
+`
+
+type DeleteCharacterSpellParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"characterId"`
+}
+
+func (q *Queries) DeleteCharacterSpell(ctx context.Context, arg DeleteCharacterSpellParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCharacterSpell, arg.ID, arg.CharacterID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteHomebrewPack = `-- name: DeleteHomebrewPack :execrows
+DELETE FROM homebrew_packs WHERE id = ?
+`
+
+func (q *Queries) DeleteHomebrewPack(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteHomebrewPack, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteLayer = `-- name: DeleteLayer :exec
+DELETE FROM layers
+WHERE id = ?
+`
+
+func (q *Queries) DeleteLayer(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteLayer, id)
+	return err
+}
+
+const deleteRecoveredExhaustion = `-- name: DeleteRecoveredExhaustion :exec
+DELETE FROM character_conditions WHERE character_id = ? AND name = 'exhaustion' AND level <= 0
+`
+
+func (q *Queries) DeleteRecoveredExhaustion(ctx context.Context, characterID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRecoveredExhaustion, characterID)
+	return err
+}
+
+const deleteScene = `-- name: DeleteScene :exec
+DELETE FROM scenes
+WHERE id = ? AND campaign_id = ?
+`
+
+type DeleteSceneParams struct {
+	ID         int64 `json:"id"`
+	CampaignID int64 `json:"campaignId"`
+}
+
+func (q *Queries) DeleteScene(ctx context.Context, arg DeleteSceneParams) error {
+	_, err := q.db.ExecContext(ctx, deleteScene, arg.ID, arg.CampaignID)
+	return err
+}
+
+const deleteToken = `-- name: DeleteToken :exec
+DELETE FROM tokens
+WHERE id = ?
+`
+
+func (q *Queries) DeleteToken(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteToken, id)
+	return err
+}
+
+const getAbilityScoreRoll = `-- name: GetAbilityScoreRoll :one
+SELECT id, user_id, character_id, rolls, created_at
+FROM ability_score_rolls
+WHERE id = ? AND user_id = ?
+`
+
+type GetAbilityScoreRollParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"userId"`
+}
+
+func (q *Queries) GetAbilityScoreRoll(ctx context.Context, arg GetAbilityScoreRollParams) (AbilityScoreRoll, error) {
+	row := q.db.QueryRowContext(ctx, getAbilityScoreRoll, arg.ID, arg.UserID)
+	var i AbilityScoreRoll
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CharacterID,
+		&i.Rolls,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getCampaignAndMapByToken = `-- name: GetCampaignAndMapByToken :one
 SELECT sc.campaign_id, t.map_id
 FROM tokens t
@@ -336,6 +547,33 @@ func (q *Queries) GetCampaignByID(ctx context.Context, id int64) (GetCampaignByI
 	return i, err
 }
 
+const getCampaignHomebrewPackByName = `-- name: GetCampaignHomebrewPackByName :one
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE campaign_id = ? AND name = ?
+`
+
+type GetCampaignHomebrewPackByNameParams struct {
+	CampaignID *int64 `json:"campaignId"`
+	Name       string `json:"name"`
+}
+
+func (q *Queries) GetCampaignHomebrewPackByName(ctx context.Context, arg GetCampaignHomebrewPackByNameParams) (HomebrewPack, error) {
+	row := q.db.QueryRowContext(ctx, getCampaignHomebrewPackByName, arg.CampaignID, arg.Name)
+	var i HomebrewPack
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Version,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getCampaignIDByMap = `-- name: GetCampaignIDByMap :one
 SELECT sc.campaign_id
 FROM maps m
@@ -363,12 +601,46 @@ func (q *Queries) GetCampaignOwner(ctx context.Context, id int64) (int64, error)
 	return owner_id, err
 }
 
+const getCharacterAbilityScores = `-- name: GetCharacterAbilityScores :one
+SELECT a.character_id, a.method, a.base, a.increases, a.roll_id, COALESCE(r.rolls, '') AS rolls, r.created_at AS rolled_at, a.created_at
+FROM character_ability_scores a
+LEFT JOIN ability_score_rolls r ON r.id = a.roll_id
+WHERE a.character_id = ?
+`
+
+type GetCharacterAbilityScoresRow struct {
+	CharacterID int64      `json:"characterId"`
+	Method      string     `json:"method"`
+	Base        string     `json:"base"`
+	Increases   string     `json:"increases"`
+	RollID      *int64     `json:"rollId"`
+	Rolls       string     `json:"rolls"`
+	RolledAt    *time.Time `json:"rolledAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+func (q *Queries) GetCharacterAbilityScores(ctx context.Context, characterID int64) (GetCharacterAbilityScoresRow, error) {
+	row := q.db.QueryRowContext(ctx, getCharacterAbilityScores, characterID)
+	var i GetCharacterAbilityScoresRow
+	err := row.Scan(
+		&i.CharacterID,
+		&i.Method,
+		&i.Base,
+		&i.Increases,
+		&i.RollID,
+		&i.Rolls,
+		&i.RolledAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getCharacterByIDAndUser = `-- name: GetCharacterByIDAndUser :one
 SELECT id, user_id, name, race, class, level, COALESCE(background, '') as background, COALESCE(alignment, '') as alignment, COALESCE(experience_points, 0) as experience_points,
        strength, dexterity, constitution, intelligence, wisdom, charisma,
        max_hp, current_hp, COALESCE(temp_hp, 0) as temp_hp, armor_class, COALESCE(speed, 0) as speed, COALESCE(hit_dice, '') as hit_dice,
-       COALESCE(skill_proficiencies, '[]') as skill_proficiencies, COALESCE(saving_throw_proficiencies, '[]') as saving_throw_proficiencies, COALESCE(features, '[]') as features, COALESCE(equipment, '[]') as equipment,
-       COALESCE(avatar_url, '') as avatar_url, created_at, updated_at
+       COALESCE(skill_proficiencies, '[]') as skill_proficiencies, COALESCE(saving_throw_proficiencies, '[]') as saving_throw_proficiencies, COALESCE(features, '[]') as features,
+       COALESCE(avatar_url, '') as avatar_url, COALESCE(proficiency_levels, '{}') as proficiency_levels, COALESCE(hit_dice_spent, 0) as hit_dice_spent, COALESCE(death_save_successes, 0) as death_save_successes, COALESCE(death_save_failures, 0) as death_save_failures, COALESCE(damage_resistances, '[]') as damage_resistances, COALESCE(damage_vulnerabilities, '[]') as damage_vulnerabilities, COALESCE(damage_immunities, '[]') as damage_immunities, COALESCE(armor_class_override, 0) as armor_class_override, COALESCE(armor_class_bonus, 0) as armor_class_bonus, created_at, updated_at
 FROM characters
 WHERE id = ? AND user_id = ?
 `
@@ -403,8 +675,16 @@ type GetCharacterByIDAndUserRow struct {
 	SkillProficiencies       string    `json:"skillProficiencies"`
 	SavingThrowProficiencies string    `json:"savingThrowProficiencies"`
 	Features                 string    `json:"features"`
-	Equipment                string    `json:"equipment"`
 	AvatarUrl                string    `json:"avatarUrl"`
+	ProficiencyLevels        string    `json:"proficiencyLevels"`
+	HitDiceSpent             int64     `json:"hitDiceSpent"`
+	DeathSaveSuccesses       int64     `json:"deathSaveSuccesses"`
+	DeathSaveFailures        int64     `json:"deathSaveFailures"`
+	DamageResistances        string    `json:"damageResistances"`
+	DamageVulnerabilities    string    `json:"damageVulnerabilities"`
+	DamageImmunities         string    `json:"damageImmunities"`
+	ArmorClassOverride       bool      `json:"armorClassOverride"`
+	ArmorClassBonus          int64     `json:"armorClassBonus"`
 	CreatedAt                time.Time `json:"createdAt"`
 	UpdatedAt                time.Time `json:"updatedAt"`
 }
@@ -437,8 +717,71 @@ func (q *Queries) GetCharacterByIDAndUser(ctx context.Context, arg GetCharacterB
 		&i.SkillProficiencies,
 		&i.SavingThrowProficiencies,
 		&i.Features,
-		&i.Equipment,
 		&i.AvatarUrl,
+		&i.ProficiencyLevels,
+		&i.HitDiceSpent,
+		&i.DeathSaveSuccesses,
+		&i.DeathSaveFailures,
+		&i.DamageResistances,
+		&i.DamageVulnerabilities,
+		&i.DamageImmunities,
+		&i.ArmorClassOverride,
+		&i.ArmorClassBonus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCharacterCoins = `-- name: GetCharacterCoins :one
+SELECT character_id, cp, sp, ep, gp, pp
+FROM character_coins
+WHERE character_id = ?
+`
+
+func (q *Queries) GetCharacterCoins(ctx context.Context, characterID int64) (CharacterCoin, error) {
+	row := q.db.QueryRowContext(ctx, getCharacterCoins, characterID)
+	var i CharacterCoin
+	err := row.Scan(
+		&i.CharacterID,
+		&i.Cp,
+		&i.Sp,
+		&i.Ep,
+		&i.Gp,
+		&i.Pp,
+	)
+	return i, err
+}
+
+const getCharacterItem = `-- name: GetCharacterItem :one
+SELECT id, character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position, created_at, updated_at
+FROM character_items
+WHERE id = ? AND character_id = ?
+`
+
+type GetCharacterItemParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"characterId"`
+}
+
+func (q *Queries) GetCharacterItem(ctx context.Context, arg GetCharacterItemParams) (CharacterItem, error) {
+	row := q.db.QueryRowContext(ctx, getCharacterItem, arg.ID, arg.CharacterID)
+	var i CharacterItem
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.ContainerID,
+		&i.Name,
+		&i.Quantity,
+		&i.Weight,
+		&i.ValueCp,
+		&i.Equipped,
+		&i.Attuned,
+		&i.IsContainer,
+		&i.ArmorType,
+		&i.ArmorClass,
+		&i.Notes,
+		&i.Position,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -459,6 +802,58 @@ func (q *Queries) GetCharacterOwner(ctx context.Context, id int64) (int64, error
 	return user_id, err
 }
 
+const getCharacterRevision = `-- name: GetCharacterRevision :one
+SELECT id, character_id, revision, reason, snapshot, created_at
+FROM character_revisions
+WHERE character_id = ? AND revision = ?
+`
+
+type GetCharacterRevisionParams struct {
+	CharacterID int64 `json:"characterId"`
+	Revision    int64 `json:"revision"`
+}
+
+func (q *Queries) GetCharacterRevision(ctx context.Context, arg GetCharacterRevisionParams) (CharacterRevision, error) {
+	row := q.db.QueryRowContext(ctx, getCharacterRevision, arg.CharacterID, arg.Revision)
+	var i CharacterRevision
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Revision,
+		&i.Reason,
+		&i.Snapshot,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCharacterSpell = `-- name: GetCharacterSpell :one
+SELECT id, character_id, name, level, school, prepared, notes, created_at
+FROM character_spells
+WHERE id = ? AND character_id = ?
+`
+
+type GetCharacterSpellParams struct {
+	ID          int64 `json:"id"`
+	CharacterID int64 `json:"characterId"`
+}
+
+func (q *Queries) GetCharacterSpell(ctx context.Context, arg GetCharacterSpellParams) (CharacterSpell, error) {
+	row := q.db.QueryRowContext(ctx, getCharacterSpell, arg.ID, arg.CharacterID)
+	var i CharacterSpell
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Name,
+		&i.Level,
+		&i.School,
+		&i.Prepared,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getFirstSceneByCampaignID = `-- name: GetFirstSceneByCampaignID :one
 SELECT id FROM scenes WHERE campaign_id = ? ORDER BY ordering ASC, id ASC LIMIT 1
 `
@@ -470,6 +865,28 @@ func (q *Queries) GetFirstSceneByCampaignID(ctx context.Context, campaignID int6
 	return id, err
 }
 
+const getHomebrewPack = `-- name: GetHomebrewPack :one
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE id = ?
+`
+
+func (q *Queries) GetHomebrewPack(ctx context.Context, id int64) (HomebrewPack, error) {
+	row := q.db.QueryRowContext(ctx, getHomebrewPack, id)
+	var i HomebrewPack
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Version,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getInviteByCode = `-- name: GetInviteByCode :one
 SELECT id, campaign_id, code, invited_by, role_default, status, expires_at, redeemed_by, redeemed_at, created_at
 FROM campaign_invites
@@ -494,6 +911,51 @@ func (q *Queries) GetInviteByCode(ctx context.Context, code string) (CampaignInv
 	return i, err
 }
 
+const getLatestCampaignRollHash = `-- name: GetLatestCampaignRollHash :one
+SELECT hash
+FROM campaign_rolls
+WHERE campaign_id = ?
+ORDER BY id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestCampaignRollHash(ctx context.Context, campaignID int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLatestCampaignRollHash, campaignID)
+	var hash string
+	err := row.Scan(&hash)
+	return hash, err
+}
+
+const getLayer = `-- name: GetLayer :one
+SELECT id, map_id, type, z_index, data
+FROM layers
+WHERE id = ?
+`
+
+func (q *Queries) GetLayer(ctx context.Context, id int64) (Layer, error) {
+	row := q.db.QueryRowContext(ctx, getLayer, id)
+	var i Layer
+	err := row.Scan(
+		&i.ID,
+		&i.MapID,
+		&i.Type,
+		&i.ZIndex,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getMapFogState = `-- name: GetMapFogState :one
+SELECT fog_state FROM maps WHERE id = ?
+`
+
+func (q *Queries) GetMapFogState(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getMapFogState, id)
+	var fog_state string
+	err := row.Scan(&fog_state)
+	return fog_state, err
+}
+
 const getMemberSummary = `-- name: GetMemberSummary :one
 SELECT m.id, m.campaign_id, m.user_id, u.username, m.role, m.status, COALESCE(m.invited_by, 0) as invited_by, m.created_at
 FROM campaign_members m
@@ -556,28 +1018,83 @@ func (q *Queries) GetMembership(ctx context.Context, arg GetMembershipParams) (G
 	return i, err
 }
 
-const getTokenByID = `-- name: GetTokenByID :one
-SELECT id, map_id, COALESCE(character_id, 0) as character_id, label, image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, COALESCE(created_by, 0) as created_by, created_at
-FROM tokens
-WHERE id = ?
+const getNextSceneOrdering = `-- name: GetNextSceneOrdering :one
+SELECT CAST(COALESCE(MAX(ordering) + 1, 0) AS INTEGER) AS next_ordering
+FROM scenes
+WHERE campaign_id = ?
 `
 
-type GetTokenByIDRow struct {
-	ID          int64     `json:"id"`
-	MapID       int64     `json:"mapId"`
-	CharacterID int64     `json:"characterId"`
-	Label       string    `json:"label"`
-	ImageUrl    *string   `json:"imageUrl"`
-	SizeSquares int64     `json:"sizeSquares"`
-	PositionX   int64     `json:"positionX"`
-	PositionY   int64     `json:"positionY"`
-	FacingDeg   int64     `json:"facingDeg"`
-	Audience    string    `json:"audience"`
-	Layer       string    `json:"layer"`
-	Tags        string    `json:"tags"`
-	Notes       string    `json:"notes"`
-	CreatedBy   int64     `json:"createdBy"`
+func (q *Queries) GetNextSceneOrdering(ctx context.Context, campaignID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getNextSceneOrdering, campaignID)
+	var next_ordering int64
+	err := row.Scan(&next_ordering)
+	return next_ordering, err
+}
+
+const getScene = `-- name: GetScene :one
+SELECT id, campaign_id, name, COALESCE(description, '') as description, ordering, is_active, created_by, created_at, updated_at
+FROM scenes
+WHERE id = ? AND campaign_id = ?
+`
+
+type GetSceneParams struct {
+	ID         int64 `json:"id"`
+	CampaignID int64 `json:"campaignId"`
+}
+
+type GetSceneRow struct {
+	ID          int64     `json:"id"`
+	CampaignID  int64     `json:"campaignId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Ordering    int64     `json:"ordering"`
+	IsActive    bool      `json:"isActive"`
+	CreatedBy   *int64    `json:"createdBy"`
 	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (q *Queries) GetScene(ctx context.Context, arg GetSceneParams) (GetSceneRow, error) {
+	row := q.db.QueryRowContext(ctx, getScene, arg.ID, arg.CampaignID)
+	var i GetSceneRow
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Description,
+		&i.Ordering,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTokenByID = `-- name: GetTokenByID :one
+SELECT id, map_id, COALESCE(character_id, 0) as character_id, label, image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, COALESCE(created_by, 0) as created_by, created_at, vision_ft, darkvision_ft
+FROM tokens
+WHERE id = ?
+`
+
+type GetTokenByIDRow struct {
+	ID           int64     `json:"id"`
+	MapID        int64     `json:"mapId"`
+	CharacterID  int64     `json:"characterId"`
+	Label        string    `json:"label"`
+	ImageUrl     *string   `json:"imageUrl"`
+	SizeSquares  int64     `json:"sizeSquares"`
+	PositionX    int64     `json:"positionX"`
+	PositionY    int64     `json:"positionY"`
+	FacingDeg    int64     `json:"facingDeg"`
+	Audience     string    `json:"audience"`
+	Layer        string    `json:"layer"`
+	Tags         string    `json:"tags"`
+	Notes        string    `json:"notes"`
+	CreatedBy    int64     `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	VisionFt     int64     `json:"visionFt"`
+	DarkvisionFt int64     `json:"darkvisionFt"`
 }
 
 func (q *Queries) GetTokenByID(ctx context.Context, id int64) (GetTokenByIDRow, error) {
@@ -599,6 +1116,29 @@ func (q *Queries) GetTokenByID(ctx context.Context, id int64) (GetTokenByIDRow,
 		&i.Notes,
 		&i.CreatedBy,
 		&i.CreatedAt,
+		&i.VisionFt,
+		&i.DarkvisionFt,
+	)
+	return i, err
+}
+
+const getUnclaimedAbilityScoreRoll = `-- name: GetUnclaimedAbilityScoreRoll :one
+SELECT id, user_id, character_id, rolls, created_at
+FROM ability_score_rolls
+WHERE user_id = ? AND character_id IS NULL
+ORDER BY id ASC
+LIMIT 1
+`
+
+func (q *Queries) GetUnclaimedAbilityScoreRoll(ctx context.Context, userID int64) (AbilityScoreRoll, error) {
+	row := q.db.QueryRowContext(ctx, getUnclaimedAbilityScoreRoll, userID)
+	var i AbilityScoreRoll
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CharacterID,
+		&i.Rolls,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -639,6 +1179,57 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 	return i, err
 }
 
+const getUserHomebrewPackByName = `-- name: GetUserHomebrewPackByName :one
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE user_id = ? AND campaign_id IS NULL AND name = ?
+`
+
+type GetUserHomebrewPackByNameParams struct {
+	UserID int64  `json:"userId"`
+	Name   string `json:"name"`
+}
+
+func (q *Queries) GetUserHomebrewPackByName(ctx context.Context, arg GetUserHomebrewPackByNameParams) (HomebrewPack, error) {
+	row := q.db.QueryRowContext(ctx, getUserHomebrewPackByName, arg.UserID, arg.Name)
+	var i HomebrewPack
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Version,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertAbilityScoreRoll = `-- name: InsertAbilityScoreRoll :one
+INSERT INTO ability_score_rolls (user_id, rolls)
+VALUES (?, ?)
+RETURNING id, user_id, character_id, rolls, created_at
+`
+
+type InsertAbilityScoreRollParams struct {
+	UserID int64  `json:"userId"`
+	Rolls  string `json:"rolls"`
+}
+
+func (q *Queries) InsertAbilityScoreRoll(ctx context.Context, arg InsertAbilityScoreRollParams) (AbilityScoreRoll, error) {
+	row := q.db.QueryRowContext(ctx, insertAbilityScoreRoll, arg.UserID, arg.Rolls)
+	var i AbilityScoreRoll
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CharacterID,
+		&i.Rolls,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const insertCampaign = `-- name: InsertCampaign :one
 INSERT INTO campaigns (owner_id, name, description, visibility, status, active_scene_id)
 VALUES (?, ?, ?, ?, ?, ?)
@@ -788,19 +1379,69 @@ func (q *Queries) InsertCampaignMember(ctx context.Context, arg InsertCampaignMe
 	return i, err
 }
 
+const insertCampaignRoll = `-- name: InsertCampaignRoll :one
+INSERT INTO campaign_rolls (campaign_id, user_id, character_id, label, expression, result, total, visibility, prev_hash, hash)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, campaign_id, user_id, character_id, label, expression, result, total, visibility, prev_hash, hash, created_at
+`
+
+type InsertCampaignRollParams struct {
+	CampaignID  int64  `json:"campaignId"`
+	UserID      int64  `json:"userId"`
+	CharacterID *int64 `json:"characterId"`
+	Label       string `json:"label"`
+	Expression  string `json:"expression"`
+	Result      string `json:"result"`
+	Total       int64  `json:"total"`
+	Visibility  string `json:"visibility"`
+	PrevHash    string `json:"prevHash"`
+	Hash        string `json:"hash"`
+}
+
+func (q *Queries) InsertCampaignRoll(ctx context.Context, arg InsertCampaignRollParams) (CampaignRoll, error) {
+	row := q.db.QueryRowContext(ctx, insertCampaignRoll,
+		arg.CampaignID,
+		arg.UserID,
+		arg.CharacterID,
+		arg.Label,
+		arg.Expression,
+		arg.Result,
+		arg.Total,
+		arg.Visibility,
+		arg.PrevHash,
+		arg.Hash,
+	)
+	var i CampaignRoll
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.UserID,
+		&i.CharacterID,
+		&i.Label,
+		&i.Expression,
+		&i.Result,
+		&i.Total,
+		&i.Visibility,
+		&i.PrevHash,
+		&i.Hash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const insertCharacter = `-- name: InsertCharacter :one
 INSERT INTO characters (
     user_id, name, race, class, level, background, alignment, experience_points,
     strength, dexterity, constitution, intelligence, wisdom, charisma,
     max_hp, current_hp, temp_hp, armor_class, speed, hit_dice,
-    skill_proficiencies, saving_throw_proficiencies, features, equipment,
-    avatar_url
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    skill_proficiencies, saving_throw_proficiencies, features,
+    avatar_url, proficiency_levels, hit_dice_spent, death_save_successes, death_save_failures, damage_resistances, damage_vulnerabilities, damage_immunities, armor_class_override, armor_class_bonus
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING id, user_id, name, race, class, level, background, alignment, experience_points,
           strength, dexterity, constitution, intelligence, wisdom, charisma,
           max_hp, current_hp, temp_hp, armor_class, speed, hit_dice,
-          skill_proficiencies, saving_throw_proficiencies, features, equipment,
-          avatar_url, created_at, updated_at
+          skill_proficiencies, saving_throw_proficiencies, features,
+          avatar_url, proficiency_levels, hit_dice_spent, death_save_successes, death_save_failures, damage_resistances, damage_vulnerabilities, damage_immunities, armor_class_override, armor_class_bonus, created_at, updated_at
 `
 
 type InsertCharacterParams struct {
@@ -827,8 +1468,16 @@ type InsertCharacterParams struct {
 	SkillProficiencies       *string `json:"skillProficiencies"`
 	SavingThrowProficiencies *string `json:"savingThrowProficiencies"`
 	Features                 *string `json:"features"`
-	Equipment                *string `json:"equipment"`
 	AvatarUrl                *string `json:"avatarUrl"`
+	ProficiencyLevels        *string `json:"proficiencyLevels"`
+	HitDiceSpent             *int64  `json:"hitDiceSpent"`
+	DeathSaveSuccesses       *int64  `json:"deathSaveSuccesses"`
+	DeathSaveFailures        *int64  `json:"deathSaveFailures"`
+	DamageResistances        *string `json:"damageResistances"`
+	DamageVulnerabilities    *string `json:"damageVulnerabilities"`
+	DamageImmunities         *string `json:"damageImmunities"`
+	ArmorClassOverride       *bool   `json:"armorClassOverride"`
+	ArmorClassBonus          *int64  `json:"armorClassBonus"`
 }
 
 func (q *Queries) InsertCharacter(ctx context.Context, arg InsertCharacterParams) (Character, error) {
@@ -856,8 +1505,16 @@ func (q *Queries) InsertCharacter(ctx context.Context, arg InsertCharacterParams
 		arg.SkillProficiencies,
 		arg.SavingThrowProficiencies,
 		arg.Features,
-		arg.Equipment,
 		arg.AvatarUrl,
+		arg.ProficiencyLevels,
+		arg.HitDiceSpent,
+		arg.DeathSaveSuccesses,
+		arg.DeathSaveFailures,
+		arg.DamageResistances,
+		arg.DamageVulnerabilities,
+		arg.DamageImmunities,
+		arg.ArmorClassOverride,
+		arg.ArmorClassBonus,
 	)
 	var i Character
 	err := row.Scan(
@@ -885,182 +1542,1734 @@ func (q *Queries) InsertCharacter(ctx context.Context, arg InsertCharacterParams
 		&i.SkillProficiencies,
 		&i.SavingThrowProficiencies,
 		&i.Features,
-		&i.Equipment,
 		&i.AvatarUrl,
+		&i.ProficiencyLevels,
+		&i.HitDiceSpent,
+		&i.DeathSaveSuccesses,
+		&i.DeathSaveFailures,
+		&i.DamageResistances,
+		&i.DamageVulnerabilities,
+		&i.DamageImmunities,
+		&i.ArmorClassOverride,
+		&i.ArmorClassBonus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const insertMembershipOnRedeem = `-- name: InsertMembershipOnRedeem :exec
-INSERT INTO campaign_members (campaign_id, user_id, role, status, invited_by)
-VALUES (?, ?, ?, 'accepted', ?)
+const insertCharacterAbilityScores = `-- name: InsertCharacterAbilityScores :exec
+INSERT INTO character_ability_scores (character_id, method, base, increases, roll_id)
+VALUES (?, ?, ?, ?, ?)
 `
 
-type InsertMembershipOnRedeemParams struct {
-	CampaignID int64  `json:"campaignId"`
-	UserID     int64  `json:"userId"`
-	Role       string `json:"role"`
-	InvitedBy  *int64 `json:"invitedBy"`
+type InsertCharacterAbilityScoresParams struct {
+	CharacterID int64  `json:"characterId"`
+	Method      string `json:"method"`
+	Base        string `json:"base"`
+	Increases   string `json:"increases"`
+	RollID      *int64 `json:"rollId"`
 }
 
-func (q *Queries) InsertMembershipOnRedeem(ctx context.Context, arg InsertMembershipOnRedeemParams) error {
-	_, err := q.db.ExecContext(ctx, insertMembershipOnRedeem,
-		arg.CampaignID,
-		arg.UserID,
-		arg.Role,
-		arg.InvitedBy,
+func (q *Queries) InsertCharacterAbilityScores(ctx context.Context, arg InsertCharacterAbilityScoresParams) error {
+	_, err := q.db.ExecContext(ctx, insertCharacterAbilityScores,
+		arg.CharacterID,
+		arg.Method,
+		arg.Base,
+		arg.Increases,
+		arg.RollID,
 	)
 	return err
 }
 
-const insertNote = `-- name: InsertNote :one
-INSERT INTO notes (user_id, entity_type, entity_id, title, body)
-VALUES (?, ?, ?, ?, ?)
-RETURNING id, user_id, entity_type, entity_id, title, body, created_at, updated_at
+const insertCharacterAttack = `-- name: InsertCharacterAttack :one
+INSERT INTO character_attacks (character_id, name, kind, ability, proficient, damage_dice, versatile_dice, damage_type, properties, bonus)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, name, kind, ability, proficient, damage_dice, versatile_dice, damage_type, properties, bonus, created_at
 `
 
-type InsertNoteParams struct {
-	UserID     int64  `json:"userId"`
-	EntityType string `json:"entityType"`
-	EntityID   *int64 `json:"entityId"`
-	Title      string `json:"title"`
-	Body       string `json:"body"`
+type InsertCharacterAttackParams struct {
+	CharacterID   int64  `json:"characterId"`
+	Name          string `json:"name"`
+	Kind          string `json:"kind"`
+	Ability       string `json:"ability"`
+	Proficient    bool   `json:"proficient"`
+	DamageDice    string `json:"damageDice"`
+	VersatileDice string `json:"versatileDice"`
+	DamageType    string `json:"damageType"`
+	Properties    string `json:"properties"`
+	Bonus         int64  `json:"bonus"`
 }
 
-// Notes queries
-func (q *Queries) InsertNote(ctx context.Context, arg InsertNoteParams) (Note, error) {
-	row := q.db.QueryRowContext(ctx, insertNote,
-		arg.UserID,
-		arg.EntityType,
-		arg.EntityID,
-		arg.Title,
-		arg.Body,
+func (q *Queries) InsertCharacterAttack(ctx context.Context, arg InsertCharacterAttackParams) (CharacterAttack, error) {
+	row := q.db.QueryRowContext(ctx, insertCharacterAttack,
+		arg.CharacterID,
+		arg.Name,
+		arg.Kind,
+		arg.Ability,
+		arg.Proficient,
+		arg.DamageDice,
+		arg.VersatileDice,
+		arg.DamageType,
+		arg.Properties,
+		arg.Bonus,
 	)
-	var i Note
+	var i CharacterAttack
 	err := row.Scan(
 		&i.ID,
-		&i.UserID,
-		&i.EntityType,
-		&i.EntityID,
-		&i.Title,
-		&i.Body,
+		&i.CharacterID,
+		&i.Name,
+		&i.Kind,
+		&i.Ability,
+		&i.Proficient,
+		&i.DamageDice,
+		&i.VersatileDice,
+		&i.DamageType,
+		&i.Properties,
+		&i.Bonus,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const listCampaignDetails = `-- name: ListCampaignDetails :many
-SELECT c.id AS campaign_id, c.owner_id, c.name, c.description, c.visibility, c.status, c.active_scene_id, c.created_at, c.updated_at,
-       cc.id AS link_id, COALESCE(ch.id, 0) AS character_id, COALESCE(ch.name, '') AS character_name, COALESCE(ch.class, '') AS character_class, COALESCE(ch.level, 0) AS character_level,
-       COALESCE(u.id, 0) AS owner_user_id, COALESCE(u.username, '') AS owner_username
-FROM campaigns c
-JOIN campaign_members m ON m.campaign_id = c.id
-LEFT JOIN campaign_characters cc ON cc.campaign_id = c.id
-LEFT JOIN characters ch ON ch.id = cc.character_id
-LEFT JOIN users u ON u.id = ch.user_id
-WHERE m.user_id = ? AND m.status = 'accepted'
-ORDER BY c.updated_at DESC, c.id, cc.id
+const insertCharacterClass = `-- name: InsertCharacterClass :exec
+INSERT INTO character_classes (character_id, class, level, position, subclass, hit_die, spellcasting_ability)
+VALUES (?, ?, ?, ?, ?, ?, ?)
 `
 
-type ListCampaignDetailsRow struct {
-	CampaignID     int64     `json:"campaignId"`
-	OwnerID        int64     `json:"ownerId"`
-	Name           string    `json:"name"`
-	Description    *string   `json:"description"`
-	Visibility     string    `json:"visibility"`
-	Status         string    `json:"status"`
-	ActiveSceneID  *int64    `json:"activeSceneId"`
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdatedAt      time.Time `json:"updatedAt"`
-	LinkID         *int64    `json:"linkId"`
-	CharacterID    int64     `json:"characterId"`
-	CharacterName  string    `json:"characterName"`
-	CharacterClass string    `json:"characterClass"`
-	CharacterLevel int64     `json:"characterLevel"`
-	OwnerUserID    int64     `json:"ownerUserId"`
-	OwnerUsername  string    `json:"ownerUsername"`
+type InsertCharacterClassParams struct {
+	CharacterID         int64  `json:"characterId"`
+	Class               string `json:"class"`
+	Level               int64  `json:"level"`
+	Position            int64  `json:"position"`
+	Subclass            string `json:"subclass"`
+	HitDie              int64  `json:"hitDie"`
+	SpellcastingAbility string `json:"spellcastingAbility"`
 }
 
-func (q *Queries) ListCampaignDetails(ctx context.Context, userID int64) ([]ListCampaignDetailsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listCampaignDetails, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ListCampaignDetailsRow
-	for rows.Next() {
-		var i ListCampaignDetailsRow
-		if err := rows.Scan(
-			&i.CampaignID,
-			&i.OwnerID,
-			&i.Name,
-			&i.Description,
-			&i.Visibility,
-			&i.Status,
-			&i.ActiveSceneID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.LinkID,
-			&i.CharacterID,
-			&i.CharacterName,
-			&i.CharacterClass,
-			&i.CharacterLevel,
-			&i.OwnerUserID,
-			&i.OwnerUsername,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) InsertCharacterClass(ctx context.Context, arg InsertCharacterClassParams) error {
+	_, err := q.db.ExecContext(ctx, insertCharacterClass,
+		arg.CharacterID,
+		arg.Class,
+		arg.Level,
+		arg.Position,
+		arg.Subclass,
+		arg.HitDie,
+		arg.SpellcastingAbility,
+	)
+	return err
 }
 
-const listCampaignHandouts = `-- name: ListCampaignHandouts :many
-SELECT id, campaign_id, title, COALESCE(description, '') as description, COALESCE(file_path, '') as file_path, created_by, created_at, updated_at
-FROM campaign_handouts
-WHERE campaign_id = ?
-ORDER BY created_at DESC, id DESC
+const insertCharacterItem = `-- name: InsertCharacterItem :one
+INSERT INTO character_items (character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position, created_at, updated_at
 `
 
-type ListCampaignHandoutsRow struct {
-	ID          int64     `json:"id"`
-	CampaignID  int64     `json:"campaignId"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	FilePath    string    `json:"filePath"`
-	CreatedBy   int64     `json:"createdBy"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+type InsertCharacterItemParams struct {
+	CharacterID int64   `json:"characterId"`
+	ContainerID *int64  `json:"containerId"`
+	Name        string  `json:"name"`
+	Quantity    int64   `json:"quantity"`
+	Weight      float64 `json:"weight"`
+	ValueCp     int64   `json:"valueCp"`
+	Equipped    bool    `json:"equipped"`
+	Attuned     bool    `json:"attuned"`
+	IsContainer bool    `json:"isContainer"`
+	ArmorType   string  `json:"armorType"`
+	ArmorClass  int64   `json:"armorClass"`
+	Notes       string  `json:"notes"`
+	Position    int64   `json:"position"`
 }
 
-// Handout queries
-func (q *Queries) ListCampaignHandouts(ctx context.Context, campaignID int64) ([]ListCampaignHandoutsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listCampaignHandouts, campaignID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ListCampaignHandoutsRow
-	for rows.Next() {
-		var i ListCampaignHandoutsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.CampaignID,
-			&i.Title,
-			&i.Description,
-			&i.FilePath,
+func (q *Queries) InsertCharacterItem(ctx context.Context, arg InsertCharacterItemParams) (CharacterItem, error) {
+	row := q.db.QueryRowContext(ctx, insertCharacterItem,
+		arg.CharacterID,
+		arg.ContainerID,
+		arg.Name,
+		arg.Quantity,
+		arg.Weight,
+		arg.ValueCp,
+		arg.Equipped,
+		arg.Attuned,
+		arg.IsContainer,
+		arg.ArmorType,
+		arg.ArmorClass,
+		arg.Notes,
+		arg.Position,
+	)
+	var i CharacterItem
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.ContainerID,
+		&i.Name,
+		&i.Quantity,
+		&i.Weight,
+		&i.ValueCp,
+		&i.Equipped,
+		&i.Attuned,
+		&i.IsContainer,
+		&i.ArmorType,
+		&i.ArmorClass,
+		&i.Notes,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertCharacterLevelUp = `-- name: InsertCharacterLevelUp :one
+INSERT INTO character_level_ups (character_id, level, hp_method, hp_roll, hp_gain, ability_increases, feat, features, class)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, level, hp_method, hp_roll, hp_gain, ability_increases, feat, features, created_at, class
+`
+
+type InsertCharacterLevelUpParams struct {
+	CharacterID      int64  `json:"characterId"`
+	Level            int64  `json:"level"`
+	HpMethod         string `json:"hpMethod"`
+	HpRoll           int64  `json:"hpRoll"`
+	HpGain           int64  `json:"hpGain"`
+	AbilityIncreases string `json:"abilityIncreases"`
+	Feat             string `json:"feat"`
+	Features         string `json:"features"`
+	Class            string `json:"class"`
+}
+
+func (q *Queries) InsertCharacterLevelUp(ctx context.Context, arg InsertCharacterLevelUpParams) (CharacterLevelUp, error) {
+	row := q.db.QueryRowContext(ctx, insertCharacterLevelUp,
+		arg.CharacterID,
+		arg.Level,
+		arg.HpMethod,
+		arg.HpRoll,
+		arg.HpGain,
+		arg.AbilityIncreases,
+		arg.Feat,
+		arg.Features,
+		arg.Class,
+	)
+	var i CharacterLevelUp
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Level,
+		&i.HpMethod,
+		&i.HpRoll,
+		&i.HpGain,
+		&i.AbilityIncreases,
+		&i.Feat,
+		&i.Features,
+		&i.CreatedAt,
+		&i.Class,
+	)
+	return i, err
+}
+
+const insertCharacterResource = `-- name: InsertCharacterResource :one
+INSERT INTO character_resources (character_id, name, max_formula, used, reset)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, character_id, name, max_formula, used, reset, created_at
+`
+
+type InsertCharacterResourceParams struct {
+	CharacterID int64  `json:"characterId"`
+	Name        string `json:"name"`
+	MaxFormula  string `json:"maxFormula"`
+	Used        int64  `json:"used"`
+	Reset       string `json:"reset"`
+}
+
+func (q *Queries) InsertCharacterResource(ctx context.Context, arg InsertCharacterResourceParams) (CharacterResource, error) {
+	row := q.db.QueryRowContext(ctx, insertCharacterResource,
+		arg.CharacterID,
+		arg.Name,
+		arg.MaxFormula,
+		arg.Used,
+		arg.Reset,
+	)
+	var i CharacterResource
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Name,
+		&i.MaxFormula,
+		&i.Used,
+		&i.Reset,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertCharacterRest = `-- name: InsertCharacterRest :one
+INSERT INTO character_rests (character_id, rest_type, hit_dice_spent, hit_dice_recovered, hp_before, hp_after, roll)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, rest_type, hit_dice_spent, hit_dice_recovered, hp_before, hp_after, roll, created_at
+`
+
+type InsertCharacterRestParams struct {
+	CharacterID      int64  `json:"characterId"`
+	RestType         string `json:"restType"`
+	HitDiceSpent     int64  `json:"hitDiceSpent"`
+	HitDiceRecovered int64  `json:"hitDiceRecovered"`
+	HpBefore         int64  `json:"hpBefore"`
+	HpAfter          int64  `json:"hpAfter"`
+	Roll             string `json:"roll"`
+}
+
+func (q *Queries) InsertCharacterRest(ctx context.Context, arg InsertCharacterRestParams) (CharacterRest, error) {
+	row := q.db.QueryRowContext(ctx, insertCharacterRest,
+		arg.CharacterID,
+		arg.RestType,
+		arg.HitDiceSpent,
+		arg.HitDiceRecovered,
+		arg.HpBefore,
+		arg.HpAfter,
+		arg.Roll,
+	)
+	var i CharacterRest
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.RestType,
+		&i.HitDiceSpent,
+		&i.HitDiceRecovered,
+		&i.HpBefore,
+		&i.HpAfter,
+		&i.Roll,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertCharacterRevision = `-- name: InsertCharacterRevision :one
+INSERT INTO character_revisions (character_id, revision, reason, snapshot)
+SELECT ?, COALESCE(MAX(r.revision), 0) + 1, ?, ?
+FROM character_revisions r
+WHERE r.character_id = ?
+RETURNING id, character_id, revision, reason, snapshot, created_at
+`
+
+type InsertCharacterRevisionParams struct {
+	CharacterID   int64  `json:"characterId"`
+	Reason        string `json:"reason"`
+	Snapshot      string `json:"snapshot"`
+	CharacterID_2 int64  `json:"characterId2"`
+}
+
+func (q *Queries) InsertCharacterRevision(ctx context.Context, arg InsertCharacterRevisionParams) (CharacterRevision, error) {
+	row := q.db.QueryRowContext(ctx, insertCharacterRevision,
+		arg.CharacterID,
+		arg.Reason,
+		arg.Snapshot,
+		arg.CharacterID_2,
+	)
+	var i CharacterRevision
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Revision,
+		&i.Reason,
+		&i.Snapshot,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertCharacterSpell = `-- name: InsertCharacterSpell :one
+INSERT INTO character_spells (character_id, name, level, school, prepared, notes)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, character_id, name, level, school, prepared, notes, created_at
+`
+
+type InsertCharacterSpellParams struct {
+	CharacterID int64  `json:"characterId"`
+	Name        string `json:"name"`
+	Level       int64  `json:"level"`
+	School      string `json:"school"`
+	Prepared    bool   `json:"prepared"`
+	Notes       string `json:"notes"`
+}
+
+func (q *Queries) InsertCharacterSpell(ctx context.Context, arg InsertCharacterSpellParams) (CharacterSpell, error) {
+	row := q.db.QueryRowContext(ctx, insertCharacterSpell,
+		arg.CharacterID,
+		arg.Name,
+		arg.Level,
+		arg.School,
+		arg.Prepared,
+		arg.Notes,
+	)
+	var i CharacterSpell
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Name,
+		&i.Level,
+		&i.School,
+		&i.Prepared,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertHomebrewPack = `-- name: InsertHomebrewPack :one
+INSERT INTO homebrew_packs (user_id, campaign_id, name, version, content)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, user_id, campaign_id, name, version, content, created_at, updated_at
+`
+
+type InsertHomebrewPackParams struct {
+	UserID     int64  `json:"userId"`
+	CampaignID *int64 `json:"campaignId"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Content    string `json:"content"`
+}
+
+func (q *Queries) InsertHomebrewPack(ctx context.Context, arg InsertHomebrewPackParams) (HomebrewPack, error) {
+	row := q.db.QueryRowContext(ctx, insertHomebrewPack,
+		arg.UserID,
+		arg.CampaignID,
+		arg.Name,
+		arg.Version,
+		arg.Content,
+	)
+	var i HomebrewPack
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Version,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertLayer = `-- name: InsertLayer :one
+INSERT INTO layers (map_id, type, z_index, data)
+VALUES (?, ?, ?, ?)
+RETURNING id, map_id, type, z_index, data
+`
+
+type InsertLayerParams struct {
+	MapID  int64  `json:"mapId"`
+	Type   string `json:"type"`
+	ZIndex int64  `json:"zIndex"`
+	Data   string `json:"data"`
+}
+
+func (q *Queries) InsertLayer(ctx context.Context, arg InsertLayerParams) (Layer, error) {
+	row := q.db.QueryRowContext(ctx, insertLayer,
+		arg.MapID,
+		arg.Type,
+		arg.ZIndex,
+		arg.Data,
+	)
+	var i Layer
+	err := row.Scan(
+		&i.ID,
+		&i.MapID,
+		&i.Type,
+		&i.ZIndex,
+		&i.Data,
+	)
+	return i, err
+}
+
+const insertMembershipOnRedeem = `-- name: InsertMembershipOnRedeem :exec
+INSERT INTO campaign_members (campaign_id, user_id, role, status, invited_by)
+VALUES (?, ?, ?, 'accepted', ?)
+`
+
+type InsertMembershipOnRedeemParams struct {
+	CampaignID int64  `json:"campaignId"`
+	UserID     int64  `json:"userId"`
+	Role       string `json:"role"`
+	InvitedBy  *int64 `json:"invitedBy"`
+}
+
+func (q *Queries) InsertMembershipOnRedeem(ctx context.Context, arg InsertMembershipOnRedeemParams) error {
+	_, err := q.db.ExecContext(ctx, insertMembershipOnRedeem,
+		arg.CampaignID,
+		arg.UserID,
+		arg.Role,
+		arg.InvitedBy,
+	)
+	return err
+}
+
+const insertNote = `-- name: InsertNote :one
+INSERT INTO notes (user_id, entity_type, entity_id, title, body)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, user_id, entity_type, entity_id, title, body, created_at, updated_at
+`
+
+type InsertNoteParams struct {
+	UserID     int64  `json:"userId"`
+	EntityType string `json:"entityType"`
+	EntityID   *int64 `json:"entityId"`
+	Title      string `json:"title"`
+	Body       string `json:"body"`
+}
+
+// Notes queries
+func (q *Queries) InsertNote(ctx context.Context, arg InsertNoteParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, insertNote,
+		arg.UserID,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Title,
+		arg.Body,
+	)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.EntityType,
+		&i.EntityID,
+		&i.Title,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCampaignAbilityScores = `-- name: ListCampaignAbilityScores :many
+SELECT a.character_id, ch.name AS character_name, u.username AS owner_username, a.method, a.base, a.increases, a.roll_id, COALESCE(r.rolls, '') AS rolls, r.created_at AS rolled_at, a.created_at
+FROM character_ability_scores a
+JOIN campaign_characters cc ON cc.character_id = a.character_id
+JOIN characters ch ON ch.id = a.character_id
+JOIN users u ON u.id = ch.user_id
+LEFT JOIN ability_score_rolls r ON r.id = a.roll_id
+WHERE cc.campaign_id = ?
+ORDER BY ch.name ASC, a.character_id ASC
+`
+
+type ListCampaignAbilityScoresRow struct {
+	CharacterID   int64      `json:"characterId"`
+	CharacterName string     `json:"characterName"`
+	OwnerUsername string     `json:"ownerUsername"`
+	Method        string     `json:"method"`
+	Base          string     `json:"base"`
+	Increases     string     `json:"increases"`
+	RollID        *int64     `json:"rollId"`
+	Rolls         string     `json:"rolls"`
+	RolledAt      *time.Time `json:"rolledAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+func (q *Queries) ListCampaignAbilityScores(ctx context.Context, campaignID int64) ([]ListCampaignAbilityScoresRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignAbilityScores, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignAbilityScoresRow
+	for rows.Next() {
+		var i ListCampaignAbilityScoresRow
+		if err := rows.Scan(
+			&i.CharacterID,
+			&i.CharacterName,
+			&i.OwnerUsername,
+			&i.Method,
+			&i.Base,
+			&i.Increases,
+			&i.RollID,
+			&i.Rolls,
+			&i.RolledAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignDetails = `-- name: ListCampaignDetails :many
+SELECT c.id AS campaign_id, c.owner_id, c.name, c.description, c.visibility, c.status, c.active_scene_id, c.created_at, c.updated_at,
+       cc.id AS link_id, COALESCE(ch.id, 0) AS character_id, COALESCE(ch.name, '') AS character_name, COALESCE(ch.class, '') AS character_class, COALESCE(ch.level, 0) AS character_level,
+       COALESCE(u.id, 0) AS owner_user_id, COALESCE(u.username, '') AS owner_username
+FROM campaigns c
+JOIN campaign_members m ON m.campaign_id = c.id
+LEFT JOIN campaign_characters cc ON cc.campaign_id = c.id
+LEFT JOIN characters ch ON ch.id = cc.character_id
+LEFT JOIN users u ON u.id = ch.user_id
+WHERE m.user_id = ? AND m.status = 'accepted'
+ORDER BY c.updated_at DESC, c.id, cc.id
+`
+
+type ListCampaignDetailsRow struct {
+	CampaignID     int64     `json:"campaignId"`
+	OwnerID        int64     `json:"ownerId"`
+	Name           string    `json:"name"`
+	Description    *string   `json:"description"`
+	Visibility     string    `json:"visibility"`
+	Status         string    `json:"status"`
+	ActiveSceneID  *int64    `json:"activeSceneId"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+	LinkID         *int64    `json:"linkId"`
+	CharacterID    int64     `json:"characterId"`
+	CharacterName  string    `json:"characterName"`
+	CharacterClass string    `json:"characterClass"`
+	CharacterLevel int64     `json:"characterLevel"`
+	OwnerUserID    int64     `json:"ownerUserId"`
+	OwnerUsername  string    `json:"ownerUsername"`
+}
+
+func (q *Queries) ListCampaignDetails(ctx context.Context, userID int64) ([]ListCampaignDetailsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignDetails, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignDetailsRow
+	for rows.Next() {
+		var i ListCampaignDetailsRow
+		if err := rows.Scan(
+			&i.CampaignID,
+			&i.OwnerID,
+			&i.Name,
+			&i.Description,
+			&i.Visibility,
+			&i.Status,
+			&i.ActiveSceneID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LinkID,
+			&i.CharacterID,
+			&i.CharacterName,
+			&i.CharacterClass,
+			&i.CharacterLevel,
+			&i.OwnerUserID,
+			&i.OwnerUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignHandouts = `-- name: ListCampaignHandouts :many
+SELECT id, campaign_id, title, COALESCE(description, '') as description, COALESCE(file_path, '') as file_path, created_by, created_at, updated_at
+FROM campaign_handouts
+WHERE campaign_id = ?
+ORDER BY created_at DESC, id DESC
+`
+
+type ListCampaignHandoutsRow struct {
+	ID          int64     `json:"id"`
+	CampaignID  int64     `json:"campaignId"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	FilePath    string    `json:"filePath"`
+	CreatedBy   int64     `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Handout queries
+func (q *Queries) ListCampaignHandouts(ctx context.Context, campaignID int64) ([]ListCampaignHandoutsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignHandouts, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignHandoutsRow
+	for rows.Next() {
+		var i ListCampaignHandoutsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Title,
+			&i.Description,
+			&i.FilePath,
 			&i.CreatedBy,
 			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignHomebrewPacks = `-- name: ListCampaignHomebrewPacks :many
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE campaign_id = ?
+ORDER BY name ASC
+`
+
+func (q *Queries) ListCampaignHomebrewPacks(ctx context.Context, campaignID *int64) ([]HomebrewPack, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignHomebrewPacks, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HomebrewPack
+	for rows.Next() {
+		var i HomebrewPack
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CampaignID,
+			&i.Name,
+			&i.Version,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignMembers = `-- name: ListCampaignMembers :many
+SELECT m.id, m.campaign_id, m.user_id, u.username, m.role, m.status, COALESCE(m.invited_by, 0) as invited_by, m.created_at
+FROM campaign_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.campaign_id = ?
+ORDER BY m.created_at ASC
+`
+
+type ListCampaignMembersRow struct {
+	ID         int64     `json:"id"`
+	CampaignID int64     `json:"campaignId"`
+	UserID     int64     `json:"userId"`
+	Username   string    `json:"username"`
+	Role       string    `json:"role"`
+	Status     string    `json:"status"`
+	InvitedBy  int64     `json:"invitedBy"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (q *Queries) ListCampaignMembers(ctx context.Context, campaignID int64) ([]ListCampaignMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignMembers, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignMembersRow
+	for rows.Next() {
+		var i ListCampaignMembersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.UserID,
+			&i.Username,
+			&i.Role,
+			&i.Status,
+			&i.InvitedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignRests = `-- name: ListCampaignRests :many
+SELECT r.id, r.character_id, ch.name AS character_name, u.username AS owner_username, r.rest_type, r.hit_dice_spent, r.hit_dice_recovered, r.hp_before, r.hp_after, r.created_at
+FROM character_rests r
+JOIN campaign_characters cc ON cc.character_id = r.character_id
+JOIN characters ch ON ch.id = r.character_id
+JOIN users u ON u.id = ch.user_id
+WHERE cc.campaign_id = ?
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT ?
+`
+
+type ListCampaignRestsParams struct {
+	CampaignID int64 `json:"campaignId"`
+	Limit      int64 `json:"limit"`
+}
+
+type ListCampaignRestsRow struct {
+	ID               int64     `json:"id"`
+	CharacterID      int64     `json:"characterId"`
+	CharacterName    string    `json:"characterName"`
+	OwnerUsername    string    `json:"ownerUsername"`
+	RestType         string    `json:"restType"`
+	HitDiceSpent     int64     `json:"hitDiceSpent"`
+	HitDiceRecovered int64     `json:"hitDiceRecovered"`
+	HpBefore         int64     `json:"hpBefore"`
+	HpAfter          int64     `json:"hpAfter"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+func (q *Queries) ListCampaignRests(ctx context.Context, arg ListCampaignRestsParams) ([]ListCampaignRestsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignRests, arg.CampaignID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignRestsRow
+	for rows.Next() {
+		var i ListCampaignRestsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.CharacterName,
+			&i.OwnerUsername,
+			&i.RestType,
+			&i.HitDiceSpent,
+			&i.HitDiceRecovered,
+			&i.HpBefore,
+			&i.HpAfter,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignRolls = `-- name: ListCampaignRolls :many
+SELECT r.id, r.campaign_id, r.user_id, u.username, r.character_id, r.label, r.expression, r.result, r.total, r.visibility, r.prev_hash, r.hash, r.created_at
+FROM campaign_rolls r
+JOIN users u ON u.id = r.user_id
+WHERE r.campaign_id = ?
+  AND (r.user_id = ? OR r.visibility IN (/*SLICE:visibilities*/?))
+ORDER BY r.id DESC
+LIMIT ?
+`
+
+type ListCampaignRollsParams struct {
+	CampaignID   int64    `json:"campaignId"`
+	UserID       int64    `json:"userId"`
+	Visibilities []string `json:"visibilities"`
+	Limit        int64    `json:"limit"`
+}
+
+type ListCampaignRollsRow struct {
+	ID          int64     `json:"id"`
+	CampaignID  int64     `json:"campaignId"`
+	UserID      int64     `json:"userId"`
+	Username    string    `json:"username"`
+	CharacterID *int64    `json:"characterId"`
+	Label       string    `json:"label"`
+	Expression  string    `json:"expression"`
+	Result      string    `json:"result"`
+	Total       int64     `json:"total"`
+	Visibility  string    `json:"visibility"`
+	PrevHash    string    `json:"prevHash"`
+	Hash        string    `json:"hash"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (q *Queries) ListCampaignRolls(ctx context.Context, arg ListCampaignRollsParams) ([]ListCampaignRollsRow, error) {
+	query := listCampaignRolls
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.CampaignID)
+	queryParams = append(queryParams, arg.UserID)
+	if len(arg.Visibilities) > 0 {
+		for _, v := range arg.Visibilities {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:visibilities*/?", strings.Repeat(",?", len(arg.Visibilities))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:visibilities*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignRollsRow
+	for rows.Next() {
+		var i ListCampaignRollsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.UserID,
+			&i.Username,
+			&i.CharacterID,
+			&i.Label,
+			&i.Expression,
+			&i.Result,
+			&i.Total,
+			&i.Visibility,
+			&i.PrevHash,
+			&i.Hash,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignRollsForAudit = `-- name: ListCampaignRollsForAudit :many
+SELECT id, campaign_id, user_id, character_id, label, expression, result, total, visibility, prev_hash, hash, created_at
+FROM campaign_rolls
+WHERE campaign_id = ?
+ORDER BY id ASC
+`
+
+func (q *Queries) ListCampaignRollsForAudit(ctx context.Context, campaignID int64) ([]CampaignRoll, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignRollsForAudit, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CampaignRoll
+	for rows.Next() {
+		var i CampaignRoll
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.UserID,
+			&i.CharacterID,
+			&i.Label,
+			&i.Expression,
+			&i.Result,
+			&i.Total,
+			&i.Visibility,
+			&i.PrevHash,
+			&i.Hash,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignsForUser = `-- name: ListCampaignsForUser :many
+SELECT c.id, c.owner_id, c.name, COALESCE(c.description, '') as description, c.visibility, c.status, c.active_scene_id, c.created_at, c.updated_at
+FROM campaigns c
+JOIN campaign_members m ON m.campaign_id = c.id
+WHERE m.user_id = ? AND m.status = 'accepted'
+ORDER BY c.updated_at DESC
+`
+
+type ListCampaignsForUserRow struct {
+	ID            int64     `json:"id"`
+	OwnerID       int64     `json:"ownerId"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	Visibility    string    `json:"visibility"`
+	Status        string    `json:"status"`
+	ActiveSceneID *int64    `json:"activeSceneId"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+func (q *Queries) ListCampaignsForUser(ctx context.Context, userID int64) ([]ListCampaignsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCampaignsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignsForUserRow
+	for rows.Next() {
+		var i ListCampaignsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Name,
+			&i.Description,
+			&i.Visibility,
+			&i.Status,
+			&i.ActiveSceneID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCatalogHomebrewPacks = `-- name: ListCatalogHomebrewPacks :many
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE (campaign_id IS NULL AND user_id = ?)
+   OR campaign_id IN (/*SLICE:campaign_ids*/?)
+ORDER BY name ASC, id ASC
+`
+
+type ListCatalogHomebrewPacksParams struct {
+	UserID      int64   `json:"userId"`
+	CampaignIds []int64 `json:"campaignIds"`
+}
+
+func (q *Queries) ListCatalogHomebrewPacks(ctx context.Context, arg ListCatalogHomebrewPacksParams) ([]HomebrewPack, error) {
+	query := listCatalogHomebrewPacks
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.UserID)
+	if len(arg.CampaignIds) > 0 {
+		for _, v := range arg.CampaignIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:campaign_ids*/?", strings.Repeat(",?", len(arg.CampaignIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:campaign_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HomebrewPack
+	for rows.Next() {
+		var i HomebrewPack
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CampaignID,
+			&i.Name,
+			&i.Version,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterAttacks = `-- name: ListCharacterAttacks :many
+SELECT id, character_id, name, kind, ability, proficient, damage_dice, versatile_dice, damage_type, properties, bonus, created_at
+FROM character_attacks
+WHERE character_id = ?
+ORDER BY id ASC
+`
+
+func (q *Queries) ListCharacterAttacks(ctx context.Context, characterID int64) ([]CharacterAttack, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterAttacks, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterAttack
+	for rows.Next() {
+		var i CharacterAttack
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Name,
+			&i.Kind,
+			&i.Ability,
+			&i.Proficient,
+			&i.DamageDice,
+			&i.VersatileDice,
+			&i.DamageType,
+			&i.Properties,
+			&i.Bonus,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterAttacksByUser = `-- name: ListCharacterAttacksByUser :many
+SELECT ca.id, ca.character_id, ca.name, ca.kind, ca.ability, ca.proficient, ca.damage_dice, ca.versatile_dice, ca.damage_type, ca.properties, ca.bonus, ca.created_at
+FROM character_attacks ca
+JOIN characters c ON c.id = ca.character_id
+WHERE c.user_id = ?
+ORDER BY ca.character_id ASC, ca.id ASC
+`
+
+type ListCharacterAttacksByUserRow struct {
+	ID            int64     `json:"id"`
+	CharacterID   int64     `json:"characterId"`
+	Name          string    `json:"name"`
+	Kind          string    `json:"kind"`
+	Ability       string    `json:"ability"`
+	Proficient    bool      `json:"proficient"`
+	DamageDice    string    `json:"damageDice"`
+	VersatileDice string    `json:"versatileDice"`
+	DamageType    string    `json:"damageType"`
+	Properties    string    `json:"properties"`
+	Bonus         int64     `json:"bonus"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+func (q *Queries) ListCharacterAttacksByUser(ctx context.Context, userID int64) ([]ListCharacterAttacksByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterAttacksByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCharacterAttacksByUserRow
+	for rows.Next() {
+		var i ListCharacterAttacksByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Name,
+			&i.Kind,
+			&i.Ability,
+			&i.Proficient,
+			&i.DamageDice,
+			&i.VersatileDice,
+			&i.DamageType,
+			&i.Properties,
+			&i.Bonus,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterCampaignIDs = `-- name: ListCharacterCampaignIDs :many
+SELECT campaign_id FROM campaign_characters WHERE character_id = ? ORDER BY campaign_id ASC
+`
+
+func (q *Queries) ListCharacterCampaignIDs(ctx context.Context, characterID int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterCampaignIDs, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var campaign_id int64
+		if err := rows.Scan(&campaign_id); err != nil {
+			return nil, err
+		}
+		items = append(items, campaign_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterClasses = `-- name: ListCharacterClasses :many
+SELECT id, character_id, class, level, position, subclass, hit_die, spellcasting_ability
+FROM character_classes
+WHERE character_id = ?
+ORDER BY position ASC, id ASC
+`
+
+func (q *Queries) ListCharacterClasses(ctx context.Context, characterID int64) ([]CharacterClass, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterClasses, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterClass
+	for rows.Next() {
+		var i CharacterClass
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Class,
+			&i.Level,
+			&i.Position,
+			&i.Subclass,
+			&i.HitDie,
+			&i.SpellcastingAbility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterClassesByCharacterIDs = `-- name: ListCharacterClassesByCharacterIDs :many
+SELECT id, character_id, class, level, position, subclass, hit_die, spellcasting_ability
+FROM character_classes
+WHERE character_id IN (/*SLICE:character_ids*/?)
+ORDER BY character_id ASC, position ASC, id ASC
+`
+
+func (q *Queries) ListCharacterClassesByCharacterIDs(ctx context.Context, characterIds []int64) ([]CharacterClass, error) {
+	query := listCharacterClassesByCharacterIDs
+	var queryParams []interface{}
+	if len(characterIds) > 0 {
+		for _, v := range characterIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:character_ids*/?", strings.Repeat(",?", len(characterIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:character_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterClass
+	for rows.Next() {
+		var i CharacterClass
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Class,
+			&i.Level,
+			&i.Position,
+			&i.Subclass,
+			&i.HitDie,
+			&i.SpellcastingAbility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterClassesByUser = `-- name: ListCharacterClassesByUser :many
+SELECT cl.id, cl.character_id, cl.class, cl.level, cl.position, cl.subclass, cl.hit_die, cl.spellcasting_ability
+FROM character_classes cl
+JOIN characters c ON c.id = cl.character_id
+WHERE c.user_id = ?
+ORDER BY cl.character_id ASC, cl.position ASC, cl.id ASC
+`
+
+type ListCharacterClassesByUserRow struct {
+	ID                  int64  `json:"id"`
+	CharacterID         int64  `json:"characterId"`
+	Class               string `json:"class"`
+	Level               int64  `json:"level"`
+	Position            int64  `json:"position"`
+	Subclass            string `json:"subclass"`
+	HitDie              int64  `json:"hitDie"`
+	SpellcastingAbility string `json:"spellcastingAbility"`
+}
+
+func (q *Queries) ListCharacterClassesByUser(ctx context.Context, userID int64) ([]ListCharacterClassesByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterClassesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCharacterClassesByUserRow
+	for rows.Next() {
+		var i ListCharacterClassesByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Class,
+			&i.Level,
+			&i.Position,
+			&i.Subclass,
+			&i.HitDie,
+			&i.SpellcastingAbility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterCoinsByUser = `-- name: ListCharacterCoinsByUser :many
+SELECT co.character_id, co.cp, co.sp, co.ep, co.gp, co.pp
+FROM character_coins co
+JOIN characters ch ON ch.id = co.character_id
+WHERE ch.user_id = ?
+`
+
+type ListCharacterCoinsByUserRow struct {
+	CharacterID int64 `json:"characterId"`
+	Cp          int64 `json:"cp"`
+	Sp          int64 `json:"sp"`
+	Ep          int64 `json:"ep"`
+	Gp          int64 `json:"gp"`
+	Pp          int64 `json:"pp"`
+}
+
+func (q *Queries) ListCharacterCoinsByUser(ctx context.Context, userID int64) ([]ListCharacterCoinsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterCoinsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCharacterCoinsByUserRow
+	for rows.Next() {
+		var i ListCharacterCoinsByUserRow
+		if err := rows.Scan(
+			&i.CharacterID,
+			&i.Cp,
+			&i.Sp,
+			&i.Ep,
+			&i.Gp,
+			&i.Pp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterConditions = `-- name: ListCharacterConditions :many
+SELECT id, character_id, name, level, source, duration, duration_unit, created_at
+FROM character_conditions
+WHERE character_id = ?
+ORDER BY name ASC
+`
+
+func (q *Queries) ListCharacterConditions(ctx context.Context, characterID int64) ([]CharacterCondition, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterConditions, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterCondition
+	for rows.Next() {
+		var i CharacterCondition
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Name,
+			&i.Level,
+			&i.Source,
+			&i.Duration,
+			&i.DurationUnit,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterConditionsByCharacterIDs = `-- name: ListCharacterConditionsByCharacterIDs :many
+SELECT id, character_id, name, level, source, duration, duration_unit, created_at
+FROM character_conditions
+WHERE character_id IN (/*SLICE:character_ids*/?)
+ORDER BY character_id ASC, name ASC
+`
+
+func (q *Queries) ListCharacterConditionsByCharacterIDs(ctx context.Context, characterIds []int64) ([]CharacterCondition, error) {
+	query := listCharacterConditionsByCharacterIDs
+	var queryParams []interface{}
+	if len(characterIds) > 0 {
+		for _, v := range characterIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:character_ids*/?", strings.Repeat(",?", len(characterIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:character_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterCondition
+	for rows.Next() {
+		var i CharacterCondition
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Name,
+			&i.Level,
+			&i.Source,
+			&i.Duration,
+			&i.DurationUnit,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterConditionsByUser = `-- name: ListCharacterConditionsByUser :many
+SELECT cc.id, cc.character_id, cc.name, cc.level, cc.source, cc.duration, cc.duration_unit, cc.created_at
+FROM character_conditions cc
+JOIN characters c ON c.id = cc.character_id
+WHERE c.user_id = ?
+ORDER BY cc.character_id ASC, cc.name ASC
+`
+
+type ListCharacterConditionsByUserRow struct {
+	ID           int64     `json:"id"`
+	CharacterID  int64     `json:"characterId"`
+	Name         string    `json:"name"`
+	Level        int64     `json:"level"`
+	Source       string    `json:"source"`
+	Duration     *int64    `json:"duration"`
+	DurationUnit string    `json:"durationUnit"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (q *Queries) ListCharacterConditionsByUser(ctx context.Context, userID int64) ([]ListCharacterConditionsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterConditionsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCharacterConditionsByUserRow
+	for rows.Next() {
+		var i ListCharacterConditionsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Name,
+			&i.Level,
+			&i.Source,
+			&i.Duration,
+			&i.DurationUnit,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterIDsByUser = `-- name: ListCharacterIDsByUser :many
+SELECT id FROM characters WHERE user_id = ?
+`
+
+func (q *Queries) ListCharacterIDsByUser(ctx context.Context, userID int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterIDsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterItems = `-- name: ListCharacterItems :many
+SELECT id, character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position, created_at, updated_at
+FROM character_items
+WHERE character_id = ?
+ORDER BY position, id
+`
+
+func (q *Queries) ListCharacterItems(ctx context.Context, characterID int64) ([]CharacterItem, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterItems, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterItem
+	for rows.Next() {
+		var i CharacterItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.ContainerID,
+			&i.Name,
+			&i.Quantity,
+			&i.Weight,
+			&i.ValueCp,
+			&i.Equipped,
+			&i.Attuned,
+			&i.IsContainer,
+			&i.ArmorType,
+			&i.ArmorClass,
+			&i.Notes,
+			&i.Position,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterItemsByUser = `-- name: ListCharacterItemsByUser :many
+SELECT i.id, i.character_id, i.container_id, i.name, i.quantity, i.weight, i.value_cp, i.equipped, i.attuned, i.is_container, i.armor_type, i.armor_class, i.notes, i.position, i.created_at, i.updated_at
+FROM character_items i
+JOIN characters ch ON ch.id = i.character_id
+WHERE ch.user_id = ?
+ORDER BY i.character_id, i.position, i.id
+`
+
+type ListCharacterItemsByUserRow struct {
+	ID          int64     `json:"id"`
+	CharacterID int64     `json:"characterId"`
+	ContainerID *int64    `json:"containerId"`
+	Name        string    `json:"name"`
+	Quantity    int64     `json:"quantity"`
+	Weight      float64   `json:"weight"`
+	ValueCp     int64     `json:"valueCp"`
+	Equipped    bool      `json:"equipped"`
+	Attuned     bool      `json:"attuned"`
+	IsContainer bool      `json:"isContainer"`
+	ArmorType   string    `json:"armorType"`
+	ArmorClass  int64     `json:"armorClass"`
+	Notes       string    `json:"notes"`
+	Position    int64     `json:"position"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (q *Queries) ListCharacterItemsByUser(ctx context.Context, userID int64) ([]ListCharacterItemsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterItemsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCharacterItemsByUserRow
+	for rows.Next() {
+		var i ListCharacterItemsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.ContainerID,
+			&i.Name,
+			&i.Quantity,
+			&i.Weight,
+			&i.ValueCp,
+			&i.Equipped,
+			&i.Attuned,
+			&i.IsContainer,
+			&i.ArmorType,
+			&i.ArmorClass,
+			&i.Notes,
+			&i.Position,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterLevelUps = `-- name: ListCharacterLevelUps :many
+SELECT id, character_id, level, hp_method, hp_roll, hp_gain, ability_increases, feat, features, created_at, class
+FROM character_level_ups
+WHERE character_id = ?
+ORDER BY level, id
+`
+
+func (q *Queries) ListCharacterLevelUps(ctx context.Context, characterID int64) ([]CharacterLevelUp, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterLevelUps, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterLevelUp
+	for rows.Next() {
+		var i CharacterLevelUp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Level,
+			&i.HpMethod,
+			&i.HpRoll,
+			&i.HpGain,
+			&i.AbilityIncreases,
+			&i.Feat,
+			&i.Features,
+			&i.CreatedAt,
+			&i.Class,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterResources = `-- name: ListCharacterResources :many
+SELECT id, character_id, name, max_formula, used, reset, created_at
+FROM character_resources
+WHERE character_id = ?
+ORDER BY id ASC
+`
+
+func (q *Queries) ListCharacterResources(ctx context.Context, characterID int64) ([]CharacterResource, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterResources, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterResource
+	for rows.Next() {
+		var i CharacterResource
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Name,
+			&i.MaxFormula,
+			&i.Used,
+			&i.Reset,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1075,42 +3284,40 @@ func (q *Queries) ListCampaignHandouts(ctx context.Context, campaignID int64) ([
 	return items, nil
 }
 
-const listCampaignMembers = `-- name: ListCampaignMembers :many
-SELECT m.id, m.campaign_id, m.user_id, u.username, m.role, m.status, COALESCE(m.invited_by, 0) as invited_by, m.created_at
-FROM campaign_members m
-JOIN users u ON u.id = m.user_id
-WHERE m.campaign_id = ?
-ORDER BY m.created_at ASC
+const listCharacterResourcesByUser = `-- name: ListCharacterResourcesByUser :many
+SELECT cr.id, cr.character_id, cr.name, cr.max_formula, cr.used, cr.reset, cr.created_at
+FROM character_resources cr
+JOIN characters c ON c.id = cr.character_id
+WHERE c.user_id = ?
+ORDER BY cr.character_id ASC, cr.id ASC
 `
 
-type ListCampaignMembersRow struct {
-	ID         int64     `json:"id"`
-	CampaignID int64     `json:"campaignId"`
-	UserID     int64     `json:"userId"`
-	Username   string    `json:"username"`
-	Role       string    `json:"role"`
-	Status     string    `json:"status"`
-	InvitedBy  int64     `json:"invitedBy"`
-	CreatedAt  time.Time `json:"createdAt"`
+type ListCharacterResourcesByUserRow struct {
+	ID          int64     `json:"id"`
+	CharacterID int64     `json:"characterId"`
+	Name        string    `json:"name"`
+	MaxFormula  string    `json:"maxFormula"`
+	Used        int64     `json:"used"`
+	Reset       string    `json:"reset"`
+	CreatedAt   time.Time `json:"createdAt"`
 }
 
-func (q *Queries) ListCampaignMembers(ctx context.Context, campaignID int64) ([]ListCampaignMembersRow, error) {
-	rows, err := q.db.QueryContext(ctx, listCampaignMembers, campaignID)
+func (q *Queries) ListCharacterResourcesByUser(ctx context.Context, userID int64) ([]ListCharacterResourcesByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterResourcesByUser, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListCampaignMembersRow
+	var items []ListCharacterResourcesByUserRow
 	for rows.Next() {
-		var i ListCampaignMembersRow
+		var i ListCharacterResourcesByUserRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.CampaignID,
-			&i.UserID,
-			&i.Username,
-			&i.Role,
-			&i.Status,
-			&i.InvitedBy,
+			&i.CharacterID,
+			&i.Name,
+			&i.MaxFormula,
+			&i.Used,
+			&i.Reset,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -1126,45 +3333,156 @@ func (q *Queries) ListCampaignMembers(ctx context.Context, campaignID int64) ([]
 	return items, nil
 }
 
-const listCampaignsForUser = `-- name: ListCampaignsForUser :many
-SELECT c.id, c.owner_id, c.name, COALESCE(c.description, '') as description, c.visibility, c.status, c.active_scene_id, c.created_at, c.updated_at
-FROM campaigns c
-JOIN campaign_members m ON m.campaign_id = c.id
-WHERE m.user_id = ? AND m.status = 'accepted'
-ORDER BY c.updated_at DESC
+const listCharacterRests = `-- name: ListCharacterRests :many
+SELECT id, character_id, rest_type, hit_dice_spent, hit_dice_recovered, hp_before, hp_after, roll, created_at
+FROM character_rests
+WHERE character_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ?
 `
 
-type ListCampaignsForUserRow struct {
-	ID            int64     `json:"id"`
-	OwnerID       int64     `json:"ownerId"`
-	Name          string    `json:"name"`
-	Description   string    `json:"description"`
-	Visibility    string    `json:"visibility"`
-	Status        string    `json:"status"`
-	ActiveSceneID *int64    `json:"activeSceneId"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+type ListCharacterRestsParams struct {
+	CharacterID int64 `json:"characterId"`
+	Limit       int64 `json:"limit"`
 }
 
-func (q *Queries) ListCampaignsForUser(ctx context.Context, userID int64) ([]ListCampaignsForUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, listCampaignsForUser, userID)
+func (q *Queries) ListCharacterRests(ctx context.Context, arg ListCharacterRestsParams) ([]CharacterRest, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterRests, arg.CharacterID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListCampaignsForUserRow
+	var items []CharacterRest
 	for rows.Next() {
-		var i ListCampaignsForUserRow
+		var i CharacterRest
 		if err := rows.Scan(
 			&i.ID,
-			&i.OwnerID,
+			&i.CharacterID,
+			&i.RestType,
+			&i.HitDiceSpent,
+			&i.HitDiceRecovered,
+			&i.HpBefore,
+			&i.HpAfter,
+			&i.Roll,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterRevisions = `-- name: ListCharacterRevisions :many
+SELECT id, character_id, revision, reason, created_at
+FROM character_revisions
+WHERE character_id = ?
+ORDER BY revision DESC
+`
+
+type ListCharacterRevisionsRow struct {
+	ID          int64     `json:"id"`
+	CharacterID int64     `json:"characterId"`
+	Revision    int64     `json:"revision"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (q *Queries) ListCharacterRevisions(ctx context.Context, characterID int64) ([]ListCharacterRevisionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterRevisions, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCharacterRevisionsRow
+	for rows.Next() {
+		var i ListCharacterRevisionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.Revision,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterSpellSlots = `-- name: ListCharacterSpellSlots :many
+SELECT character_id, kind, slot_level, expended
+FROM character_spell_slots
+WHERE character_id = ?
+ORDER BY kind, slot_level
+`
+
+func (q *Queries) ListCharacterSpellSlots(ctx context.Context, characterID int64) ([]CharacterSpellSlot, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterSpellSlots, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterSpellSlot
+	for rows.Next() {
+		var i CharacterSpellSlot
+		if err := rows.Scan(
+			&i.CharacterID,
+			&i.Kind,
+			&i.SlotLevel,
+			&i.Expended,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCharacterSpells = `-- name: ListCharacterSpells :many
+SELECT id, character_id, name, level, school, prepared, notes, created_at
+FROM character_spells
+WHERE character_id = ?
+ORDER BY level, name
+`
+
+func (q *Queries) ListCharacterSpells(ctx context.Context, characterID int64) ([]CharacterSpell, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterSpells, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterSpell
+	for rows.Next() {
+		var i CharacterSpell
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
 			&i.Name,
-			&i.Description,
-			&i.Visibility,
-			&i.Status,
-			&i.ActiveSceneID,
+			&i.Level,
+			&i.School,
+			&i.Prepared,
+			&i.Notes,
 			&i.CreatedAt,
-			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1183,8 +3501,8 @@ const listCharactersByUser = `-- name: ListCharactersByUser :many
 SELECT id, user_id, name, race, class, level, COALESCE(background, '') as background, COALESCE(alignment, '') as alignment, COALESCE(experience_points, 0) as experience_points,
        strength, dexterity, constitution, intelligence, wisdom, charisma,
        max_hp, current_hp, COALESCE(temp_hp, 0) as temp_hp, armor_class, COALESCE(speed, 0) as speed, COALESCE(hit_dice, '') as hit_dice,
-       COALESCE(skill_proficiencies, '[]') as skill_proficiencies, COALESCE(saving_throw_proficiencies, '[]') as saving_throw_proficiencies, COALESCE(features, '[]') as features, COALESCE(equipment, '[]') as equipment,
-       COALESCE(avatar_url, '') as avatar_url, created_at, updated_at
+       COALESCE(skill_proficiencies, '[]') as skill_proficiencies, COALESCE(saving_throw_proficiencies, '[]') as saving_throw_proficiencies, COALESCE(features, '[]') as features,
+       COALESCE(avatar_url, '') as avatar_url, COALESCE(proficiency_levels, '{}') as proficiency_levels, COALESCE(hit_dice_spent, 0) as hit_dice_spent, COALESCE(death_save_successes, 0) as death_save_successes, COALESCE(death_save_failures, 0) as death_save_failures, COALESCE(damage_resistances, '[]') as damage_resistances, COALESCE(damage_vulnerabilities, '[]') as damage_vulnerabilities, COALESCE(damage_immunities, '[]') as damage_immunities, COALESCE(armor_class_override, 0) as armor_class_override, COALESCE(armor_class_bonus, 0) as armor_class_bonus, created_at, updated_at
 FROM characters
 WHERE user_id = ?
 ORDER BY updated_at DESC
@@ -1215,8 +3533,16 @@ type ListCharactersByUserRow struct {
 	SkillProficiencies       string    `json:"skillProficiencies"`
 	SavingThrowProficiencies string    `json:"savingThrowProficiencies"`
 	Features                 string    `json:"features"`
-	Equipment                string    `json:"equipment"`
 	AvatarUrl                string    `json:"avatarUrl"`
+	ProficiencyLevels        string    `json:"proficiencyLevels"`
+	HitDiceSpent             int64     `json:"hitDiceSpent"`
+	DeathSaveSuccesses       int64     `json:"deathSaveSuccesses"`
+	DeathSaveFailures        int64     `json:"deathSaveFailures"`
+	DamageResistances        string    `json:"damageResistances"`
+	DamageVulnerabilities    string    `json:"damageVulnerabilities"`
+	DamageImmunities         string    `json:"damageImmunities"`
+	ArmorClassOverride       bool      `json:"armorClassOverride"`
+	ArmorClassBonus          int64     `json:"armorClassBonus"`
 	CreatedAt                time.Time `json:"createdAt"`
 	UpdatedAt                time.Time `json:"updatedAt"`
 }
@@ -1256,8 +3582,16 @@ func (q *Queries) ListCharactersByUser(ctx context.Context, userID int64) ([]Lis
 			&i.SkillProficiencies,
 			&i.SavingThrowProficiencies,
 			&i.Features,
-			&i.Equipment,
 			&i.AvatarUrl,
+			&i.ProficiencyLevels,
+			&i.HitDiceSpent,
+			&i.DeathSaveSuccesses,
+			&i.DeathSaveFailures,
+			&i.DamageResistances,
+			&i.DamageVulnerabilities,
+			&i.DamageImmunities,
+			&i.ArmorClassOverride,
+			&i.ArmorClassBonus,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -1274,8 +3608,44 @@ func (q *Queries) ListCharactersByUser(ctx context.Context, userID int64) ([]Lis
 	return items, nil
 }
 
+const listMapWalls = `-- name: ListMapWalls :many
+SELECT id, map_id, type, z_index, data
+FROM layers
+WHERE map_id = ? AND type IN ('wall', 'door')
+ORDER BY id ASC
+`
+
+func (q *Queries) ListMapWalls(ctx context.Context, mapID int64) ([]Layer, error) {
+	rows, err := q.db.QueryContext(ctx, listMapWalls, mapID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Layer
+	for rows.Next() {
+		var i Layer
+		if err := rows.Scan(
+			&i.ID,
+			&i.MapID,
+			&i.Type,
+			&i.ZIndex,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMapsBySceneIDs = `-- name: ListMapsBySceneIDs :many
-SELECT id, scene_id, name, COALESCE(base_image_url, '') as base_image_url, grid_size_ft, CAST(width_px AS INTEGER) as width_px, CAST(height_px AS INTEGER) as height_px, lighting_mode, fog_state, created_at
+SELECT id, scene_id, name, COALESCE(base_image_url, '') as base_image_url, grid_size_ft, width_px, height_px, lighting_mode, fog_state, created_at
 FROM maps
 WHERE scene_id IN (/*SLICE:scene_ids*/?)
 ORDER BY id ASC
@@ -1287,8 +3657,8 @@ type ListMapsBySceneIDsRow struct {
 	Name         string    `json:"name"`
 	BaseImageUrl string    `json:"baseImageUrl"`
 	GridSizeFt   int64     `json:"gridSizeFt"`
-	WidthPx      int64     `json:"widthPx"`
-	HeightPx     int64     `json:"heightPx"`
+	WidthPx      *int64    `json:"widthPx"`
+	HeightPx     *int64    `json:"heightPx"`
 	LightingMode string    `json:"lightingMode"`
 	FogState     string    `json:"fogState"`
 	CreatedAt    time.Time `json:"createdAt"`
@@ -1462,28 +3832,30 @@ func (q *Queries) ListScenes(ctx context.Context, campaignID int64) ([]ListScene
 }
 
 const listTokensByMapIDs = `-- name: ListTokensByMapIDs :many
-SELECT id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at
+SELECT id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at, vision_ft, darkvision_ft
 FROM tokens
 WHERE map_id IN (/*SLICE:map_ids*/?)
 ORDER BY id ASC
 `
 
 type ListTokensByMapIDsRow struct {
-	ID          int64     `json:"id"`
-	MapID       int64     `json:"mapId"`
-	CharacterID *int64    `json:"characterId"`
-	Label       string    `json:"label"`
-	ImageUrl    string    `json:"imageUrl"`
-	SizeSquares int64     `json:"sizeSquares"`
-	PositionX   int64     `json:"positionX"`
-	PositionY   int64     `json:"positionY"`
-	FacingDeg   int64     `json:"facingDeg"`
-	Audience    string    `json:"audience"`
-	Layer       string    `json:"layer"`
-	Tags        string    `json:"tags"`
-	Notes       string    `json:"notes"`
-	CreatedBy   *int64    `json:"createdBy"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID           int64     `json:"id"`
+	MapID        int64     `json:"mapId"`
+	CharacterID  *int64    `json:"characterId"`
+	Label        string    `json:"label"`
+	ImageUrl     string    `json:"imageUrl"`
+	SizeSquares  int64     `json:"sizeSquares"`
+	PositionX    int64     `json:"positionX"`
+	PositionY    int64     `json:"positionY"`
+	FacingDeg    int64     `json:"facingDeg"`
+	Audience     string    `json:"audience"`
+	Layer        string    `json:"layer"`
+	Tags         string    `json:"tags"`
+	Notes        string    `json:"notes"`
+	CreatedBy    *int64    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	VisionFt     int64     `json:"visionFt"`
+	DarkvisionFt int64     `json:"darkvisionFt"`
 }
 
 func (q *Queries) ListTokensByMapIDs(ctx context.Context, mapIds []int64) ([]ListTokensByMapIDsRow, error) {
@@ -1521,6 +3893,8 @@ func (q *Queries) ListTokensByMapIDs(ctx context.Context, mapIds []int64) ([]Lis
 			&i.Notes,
 			&i.CreatedBy,
 			&i.CreatedAt,
+			&i.VisionFt,
+			&i.DarkvisionFt,
 		); err != nil {
 			return nil, err
 		}
@@ -1536,7 +3910,7 @@ func (q *Queries) ListTokensByMapIDs(ctx context.Context, mapIds []int64) ([]Lis
 }
 
 const listTokensByMapIDsForPlayer = `-- name: ListTokensByMapIDsForPlayer :many
-SELECT id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at
+SELECT id, map_id, character_id, label, COALESCE(image_url, '') as image_url, size_squares, position_x, position_y, facing_deg, audience, layer, tags, COALESCE(notes, '') as notes, created_by, created_at, vision_ft, darkvision_ft
 FROM tokens
 WHERE map_id IN (/*SLICE:map_ids*/?)
   AND layer != 'gm'
@@ -1544,21 +3918,23 @@ ORDER BY id ASC
 `
 
 type ListTokensByMapIDsForPlayerRow struct {
-	ID          int64     `json:"id"`
-	MapID       int64     `json:"mapId"`
-	CharacterID *int64    `json:"characterId"`
-	Label       string    `json:"label"`
-	ImageUrl    string    `json:"imageUrl"`
-	SizeSquares int64     `json:"sizeSquares"`
-	PositionX   int64     `json:"positionX"`
-	PositionY   int64     `json:"positionY"`
-	FacingDeg   int64     `json:"facingDeg"`
-	Audience    string    `json:"audience"`
-	Layer       string    `json:"layer"`
-	Tags        string    `json:"tags"`
-	Notes       string    `json:"notes"`
-	CreatedBy   *int64    `json:"createdBy"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID           int64     `json:"id"`
+	MapID        int64     `json:"mapId"`
+	CharacterID  *int64    `json:"characterId"`
+	Label        string    `json:"label"`
+	ImageUrl     string    `json:"imageUrl"`
+	SizeSquares  int64     `json:"sizeSquares"`
+	PositionX    int64     `json:"positionX"`
+	PositionY    int64     `json:"positionY"`
+	FacingDeg    int64     `json:"facingDeg"`
+	Audience     string    `json:"audience"`
+	Layer        string    `json:"layer"`
+	Tags         string    `json:"tags"`
+	Notes        string    `json:"notes"`
+	CreatedBy    *int64    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	VisionFt     int64     `json:"visionFt"`
+	DarkvisionFt int64     `json:"darkvisionFt"`
 }
 
 func (q *Queries) ListTokensByMapIDsForPlayer(ctx context.Context, mapIds []int64) ([]ListTokensByMapIDsForPlayerRow, error) {
@@ -1596,6 +3972,47 @@ func (q *Queries) ListTokensByMapIDsForPlayer(ctx context.Context, mapIds []int6
 			&i.Notes,
 			&i.CreatedBy,
 			&i.CreatedAt,
+			&i.VisionFt,
+			&i.DarkvisionFt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserHomebrewPacks = `-- name: ListUserHomebrewPacks :many
+SELECT id, user_id, campaign_id, name, version, content, created_at, updated_at
+FROM homebrew_packs
+WHERE user_id = ? AND campaign_id IS NULL
+ORDER BY name ASC
+`
+
+func (q *Queries) ListUserHomebrewPacks(ctx context.Context, userID int64) ([]HomebrewPack, error) {
+	rows, err := q.db.QueryContext(ctx, listUserHomebrewPacks, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HomebrewPack
+	for rows.Next() {
+		var i HomebrewPack
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CampaignID,
+			&i.Name,
+			&i.Version,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1627,6 +4044,33 @@ func (q *Queries) MarkInviteRedeemed(ctx context.Context, arg MarkInviteRedeemed
 	return err
 }
 
+const reduceCharacterExhaustion = `-- name: ReduceCharacterExhaustion :exec
+UPDATE character_conditions
+SET level = level - 1
+WHERE character_id = ? AND name = 'exhaustion'
+`
+
+func (q *Queries) ReduceCharacterExhaustion(ctx context.Context, characterID int64) error {
+	_, err := q.db.ExecContext(ctx, reduceCharacterExhaustion, characterID)
+	return err
+}
+
+const resetCharacterResources = `-- name: ResetCharacterResources :exec
+UPDATE character_resources
+SET used = 0
+WHERE character_id = ? AND reset = ?
+`
+
+type ResetCharacterResourcesParams struct {
+	CharacterID int64  `json:"characterId"`
+	Reset       string `json:"reset"`
+}
+
+func (q *Queries) ResetCharacterResources(ctx context.Context, arg ResetCharacterResourcesParams) error {
+	_, err := q.db.ExecContext(ctx, resetCharacterResources, arg.CharacterID, arg.Reset)
+	return err
+}
+
 const revokeMember = `-- name: RevokeMember :exec
 UPDATE campaign_members
 SET status = 'revoked'
@@ -1638,8 +4082,35 @@ type RevokeMemberParams struct {
 	UserID     int64 `json:"userId"`
 }
 
-func (q *Queries) RevokeMember(ctx context.Context, arg RevokeMemberParams) error {
-	_, err := q.db.ExecContext(ctx, revokeMember, arg.CampaignID, arg.UserID)
+func (q *Queries) RevokeMember(ctx context.Context, arg RevokeMemberParams) error {
+	_, err := q.db.ExecContext(ctx, revokeMember, arg.CampaignID, arg.UserID)
+	return err
+}
+
+const setActiveScene = `-- name: SetActiveScene :exec
+UPDATE scenes
+SET is_active = (id = ?)
+WHERE campaign_id = ?
+`
+
+type SetActiveSceneParams struct {
+	ID         int64 `json:"id"`
+	CampaignID int64 `json:"campaignId"`
+}
+
+func (q *Queries) SetActiveScene(ctx context.Context, arg SetActiveSceneParams) error {
+	_, err := q.db.ExecContext(ctx, setActiveScene, arg.ID, arg.CampaignID)
+	return err
+}
+
+const touchCharacter = `-- name: TouchCharacter :exec
+UPDATE characters
+SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+WHERE id = ?
+`
+
+func (q *Queries) TouchCharacter(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, touchCharacter, id)
 	return err
 }
 
@@ -1757,14 +4228,23 @@ UPDATE characters SET
     name = ?, race = ?, class = ?, level = ?, background = ?, alignment = ?, experience_points = ?,
     strength = ?, dexterity = ?, constitution = ?, intelligence = ?, wisdom = ?, charisma = ?,
     max_hp = ?, current_hp = ?, temp_hp = ?, armor_class = ?, speed = ?, hit_dice = ?,
-    skill_proficiencies = ?, saving_throw_proficiencies = ?, features = ?, equipment = ?,
-    updated_at = CURRENT_TIMESTAMP
+    skill_proficiencies = ?, saving_throw_proficiencies = ?, features = ?,
+    proficiency_levels = ?,
+    hit_dice_spent = ?,
+    death_save_successes = ?,
+    death_save_failures = ?,
+    damage_resistances = ?,
+    damage_vulnerabilities = ?,
+    damage_immunities = ?,
+    armor_class_override = ?,
+    armor_class_bonus = ?,
+    updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
 WHERE id = ? AND user_id = ?
 RETURNING id, user_id, name, race, class, level, background, alignment, experience_points,
           strength, dexterity, constitution, intelligence, wisdom, charisma,
           max_hp, current_hp, temp_hp, armor_class, speed, hit_dice,
-          skill_proficiencies, saving_throw_proficiencies, features, equipment,
-          avatar_url, created_at, updated_at
+          skill_proficiencies, saving_throw_proficiencies, features,
+          avatar_url, proficiency_levels, hit_dice_spent, death_save_successes, death_save_failures, damage_resistances, damage_vulnerabilities, damage_immunities, armor_class_override, armor_class_bonus, created_at, updated_at
 `
 
 type UpdateCharacterParams struct {
@@ -1790,7 +4270,15 @@ type UpdateCharacterParams struct {
 	SkillProficiencies       *string `json:"skillProficiencies"`
 	SavingThrowProficiencies *string `json:"savingThrowProficiencies"`
 	Features                 *string `json:"features"`
-	Equipment                *string `json:"equipment"`
+	ProficiencyLevels        *string `json:"proficiencyLevels"`
+	HitDiceSpent             *int64  `json:"hitDiceSpent"`
+	DeathSaveSuccesses       *int64  `json:"deathSaveSuccesses"`
+	DeathSaveFailures        *int64  `json:"deathSaveFailures"`
+	DamageResistances        *string `json:"damageResistances"`
+	DamageVulnerabilities    *string `json:"damageVulnerabilities"`
+	DamageImmunities         *string `json:"damageImmunities"`
+	ArmorClassOverride       *bool   `json:"armorClassOverride"`
+	ArmorClassBonus          *int64  `json:"armorClassBonus"`
 	ID                       int64   `json:"id"`
 	UserID                   int64   `json:"userId"`
 }
@@ -1819,7 +4307,15 @@ func (q *Queries) UpdateCharacter(ctx context.Context, arg UpdateCharacterParams
 		arg.SkillProficiencies,
 		arg.SavingThrowProficiencies,
 		arg.Features,
-		arg.Equipment,
+		arg.ProficiencyLevels,
+		arg.HitDiceSpent,
+		arg.DeathSaveSuccesses,
+		arg.DeathSaveFailures,
+		arg.DamageResistances,
+		arg.DamageVulnerabilities,
+		arg.DamageImmunities,
+		arg.ArmorClassOverride,
+		arg.ArmorClassBonus,
 		arg.ID,
 		arg.UserID,
 	)
@@ -1849,23 +4345,84 @@ func (q *Queries) UpdateCharacter(ctx context.Context, arg UpdateCharacterParams
 		&i.SkillProficiencies,
 		&i.SavingThrowProficiencies,
 		&i.Features,
-		&i.Equipment,
 		&i.AvatarUrl,
+		&i.ProficiencyLevels,
+		&i.HitDiceSpent,
+		&i.DeathSaveSuccesses,
+		&i.DeathSaveFailures,
+		&i.DamageResistances,
+		&i.DamageVulnerabilities,
+		&i.DamageImmunities,
+		&i.ArmorClassOverride,
+		&i.ArmorClassBonus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const updateCharacterAttack = `-- name: UpdateCharacterAttack :one
+UPDATE character_attacks
+SET name = ?, kind = ?, ability = ?, proficient = ?, damage_dice = ?, versatile_dice = ?, damage_type = ?, properties = ?, bonus = ?
+WHERE id = ? AND character_id = ?
+RETURNING id, character_id, name, kind, ability, proficient, damage_dice, versatile_dice, damage_type, properties, bonus, created_at
+`
+
+type UpdateCharacterAttackParams struct {
+	Name          string `json:"name"`
+	Kind          string `json:"kind"`
+	Ability       string `json:"ability"`
+	Proficient    bool   `json:"proficient"`
+	DamageDice    string `json:"damageDice"`
+	VersatileDice string `json:"versatileDice"`
+	DamageType    string `json:"damageType"`
+	Properties    string `json:"properties"`
+	Bonus         int64  `json:"bonus"`
+	ID            int64  `json:"id"`
+	CharacterID   int64  `json:"characterId"`
+}
+
+func (q *Queries) UpdateCharacterAttack(ctx context.Context, arg UpdateCharacterAttackParams) (CharacterAttack, error) {
+	row := q.db.QueryRowContext(ctx, updateCharacterAttack,
+		arg.Name,
+		arg.Kind,
+		arg.Ability,
+		arg.Proficient,
+		arg.DamageDice,
+		arg.VersatileDice,
+		arg.DamageType,
+		arg.Properties,
+		arg.Bonus,
+		arg.ID,
+		arg.CharacterID,
+	)
+	var i CharacterAttack
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Name,
+		&i.Kind,
+		&i.Ability,
+		&i.Proficient,
+		&i.DamageDice,
+		&i.VersatileDice,
+		&i.DamageType,
+		&i.Properties,
+		&i.Bonus,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const updateCharacterAvatar = `-- name: UpdateCharacterAvatar :one
 UPDATE characters
-SET avatar_url = ?, updated_at = CURRENT_TIMESTAMP
+SET avatar_url = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
 WHERE id = ? AND user_id = ?
 RETURNING id, user_id, name, race, class, level, background, alignment, experience_points,
           strength, dexterity, constitution, intelligence, wisdom, charisma,
           max_hp, current_hp, temp_hp, armor_class, speed, hit_dice,
-          skill_proficiencies, saving_throw_proficiencies, features, equipment,
-          avatar_url, created_at, updated_at
+          skill_proficiencies, saving_throw_proficiencies, features,
+          avatar_url, proficiency_levels, hit_dice_spent, death_save_successes, death_save_failures, damage_resistances, damage_vulnerabilities, damage_immunities, armor_class_override, armor_class_bonus, created_at, updated_at
 `
 
 type UpdateCharacterAvatarParams struct {
@@ -1902,14 +4459,286 @@ func (q *Queries) UpdateCharacterAvatar(ctx context.Context, arg UpdateCharacter
 		&i.SkillProficiencies,
 		&i.SavingThrowProficiencies,
 		&i.Features,
-		&i.Equipment,
 		&i.AvatarUrl,
+		&i.ProficiencyLevels,
+		&i.HitDiceSpent,
+		&i.DeathSaveSuccesses,
+		&i.DeathSaveFailures,
+		&i.DamageResistances,
+		&i.DamageVulnerabilities,
+		&i.DamageImmunities,
+		&i.ArmorClassOverride,
+		&i.ArmorClassBonus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateCharacterHitPoints = `-- name: UpdateCharacterHitPoints :exec
+UPDATE characters
+SET current_hp = ?, temp_hp = ?, hit_dice_spent = ?, death_save_successes = ?, death_save_failures = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+WHERE id = ? AND user_id = ?
+`
+
+type UpdateCharacterHitPointsParams struct {
+	CurrentHp          int64  `json:"currentHp"`
+	TempHp             *int64 `json:"tempHp"`
+	HitDiceSpent       *int64 `json:"hitDiceSpent"`
+	DeathSaveSuccesses *int64 `json:"deathSaveSuccesses"`
+	DeathSaveFailures  *int64 `json:"deathSaveFailures"`
+	ID                 int64  `json:"id"`
+	UserID             int64  `json:"userId"`
+}
+
+func (q *Queries) UpdateCharacterHitPoints(ctx context.Context, arg UpdateCharacterHitPointsParams) error {
+	_, err := q.db.ExecContext(ctx, updateCharacterHitPoints,
+		arg.CurrentHp,
+		arg.TempHp,
+		arg.HitDiceSpent,
+		arg.DeathSaveSuccesses,
+		arg.DeathSaveFailures,
+		arg.ID,
+		arg.UserID,
+	)
+	return err
+}
+
+const updateCharacterItem = `-- name: UpdateCharacterItem :one
+UPDATE character_items
+SET container_id = ?, name = ?, quantity = ?, weight = ?, value_cp = ?, equipped = ?, attuned = ?, is_container = ?, armor_type = ?, armor_class = ?, notes = ?, position = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND character_id = ?
+RETURNING id, character_id, container_id, name, quantity, weight, value_cp, equipped, attuned, is_container, armor_type, armor_class, notes, position, created_at, updated_at
+`
+
+type UpdateCharacterItemParams struct {
+	ContainerID *int64  `json:"containerId"`
+	Name        string  `json:"name"`
+	Quantity    int64   `json:"quantity"`
+	Weight      float64 `json:"weight"`
+	ValueCp     int64   `json:"valueCp"`
+	Equipped    bool    `json:"equipped"`
+	Attuned     bool    `json:"attuned"`
+	IsContainer bool    `json:"isContainer"`
+	ArmorType   string  `json:"armorType"`
+	ArmorClass  int64   `json:"armorClass"`
+	Notes       string  `json:"notes"`
+	Position    int64   `json:"position"`
+	ID          int64   `json:"id"`
+	CharacterID int64   `json:"characterId"`
+}
+
+func (q *Queries) UpdateCharacterItem(ctx context.Context, arg UpdateCharacterItemParams) (CharacterItem, error) {
+	row := q.db.QueryRowContext(ctx, updateCharacterItem,
+		arg.ContainerID,
+		arg.Name,
+		arg.Quantity,
+		arg.Weight,
+		arg.ValueCp,
+		arg.Equipped,
+		arg.Attuned,
+		arg.IsContainer,
+		arg.ArmorType,
+		arg.ArmorClass,
+		arg.Notes,
+		arg.Position,
+		arg.ID,
+		arg.CharacterID,
+	)
+	var i CharacterItem
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.ContainerID,
+		&i.Name,
+		&i.Quantity,
+		&i.Weight,
+		&i.ValueCp,
+		&i.Equipped,
+		&i.Attuned,
+		&i.IsContainer,
+		&i.ArmorType,
+		&i.ArmorClass,
+		&i.Notes,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateCharacterResource = `-- name: UpdateCharacterResource :one
+UPDATE character_resources
+SET name = ?, max_formula = ?, used = ?, reset = ?
+WHERE id = ? AND character_id = ?
+RETURNING id, character_id, name, max_formula, used, reset, created_at
+`
+
+type UpdateCharacterResourceParams struct {
+	Name        string `json:"name"`
+	MaxFormula  string `json:"maxFormula"`
+	Used        int64  `json:"used"`
+	Reset       string `json:"reset"`
+	ID          int64  `json:"id"`
+	CharacterID int64  `json:"characterId"`
+}
+
+func (q *Queries) UpdateCharacterResource(ctx context.Context, arg UpdateCharacterResourceParams) (CharacterResource, error) {
+	row := q.db.QueryRowContext(ctx, updateCharacterResource,
+		arg.Name,
+		arg.MaxFormula,
+		arg.Used,
+		arg.Reset,
+		arg.ID,
+		arg.CharacterID,
+	)
+	var i CharacterResource
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Name,
+		&i.MaxFormula,
+		&i.Used,
+		&i.Reset,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateCharacterSpell = `-- name: UpdateCharacterSpell :one
+UPDATE character_spells
+SET name = ?, level = ?, school = ?, prepared = ?, notes = ?
+WHERE id = ? AND character_id = ?
+RETURNING id, character_id, name, level, school, prepared, notes, created_at
+`
+
+type UpdateCharacterSpellParams struct {
+	Name        string `json:"name"`
+	Level       int64  `json:"level"`
+	School      string `json:"school"`
+	Prepared    bool   `json:"prepared"`
+	Notes       string `json:"notes"`
+	ID          int64  `json:"id"`
+	CharacterID int64  `json:"characterId"`
+}
+
+func (q *Queries) UpdateCharacterSpell(ctx context.Context, arg UpdateCharacterSpellParams) (CharacterSpell, error) {
+	row := q.db.QueryRowContext(ctx, updateCharacterSpell,
+		arg.Name,
+		arg.Level,
+		arg.School,
+		arg.Prepared,
+		arg.Notes,
+		arg.ID,
+		arg.CharacterID,
+	)
+	var i CharacterSpell
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Name,
+		&i.Level,
+		&i.School,
+		&i.Prepared,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateHomebrewPack = `-- name: UpdateHomebrewPack :one
+UPDATE homebrew_packs
+SET user_id = ?, version = ?, content = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, user_id, campaign_id, name, version, content, created_at, updated_at
+`
+
+type UpdateHomebrewPackParams struct {
+	UserID  int64  `json:"userId"`
+	Version string `json:"version"`
+	Content string `json:"content"`
+	ID      int64  `json:"id"`
+}
+
+func (q *Queries) UpdateHomebrewPack(ctx context.Context, arg UpdateHomebrewPackParams) (HomebrewPack, error) {
+	row := q.db.QueryRowContext(ctx, updateHomebrewPack,
+		arg.UserID,
+		arg.Version,
+		arg.Content,
+		arg.ID,
+	)
+	var i HomebrewPack
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Version,
+		&i.Content,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const updateLayer = `-- name: UpdateLayer :one
+UPDATE layers
+SET type = ?, data = ?
+WHERE id = ?
+RETURNING id, map_id, type, z_index, data
+`
+
+type UpdateLayerParams struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+	ID   int64  `json:"id"`
+}
+
+func (q *Queries) UpdateLayer(ctx context.Context, arg UpdateLayerParams) (Layer, error) {
+	row := q.db.QueryRowContext(ctx, updateLayer, arg.Type, arg.Data, arg.ID)
+	var i Layer
+	err := row.Scan(
+		&i.ID,
+		&i.MapID,
+		&i.Type,
+		&i.ZIndex,
+		&i.Data,
+	)
+	return i, err
+}
+
+const updateMapFogState = `-- name: UpdateMapFogState :exec
+UPDATE maps
+SET fog_state = ?
+WHERE id = ?
+`
+
+type UpdateMapFogStateParams struct {
+	FogState string `json:"fogState"`
+	ID       int64  `json:"id"`
+}
+
+func (q *Queries) UpdateMapFogState(ctx context.Context, arg UpdateMapFogStateParams) error {
+	_, err := q.db.ExecContext(ctx, updateMapFogState, arg.FogState, arg.ID)
+	return err
+}
+
+const updateMapLighting = `-- name: UpdateMapLighting :exec
+UPDATE maps
+SET lighting_mode = ?
+WHERE id = ?
+`
+
+type UpdateMapLightingParams struct {
+	LightingMode string `json:"lightingMode"`
+	ID           int64  `json:"id"`
+}
+
+func (q *Queries) UpdateMapLighting(ctx context.Context, arg UpdateMapLightingParams) error {
+	_, err := q.db.ExecContext(ctx, updateMapLighting, arg.LightingMode, arg.ID)
+	return err
+}
+
 const updateMemberRole = `-- name: UpdateMemberRole :one
 UPDATE campaign_members
 SET role = ?
@@ -1938,6 +4767,102 @@ func (q *Queries) UpdateMemberRole(ctx context.Context, arg UpdateMemberRolePara
 	return i, err
 }
 
+const updateScene = `-- name: UpdateScene :one
+UPDATE scenes
+SET name = ?, description = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND campaign_id = ?
+RETURNING id, campaign_id, name, COALESCE(description, '') as description, ordering, is_active, created_by, created_at, updated_at
+`
+
+type UpdateSceneParams struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	ID          int64   `json:"id"`
+	CampaignID  int64   `json:"campaignId"`
+}
+
+type UpdateSceneRow struct {
+	ID          int64     `json:"id"`
+	CampaignID  int64     `json:"campaignId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Ordering    int64     `json:"ordering"`
+	IsActive    bool      `json:"isActive"`
+	CreatedBy   *int64    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (q *Queries) UpdateScene(ctx context.Context, arg UpdateSceneParams) (UpdateSceneRow, error) {
+	row := q.db.QueryRowContext(ctx, updateScene,
+		arg.Name,
+		arg.Description,
+		arg.ID,
+		arg.CampaignID,
+	)
+	var i UpdateSceneRow
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Description,
+		&i.Ordering,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateSceneOrdering = `-- name: UpdateSceneOrdering :exec
+UPDATE scenes
+SET ordering = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND campaign_id = ?
+`
+
+type UpdateSceneOrderingParams struct {
+	Ordering   int64 `json:"ordering"`
+	ID         int64 `json:"id"`
+	CampaignID int64 `json:"campaignId"`
+}
+
+func (q *Queries) UpdateSceneOrdering(ctx context.Context, arg UpdateSceneOrderingParams) error {
+	_, err := q.db.ExecContext(ctx, updateSceneOrdering, arg.Ordering, arg.ID, arg.CampaignID)
+	return err
+}
+
+const updateToken = `-- name: UpdateToken :exec
+UPDATE tokens
+SET label = ?, image_url = ?, size_squares = ?, facing_deg = ?, audience = ?, tags = ?, notes = ?
+WHERE id = ?
+`
+
+type UpdateTokenParams struct {
+	Label       string  `json:"label"`
+	ImageUrl    *string `json:"imageUrl"`
+	SizeSquares int64   `json:"sizeSquares"`
+	FacingDeg   int64   `json:"facingDeg"`
+	Audience    string  `json:"audience"`
+	Tags        string  `json:"tags"`
+	Notes       *string `json:"notes"`
+	ID          int64   `json:"id"`
+}
+
+func (q *Queries) UpdateToken(ctx context.Context, arg UpdateTokenParams) error {
+	_, err := q.db.ExecContext(ctx, updateToken,
+		arg.Label,
+		arg.ImageUrl,
+		arg.SizeSquares,
+		arg.FacingDeg,
+		arg.Audience,
+		arg.Tags,
+		arg.Notes,
+		arg.ID,
+	)
+	return err
+}
+
 const updateTokenLayer = `-- name: UpdateTokenLayer :exec
 UPDATE tokens
 SET layer = ?
@@ -1971,6 +4896,113 @@ func (q *Queries) UpdateTokenPosition(ctx context.Context, arg UpdateTokenPositi
 	return err
 }
 
+const updateTokenVision = `-- name: UpdateTokenVision :exec
+UPDATE tokens
+SET vision_ft = ?, darkvision_ft = ?
+WHERE id = ?
+`
+
+type UpdateTokenVisionParams struct {
+	VisionFt     int64 `json:"visionFt"`
+	DarkvisionFt int64 `json:"darkvisionFt"`
+	ID           int64 `json:"id"`
+}
+
+func (q *Queries) UpdateTokenVision(ctx context.Context, arg UpdateTokenVisionParams) error {
+	_, err := q.db.ExecContext(ctx, updateTokenVision, arg.VisionFt, arg.DarkvisionFt, arg.ID)
+	return err
+}
+
+const upsertCharacterCoins = `-- name: UpsertCharacterCoins :exec
+INSERT INTO character_coins (character_id, cp, sp, ep, gp, pp)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (character_id) DO UPDATE SET cp = excluded.cp, sp = excluded.sp, ep = excluded.ep, gp = excluded.gp, pp = excluded.pp
+`
+
+type UpsertCharacterCoinsParams struct {
+	CharacterID int64 `json:"characterId"`
+	Cp          int64 `json:"cp"`
+	Sp          int64 `json:"sp"`
+	Ep          int64 `json:"ep"`
+	Gp          int64 `json:"gp"`
+	Pp          int64 `json:"pp"`
+}
+
+func (q *Queries) UpsertCharacterCoins(ctx context.Context, arg UpsertCharacterCoinsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCharacterCoins,
+		arg.CharacterID,
+		arg.Cp,
+		arg.Sp,
+		arg.Ep,
+		arg.Gp,
+		arg.Pp,
+	)
+	return err
+}
+
+const upsertCharacterCondition = `-- name: UpsertCharacterCondition :one
+INSERT INTO character_conditions (character_id, name, level, source, duration, duration_unit)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (character_id, name) DO UPDATE
+SET level = excluded.level, source = excluded.source, duration = excluded.duration, duration_unit = excluded.duration_unit, created_at = CURRENT_TIMESTAMP
+RETURNING id, character_id, name, level, source, duration, duration_unit, created_at
+`
+
+type UpsertCharacterConditionParams struct {
+	CharacterID  int64  `json:"characterId"`
+	Name         string `json:"name"`
+	Level        int64  `json:"level"`
+	Source       string `json:"source"`
+	Duration     *int64 `json:"duration"`
+	DurationUnit string `json:"durationUnit"`
+}
+
+func (q *Queries) UpsertCharacterCondition(ctx context.Context, arg UpsertCharacterConditionParams) (CharacterCondition, error) {
+	row := q.db.QueryRowContext(ctx, upsertCharacterCondition,
+		arg.CharacterID,
+		arg.Name,
+		arg.Level,
+		arg.Source,
+		arg.Duration,
+		arg.DurationUnit,
+	)
+	var i CharacterCondition
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.Name,
+		&i.Level,
+		&i.Source,
+		&i.Duration,
+		&i.DurationUnit,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertCharacterSpellSlot = `-- name: UpsertCharacterSpellSlot :exec
+INSERT INTO character_spell_slots (character_id, kind, slot_level, expended)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (character_id, kind, slot_level) DO UPDATE SET expended = excluded.expended
+`
+
+type UpsertCharacterSpellSlotParams struct {
+	CharacterID int64  `json:"characterId"`
+	Kind        string `json:"kind"`
+	SlotLevel   int64  `json:"slotLevel"`
+	Expended    int64  `json:"expended"`
+}
+
+func (q *Queries) UpsertCharacterSpellSlot(ctx context.Context, arg UpsertCharacterSpellSlotParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCharacterSpellSlot,
+		arg.CharacterID,
+		arg.Kind,
+		arg.SlotLevel,
+		arg.Expended,
+	)
+	return err
+}
+
 const upsertMembershipOnRedeem = `-- name: UpsertMembershipOnRedeem :exec
 UPDATE campaign_members
 SET role = ?, status = 'accepted'
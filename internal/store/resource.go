@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// When a resource's uses come back. A long rest also covers resources that reset at dawn.
+const (
+	ResetShortRest = "short"
+	ResetLongRest  = "long"
+	ResetDawn      = "dawn"
+)
+
+// ClassResource is a limited-use feature with its maximum worked out for the character.
+type ClassResource struct {
+	CharacterResource
+	Max       int `json:"max"`
+	Available int `json:"available"`
+}
+
+// ListCharacterResources returns the character's resources in the order they were added.
+func (s *Store) ListCharacterResources(c *CharacterWithStats) ([]ClassResource, error) {
+	rows, err := s.q.ListCharacterResources(context.Background(), c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+	c.Resources = newClassResources(rows)
+	c.applyResources()
+	return c.Resources, nil
+}
+
+// AddCharacterResource adds a resource to the character.
+func (s *Store) AddCharacterResource(c *CharacterWithStats, res CharacterResource) (*ClassResource, error) {
+	if err := c.validateResource(&res); err != nil {
+		return nil, err
+	}
+
+	inserted, err := s.q.InsertCharacterResource(context.Background(), InsertCharacterResourceParams{
+		CharacterID: c.ID,
+		Name:        res.Name,
+		MaxFormula:  res.MaxFormula,
+		Used:        res.Used,
+		Reset:       res.Reset,
+	})
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return nil, ErrResourceExists
+		}
+		return nil, fmt.Errorf("failed to add resource: %w", err)
+	}
+	if err := markCharacterChanged(context.Background(), s.q, c.ID); err != nil {
+		return nil, err
+	}
+	return c.classResource(inserted), nil
+}
+
+// UpdateCharacterResource replaces a resource's name, maximum, uses spent and reset rule.
+func (s *Store) UpdateCharacterResource(c *CharacterWithStats, res CharacterResource) (*ClassResource, error) {
+	if err := c.validateResource(&res); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.q.UpdateCharacterResource(context.Background(), UpdateCharacterResourceParams{
+		Name:        res.Name,
+		MaxFormula:  res.MaxFormula,
+		Used:        res.Used,
+		Reset:       res.Reset,
+		ID:          res.ID,
+		CharacterID: c.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrResourceNotFound
+		}
+		if isUniqueConstraintError(err) {
+			return nil, ErrResourceExists
+		}
+		return nil, fmt.Errorf("failed to update resource: %w", err)
+	}
+	if err := markCharacterChanged(context.Background(), s.q, c.ID); err != nil {
+		return nil, err
+	}
+	return c.classResource(updated), nil
+}
+
+// DeleteCharacterResource removes a resource from the character.
+func (s *Store) DeleteCharacterResource(characterID, resourceID int64) error {
+	rows, err := s.q.DeleteCharacterResource(context.Background(), DeleteCharacterResourceParams{ID: resourceID, CharacterID: characterID})
+	if err != nil {
+		return fmt.Errorf("failed to delete resource: %w", err)
+	}
+	if rows == 0 {
+		return ErrResourceNotFound
+	}
+	return markCharacterChanged(context.Background(), s.q, characterID)
+}
+
+// validateResource trims the name and formula, checks the formula works out for the
+// character and that no more uses are spent than it allows.
+func (c *CharacterWithStats) validateResource(res *CharacterResource) error {
+	res.Name = strings.TrimSpace(res.Name)
+	res.MaxFormula = strings.ToLower(strings.Join(strings.Fields(res.MaxFormula), ""))
+	res.Reset = strings.ToLower(strings.TrimSpace(res.Reset))
+	if res.Name == "" || (res.Reset != ResetShortRest && res.Reset != ResetLongRest && res.Reset != ResetDawn) {
+		return ErrInvalidResource
+	}
+	total, err := c.resourceMax(res.MaxFormula)
+	if err != nil || res.Used < 0 || res.Used > int64(total) {
+		return ErrInvalidResource
+	}
+	return nil
+}
+
+// resourceMax works out a resource formula for the character: numbers, "level" (the
+// character level), "prof" (the proficiency bonus), an ability for its modifier or a
+// class for the character's level in it, each optionally multiplied or divided by a
+// number and added or subtracted, e.g. "prof", "cha", "level/2+1" or "monk". Division
+// rounds down, and the result is at least 1.
+func (c *CharacterWithStats) resourceMax(formula string) (int, error) {
+	if formula == "" {
+		return 0, ErrInvalidResource
+	}
+
+	total := 0
+	for formula != "" {
+		sign := 1
+		switch formula[0] {
+		case '-':
+			sign = -1
+			formula = formula[1:]
+		case '+':
+			formula = formula[1:]
+		}
+		end := strings.IndexAny(formula, "+-")
+		if end < 0 {
+			end = len(formula)
+		}
+		value, err := c.resourceTerm(formula[:end])
+		if err != nil {
+			return 0, err
+		}
+		total += sign * value
+		formula = formula[end:]
+	}
+	return max(1, total), nil
+}
+
+// resourceTerm evaluates one term of a resource formula, such as "level/2".
+func (c *CharacterWithStats) resourceTerm(term string) (int, error) {
+	base, factor, op := term, 1, byte(0)
+	if i := strings.IndexAny(term, "*/"); i >= 0 {
+		n, err := strconv.Atoi(term[i+1:])
+		if err != nil || n <= 0 {
+			return 0, ErrInvalidResource
+		}
+		base, factor, op = term[:i], n, term[i]
+	}
+
+	value := 0
+	switch n, err := strconv.Atoi(base); {
+	case err == nil && n >= 0:
+		value = n
+	case base == "level":
+		value = c.totalLevel()
+	case base == "prof":
+		value = c.ProficiencyBonus
+	default:
+		if ability, ok := lookupAbility(base); ok {
+			value = c.AbilityModifier(ability)
+			break
+		}
+		// Classes are matched without spaces, as the formula has none.
+		known := false
+		for class := range ClassHitDice {
+			known = known || strings.EqualFold(class, base)
+		}
+		for _, cl := range c.classLevels() {
+			if strings.EqualFold(strings.ReplaceAll(cl.Class, " ", ""), base) {
+				known = true
+				value = int(cl.Level)
+			}
+		}
+		if !known {
+			return 0, ErrInvalidResource
+		}
+	}
+
+	if op == '/' {
+		return value / factor, nil
+	}
+	return value * factor, nil
+}
+
+// applyResources works out each resource's maximum and the uses still available.
+func (c *CharacterWithStats) applyResources() {
+	if c.Resources == nil {
+		c.Resources = []ClassResource{}
+	}
+	for i := range c.Resources {
+		c.Resources[i] = *c.classResource(c.Resources[i].CharacterResource)
+	}
+}
+
+func (c *CharacterWithStats) classResource(res CharacterResource) *ClassResource {
+	total, err := c.resourceMax(res.MaxFormula)
+	if err != nil {
+		total = 0
+	}
+	used := min(int(res.Used), total)
+	return &ClassResource{CharacterResource: res, Max: total, Available: total - used}
+}
+
+func newClassResources(rows []CharacterResource) []ClassResource {
+	resources := make([]ClassResource, 0, len(rows))
+	for _, r := range rows {
+		resources = append(resources, ClassResource{CharacterResource: r})
+	}
+	return resources
+}
+
+// resourceResets lists the reset rules a rest of the given type recovers.
+func resourceResets(restType string) []string {
+	if restType == RestLong {
+		return []string{ResetShortRest, ResetLongRest, ResetDawn}
+	}
+	return []string{ResetShortRest}
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+func TestResourceFormulas(t *testing.T) {
+	c := newTestCharacter()
+	c.Level = 5
+	c.Charisma = 16
+	c.ComputeModifiers()
+
+	cases := map[string]int{
+		"2":           2,
+		"prof":        3,
+		"cha":         3,
+		"level/2+1":   3,
+		"rogue*2":     10,
+		"str":         1, // -1 is raised to the minimum of 1
+		"level-cha-1": 1,
+	}
+	for formula, want := range cases {
+		if got, err := c.resourceMax(formula); err != nil || got != want {
+			t.Errorf("resourceMax(%q) = %d, %v; want %d", formula, got, err, want)
+		}
+	}
+	for _, formula := range []string{"", "luck", "level/0", "level+", "2*x"} {
+		if _, err := c.resourceMax(formula); err != ErrInvalidResource {
+			t.Errorf("resourceMax(%q): expected ErrInvalidResource, got %v", formula, err)
+		}
+	}
+}
+
+func TestResourcesResetOnRest(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("raging", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Class = "Barbarian"
+	c.Level = 3
+	c.MaxHp = 30
+	c.CurrentHp = 30
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	bad := []CharacterResource{
+		{Name: "", MaxFormula: "3", Reset: ResetLongRest},
+		{Name: "Rage", MaxFormula: "3", Reset: "weekly"},
+		{Name: "Rage", MaxFormula: "3", Used: 4, Reset: ResetLongRest},
+	}
+	for _, res := range bad {
+		if _, err := s.AddCharacterResource(c, res); err != ErrInvalidResource {
+			t.Fatalf("%+v: expected ErrInvalidResource, got %v", res, err)
+		}
+	}
+
+	rage, err := s.AddCharacterResource(c, CharacterResource{Name: "Rage", MaxFormula: "3", Used: 2, Reset: ResetLongRest})
+	if err != nil {
+		t.Fatalf("add rage: %v", err)
+	}
+	if rage.Max != 3 || rage.Available != 1 {
+		t.Fatalf("rage = %+v", rage)
+	}
+	if _, err := s.AddCharacterResource(c, CharacterResource{Name: "Rage", MaxFormula: "3", Reset: ResetLongRest}); err != ErrResourceExists {
+		t.Fatalf("expected ErrResourceExists, got %v", err)
+	}
+	surge, err := s.AddCharacterResource(c, CharacterResource{Name: "Second Wind", MaxFormula: " PROF ", Reset: "Short"})
+	if err != nil {
+		t.Fatalf("add second wind: %v", err)
+	}
+	surge.Used = 1
+	if _, err := s.UpdateCharacterResource(c, surge.CharacterResource); err != nil {
+		t.Fatalf("spend second wind: %v", err)
+	}
+	surge.ID = 999
+	if _, err := s.UpdateCharacterResource(c, surge.CharacterResource); err != ErrResourceNotFound {
+		t.Fatalf("expected ErrResourceNotFound, got %v", err)
+	}
+
+	got, _ := s.GetCharacter(c.ID, user.ID)
+	if len(got.Resources) != 2 || got.Resources[1].MaxFormula != "prof" || got.Resources[1].Available != 1 {
+		t.Fatalf("resources = %+v", got.Resources)
+	}
+
+	// A short rest only brings back Second Wind; a long rest brings back Rage.
+	rest, err := s.Rest(got, RestShort, 0, dice.NewSeededRNG(1))
+	if err != nil {
+		t.Fatalf("short rest: %v", err)
+	}
+	if len(rest.ResourcesRecovered) != 1 || rest.ResourcesRecovered[0] != "Second Wind" || rest.Character.Resources[0].Available != 1 {
+		t.Fatalf("after short rest = %v, %+v", rest.ResourcesRecovered, rest.Character.Resources)
+	}
+	rest, err = s.Rest(rest.Character, RestLong, 0, dice.NewSeededRNG(1))
+	if err != nil {
+		t.Fatalf("long rest: %v", err)
+	}
+	if len(rest.ResourcesRecovered) != 1 || rest.Character.Resources[0].Available != 3 {
+		t.Fatalf("after long rest = %v, %+v", rest.ResourcesRecovered, rest.Character.Resources)
+	}
+
+	if err := s.DeleteCharacterResource(c.ID, rage.ID); err != nil {
+		t.Fatalf("delete resource: %v", err)
+	}
+	if err := s.DeleteCharacterResource(c.ID, rage.ID); err != ErrResourceNotFound {
+		t.Fatalf("expected ErrResourceNotFound, got %v", err)
+	}
+}
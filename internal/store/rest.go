@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+// Rest types.
+const (
+	RestShort = "short"
+	RestLong  = "long"
+)
+
+// RestResult is the character after resting and the recorded rest.
+type RestResult struct {
+	Character *CharacterWithStats `json:"character"`
+	Rest      CharacterRest       `json:"rest"`
+	Roll      *dice.Result        `json:"roll,omitempty"`
+	// ResourcesRecovered names the resources whose spent uses the rest restored.
+	ResourcesRecovered []string `json:"resourcesRecovered"`
+}
+
+// Rest applies a short or long rest. A short rest spends hitDice hit dice, largest
+// first, each healing a roll of the die plus the Constitution modifier, and recovers
+// pact magic slots. A long rest restores all hit points, clears temporary hit points,
+// recovers half the character's hit dice (at least one), resets every spell slot and
+// removes one level of exhaustion. Resources that reset on a short rest recover on
+// either; those that reset on a long rest or at dawn recover on a long rest. A character
+// must have at least 1 hit point to benefit from a long rest.
+func (s *Store) Rest(c *CharacterWithStats, restType string, hitDice int, rng dice.RNG) (*RestResult, error) {
+	if c.Status == StatusDead {
+		return nil, ErrCharacterDead
+	}
+	rest := InsertCharacterRestParams{
+		CharacterID: c.ID,
+		RestType:    restType,
+		HpBefore:    c.CurrentHp,
+	}
+	var roll *dice.Result
+	resetKinds := []string{SlotKindPact}
+
+	switch restType {
+	case RestShort:
+		if hitDice < 0 || hitDice > c.HitDiceRemaining {
+			return nil, ErrNotEnoughHitDice
+		}
+		if hitDice > 0 {
+			// Hit dice are spent largest first from the pool of every class's dice.
+			pool := c.hitDicePool(c.HitDie())
+			spent := min(int(c.HitDiceSpent), len(pool)-hitDice)
+			expr := hitDiceExpression(pool[spent : spent+hitDice])
+			if bonus := hitDice * c.ConstitutionModifier; bonus != 0 {
+				expr += fmt.Sprintf("%+d", bonus)
+			}
+			var err error
+			if roll, err = dice.Roll(expr, rng); err != nil {
+				return nil, fmt.Errorf("failed to roll hit dice: %w", err)
+			}
+			c.CurrentHp = min(c.MaxHp, c.CurrentHp+int64(max(0, roll.Total)))
+			c.HitDiceSpent += int64(hitDice)
+			rest.HitDiceSpent = int64(hitDice)
+		}
+	case RestLong:
+		if c.CurrentHp <= 0 {
+			return nil, ErrLongRestAtZeroHp
+		}
+		recovered := min(c.HitDiceSpent, int64(max(1, c.totalLevel()/2)))
+		c.HitDiceSpent -= recovered
+		rest.HitDiceRecovered = recovered
+		c.CurrentHp = c.MaxHp
+		c.TempHp = 0
+		resetKinds = append(resetKinds, SlotKindSpell)
+	default:
+		return nil, ErrInvalidRestType
+	}
+	rest.HpAfter = c.CurrentHp
+	if c.CurrentHp > 0 {
+		c.DeathSaveSuccesses = 0
+		c.DeathSaveFailures = 0
+	}
+	if roll != nil {
+		data, err := json.Marshal(roll)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode roll: %w", err)
+		}
+		rest.Roll = string(data)
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	if err := qtx.UpdateCharacterHitPoints(ctx, UpdateCharacterHitPointsParams{
+		CurrentHp:          c.CurrentHp,
+		TempHp:             &c.TempHp,
+		HitDiceSpent:       &c.HitDiceSpent,
+		DeathSaveSuccesses: &c.DeathSaveSuccesses,
+		DeathSaveFailures:  &c.DeathSaveFailures,
+		ID:                 c.ID,
+		UserID:             c.UserID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update hit points: %w", err)
+	}
+	for _, kind := range resetKinds {
+		if err := qtx.ClearCharacterSpellSlots(ctx, ClearCharacterSpellSlotsParams{CharacterID: c.ID, Kind: kind}); err != nil {
+			return nil, fmt.Errorf("failed to reset spell slots: %w", err)
+		}
+	}
+	recovered := []string{}
+	for _, reset := range resourceResets(restType) {
+		if err := qtx.ResetCharacterResources(ctx, ResetCharacterResourcesParams{CharacterID: c.ID, Reset: reset}); err != nil {
+			return nil, fmt.Errorf("failed to reset resources: %w", err)
+		}
+		for _, res := range c.Resources {
+			if res.Reset == reset && res.Used > 0 {
+				recovered = append(recovered, res.Name)
+			}
+		}
+	}
+	if restType == RestLong {
+		if err := qtx.ReduceCharacterExhaustion(ctx, c.ID); err != nil {
+			return nil, fmt.Errorf("failed to reduce exhaustion: %w", err)
+		}
+		if err := qtx.DeleteRecoveredExhaustion(ctx, c.ID); err != nil {
+			return nil, fmt.Errorf("failed to reduce exhaustion: %w", err)
+		}
+	}
+	recorded, err := qtx.InsertCharacterRest(ctx, rest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record rest: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rest: %w", err)
+	}
+
+	updated, err := s.GetCharacter(c.ID, c.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &RestResult{Character: updated, Rest: recorded, Roll: roll, ResourcesRecovered: recovered}, nil
+}
+
+// ListCharacterRests returns the character's most recent rests.
+func (s *Store) ListCharacterRests(characterID int64, limit int) ([]CharacterRest, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	rests, err := s.q.ListCharacterRests(context.Background(), ListCharacterRestsParams{CharacterID: characterID, Limit: int64(limit)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rests: %w", err)
+	}
+	if rests == nil {
+		rests = []CharacterRest{}
+	}
+	return rests, nil
+}
+
+// ListCampaignRests returns recent rests by characters in the campaign, for its members.
+func (s *Store) ListCampaignRests(campaignID, userID int64, limit int) ([]ListCampaignRestsRow, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	_, status, err := s.getMembership(campaignID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "accepted" {
+		return nil, ErrNotPermitted
+	}
+
+	rests, err := s.q.ListCampaignRests(context.Background(), ListCampaignRestsParams{CampaignID: campaignID, Limit: int64(limit)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rests: %w", err)
+	}
+	if rests == nil {
+		rests = []ListCampaignRestsRow{}
+	}
+	return rests, nil
+}
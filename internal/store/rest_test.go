@@ -0,0 +1,106 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+)
+
+func TestShortAndLongRest(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("sleeper", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Class = "Wizard"
+	c.Level = 4
+	c.Constitution = 14
+	c.MaxHp = 26
+	c.CurrentHp = 5
+	c.TempHp = 4
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	if _, err := s.SetSpellSlotsExpended(c, SlotKindSpell, 1, 3); err != nil {
+		t.Fatalf("spend slots: %v", err)
+	}
+
+	if _, err := s.Rest(c, "nap", 0, dice.NewSeededRNG(1)); err != ErrInvalidRestType {
+		t.Fatalf("expected ErrInvalidRestType, got %v", err)
+	}
+	if _, err := s.Rest(c, RestShort, 5, dice.NewSeededRNG(1)); err != ErrNotEnoughHitDice {
+		t.Fatalf("expected ErrNotEnoughHitDice, got %v", err)
+	}
+
+	short, err := s.Rest(c, RestShort, 3, dice.NewSeededRNG(1))
+	if err != nil {
+		t.Fatalf("short rest: %v", err)
+	}
+	healed := int64(short.Roll.Total)
+	if short.Roll.Expression != "3d6+6" || short.Character.CurrentHp != min(26, 5+healed) {
+		t.Fatalf("short rest rolled %s = %d, hp %d", short.Roll.Expression, healed, short.Character.CurrentHp)
+	}
+	if short.Character.HitDiceRemaining != 1 || short.Character.TempHp != 4 {
+		t.Fatalf("after short rest: %d hit dice, %d temp hp", short.Character.HitDiceRemaining, short.Character.TempHp)
+	}
+
+	long, err := s.Rest(short.Character, RestLong, 0, dice.NewSeededRNG(1))
+	if err != nil {
+		t.Fatalf("long rest: %v", err)
+	}
+	got := long.Character
+	if got.CurrentHp != 26 || got.TempHp != 0 || got.HitDiceRemaining != 3 || long.Rest.HitDiceRecovered != 2 {
+		t.Fatalf("after long rest: hp %d temp %d hit dice %d", got.CurrentHp, got.TempHp, got.HitDiceRemaining)
+	}
+	book, err := s.GetSpellbook(got)
+	if err != nil {
+		t.Fatalf("spellbook: %v", err)
+	}
+	if book.Slots[0].Expended != 0 {
+		t.Fatalf("long rest should reset spell slots: %+v", book.Slots[0])
+	}
+
+	campaign, err := s.CreateCampaign(user.ID, "Table", "", "private", "")
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	if _, err := s.AddCharacterToCampaign(campaign.ID, c.ID, user.ID); err != nil {
+		t.Fatalf("add character: %v", err)
+	}
+	rests, err := s.ListCampaignRests(campaign.ID, user.ID, 0)
+	if err != nil {
+		t.Fatalf("campaign rests: %v", err)
+	}
+	if len(rests) != 2 || rests[0].RestType != RestLong || rests[0].CharacterName != "Vex" {
+		t.Fatalf("campaign rests = %+v", rests)
+	}
+	stranger, _ := s.CreateUser("stranger", "hash")
+	if _, err := s.ListCampaignRests(campaign.ID, stranger.ID, 0); err != ErrNotCampaignMember {
+		t.Fatalf("expected ErrNotCampaignMember, got %v", err)
+	}
+}
+
+func TestLongRestNeedsHitPoints(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("fallen", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.CurrentHp = 0
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	if _, err := s.Rest(c, RestLong, 0, dice.NewSeededRNG(1)); err != ErrLongRestAtZeroHp {
+		t.Fatalf("expected ErrLongRestAtZeroHp, got %v", err)
+	}
+	got, err := s.GetCharacter(c.ID, user.ID)
+	if err != nil {
+		t.Fatalf("get character: %v", err)
+	}
+	if got.CurrentHp != 0 {
+		t.Fatalf("a rejected long rest should not heal, hp %d", got.CurrentHp)
+	}
+}
@@ -0,0 +1,290 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Reasons recorded with a revision, describing the write that replaced it.
+const (
+	RevisionReasonUpdate  = "update"
+	RevisionReasonRestore = "restore"
+	RevisionReasonLevelUp = "level-up"
+)
+
+// revisionIgnoredFields are bookkeeping fields left out of diffs and restores. Items and
+// coins are restored but not diffed; equipment lists the item names.
+var revisionIgnoredFields = map[string]bool{
+	"id":        true,
+	"userId":    true,
+	"avatarUrl": true,
+	"createdAt": true,
+	"updatedAt": true,
+	"items":     true,
+	"coins":     true,
+}
+
+// CharacterSnapshot is the stored content of a revision: the sheet as it was before an
+// update, including the items carried, the coin purse and the levels in each class.
+// Revisions saved before items and coins were recorded only have the item names.
+type CharacterSnapshot struct {
+	CharacterModel
+	Equipment []string        `json:"equipment"`
+	Items     []CharacterItem `json:"items,omitempty"`
+	Coins     *CharacterCoin  `json:"coins,omitempty"`
+	Classes   []ClassLevel    `json:"classes,omitempty"`
+}
+
+// FieldChange is one field that differs between two versions of a character.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// RevisionDiff lists the field changes from one revision to another, or to the current
+// sheet when To is nil.
+type RevisionDiff struct {
+	From    int64         `json:"from"`
+	To      *int64        `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// ListCharacterRevisions returns the character's revisions, newest first, without snapshots.
+func (s *Store) ListCharacterRevisions(characterID int64) ([]ListCharacterRevisionsRow, error) {
+	revisions, err := s.q.ListCharacterRevisions(context.Background(), characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	if revisions == nil {
+		revisions = []ListCharacterRevisionsRow{}
+	}
+	return revisions, nil
+}
+
+// DiffCharacterRevision compares a revision with another revision, or with the current
+// sheet when to is nil.
+func (s *Store) DiffCharacterRevision(c *CharacterWithStats, from int64, to *int64) (*RevisionDiff, error) {
+	ctx := context.Background()
+
+	before, err := s.characterSnapshot(ctx, c.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	after := &CharacterSnapshot{CharacterModel: c.CharacterModel, Equipment: c.Equipment, Classes: c.classLevelList()}
+	if to != nil {
+		if after, err = s.characterSnapshot(ctx, c.ID, *to); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := diffSnapshots(before, after)
+	if err != nil {
+		return nil, err
+	}
+	return &RevisionDiff{From: from, To: to, Changes: changes}, nil
+}
+
+// RestoreCharacterRevision writes a revision back over the character, with its items and
+// coins. The sheet being replaced is itself saved as a new revision, so a restore can be
+// undone. Revisions that only recorded item names restore the items by name and leave
+// the coins as they are.
+func (s *Store) RestoreCharacterRevision(c *CharacterWithStats, revision int64) error {
+	snap, err := s.characterSnapshot(context.Background(), c.ID, revision)
+	if err != nil {
+		return err
+	}
+
+	restored := &CharacterWithStats{CharacterModel: snap.CharacterModel, Equipment: snap.Equipment}
+	restored.ID = c.ID
+	restored.UserID = c.UserID
+	if restored.Equipment == nil {
+		restored.Equipment = []string{}
+	}
+	for _, cl := range snap.Classes {
+		restored.Classes = append(restored.Classes, CharacterClass{Class: cl.Class, Level: cl.Level, Subclass: cl.Subclass})
+	}
+	var restoreInventory func(ctx context.Context, q *Queries) error
+	if snap.Items != nil {
+		restored.Equipment = nil
+		restoreInventory = func(ctx context.Context, q *Queries) error {
+			return replaceInventory(ctx, q, c.ID, snap.Items, snap.Coins)
+		}
+	}
+	if err := s.updateCharacter(restored, RevisionReasonRestore, nil, restoreInventory); err != nil {
+		return err
+	}
+	*c = *restored
+	return nil
+}
+
+func (s *Store) characterSnapshot(ctx context.Context, characterID, revision int64) (*CharacterSnapshot, error) {
+	rev, err := s.q.GetCharacterRevision(ctx, GetCharacterRevisionParams{CharacterID: characterID, Revision: revision})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	var snap CharacterSnapshot
+	if err := json.Unmarshal([]byte(rev.Snapshot), &snap); err != nil {
+		return nil, fmt.Errorf("failed to decode revision: %w", err)
+	}
+	return &snap, nil
+}
+
+// saveRevision snapshots the character as currently stored, before it is overwritten,
+// and returns the stored row.
+func saveRevision(ctx context.Context, q *Queries, id, userID int64, reason string) (CharacterModel, error) {
+	current, err := q.GetCharacterByIDAndUser(ctx, GetCharacterByIDAndUserParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return current, fmt.Errorf("character not found")
+		}
+		return current, fmt.Errorf("failed to get character: %w", err)
+	}
+	items, err := q.ListCharacterItems(ctx, id)
+	if err != nil {
+		return current, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	classes, err := q.ListCharacterClasses(ctx, id)
+	if err != nil {
+		return current, fmt.Errorf("failed to list classes: %w", err)
+	}
+
+	coins, err := q.GetCharacterCoins(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return current, fmt.Errorf("failed to get coins: %w", err)
+	}
+	coins.CharacterID = id
+
+	snap := CharacterSnapshot{
+		CharacterModel: current,
+		Equipment:      make([]string, 0, len(items)),
+		Items:          make([]CharacterItem, 0, len(items)),
+		Coins:          &coins,
+	}
+	for _, item := range items {
+		snap.Equipment = append(snap.Equipment, item.Name)
+		snap.Items = append(snap.Items, item)
+	}
+	for _, cl := range classes {
+		snap.Classes = append(snap.Classes, ClassLevel{Class: cl.Class, Level: cl.Level, Subclass: cl.Subclass})
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return current, fmt.Errorf("failed to encode revision: %w", err)
+	}
+
+	if _, err := q.InsertCharacterRevision(ctx, InsertCharacterRevisionParams{
+		CharacterID:   id,
+		Reason:        reason,
+		Snapshot:      string(data),
+		CharacterID_2: id,
+	}); err != nil {
+		return current, fmt.Errorf("failed to save revision: %w", err)
+	}
+	return current, nil
+}
+
+// replaceInventory swaps the character's items and coins for those of a revision.
+func replaceInventory(ctx context.Context, q *Queries, characterID int64, items []CharacterItem, coins *CharacterCoin) error {
+	existing, err := q.ListCharacterItems(ctx, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to list items: %w", err)
+	}
+	for _, item := range existing {
+		if _, err := q.DeleteCharacterItem(ctx, DeleteCharacterItemParams{ID: item.ID, CharacterID: characterID}); err != nil {
+			return fmt.Errorf("failed to remove item: %w", err)
+		}
+	}
+	if err := importItems(ctx, q, characterID, items); err != nil {
+		return err
+	}
+	if coins == nil {
+		return nil
+	}
+	if err := q.UpsertCharacterCoins(ctx, UpsertCharacterCoinsParams{
+		CharacterID: characterID, Cp: coins.Cp, Sp: coins.Sp, Ep: coins.Ep, Gp: coins.Gp, Pp: coins.Pp,
+	}); err != nil {
+		return fmt.Errorf("failed to restore coins: %w", err)
+	}
+	return nil
+}
+
+// diffSnapshots compares two snapshots field by field using their JSON names.
+func diffSnapshots(before, after *CharacterSnapshot) ([]FieldChange, error) {
+	a, err := snapshotFields(before)
+	if err != nil {
+		return nil, err
+	}
+	b, err := snapshotFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(a))
+	for field := range a {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	changes := []FieldChange{}
+	for _, field := range fields {
+		if revisionIgnoredFields[field] || reflect.DeepEqual(a[field], b[field]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, From: a[field], To: b[field]})
+	}
+	return changes, nil
+}
+
+// snapshotFields flattens a snapshot into its JSON fields, decoding the JSON-encoded
+// list and map columns so the diff shows their contents rather than raw strings.
+func snapshotFields(snap *CharacterSnapshot) (map[string]any, error) {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	for field, empty := range map[string]string{
+		"skillProficiencies":       "[]",
+		"savingThrowProficiencies": "[]",
+		"features":                 "[]",
+		"damageResistances":        "[]",
+		"damageVulnerabilities":    "[]",
+		"damageImmunities":         "[]",
+		"proficiencyLevels":        "{}",
+	} {
+		raw, ok := fields[field].(string)
+		if !ok {
+			continue
+		}
+		if raw == "" {
+			raw = empty
+		}
+		var decoded any
+		if json.Unmarshal([]byte(raw), &decoded) == nil {
+			fields[field] = decoded
+		}
+	}
+	if fields["equipment"] == nil {
+		fields["equipment"] = []any{}
+	}
+	// Revisions saved before class levels were recorded were a single class.
+	if fields["classes"] == nil {
+		fields["classes"] = []any{map[string]any{"class": fields["class"], "level": fields["level"]}}
+	}
+	return fields, nil
+}
@@ -0,0 +1,88 @@
+package store
+
+import "testing"
+
+func TestRevisionsDiffAndRestore(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("archivist", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Equipment = []string{"Dagger", "Thieves' Tools"}
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	inv, _ := s.GetInventory(c)
+	dagger := inv.Items[0]
+	dagger.Weight = 1
+	dagger.Notes = "Silvered"
+	if _, err := s.UpdateCharacterItem(dagger); err != nil {
+		t.Fatalf("update item: %v", err)
+	}
+	if _, err := s.SetCharacterCoins(CharacterCoin{CharacterID: c.ID, Gp: 12}); err != nil {
+		t.Fatalf("set coins: %v", err)
+	}
+
+	// A stale tab saves over the sheet with the wrong hit points and no equipment.
+	c.CurrentHp = 1
+	c.Name = "Vex the Unlucky"
+	c.Equipment = []string{}
+	if err := s.UpdateCharacter(c); err != nil {
+		t.Fatalf("update character: %v", err)
+	}
+
+	revisions, err := s.ListCharacterRevisions(c.ID)
+	if err != nil {
+		t.Fatalf("list revisions: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Revision != 1 || revisions[0].Reason != RevisionReasonUpdate {
+		t.Fatalf("revisions = %+v", revisions)
+	}
+
+	diff, err := s.DiffCharacterRevision(c, 1, nil)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	changed := map[string]FieldChange{}
+	for _, ch := range diff.Changes {
+		changed[ch.Field] = ch
+	}
+	if len(changed) != 3 || changed["currentHp"].From != float64(9) || changed["currentHp"].To != float64(1) {
+		t.Fatalf("changes = %+v", diff.Changes)
+	}
+	if _, ok := changed["equipment"]; !ok || changed["name"].From != "Vex" {
+		t.Fatalf("changes = %+v", diff.Changes)
+	}
+
+	if _, err := s.SetCharacterCoins(CharacterCoin{CharacterID: c.ID}); err != nil {
+		t.Fatalf("spend coins: %v", err)
+	}
+	if err := s.RestoreCharacterRevision(c, 1); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	got, err := s.GetCharacter(c.ID, user.ID)
+	if err != nil {
+		t.Fatalf("get character: %v", err)
+	}
+	if got.Name != "Vex" || got.CurrentHp != 9 || len(got.Equipment) != 2 {
+		t.Fatalf("restored = %s hp %d equipment %v", got.Name, got.CurrentHp, got.Equipment)
+	}
+	// Item details and coins come back too.
+	inv, _ = s.GetInventory(got)
+	if inv.Items[0].Name != "Dagger" || inv.Items[0].Weight != 1 || inv.Items[0].Notes != "Silvered" || inv.Coins.Gp != 12 {
+		t.Fatalf("restored inventory = %+v, %+v", inv.Items, inv.Coins)
+	}
+
+	// The restore saved the bad version too, so it can be undone.
+	diff, err = s.DiffCharacterRevision(c, 1, ptr(int64(2)))
+	if err != nil {
+		t.Fatalf("diff revisions: %v", err)
+	}
+	if len(diff.Changes) != 3 {
+		t.Fatalf("changes between revisions = %+v", diff.Changes)
+	}
+	if _, err := s.DiffCharacterRevision(c, 9, nil); err != ErrRevisionNotFound {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
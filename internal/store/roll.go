@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+// CreateCampaignRoll records a roll in the campaign log, chaining its hash to the previous roll.
+func (s *Store) CreateCampaignRoll(campaignID, userID int64, characterID *int64, label, visibility string, result *dice.Result) (*models.CampaignRoll, error) {
+	if visibility == "" {
+		visibility = models.RollVisibilityPublic
+	}
+	if !isValidRollVisibility(visibility) {
+		return nil, ErrInvalidRollVisibility
+	}
+
+	role, status, err := s.getMembership(campaignID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "accepted" {
+		return nil, ErrNotPermitted
+	}
+
+	if characterID != nil {
+		owned, err := s.characterOwnedByUser(*characterID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !owned && role != "owner" && role != "editor" {
+			return nil, ErrCharacterNotOwned
+		}
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode roll: %w", err)
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+
+	prevHash, err := qtx.GetLatestCampaignRollHash(ctx, campaignID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to read roll chain: %w", err)
+	}
+
+	params := InsertCampaignRollParams{
+		CampaignID:  campaignID,
+		UserID:      userID,
+		CharacterID: characterID,
+		Label:       label,
+		Expression:  result.Expression,
+		Result:      string(resultJSON),
+		Total:       int64(result.Total),
+		Visibility:  visibility,
+		PrevHash:    prevHash,
+	}
+	params.Hash = rollHash(params.PrevHash, params.CampaignID, params.UserID, params.Label, params.Expression, params.Result, params.Total, params.Visibility)
+
+	inserted, err := qtx.InsertCampaignRoll(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record roll: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit roll: %w", err)
+	}
+
+	username := ""
+	if u, err := s.GetUserByID(userID); err == nil {
+		username = u.Username
+	}
+
+	return &models.CampaignRoll{
+		ID:          inserted.ID,
+		CampaignID:  inserted.CampaignID,
+		UserID:      inserted.UserID,
+		Username:    username,
+		CharacterID: inserted.CharacterID,
+		Label:       inserted.Label,
+		Expression:  inserted.Expression,
+		Result:      json.RawMessage(inserted.Result),
+		Total:       int(inserted.Total),
+		Visibility:  inserted.Visibility,
+		PrevHash:    inserted.PrevHash,
+		Hash:        inserted.Hash,
+		CreatedAt:   inserted.CreatedAt,
+	}, nil
+}
+
+// ListCampaignRolls returns the most recent rolls the user may see, newest first.
+// Owners and editors see gm-only whispers; everyone sees public rolls and their own.
+func (s *Store) ListCampaignRolls(campaignID, userID int64, limit int) ([]*models.CampaignRoll, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	role, status, err := s.getMembership(campaignID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "accepted" {
+		return nil, ErrNotPermitted
+	}
+
+	visibilities := []string{models.RollVisibilityPublic}
+	if role == "owner" || role == "editor" {
+		visibilities = append(visibilities, models.RollVisibilityGMOnly)
+	}
+
+	ctx := context.Background()
+	rows, err := s.q.ListCampaignRolls(ctx, ListCampaignRollsParams{
+		CampaignID:   campaignID,
+		UserID:       userID,
+		Visibilities: visibilities,
+		Limit:        int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rolls: %w", err)
+	}
+
+	rolls := make([]*models.CampaignRoll, 0, len(rows))
+	for _, r := range rows {
+		rolls = append(rolls, &models.CampaignRoll{
+			ID:          r.ID,
+			CampaignID:  r.CampaignID,
+			UserID:      r.UserID,
+			Username:    r.Username,
+			CharacterID: r.CharacterID,
+			Label:       r.Label,
+			Expression:  r.Expression,
+			Result:      json.RawMessage(r.Result),
+			Total:       int(r.Total),
+			Visibility:  r.Visibility,
+			PrevHash:    r.PrevHash,
+			Hash:        r.Hash,
+			CreatedAt:   r.CreatedAt,
+		})
+	}
+
+	return rolls, nil
+}
+
+// AuditCampaignRolls recomputes the hash chain for every roll in a campaign (owner/editor only).
+func (s *Store) AuditCampaignRolls(campaignID, userID int64) (*models.CampaignRollAudit, error) {
+	role, status, err := s.getMembership(campaignID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "accepted" || (role != "owner" && role != "editor") {
+		return nil, ErrNotPermitted
+	}
+
+	ctx := context.Background()
+	rows, err := s.q.ListCampaignRollsForAudit(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rolls: %w", err)
+	}
+
+	audit := &models.CampaignRollAudit{CampaignID: campaignID, Count: len(rows), Valid: true}
+	prev := ""
+	for _, r := range rows {
+		expected := rollHash(prev, r.CampaignID, r.UserID, r.Label, r.Expression, r.Result, r.Total, r.Visibility)
+		if r.PrevHash != prev || r.Hash != expected {
+			audit.Valid = false
+			audit.BrokenAtID = ptr(r.ID)
+			break
+		}
+		prev = r.Hash
+	}
+	audit.LatestHash = prev
+
+	return audit, nil
+}
+
+// rollHash covers everything but character_id, which is cleared when a character is deleted.
+func rollHash(prevHash string, campaignID, userID int64, label, expression, result string, total int64, visibility string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n%d\n%q\n%q\n%s\n%d\n%s", prevHash, campaignID, userID, label, expression, result, total, visibility)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isValidRollVisibility(visibility string) bool {
+	switch visibility {
+	case models.RollVisibilityPublic, models.RollVisibilityGMOnly, models.RollVisibilitySelf:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/dice"
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+func TestCampaignRolls_VisibilityAndAudit(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	gm, _ := s.CreateUser("gm", "hash")
+	player, _ := s.CreateUser("player", "hash")
+	other, _ := s.CreateUser("other", "hash")
+
+	camp, err := s.CreateCampaign(gm.ID, "Rolls", "", models.CampaignVisibilityPrivate, models.CampaignStatusInProgress)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	for _, id := range []int64{player.ID, other.ID} {
+		if _, err := s.db.Exec(`INSERT INTO campaign_members (campaign_id, user_id, role, status) VALUES (?, ?, 'viewer', 'accepted')`, camp.ID, id); err != nil {
+			t.Fatalf("insert viewer: %v", err)
+		}
+	}
+
+	rng := dice.NewSeededRNG(7)
+	roll := func(userID int64, visibility string) {
+		t.Helper()
+		res, err := dice.Roll("1d20+2", rng)
+		if err != nil {
+			t.Fatalf("roll: %v", err)
+		}
+		if _, err := s.CreateCampaignRoll(camp.ID, userID, nil, "", visibility, res); err != nil {
+			t.Fatalf("create roll (%s): %v", visibility, err)
+		}
+	}
+	roll(player.ID, models.RollVisibilityPublic)
+	roll(player.ID, models.RollVisibilityGMOnly)
+	roll(player.ID, models.RollVisibilitySelf)
+	roll(gm.ID, models.RollVisibilityGMOnly)
+
+	counts := map[int64]int{gm.ID: 3, player.ID: 3, other.ID: 1}
+	for userID, want := range counts {
+		rolls, err := s.ListCampaignRolls(camp.ID, userID, 0)
+		if err != nil {
+			t.Fatalf("list rolls: %v", err)
+		}
+		if len(rolls) != want {
+			t.Fatalf("user %d: expected %d rolls, got %d", userID, want, len(rolls))
+		}
+	}
+
+	if _, err := s.AuditCampaignRolls(camp.ID, player.ID); err != ErrNotPermitted {
+		t.Fatalf("expected ErrNotPermitted for player audit, got %v", err)
+	}
+
+	audit, err := s.AuditCampaignRolls(camp.ID, gm.ID)
+	if err != nil {
+		t.Fatalf("audit: %v", err)
+	}
+	if !audit.Valid || audit.Count != 4 {
+		t.Fatalf("expected valid chain of 4, got %+v", audit)
+	}
+
+	if _, err := s.db.Exec(`UPDATE campaign_rolls SET total = 20 WHERE id = (SELECT MIN(id) FROM campaign_rolls)`); err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+	audit, err = s.AuditCampaignRolls(camp.ID, gm.ID)
+	if err != nil {
+		t.Fatalf("audit: %v", err)
+	}
+	if audit.Valid || audit.BrokenAtID == nil {
+		t.Fatalf("expected tampering to be detected, got %+v", audit)
+	}
+
+	if _, err := s.CreateCampaignRoll(camp.ID, player.ID, nil, "", "secret", &dice.Result{}); err != ErrInvalidRollVisibility {
+		t.Fatalf("expected ErrInvalidRollVisibility, got %v", err)
+	}
+}
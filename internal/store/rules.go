@@ -0,0 +1,147 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/rules"
+)
+
+// applyCreationRules checks a new character's species, classes, subclasses and
+// background against the rules catalog and applies what they grant: the species' speed
+// and traits, the starting class's saving throws and hit die, the subclasses' features,
+// and the background's skills and feat. Traits, skills and feats the character already
+// lists are not repeated.
+func (c *CharacterWithStats) applyCreationRules(catalog *rules.Catalog) error {
+	species, ok := catalog.LookupSpecies(c.Race)
+	if !ok {
+		return ErrUnknownSpecies
+	}
+	c.Race = species.Name
+	c.Speed = int64(species.Speed)
+	features := appendMissing(parseStringArray(c.Features), species.Traits...)
+
+	for i, cl := range c.Classes {
+		class, ok := catalog.LookupClass(cl.Class)
+		if !ok {
+			return ErrUnknownClass
+		}
+		c.Classes[i].Class = class.Name
+	}
+	c.Class = c.Classes[0].Class
+	if err := c.checkSubclasses(catalog, nil); err != nil {
+		return err
+	}
+	for _, cl := range c.Classes {
+		if subclass, ok := catalog.LookupSubclass(cl.Class, cl.Subclass); ok {
+			features = appendMissing(features, subclass.Features...)
+		}
+	}
+	class, _ := catalog.LookupClass(c.Class)
+	c.SavingThrowProficiencies = marshalStringArray(class.SavingThrows)
+	c.applyClassRules(catalog)
+
+	if c.Background != "" {
+		background, ok := catalog.LookupBackground(c.Background)
+		if !ok {
+			return ErrUnknownBackground
+		}
+		c.Background = background.Name
+		skills := appendMissing(parseStringArray(c.SkillProficiencies), background.SkillProficiencies...)
+		c.SkillProficiencies = marshalStringArray(skills)
+		features = appendMissing(features, background.Feat)
+	}
+	c.Features = marshalStringArray(features)
+	return nil
+}
+
+// checkChangedOptions matches a changed species, background or new class against the
+// catalog, using its canonical name. Options the character already had are kept even if
+// the pack they came from has since been removed.
+func (c *CharacterWithStats) checkChangedOptions(catalog *rules.Catalog, current CharacterModel, previous []CharacterClass) error {
+	if !strings.EqualFold(c.Race, current.Race) {
+		species, ok := catalog.LookupSpecies(c.Race)
+		if !ok {
+			return ErrUnknownSpecies
+		}
+		c.Race = species.Name
+	}
+	for i, cl := range c.Classes {
+		kept := false
+		for _, p := range previous {
+			kept = kept || strings.EqualFold(p.Class, cl.Class)
+		}
+		if kept {
+			continue
+		}
+		class, ok := catalog.LookupClass(cl.Class)
+		if !ok {
+			return ErrUnknownClass
+		}
+		c.Classes[i].Class = class.Name
+	}
+	c.Class = c.Classes[0].Class
+	if c.Background != "" && !strings.EqualFold(c.Background, current.Background) {
+		background, ok := catalog.LookupBackground(c.Background)
+		if !ok {
+			return ErrUnknownBackground
+		}
+		c.Background = background.Name
+	}
+	return nil
+}
+
+// applyClassRules copies each class's hit die and spellcasting ability from the catalog,
+// so that homebrew classes keep theirs, and works out the hit dice again. Classes missing
+// from the catalog, such as those of a deleted pack, keep what they had.
+func (c *CharacterWithStats) applyClassRules(catalog *rules.Catalog) {
+	for i, cl := range c.Classes {
+		if class, ok := catalog.LookupClass(cl.Class); ok {
+			c.Classes[i].HitDie = int64(class.HitDie)
+			c.Classes[i].SpellcastingAbility = class.SpellcastingAbility
+		}
+	}
+	c.HitDice = hitDiceExpression(c.hitDicePool(c.HitDie()))
+}
+
+// checkSubclasses matches each class's subclass to one of that class's subclasses in the
+// catalog, using its canonical name. Subclasses the character already had in previous
+// are kept even if the pack they came from has since been removed.
+func (c *CharacterWithStats) checkSubclasses(catalog *rules.Catalog, previous []CharacterClass) error {
+	for i, cl := range c.Classes {
+		if cl.Subclass == "" {
+			continue
+		}
+		kept := false
+		for _, p := range previous {
+			kept = kept || (p.Class == cl.Class && p.Subclass == cl.Subclass)
+		}
+		if kept {
+			continue
+		}
+		subclass, ok := catalog.LookupSubclass(cl.Class, cl.Subclass)
+		if !ok {
+			return ErrUnknownSubclass
+		}
+		c.Classes[i].Subclass = subclass.Name
+	}
+	return nil
+}
+
+// appendMissing adds the values not already in list, matching names loosely.
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		if !containsKey(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// classHitDice maps each class in the rules catalog to the size of its hit die.
+func classHitDice() map[string]int {
+	dice := make(map[string]int, len(rules.SRD().Classes))
+	for _, class := range rules.SRD().Classes {
+		dice[class.Name] = class.HitDie
+	}
+	return dice
+}
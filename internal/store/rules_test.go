@@ -0,0 +1,83 @@
+package store
+
+import "testing"
+
+func TestCreateCharacterAppliesRules(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("ruled", "hash")
+	for _, change := range []func(c *CharacterWithStats){
+		func(c *CharacterWithStats) { c.Race = "Kender" },
+		func(c *CharacterWithStats) { c.Class = "Artificer" },
+		func(c *CharacterWithStats) { c.Background = "Pirate" },
+	} {
+		c := newTestCharacter()
+		c.UserID = user.ID
+		change(c)
+		err := s.CreateCharacter(c)
+		if err != ErrUnknownSpecies && err != ErrUnknownClass && err != ErrUnknownBackground {
+			t.Fatalf("%s/%s/%s: expected an unknown rules error, got %v", c.Race, c.Class, c.Background, err)
+		}
+	}
+
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Race = "goliath"
+	c.Class = "fighter"
+	c.Background = "criminal"
+	c.SavingThrowProficiencies = `["charisma"]`
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	if c.Race != "Goliath" || c.Speed != 35 || c.Background != "Criminal" || c.HitDice != "1d10" {
+		t.Fatalf("character = %s %s speed %d hit dice %s", c.Race, c.Background, c.Speed, c.HitDice)
+	}
+	if c.SavingThrowProficiencies != `["strength","constitution"]` {
+		t.Fatalf("saving throws = %s", c.SavingThrowProficiencies)
+	}
+	// Stealth and Sleight of Hand were already chosen, so the background adds nothing new.
+	if c.SkillProficiencies != `["Stealth","Sleight of Hand"]` {
+		t.Fatalf("skills = %s", c.SkillProficiencies)
+	}
+	if c.Features != `["Large Form","Powerful Build","Giant Ancestry","Alert"]` {
+		t.Fatalf("features = %s", c.Features)
+	}
+}
+
+func TestUpdateCharacterChecksChangedOptions(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("reroll", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	for _, tc := range []struct {
+		change func(c *CharacterWithStats)
+		want   error
+	}{
+		{func(c *CharacterWithStats) { c.Race = "Kender" }, ErrUnknownSpecies},
+		{func(c *CharacterWithStats) { c.Class = "Artificer"; c.Classes = nil }, ErrUnknownClass},
+		{func(c *CharacterWithStats) { c.Background = "Pirate" }, ErrUnknownBackground},
+	} {
+		edit, _ := s.GetCharacter(c.ID, user.ID)
+		tc.change(edit)
+		if err := s.UpdateCharacter(edit); err != tc.want {
+			t.Fatalf("%s/%s/%s: expected %v, got %v", edit.Race, edit.Class, edit.Background, tc.want, err)
+		}
+	}
+
+	edit, _ := s.GetCharacter(c.ID, user.ID)
+	edit.Race = "goliath"
+	edit.Background = "criminal"
+	if err := s.UpdateCharacter(edit); err != nil {
+		t.Fatalf("update character: %v", err)
+	}
+	if edit.Race != "Goliath" || edit.Background != "Criminal" {
+		t.Fatalf("character = %s %s", edit.Race, edit.Background)
+	}
+}
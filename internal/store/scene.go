@@ -0,0 +1,284 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+// ListScenes returns the campaign's scenes in order. Owners and editors see every scene;
+// other members only see the active one.
+func (s *Store) ListScenes(campaignID, userID int64) ([]models.Scene, error) {
+	role, status, err := s.getMembership(campaignID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "accepted" {
+		return nil, ErrNotPermitted
+	}
+
+	rows, err := s.q.ListScenes(context.Background(), campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scenes: %w", err)
+	}
+	isGM := role == "owner" || role == "editor"
+	scenes := make([]models.Scene, 0, len(rows))
+	for _, r := range rows {
+		if !isGM && !r.IsActive {
+			continue
+		}
+		scenes = append(scenes, sceneToModel(r))
+	}
+	return scenes, nil
+}
+
+// CreateScene adds a scene after the campaign's existing ones. The campaign's first scene
+// becomes its active scene.
+func (s *Store) CreateScene(campaignID, userID int64, name, description string) (*models.Scene, error) {
+	if err := s.requireCampaignEditor(campaignID, userID); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidScene
+	}
+	campaign, err := s.getCampaignByID(campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	ordering, err := qtx.GetNextSceneOrdering(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to order scene: %w", err)
+	}
+	activate := campaign.ActiveSceneID == nil
+	row, err := qtx.CreateScene(ctx, CreateSceneParams{
+		CampaignID:  campaignID,
+		Name:        name,
+		Description: &description,
+		Ordering:    ordering,
+		IsActive:    activate,
+		CreatedBy:   &userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scene: %w", err)
+	}
+	if activate {
+		if err := activateScene(ctx, qtx, campaignID, &row.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit scene: %w", err)
+	}
+
+	scene := sceneToModel(ListScenesRow(row))
+	return &scene, nil
+}
+
+// UpdateScene renames a scene and replaces its description.
+func (s *Store) UpdateScene(campaignID, sceneID, userID int64, name, description string) (*models.Scene, error) {
+	if err := s.requireCampaignEditor(campaignID, userID); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidScene
+	}
+
+	row, err := s.q.UpdateScene(context.Background(), UpdateSceneParams{
+		Name:        name,
+		Description: &description,
+		ID:          sceneID,
+		CampaignID:  campaignID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSceneNotFound
+		}
+		return nil, fmt.Errorf("failed to update scene: %w", err)
+	}
+	scene := sceneToModel(ListScenesRow(row))
+	return &scene, nil
+}
+
+// ReorderScenes puts the campaign's scenes in the given order, which must list every
+// scene exactly once.
+func (s *Store) ReorderScenes(campaignID, userID int64, sceneIDs []int64) ([]models.Scene, error) {
+	if err := s.requireCampaignEditor(campaignID, userID); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	rows, err := qtx.ListScenes(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scenes: %w", err)
+	}
+	if len(sceneIDs) != len(rows) {
+		return nil, ErrInvalidSceneOrder
+	}
+	byID := make(map[int64]ListScenesRow, len(rows))
+	for _, r := range rows {
+		byID[r.ID] = r
+	}
+	scenes := make([]models.Scene, 0, len(rows))
+	for i, id := range sceneIDs {
+		r, ok := byID[id]
+		if !ok {
+			return nil, ErrInvalidSceneOrder
+		}
+		delete(byID, id)
+		if err := qtx.UpdateSceneOrdering(ctx, UpdateSceneOrderingParams{
+			Ordering:   int64(i),
+			ID:         id,
+			CampaignID: campaignID,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to reorder scenes: %w", err)
+		}
+		r.Ordering = int64(i)
+		scenes = append(scenes, sceneToModel(r))
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit scene order: %w", err)
+	}
+	return scenes, nil
+}
+
+// ActivateScene makes a scene the one players see, marking it as the only active scene
+// and pointing the campaign at it together.
+func (s *Store) ActivateScene(campaignID, sceneID, userID int64) (*models.Scene, error) {
+	if err := s.requireCampaignEditor(campaignID, userID); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	row, err := qtx.GetScene(ctx, GetSceneParams{ID: sceneID, CampaignID: campaignID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSceneNotFound
+		}
+		return nil, fmt.Errorf("failed to get scene: %w", err)
+	}
+	if err := activateScene(ctx, qtx, campaignID, &sceneID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit scene activation: %w", err)
+	}
+
+	row.IsActive = true
+	scene := sceneToModel(ListScenesRow(row))
+	return &scene, nil
+}
+
+// DeleteScene removes a scene with its maps and tokens. When the active scene is removed
+// the first remaining scene takes its place.
+func (s *Store) DeleteScene(campaignID, sceneID, userID int64) error {
+	if err := s.requireCampaignEditor(campaignID, userID); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.q.WithTx(tx)
+	row, err := qtx.GetScene(ctx, GetSceneParams{ID: sceneID, CampaignID: campaignID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSceneNotFound
+		}
+		return fmt.Errorf("failed to get scene: %w", err)
+	}
+	if err := qtx.DeleteScene(ctx, DeleteSceneParams{ID: sceneID, CampaignID: campaignID}); err != nil {
+		return fmt.Errorf("failed to delete scene: %w", err)
+	}
+	if row.IsActive {
+		var next *int64
+		nextID, err := qtx.GetFirstSceneByCampaignID(ctx, campaignID)
+		switch {
+		case err == nil:
+			next = &nextID
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("failed to get next scene: %w", err)
+		}
+		if err := activateScene(ctx, qtx, campaignID, next); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit scene deletion: %w", err)
+	}
+	return nil
+}
+
+// activateScene marks sceneID as the campaign's only active scene, or clears the active
+// scene when sceneID is nil.
+func activateScene(ctx context.Context, q *Queries, campaignID int64, sceneID *int64) error {
+	var id int64
+	if sceneID != nil {
+		id = *sceneID
+	}
+	if err := q.SetActiveScene(ctx, SetActiveSceneParams{ID: id, CampaignID: campaignID}); err != nil {
+		return fmt.Errorf("failed to activate scene: %w", err)
+	}
+	if err := q.UpdateCampaignActiveScene(ctx, UpdateCampaignActiveSceneParams{ActiveSceneID: sceneID, ID: campaignID}); err != nil {
+		return fmt.Errorf("failed to set active scene: %w", err)
+	}
+	return nil
+}
+
+// requireCampaignEditor checks that the user is an accepted owner or editor of the campaign.
+func (s *Store) requireCampaignEditor(campaignID, userID int64) error {
+	role, status, err := s.getMembership(campaignID, userID)
+	if err != nil {
+		return err
+	}
+	if status != "accepted" || (role != "owner" && role != "editor") {
+		return ErrNotPermitted
+	}
+	return nil
+}
+
+func sceneToModel(r ListScenesRow) models.Scene {
+	return models.Scene{
+		ID:          r.ID,
+		CampaignID:  r.CampaignID,
+		Name:        r.Name,
+		Description: r.Description,
+		Ordering:    int(r.Ordering),
+		IsActive:    r.IsActive,
+		CreatedBy:   r.CreatedBy,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
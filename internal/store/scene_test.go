@@ -0,0 +1,154 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+func TestScenes_CreateReorderActivateDelete(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	owner, _ := s.CreateUser("owner", "hash")
+	viewer, _ := s.CreateUser("viewer", "hash")
+	camp, err := s.CreateCampaign(owner.ID, "Quest", "", models.CampaignVisibilityPrivate, models.CampaignStatusNotStarted)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO campaign_members (campaign_id, user_id, role, status) VALUES (?, ?, 'viewer', 'accepted')`, camp.ID, viewer.ID); err != nil {
+		t.Fatalf("insert viewer: %v", err)
+	}
+
+	if _, err := s.CreateScene(camp.ID, viewer.ID, "Cave", ""); err != ErrNotPermitted {
+		t.Fatalf("viewer expected ErrNotPermitted, got %v", err)
+	}
+	if _, err := s.CreateScene(camp.ID, owner.ID, "  ", ""); err != ErrInvalidScene {
+		t.Fatalf("expected ErrInvalidScene, got %v", err)
+	}
+
+	tavern, err := s.CreateScene(camp.ID, owner.ID, "Tavern", "Start here")
+	if err != nil {
+		t.Fatalf("create tavern: %v", err)
+	}
+	if !tavern.IsActive || tavern.Ordering != 0 {
+		t.Fatalf("first scene should be active at 0: %+v", tavern)
+	}
+	cave, err := s.CreateScene(camp.ID, owner.ID, "Cave", "")
+	if err != nil {
+		t.Fatalf("create cave: %v", err)
+	}
+	if cave.IsActive || cave.Ordering != 1 {
+		t.Fatalf("second scene should be inactive at 1: %+v", cave)
+	}
+	assertActiveScene(t, s, camp.ID, &tavern.ID)
+
+	if _, err := s.ReorderScenes(camp.ID, owner.ID, []int64{cave.ID}); err != ErrInvalidSceneOrder {
+		t.Fatalf("expected ErrInvalidSceneOrder, got %v", err)
+	}
+	scenes, err := s.ReorderScenes(camp.ID, owner.ID, []int64{cave.ID, tavern.ID})
+	if err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	if scenes[0].ID != cave.ID || scenes[1].Ordering != 1 {
+		t.Fatalf("unexpected order: %+v", scenes)
+	}
+
+	if _, err := s.ActivateScene(camp.ID, cave.ID, viewer.ID); err != ErrNotPermitted {
+		t.Fatalf("viewer expected ErrNotPermitted, got %v", err)
+	}
+	if _, err := s.ActivateScene(camp.ID, cave.ID, owner.ID); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+	assertActiveScene(t, s, camp.ID, &cave.ID)
+
+	visible, err := s.ListScenes(camp.ID, viewer.ID)
+	if err != nil {
+		t.Fatalf("viewer list: %v", err)
+	}
+	if len(visible) != 1 || visible[0].ID != cave.ID {
+		t.Fatalf("viewer should only see the active scene: %+v", visible)
+	}
+	all, err := s.ListScenes(camp.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("owner list: %v", err)
+	}
+	if len(all) != 2 || all[1].IsActive {
+		t.Fatalf("owner should see both scenes with one active: %+v", all)
+	}
+
+	renamed, err := s.UpdateScene(camp.ID, tavern.ID, owner.ID, "Inn", "Rooms upstairs")
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if renamed.Name != "Inn" || renamed.Description != "Rooms upstairs" {
+		t.Fatalf("unexpected update: %+v", renamed)
+	}
+
+	if err := s.DeleteScene(camp.ID, cave.ID, owner.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	assertActiveScene(t, s, camp.ID, &tavern.ID)
+	if err := s.DeleteScene(camp.ID, tavern.ID, owner.ID); err != nil {
+		t.Fatalf("delete last: %v", err)
+	}
+	assertActiveScene(t, s, camp.ID, nil)
+	if err := s.DeleteScene(camp.ID, tavern.ID, owner.ID); err != ErrSceneNotFound {
+		t.Fatalf("expected ErrSceneNotFound, got %v", err)
+	}
+}
+
+func TestScenes_SceneBelongsToCampaign(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	owner, _ := s.CreateUser("owner", "hash")
+	first, _ := s.CreateCampaign(owner.ID, "One", "", models.CampaignVisibilityPrivate, models.CampaignStatusNotStarted)
+	second, _ := s.CreateCampaign(owner.ID, "Two", "", models.CampaignVisibilityPrivate, models.CampaignStatusNotStarted)
+
+	scene, err := s.CreateScene(first.ID, owner.ID, "Road", "")
+	if err != nil {
+		t.Fatalf("create scene: %v", err)
+	}
+	if _, err := s.ActivateScene(second.ID, scene.ID, owner.ID); err != ErrSceneNotFound {
+		t.Fatalf("expected ErrSceneNotFound, got %v", err)
+	}
+	if _, err := s.UpdateScene(second.ID, scene.ID, owner.ID, "Path", ""); err != ErrSceneNotFound {
+		t.Fatalf("expected ErrSceneNotFound, got %v", err)
+	}
+	if _, err := s.CreateMapForCampaign(second.ID, owner.ID, &scene.ID, "Map", "/uploads/map.png"); err != ErrSceneNotFound {
+		t.Fatalf("expected ErrSceneNotFound, got %v", err)
+	}
+}
+
+func TestScenes_DefaultSceneIsActive(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	owner, _ := s.CreateUser("owner", "hash")
+	camp, _ := s.CreateCampaign(owner.ID, "Quest", "", models.CampaignVisibilityPrivate, models.CampaignStatusNotStarted)
+
+	sceneID, err := s.ensureDefaultScene(camp.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("default scene: %v", err)
+	}
+	assertActiveScene(t, s, camp.ID, &sceneID)
+}
+
+func assertActiveScene(t *testing.T, s *Store, campaignID int64, want *int64) {
+	t.Helper()
+	campaign, err := s.getCampaignByID(campaignID)
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if (campaign.ActiveSceneID == nil) != (want == nil) || (want != nil && *campaign.ActiveSceneID != *want) {
+		t.Fatalf("active scene = %v, want %v", campaign.ActiveSceneID, want)
+	}
+	var active int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM scenes WHERE campaign_id = ? AND is_active`, campaignID).Scan(&active); err != nil {
+		t.Fatalf("count active: %v", err)
+	}
+	if (want == nil && active != 0) || (want != nil && active != 1) {
+		t.Fatalf("%d scenes marked active, want scene %v", active, want)
+	}
+}
@@ -0,0 +1,429 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Spell slot kinds stored in character_spell_slots.
+const (
+	SlotKindSpell = "spell"
+	SlotKindPact  = "pact"
+)
+
+// Caster progressions used to derive spell slots from class and level.
+const (
+	CasterNone = ""
+	CasterFull = "full"
+	CasterHalf = "half"
+	CasterPact = "pact"
+)
+
+// CasterProgressions maps each spellcasting class to how it gains slots.
+var CasterProgressions = map[string]string{
+	"Bard":     CasterFull,
+	"Cleric":   CasterFull,
+	"Druid":    CasterFull,
+	"Sorcerer": CasterFull,
+	"Wizard":   CasterFull,
+	"Paladin":  CasterHalf,
+	"Ranger":   CasterHalf,
+	"Warlock":  CasterPact,
+}
+
+// KnownSpellCasters are the classes that know a fixed list of spells instead of preparing
+// them each day, so they can cast any spell they have.
+var KnownSpellCasters = map[string]bool{
+	"Bard":     true,
+	"Ranger":   true,
+	"Sorcerer": true,
+	"Warlock":  true,
+}
+
+// mustPrepareSpells reports whether the character has to prepare a spell before casting it.
+// Spells do not record which class taught them, so a character with any known-spell class
+// can cast every spell they have.
+func (c *CharacterWithStats) mustPrepareSpells() bool {
+	for _, cl := range c.classLevels() {
+		if KnownSpellCasters[cl.Class] {
+			return false
+		}
+	}
+	return true
+}
+
+// fullCasterSlots[n-1] lists slots per spell level (1st..9th) for caster level n.
+var fullCasterSlots = [20][9]int{
+	{2},
+	{3},
+	{4, 2},
+	{4, 3},
+	{4, 3, 2},
+	{4, 3, 3},
+	{4, 3, 3, 1},
+	{4, 3, 3, 2},
+	{4, 3, 3, 3, 1},
+	{4, 3, 3, 3, 2},
+	{4, 3, 3, 3, 2, 1},
+	{4, 3, 3, 3, 2, 1},
+	{4, 3, 3, 3, 2, 1, 1},
+	{4, 3, 3, 3, 2, 1, 1},
+	{4, 3, 3, 3, 2, 1, 1, 1},
+	{4, 3, 3, 3, 2, 1, 1, 1},
+	{4, 3, 3, 3, 2, 1, 1, 1, 1},
+	{4, 3, 3, 3, 3, 1, 1, 1, 1},
+	{4, 3, 3, 3, 3, 2, 1, 1, 1},
+	{4, 3, 3, 3, 3, 2, 2, 1, 1},
+}
+
+// casterLevel converts class levels into levels on the full caster slot table.
+// Half casters round up, so paladins and rangers have slots from 1st level.
+func casterLevel(class string, level int) int {
+	switch CasterProgressions[class] {
+	case CasterFull:
+		return level
+	case CasterHalf:
+		return (level + 1) / 2
+	}
+	return 0
+}
+
+// casterLevel combines the character's spellcasting classes into one level on the full
+// caster slot table, as the multiclass spellcaster rules do. Pact magic is kept apart.
+func (c *CharacterWithStats) casterLevel() int {
+	total := 0
+	for _, cl := range c.classLevels() {
+		total += casterLevel(cl.Class, int(cl.Level))
+	}
+	return total
+}
+
+// pactCasterLevel is the character's level in pact magic classes.
+func (c *CharacterWithStats) pactCasterLevel() int {
+	total := 0
+	for _, cl := range c.classLevels() {
+		if CasterProgressions[cl.Class] == CasterPact {
+			total += int(cl.Level)
+		}
+	}
+	return total
+}
+
+// spellSlotMaximums returns the number of slots per spell level (index 0 is 1st level).
+func spellSlotMaximums(casterLevel int) [9]int {
+	if casterLevel <= 0 {
+		return [9]int{}
+	}
+	if casterLevel > 20 {
+		casterLevel = 20
+	}
+	return fullCasterSlots[casterLevel-1]
+}
+
+// pactSlots returns the warlock pact magic slot count and slot level.
+func pactSlots(warlockLevel int) (count, slotLevel int) {
+	switch {
+	case warlockLevel <= 0:
+		return 0, 0
+	case warlockLevel == 1:
+		count = 1
+	case warlockLevel <= 10:
+		count = 2
+	case warlockLevel <= 16:
+		count = 3
+	default:
+		count = 4
+	}
+	slotLevel = min((warlockLevel+1)/2, 5)
+	return count, slotLevel
+}
+
+// SpellSlot is the state of the slots at one spell level.
+type SpellSlot struct {
+	Level     int `json:"level"`
+	Max       int `json:"max"`
+	Expended  int `json:"expended"`
+	Available int `json:"available"`
+}
+
+// Spellbook is a character's spells together with their casting stats and slots.
+type Spellbook struct {
+	SpellcastingAbility string              `json:"spellcastingAbility,omitempty"`
+	SpellSaveDC         *int                `json:"spellSaveDc,omitempty"`
+	SpellAttackBonus    *int                `json:"spellAttackBonus,omitempty"`
+	Spellcasting        []ClassSpellcasting `json:"spellcasting"`
+	Slots               []SpellSlot         `json:"slots"`
+	PactSlots           *SpellSlot          `json:"pactSlots,omitempty"`
+	Spells              []CharacterSpell    `json:"spells"`
+}
+
+// SpellCast describes a successful cast and the spellbook after spending the slot.
+type SpellCast struct {
+	Spell     CharacterSpell `json:"spell"`
+	SlotLevel int            `json:"slotLevel"`
+	Pact      bool           `json:"pact"`
+	Spellbook *Spellbook     `json:"spellbook"`
+}
+
+// GetSpellbook returns the character's spells, casting stats and slot usage.
+func (s *Store) GetSpellbook(c *CharacterWithStats) (*Spellbook, error) {
+	ctx := context.Background()
+
+	spells, err := s.q.ListCharacterSpells(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spells: %w", err)
+	}
+	if spells == nil {
+		spells = []CharacterSpell{}
+	}
+
+	slots, pact, err := s.spellSlots(ctx, s.q, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Spellbook{
+		SpellcastingAbility: c.SpellcastingAbility,
+		SpellSaveDC:         c.SpellSaveDC,
+		SpellAttackBonus:    c.SpellAttackBonus,
+		Spellcasting:        c.Spellcasting,
+		Slots:               slots,
+		PactSlots:           pact,
+		Spells:              spells,
+	}, nil
+}
+
+// AddCharacterSpell adds a spell to the character's known spells.
+func (s *Store) AddCharacterSpell(spell CharacterSpell) (*CharacterSpell, error) {
+	if err := validateSpell(&spell); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	inserted, err := s.q.InsertCharacterSpell(ctx, InsertCharacterSpellParams{
+		CharacterID: spell.CharacterID,
+		Name:        spell.Name,
+		Level:       spell.Level,
+		School:      spell.School,
+		Prepared:    spell.Prepared,
+		Notes:       spell.Notes,
+	})
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return nil, ErrSpellExists
+		}
+		return nil, fmt.Errorf("failed to add spell: %w", err)
+	}
+	if err := markCharacterChanged(ctx, s.q, spell.CharacterID); err != nil {
+		return nil, err
+	}
+	return &inserted, nil
+}
+
+// UpdateCharacterSpell replaces a known spell's details, including whether it is prepared.
+func (s *Store) UpdateCharacterSpell(spell CharacterSpell) (*CharacterSpell, error) {
+	if err := validateSpell(&spell); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	updated, err := s.q.UpdateCharacterSpell(ctx, UpdateCharacterSpellParams{
+		Name:        spell.Name,
+		Level:       spell.Level,
+		School:      spell.School,
+		Prepared:    spell.Prepared,
+		Notes:       spell.Notes,
+		ID:          spell.ID,
+		CharacterID: spell.CharacterID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSpellNotFound
+		}
+		if isUniqueConstraintError(err) {
+			return nil, ErrSpellExists
+		}
+		return nil, fmt.Errorf("failed to update spell: %w", err)
+	}
+	if err := markCharacterChanged(ctx, s.q, spell.CharacterID); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteCharacterSpell removes a spell from the character.
+func (s *Store) DeleteCharacterSpell(characterID, spellID int64) error {
+	ctx := context.Background()
+
+	rows, err := s.q.DeleteCharacterSpell(ctx, DeleteCharacterSpellParams{ID: spellID, CharacterID: characterID})
+	if err != nil {
+		return fmt.Errorf("failed to delete spell: %w", err)
+	}
+	if rows == 0 {
+		return ErrSpellNotFound
+	}
+	return markCharacterChanged(ctx, s.q, characterID)
+}
+
+// CastSpell spends a slot for a spell. Classes that prepare spells can only cast prepared
+// ones, and cantrips never use a slot. When pact is set the
+// warlock pact slot is used and slotLevel is ignored; otherwise slotLevel defaults to the spell's level.
+func (s *Store) CastSpell(c *CharacterWithStats, spellID int64, slotLevel int, pact bool) (*SpellCast, error) {
+	ctx := context.Background()
+
+	spell, err := s.q.GetCharacterSpell(ctx, GetCharacterSpellParams{ID: spellID, CharacterID: c.ID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSpellNotFound
+		}
+		return nil, fmt.Errorf("failed to get spell: %w", err)
+	}
+
+	cast := &SpellCast{Spell: spell}
+	if spell.Level > 0 {
+		if !spell.Prepared && c.mustPrepareSpells() {
+			return nil, ErrSpellNotPrepared
+		}
+
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		qtx := s.q.WithTx(tx)
+		slots, pactSlot, err := s.spellSlots(ctx, qtx, c)
+		if err != nil {
+			return nil, err
+		}
+
+		kind := SlotKindSpell
+		var slot *SpellSlot
+		if pact {
+			kind = SlotKindPact
+			slot = pactSlot
+		} else {
+			if slotLevel == 0 {
+				slotLevel = int(spell.Level)
+			}
+			if slotLevel >= 1 && slotLevel <= len(slots) {
+				slot = &slots[slotLevel-1]
+			}
+		}
+		if slot == nil || slot.Max == 0 || slot.Level < int(spell.Level) {
+			return nil, ErrInvalidSpellSlot
+		}
+		if slot.Available == 0 {
+			return nil, ErrNoSpellSlot
+		}
+
+		if err := qtx.UpsertCharacterSpellSlot(ctx, UpsertCharacterSpellSlotParams{
+			CharacterID: c.ID,
+			Kind:        kind,
+			SlotLevel:   int64(slot.Level),
+			Expended:    int64(slot.Expended + 1),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to spend spell slot: %w", err)
+		}
+		if err := markCharacterChanged(ctx, qtx, c.ID); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit spell slot: %w", err)
+		}
+
+		cast.SlotLevel = slot.Level
+		cast.Pact = pact
+	}
+
+	book, err := s.GetSpellbook(c)
+	if err != nil {
+		return nil, err
+	}
+	cast.Spellbook = book
+	return cast, nil
+}
+
+// SetSpellSlotsExpended records how many slots of a level have been used, for manual corrections.
+func (s *Store) SetSpellSlotsExpended(c *CharacterWithStats, kind string, slotLevel, expended int) (*Spellbook, error) {
+	ctx := context.Background()
+
+	slots, pactSlot, err := s.spellSlots(ctx, s.q, c)
+	if err != nil {
+		return nil, err
+	}
+
+	var slot *SpellSlot
+	switch kind {
+	case SlotKindSpell, "":
+		kind = SlotKindSpell
+		if slotLevel >= 1 && slotLevel <= len(slots) {
+			slot = &slots[slotLevel-1]
+		}
+	case SlotKindPact:
+		slot = pactSlot
+	}
+	if slot == nil || slot.Max == 0 || expended < 0 || expended > slot.Max {
+		return nil, ErrInvalidSpellSlot
+	}
+
+	if err := s.q.UpsertCharacterSpellSlot(ctx, UpsertCharacterSpellSlotParams{
+		CharacterID: c.ID,
+		Kind:        kind,
+		SlotLevel:   int64(slot.Level),
+		Expended:    int64(expended),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update spell slots: %w", err)
+	}
+	if err := markCharacterChanged(ctx, s.q, c.ID); err != nil {
+		return nil, err
+	}
+
+	return s.GetSpellbook(c)
+}
+
+// spellSlots combines the class-derived maximums with the stored expended counts.
+// Slots are always returned for levels 1-9; pact is nil for non-warlocks.
+func (s *Store) spellSlots(ctx context.Context, q *Queries, c *CharacterWithStats) ([]SpellSlot, *SpellSlot, error) {
+	rows, err := q.ListCharacterSpellSlots(ctx, c.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list spell slots: %w", err)
+	}
+	expended := map[string]map[int]int{SlotKindSpell: {}, SlotKindPact: {}}
+	for _, r := range rows {
+		if m, ok := expended[r.Kind]; ok {
+			m[int(r.SlotLevel)] = int(r.Expended)
+		}
+	}
+
+	maxes := spellSlotMaximums(c.casterLevel())
+	slots := make([]SpellSlot, len(maxes))
+	for i, n := range maxes {
+		slots[i] = newSpellSlot(i+1, n, expended[SlotKindSpell][i+1])
+	}
+
+	var pact *SpellSlot
+	if pactLevel := c.pactCasterLevel(); pactLevel > 0 {
+		count, level := pactSlots(pactLevel)
+		slot := newSpellSlot(level, count, expended[SlotKindPact][level])
+		pact = &slot
+	}
+
+	return slots, pact, nil
+}
+
+func newSpellSlot(level, total, expended int) SpellSlot {
+	expended = min(expended, total)
+	return SpellSlot{Level: level, Max: total, Expended: expended, Available: total - expended}
+}
+
+func validateSpell(spell *CharacterSpell) error {
+	spell.Name = strings.TrimSpace(spell.Name)
+	if spell.Name == "" || spell.Level < 0 || spell.Level > 9 {
+		return ErrInvalidSpell
+	}
+	return nil
+}
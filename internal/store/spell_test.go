@@ -0,0 +1,154 @@
+package store
+
+import "testing"
+
+func TestSpellSlotProgression(t *testing.T) {
+	cases := []struct {
+		class string
+		level int
+		want  [9]int
+	}{
+		{"Wizard", 3, [9]int{4, 2}},
+		{"Cleric", 20, [9]int{4, 3, 3, 3, 3, 2, 2, 1, 1}},
+		{"Paladin", 1, [9]int{2}},
+		{"Ranger", 5, [9]int{4, 2}},
+		{"Fighter", 10, [9]int{}},
+		{"Warlock", 5, [9]int{}},
+	}
+	for _, tc := range cases {
+		if got := spellSlotMaximums(casterLevel(tc.class, tc.level)); got != tc.want {
+			t.Errorf("%s %d slots = %v, want %v", tc.class, tc.level, got, tc.want)
+		}
+	}
+
+	for level, want := range map[int][2]int{1: {1, 1}, 2: {2, 1}, 5: {2, 3}, 11: {3, 5}, 20: {4, 5}} {
+		count, slotLevel := pactSlots(level)
+		if count != want[0] || slotLevel != want[1] {
+			t.Errorf("warlock %d pact slots = %d at %d, want %d at %d", level, count, slotLevel, want[0], want[1])
+		}
+	}
+}
+
+func TestCastSpellSpendsSlots(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("caster", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Class = "Wizard"
+	c.Level = 3
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	add := func(name string, level int64, prepared bool) CharacterSpell {
+		t.Helper()
+		spell, err := s.AddCharacterSpell(CharacterSpell{CharacterID: c.ID, Name: name, Level: level, Prepared: prepared})
+		if err != nil {
+			t.Fatalf("add %s: %v", name, err)
+		}
+		return *spell
+	}
+	bolt := add("Fire Bolt", 0, false)
+	missile := add("Magic Missile", 1, true)
+	shield := add("Shield", 1, false)
+
+	if _, err := s.AddCharacterSpell(CharacterSpell{CharacterID: c.ID, Name: "Shield", Level: 1}); err != ErrSpellExists {
+		t.Fatalf("expected ErrSpellExists, got %v", err)
+	}
+
+	cast, err := s.CastSpell(c, bolt.ID, 0, false)
+	if err != nil || cast.SlotLevel != 0 {
+		t.Fatalf("cantrip should not need a slot: %+v %v", cast, err)
+	}
+	if _, err := s.CastSpell(c, shield.ID, 0, false); err != ErrSpellNotPrepared {
+		t.Fatalf("expected ErrSpellNotPrepared, got %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := s.CastSpell(c, missile.ID, 1, false); err != nil {
+			t.Fatalf("cast %d: %v", i, err)
+		}
+	}
+	if _, err := s.CastSpell(c, missile.ID, 1, false); err != ErrNoSpellSlot {
+		t.Fatalf("expected ErrNoSpellSlot, got %v", err)
+	}
+
+	cast, err = s.CastSpell(c, missile.ID, 2, false)
+	if err != nil {
+		t.Fatalf("upcast: %v", err)
+	}
+	if slots := cast.Spellbook.Slots; slots[0].Available != 0 || slots[1].Available != 1 {
+		t.Fatalf("unexpected slots after upcast: %+v", slots[:2])
+	}
+	if _, err := s.CastSpell(c, missile.ID, 3, false); err != ErrInvalidSpellSlot {
+		t.Fatalf("expected ErrInvalidSpellSlot for a 3rd level slot, got %v", err)
+	}
+	if _, err := s.CastSpell(c, missile.ID, 0, true); err != ErrInvalidSpellSlot {
+		t.Fatalf("expected ErrInvalidSpellSlot for pact magic on a wizard, got %v", err)
+	}
+
+	book, err := s.SetSpellSlotsExpended(c, SlotKindSpell, 1, 0)
+	if err != nil {
+		t.Fatalf("reset slots: %v", err)
+	}
+	if book.Slots[0].Available != 4 || len(book.Spells) != 3 {
+		t.Fatalf("unexpected spellbook: %+v", book)
+	}
+	if _, err := s.SetSpellSlotsExpended(c, SlotKindSpell, 1, 5); err != ErrInvalidSpellSlot {
+		t.Fatalf("expected ErrInvalidSpellSlot, got %v", err)
+	}
+}
+
+func TestCastSpellPactMagic(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("warlock", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Class = "Warlock"
+	c.Level = 5
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	hex, err := s.AddCharacterSpell(CharacterSpell{CharacterID: c.ID, Name: "Hex", Level: 1, Prepared: true})
+	if err != nil {
+		t.Fatalf("add spell: %v", err)
+	}
+
+	cast, err := s.CastSpell(c, hex.ID, 0, true)
+	if err != nil {
+		t.Fatalf("cast: %v", err)
+	}
+	if cast.SlotLevel != 3 || cast.Spellbook.PactSlots.Available != 1 {
+		t.Fatalf("expected a 3rd level pact slot to be spent, got %+v", cast)
+	}
+	if _, err := s.CastSpell(c, hex.ID, 0, false); err != ErrInvalidSpellSlot {
+		t.Fatalf("warlocks have no regular slots, got %v", err)
+	}
+}
+
+func TestCastSpellKnownCastersNeedNotPrepare(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	user, _ := s.CreateUser("sorcerer", "hash")
+	c := newTestCharacter()
+	c.UserID = user.ID
+	c.Class = "Sorcerer"
+	c.Level = 1
+	if err := s.CreateCharacter(c); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+
+	missile, err := s.AddCharacterSpell(CharacterSpell{CharacterID: c.ID, Name: "Magic Missile", Level: 1})
+	if err != nil {
+		t.Fatalf("add spell: %v", err)
+	}
+	if _, err := s.CastSpell(c, missile.ID, 0, false); err != nil {
+		t.Fatalf("sorcerers know their spells and should cast without preparing: %v", err)
+	}
+}
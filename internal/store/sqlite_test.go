@@ -78,7 +78,6 @@ func TestAddCharacterToCampaign_AllowsOwnerEditor(t *testing.T) {
 			SkillProficiencies:       "[]",
 			SavingThrowProficiencies: "[]",
 			Features:                 "[]",
-			Equipment:                "[]",
 		},
 	}
 
@@ -120,7 +119,6 @@ func TestAddCharacterToCampaign_ViewerForbidden(t *testing.T) {
 			SkillProficiencies:       "[]",
 			SavingThrowProficiencies: "[]",
 			Features:                 "[]",
-			Equipment:                "[]",
 		},
 	}
 	_ = s.CreateCharacter(character)
@@ -158,7 +156,6 @@ func TestAddCharacterToCampaign_OwnershipRequired(t *testing.T) {
 			SkillProficiencies:       "[]",
 			SavingThrowProficiencies: "[]",
 			Features:                 "[]",
-			Equipment:                "[]",
 		},
 	}
 	_ = s.CreateCharacter(foreignChar)
@@ -23,6 +23,74 @@ var ErrAlreadyMember = errors.New("user is already a member")
 var ErrCampaignMapNotFound = errors.New("campaign map not found")
 var ErrCampaignHandoutNotFound = errors.New("campaign handout not found")
 var ErrTokenNotFound = errors.New("token not found")
+var ErrInvalidRollVisibility = errors.New("invalid roll visibility")
+var ErrUnknownCheck = errors.New("unknown check")
+var ErrInvalidProficiencyLevel = errors.New("invalid proficiency level")
+var ErrInvalidSpell = errors.New("spell needs a name and a level from 0 to 9")
+var ErrSpellExists = errors.New("character already knows this spell")
+var ErrSpellNotFound = errors.New("spell not found")
+var ErrSpellNotPrepared = errors.New("spell is not prepared")
+var ErrInvalidSpellSlot = errors.New("invalid spell slot")
+var ErrNoSpellSlot = errors.New("no spell slots remaining at that level")
+var ErrItemNotFound = errors.New("item not found")
+var ErrInvalidItem = errors.New("item needs a name and non-negative quantity, weight and value")
+var ErrInvalidContainer = errors.New("invalid container")
+var ErrAttunementLimit = errors.New("a character can attune to at most 3 items")
+var ErrInvalidCoins = errors.New("coin amounts cannot be negative")
+var ErrMaxLevel = errors.New("character is already at the maximum level")
+var ErrInvalidHitPointMethod = errors.New(`hit points must be "average" or "roll"`)
+var ErrAbilityIncreaseRequired = errors.New("this level grants an ability score improvement: choose either ability increases or a feat")
+var ErrAbilityIncreaseNotAvailable = errors.New("this level does not grant an ability score improvement or feat")
+var ErrInvalidAbilityIncrease = errors.New("ability score improvement must add +2 to one ability or +1 to two, up to a maximum of 20")
+var ErrInvalidLevelUpFeature = errors.New("feature is not one the class or its subclass gains at this level")
+var ErrRevisionNotFound = errors.New("revision not found")
+var ErrPreconditionFailed = errors.New("character was changed by another session; reload and try again")
+var ErrInvalidRestType = errors.New(`rest type must be "short" or "long"`)
+var ErrNotEnoughHitDice = errors.New("not enough hit dice remaining")
+var ErrLongRestAtZeroHp = errors.New("a character needs at least 1 hit point to benefit from a long rest")
+var ErrInvalidHitPointChange = errors.New(`hit point change must be "damage", "heal", "temp" or "death-save" with a non-negative amount`)
+var ErrInvalidDamageType = errors.New("unknown damage type")
+var ErrCharacterDead = errors.New("character is dead")
+var ErrNotDying = errors.New("only a dying character makes death saving throws")
+var ErrInvalidCondition = errors.New("condition needs a known name, exhaustion a level from 1 to 6, and any duration a positive number of rounds or minutes")
+var ErrConditionNotFound = errors.New("condition not found")
+var ErrInvalidClasses = errors.New("classes need distinct names and levels of at least 1, totalling no more than 20")
+var ErrMulticlassPrerequisite = errors.New("multiclassing needs a score of 13 or higher in the primary ability of every class")
+var ErrClassesRequired = errors.New("a multiclass character needs its classes listed to change its starting class")
+var ErrInvalidResource = errors.New(`resource needs a name, a max formula such as "prof", "cha" or "level/2+1", no more uses spent than the max, and a reset of "short", "long" or "dawn"`)
+var ErrResourceExists = errors.New("character already has a resource with this name")
+var ErrResourceNotFound = errors.New("resource not found")
+var ErrInvalidAttack = errors.New("attack needs a name, a kind of melee, ranged or spell, valid damage dice and damage type, and known weapon properties; versatile weapons need versatile dice")
+var ErrAttackNotFound = errors.New("attack not found")
+var ErrInvalidArmor = errors.New("armor type must be light, medium, heavy or shield with a positive armor class")
+var ErrUnknownSpecies = errors.New("unknown species")
+var ErrUnknownClass = errors.New("unknown class")
+var ErrUnknownBackground = errors.New("unknown background")
+var ErrUnknownSubclass = errors.New("unknown subclass for this class")
+var ErrPackNotFound = errors.New("homebrew pack not found")
+var ErrPackVersion = errors.New("a pack with this name already has this version or a newer one")
+var ErrAbilityRollNotFound = errors.New("ability score roll not found")
+var ErrAbilityRollUsed = errors.New("ability score roll has already been used")
+var ErrLevelUpRequired = errors.New("level and max hit points can only change through a level up")
+var ErrAbilityScoresLocked = errors.New("ability scores can only change through an ability score improvement at level up")
+var ErrAbilityScoresNotFound = errors.New("no ability score generation recorded for this character")
+var ErrInvalidExport = errors.New("invalid character export")
+var ErrUnsupportedExportVersion = errors.New("character export is from a newer schema version")
+var ErrInvalidImportedStats = errors.New("imported character needs ability scores from 1 to 30, at least 1 maximum hit point, current hit points from 0 to the maximum, no negative temporary hit points and no more hit dice spent than its level")
+var ErrSceneNotFound = errors.New("scene not found")
+var ErrInvalidScene = errors.New("scene needs a name")
+var ErrInvalidSceneOrder = errors.New("scene order must list every scene in the campaign exactly once")
+var ErrInvalidToken = errors.New("token needs a label")
+var ErrInvalidTokenAudience = errors.New(`token audience must be "gm-only", "all", or entries such as "user:12" and "character:34"`)
+var ErrInvalidFog = errors.New("fog change needs either grid cells with non-negative coordinates or a polygon of at least three points")
+var ErrInvalidFogMode = errors.New(`fog mode must be "grid", "polygon" or empty to remove the fog`)
+var ErrFogTooDetailed = errors.New("revealed fog has too many points; reveal larger areas or reset the fog")
+var ErrFogModeMismatch = errors.New("fog change does not match the map's fog mode; reset the fog to change modes")
+var ErrInvalidTokenLayer = errors.New(`token layer must be "map", "object", "token" or "gm"`)
+var ErrWallNotFound = errors.New("wall not found")
+var ErrInvalidWall = errors.New(`wall needs two different points, a kind of "wall" or "door", and for doors a state of "open", "closed" or "locked"`)
+var ErrInvalidLightingMode = errors.New(`lighting mode must be "none" or "basic"`)
+var ErrInvalidVision = errors.New("vision and darkvision must be between 0 and 1000 feet")
 
 // Store wraps the sqlc Queries with convenience helpers and API-facing models.
 type Store struct {
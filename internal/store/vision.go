@@ -0,0 +1,254 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/geometry"
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+// maxVisionFt limits how far a token can be set to see.
+const maxVisionFt = 1000
+
+// ListWalls returns a map's walls and doors to the campaign's owner and editors.
+func (s *Store) ListWalls(mapID, userID int64) ([]models.Wall, error) {
+	if err := s.authorizeMapEdit(mapID, userID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.q.ListMapWalls(context.Background(), mapID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list walls: %w", err)
+	}
+	walls := make([]models.Wall, 0, len(rows))
+	for _, row := range rows {
+		walls = append(walls, layerToWall(row))
+	}
+	return walls, nil
+}
+
+// CreateWall adds a wall or door to a map. Doors start closed unless a state is given.
+func (s *Store) CreateWall(mapID, userID int64, wall models.Wall) (*models.Wall, error) {
+	if err := s.authorizeMapEdit(mapID, userID); err != nil {
+		return nil, err
+	}
+	if err := normalizeWall(&wall); err != nil {
+		return nil, err
+	}
+
+	row, err := s.q.InsertLayer(context.Background(), InsertLayerParams{
+		MapID: mapID,
+		Type:  wall.Kind,
+		Data:  wallData(wall),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wall: %w", err)
+	}
+	created := layerToWall(row)
+	return &created, nil
+}
+
+// UpdateWall moves a wall or door, or opens, closes or locks a door.
+func (s *Store) UpdateWall(wallID, userID int64, wall models.Wall) (*models.Wall, error) {
+	if _, err := s.authorizeWallEdit(wallID, userID); err != nil {
+		return nil, err
+	}
+	if err := normalizeWall(&wall); err != nil {
+		return nil, err
+	}
+
+	row, err := s.q.UpdateLayer(context.Background(), UpdateLayerParams{
+		Type: wall.Kind,
+		Data: wallData(wall),
+		ID:   wallID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update wall: %w", err)
+	}
+	updated := layerToWall(row)
+	return &updated, nil
+}
+
+// DeleteWall removes a wall or door from its map.
+func (s *Store) DeleteWall(wallID, userID int64) error {
+	if _, err := s.authorizeWallEdit(wallID, userID); err != nil {
+		return err
+	}
+
+	if err := s.q.DeleteLayer(context.Background(), wallID); err != nil {
+		return fmt.Errorf("failed to delete wall: %w", err)
+	}
+	return nil
+}
+
+// UpdateMapLighting switches a map between no lighting, where players see every token,
+// and basic lighting, where they only see what their tokens can see.
+func (s *Store) UpdateMapLighting(mapID, userID int64, mode string) error {
+	if mode != models.LightingNone && mode != models.LightingBasic {
+		return ErrInvalidLightingMode
+	}
+	if err := s.authorizeMapEdit(mapID, userID); err != nil {
+		return err
+	}
+
+	if err := s.q.UpdateMapLighting(context.Background(), UpdateMapLightingParams{LightingMode: mode, ID: mapID}); err != nil {
+		return fmt.Errorf("failed to update lighting: %w", err)
+	}
+	return nil
+}
+
+// UpdateTokenVision sets how far a token sees, in feet, if the actor can edit the
+// campaign.
+func (s *Store) UpdateTokenVision(tokenID, userID int64, visionFt, darkvisionFt int) (*models.Token, error) {
+	if err := s.authorizeTokenEdit(tokenID, userID); err != nil {
+		return nil, err
+	}
+	if visionFt < 0 || darkvisionFt < 0 || visionFt > maxVisionFt || darkvisionFt > maxVisionFt {
+		return nil, ErrInvalidVision
+	}
+
+	ctx := context.Background()
+	err := s.q.UpdateTokenVision(ctx, UpdateTokenVisionParams{
+		VisionFt:     int64(visionFt),
+		DarkvisionFt: int64(darkvisionFt),
+		ID:           tokenID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update token vision: %w", err)
+	}
+
+	return s.getToken(ctx, tokenID)
+}
+
+// authorizeWallEdit checks that the wall exists and that the actor is an accepted owner
+// or editor of its map's campaign.
+func (s *Store) authorizeWallEdit(wallID, userID int64) (*Layer, error) {
+	row, err := s.q.GetLayer(context.Background(), wallID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWallNotFound
+		}
+		return nil, fmt.Errorf("failed to get wall: %w", err)
+	}
+	if row.Type != models.WallKindWall && row.Type != models.WallKindDoor {
+		return nil, ErrWallNotFound
+	}
+	if err := s.authorizeMapEdit(row.MapID, userID); err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func normalizeWall(w *models.Wall) error {
+	for _, v := range []float64{w.A.X, w.A.Y, w.B.X, w.B.Y} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ErrInvalidWall
+		}
+	}
+	if w.A == w.B {
+		return ErrInvalidWall
+	}
+	switch w.Kind {
+	case models.WallKindWall:
+		w.State = ""
+	case models.WallKindDoor:
+		switch w.State {
+		case "":
+			w.State = models.DoorClosed
+		case models.DoorOpen, models.DoorClosed, models.DoorLocked:
+		default:
+			return ErrInvalidWall
+		}
+	default:
+		return ErrInvalidWall
+	}
+	return nil
+}
+
+// wallLayerData is how a wall is kept in layers.data.
+type wallLayerData struct {
+	A     geometry.Point `json:"a"`
+	B     geometry.Point `json:"b"`
+	State string         `json:"state,omitempty"`
+}
+
+func wallData(w models.Wall) string {
+	data, err := json.Marshal(wallLayerData{A: w.A, B: w.B, State: w.State})
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+func layerToWall(row Layer) models.Wall {
+	var data wallLayerData
+	_ = json.Unmarshal([]byte(row.Data), &data)
+	return models.Wall{
+		ID:    row.ID,
+		MapID: row.MapID,
+		Kind:  row.Type,
+		A:     data.A,
+		B:     data.B,
+		State: data.State,
+	}
+}
+
+// playerSight is what a player's tokens can see on a map with basic lighting.
+type playerSight struct {
+	area geometry.Region
+}
+
+// loadPlayerSight works out the area seen by the viewer's tokens on the map. Each token
+// sees from its centre as far as the farther of its vision and darkvision, and walls and
+// closed or locked doors block its sight.
+func (s *Store) loadPlayerSight(ctx context.Context, mapID, gridSizeFt int64, viewer *tokenViewer, tokens []models.Token) (*playerSight, error) {
+	rows, err := s.q.ListMapWalls(ctx, mapID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list walls: %w", err)
+	}
+	var walls []geometry.Segment
+	for _, row := range rows {
+		if w := layerToWall(row); w.BlocksSight() {
+			walls = append(walls, geometry.Segment{A: w.A, B: w.B})
+		}
+	}
+	if gridSizeFt <= 0 {
+		gridSizeFt = 5
+	}
+
+	sight := &playerSight{}
+	for i := range tokens {
+		t := &tokens[i]
+		if !viewer.owns(t) {
+			continue
+		}
+		rangeFt := max(t.VisionFt, t.DarkvisionFt)
+		if rangeFt <= 0 {
+			continue
+		}
+		half := float64(max(t.SizeSquares, 1)) / 2
+		origin := geometry.Point{X: float64(t.PositionX) + half, Y: float64(t.PositionY) + half}
+		seen := geometry.Sight(origin, walls, float64(rangeFt)/float64(gridSizeFt))
+		sight.area = geometry.Union(sight.area, seen)
+	}
+	return sight, nil
+}
+
+// sees reports whether the centre of any square the token covers is in sight.
+func (p *playerSight) sees(t *models.Token) bool {
+	size := max(t.SizeSquares, 1)
+	for dy := 0; dy < size; dy++ {
+		for dx := 0; dx < size; dx++ {
+			centre := geometry.Point{X: float64(t.PositionX+dx) + 0.5, Y: float64(t.PositionY+dy) + 0.5}
+			if p.area.Contains(centre) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/jasoncabot/dicewizard-characters/internal/geometry"
+	"github.com/jasoncabot/dicewizard-characters/internal/models"
+)
+
+func TestVision_WallsAndDoorsBlockSight(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	owner, player, camp, m := setupFogMap(t, s)
+
+	hero := newTestCharacter()
+	hero.UserID = player.ID
+	if err := s.CreateCharacter(hero); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	heroToken, err := s.CreateToken(m.ID, owner.ID, &hero.ID, "Hero", "", 1, 0, 0, 0, nil, nil, "")
+	if err != nil {
+		t.Fatalf("create hero: %v", err)
+	}
+	if _, err := s.CreateToken(m.ID, owner.ID, nil, "Goblin", "", 1, 4, 0, 0, []string{"all"}, nil, ""); err != nil {
+		t.Fatalf("create goblin: %v", err)
+	}
+	if _, err := s.CreateToken(m.ID, owner.ID, nil, "Dragon", "", 1, 20, 0, 0, []string{"all"}, nil, ""); err != nil {
+		t.Fatalf("create dragon: %v", err)
+	}
+
+	if err := s.UpdateMapLighting(m.ID, owner.ID, "dim"); err != ErrInvalidLightingMode {
+		t.Fatalf("expected ErrInvalidLightingMode, got %v", err)
+	}
+	if err := s.UpdateMapLighting(m.ID, player.ID, models.LightingBasic); err != ErrNotPermitted {
+		t.Fatalf("player expected ErrNotPermitted, got %v", err)
+	}
+	if err := s.UpdateMapLighting(m.ID, owner.ID, models.LightingBasic); err != nil {
+		t.Fatalf("lighting: %v", err)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 1 || got[0] != "Hero" {
+		t.Fatalf("a token without vision should only show itself: %v", got)
+	}
+
+	if _, err := s.UpdateTokenVision(heroToken.ID, owner.ID, -5, 0); err != ErrInvalidVision {
+		t.Fatalf("expected ErrInvalidVision, got %v", err)
+	}
+	updated, err := s.UpdateTokenVision(heroToken.ID, owner.ID, 30, 60)
+	if err != nil {
+		t.Fatalf("vision: %v", err)
+	}
+	if updated.VisionFt != 30 || updated.DarkvisionFt != 60 {
+		t.Fatalf("unexpected vision: %+v", updated)
+	}
+	// Darkvision of 60ft reaches 12 squares: the goblin is in range, the dragon is not.
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 2 || got[1] != "Goblin" {
+		t.Fatalf("hero should see the goblin only: %v", got)
+	}
+
+	if _, err := s.CreateWall(m.ID, player.ID, models.Wall{Kind: models.WallKindWall, A: geometry.Point{X: 2, Y: -5}, B: geometry.Point{X: 2, Y: 5}}); err != ErrNotPermitted {
+		t.Fatalf("player expected ErrNotPermitted, got %v", err)
+	}
+	if _, err := s.CreateWall(m.ID, owner.ID, models.Wall{Kind: models.WallKindDoor, A: geometry.Point{X: 2, Y: 0}, B: geometry.Point{X: 2, Y: 0}}); err != ErrInvalidWall {
+		t.Fatalf("expected ErrInvalidWall, got %v", err)
+	}
+	door, err := s.CreateWall(m.ID, owner.ID, models.Wall{Kind: models.WallKindDoor, A: geometry.Point{X: 2, Y: -5}, B: geometry.Point{X: 2, Y: 5}})
+	if err != nil {
+		t.Fatalf("create door: %v", err)
+	}
+	if door.State != models.DoorClosed {
+		t.Fatalf("doors should start closed: %+v", door)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 1 {
+		t.Fatalf("closed door should hide the goblin: %v", got)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, owner.ID); len(got) != 3 {
+		t.Fatalf("GM should see every token: %v", got)
+	}
+
+	door.State = models.DoorOpen
+	if _, err := s.UpdateWall(door.ID, owner.ID, *door); err != nil {
+		t.Fatalf("open door: %v", err)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 2 {
+		t.Fatalf("open door should show the goblin: %v", got)
+	}
+
+	walls, err := s.ListWalls(m.ID, owner.ID)
+	if err != nil || len(walls) != 1 || walls[0].State != models.DoorOpen {
+		t.Fatalf("unexpected walls %+v (%v)", walls, err)
+	}
+	if err := s.DeleteWall(door.ID, player.ID); err != ErrNotPermitted {
+		t.Fatalf("player expected ErrNotPermitted, got %v", err)
+	}
+	if err := s.DeleteWall(door.ID, owner.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.DeleteWall(door.ID, owner.ID); err != ErrWallNotFound {
+		t.Fatalf("expected ErrWallNotFound, got %v", err)
+	}
+
+	if err := s.UpdateMapLighting(m.ID, owner.ID, models.LightingNone); err != nil {
+		t.Fatalf("lighting off: %v", err)
+	}
+	if got := playerTokenLabels(t, s, camp.ID, player.ID); len(got) != 3 {
+		t.Fatalf("without lighting the player should see every token: %v", got)
+	}
+}
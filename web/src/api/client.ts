@@ -1,4 +1,5 @@
 import type {
+  AbilityRoll,
   Character,
   CharacterCreate,
   User,
@@ -105,10 +106,39 @@ export const charactersApi = {
       body: JSON.stringify(data),
     }),
 
-  update: (id: number, data: Partial<CharacterCreate>): Promise<Character> =>
+  // Six 4d6-drop-lowest rolls made by the server, claimed by the character created
+  // with its id as abilityRollId.
+  rollAbilityScores: (): Promise<AbilityRoll> =>
+    request("/characters/ability-rolls", {
+      method: "POST",
+    }),
+
+  // Pass the character's updatedAt as the version to fail with 412 instead of
+  // overwriting changes saved elsewhere since it was loaded.
+  update: (
+    id: number,
+    data: Partial<CharacterCreate>,
+    version?: string,
+  ): Promise<Character> =>
     request(`/characters/${id}`, {
       method: "PUT",
       body: JSON.stringify(data),
+      headers: version ? { "If-Match": `"${version}"` } : undefined,
+    }),
+
+  // JSON Merge Patch: only the fields present are changed, null resets a field.
+  patch: (
+    id: number,
+    data: Partial<CharacterCreate>,
+    version?: string,
+  ): Promise<Character> =>
+    request(`/characters/${id}`, {
+      method: "PATCH",
+      body: JSON.stringify(data),
+      headers: {
+        "Content-Type": "application/merge-patch+json",
+        ...(version && { "If-Match": `"${version}"` }),
+      },
     }),
 
   uploadAvatar: (id: number, file: File): Promise<Character> => {
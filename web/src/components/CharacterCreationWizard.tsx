@@ -5,6 +5,7 @@ import { charactersApi } from "../api/client";
 import type {
   CharacterCreate,
   Ability,
+  AbilityScoreMethod,
   SkillName,
   Species,
   ClassName,
@@ -13,13 +14,13 @@ import type {
 } from "../types/character";
 import {
   ABILITIES,
+  BACKGROUND_ABILITIES,
   CLASS_HIT_DICE,
   CLASS_SAVING_THROWS,
   SPECIES_TRAITS,
   POINT_BUY_COSTS,
   POINT_BUY_TOTAL,
   calculateModifier,
-  rollAbilityScore,
 } from "../types/character";
 import { WIZARD_STEPS, type WizardStep } from "../data/characterData";
 import type { AbilityMethod, AbilityScores } from "./character-sheet/types";
@@ -48,6 +49,30 @@ const defaultScores: AbilityScores = {
   charisma: 10,
 };
 
+// The server's name for each way of generating ability scores
+const ABILITY_SCORE_METHODS: Record<AbilityMethod, AbilityScoreMethod> = {
+  standard: "standard-array",
+  pointbuy: "point-buy",
+  roll: "rolled",
+};
+
+// +2 and +1 to the first two of the background's abilities until the player picks others
+const defaultIncreases = (
+  background: BackgroundName,
+): Partial<Record<Ability, number>> => {
+  const [first, second] = BACKGROUND_ABILITIES[background];
+  return { [first]: 2, [second]: 1 };
+};
+
+// A background gives +2 and +1 to two of its abilities, or +1 to all three
+const isValidIncrease = (increases: Partial<Record<Ability, number>>) => {
+  const amounts = Object.values(increases)
+    .filter((amount) => (amount ?? 0) > 0)
+    .sort()
+    .join(",");
+  return amounts === "1,2" || amounts === "1,1,1";
+};
+
 export function CharacterCreationWizard({
   onBack,
   onSaved,
@@ -82,6 +107,19 @@ export function CharacterCreationWizard({
   const [rolledScores, setRolledScores] = useState<
     { rolls: number[]; total: number }[]
   >([]);
+  const [abilityRollId, setAbilityRollId] = useState<number | null>(null);
+  const [abilityIncreases, setAbilityIncreases] = useState<
+    Partial<Record<Ability, number>>
+  >(() => defaultIncreases("Soldier"));
+
+  // The scores the character will have: the base scores plus the background's increases
+  const finalScores = useMemo(() => {
+    const scores = { ...abilityScores };
+    ABILITIES.forEach((ability) => {
+      scores[ability] += abilityIncreases[ability] ?? 0;
+    });
+    return scores;
+  }, [abilityScores, abilityIncreases]);
 
   // Proficiencies
   const [skillProficiencies, setSkillProficiencies] = useState<SkillName[]>([]);
@@ -94,9 +132,9 @@ export function CharacterCreationWizard({
 
   const derivedStats = useMemo(() => {
     const hitDie = CLASS_HIT_DICE[selectedClass] || 8;
-    const conMod = calculateModifier(abilityScores.constitution);
+    const conMod = calculateModifier(finalScores.constitution);
     const hp = Math.max(1, hitDie + conMod);
-    const dexMod = calculateModifier(abilityScores.dexterity);
+    const dexMod = calculateModifier(finalScores.dexterity);
     const speed = SPECIES_TRAITS[selectedSpecies]?.speed || 30;
 
     return {
@@ -106,7 +144,7 @@ export function CharacterCreationWizard({
       armorClass: 10 + dexMod,
       speed,
     };
-  }, [selectedClass, selectedSpecies, abilityScores]);
+  }, [selectedClass, selectedSpecies, finalScores]);
 
   // Build character data for steps that need it
   const characterData = useMemo<Partial<CharacterCreate>>(
@@ -160,6 +198,18 @@ export function CharacterCreationWizard({
     }
   }, [currentStepIndex, isFirstStep, navigateToStep]);
 
+  const selectBackground = useCallback((background: BackgroundName) => {
+    setSelectedBackground(background);
+    setAbilityIncreases(defaultIncreases(background));
+  }, []);
+
+  const updateAbilityIncrease = useCallback(
+    (ability: Ability, amount: number) => {
+      setAbilityIncreases((prev) => ({ ...prev, [ability]: amount }));
+    },
+    [],
+  );
+
   const updateCharacterData = useCallback(
     <K extends keyof CharacterCreate>(key: K, value: CharacterCreate[K]) => {
       switch (key) {
@@ -173,14 +223,14 @@ export function CharacterCreationWizard({
           setSelectedClass(value as ClassName);
           break;
         case "background":
-          setSelectedBackground(value as BackgroundName);
+          selectBackground(value as BackgroundName);
           break;
         case "alignment":
           setSelectedAlignment(value as Alignment);
           break;
       }
     },
-    [],
+    [selectBackground],
   );
 
   // Standard array logic
@@ -222,16 +272,30 @@ export function CharacterCreationWizard({
     [applyStandardAssignments, standardArrayAssignments],
   );
 
-  // Roll ability scores
+  // Roll ability scores on the server, which checks the character uses this roll
+  const rollMutation = useMutation({
+    mutationFn: () => charactersApi.rollAbilityScores(),
+    onSuccess: (roll) => {
+      setAbilityRollId(roll.id);
+      setRolledScores(
+        roll.rolls.map((result) => ({
+          rolls: result.terms.flatMap(
+            (term) => term.dice?.map((die) => die.value) ?? [],
+          ),
+          total: result.total,
+        })),
+      );
+      const newScores = { ...defaultScores };
+      ABILITIES.forEach((ability, index) => {
+        newScores[ability] = roll.scores[index];
+      });
+      setAbilityScores(newScores);
+    },
+  });
+
   const handleRollAll = useCallback(() => {
-    const newRolls = ABILITIES.map(() => rollAbilityScore());
-    setRolledScores(newRolls);
-    const newScores = { ...defaultScores };
-    ABILITIES.forEach((ability, index) => {
-      newScores[ability] = newRolls[index].total;
-    });
-    setAbilityScores(newScores);
-  }, []);
+    rollMutation.mutate();
+  }, [rollMutation]);
 
   // Point buy logic
   const pointBuyCost = Object.values(abilityScores).reduce(
@@ -273,6 +337,10 @@ export function CharacterCreationWizard({
       alignment: selectedAlignment,
       experiencePoints: 0,
       ...abilityScores,
+      abilityScoreMethod: ABILITY_SCORE_METHODS[abilityMethod],
+      abilityRollId:
+        abilityMethod === "roll" ? (abilityRollId ?? undefined) : undefined,
+      abilityIncreases,
       ...derivedStats,
       tempHp: 0,
       skillProficiencies,
@@ -289,6 +357,9 @@ export function CharacterCreationWizard({
     selectedBackground,
     selectedAlignment,
     abilityScores,
+    abilityMethod,
+    abilityRollId,
+    abilityIncreases,
     derivedStats,
     skillProficiencies,
     savingThrowProficiencies,
@@ -305,6 +376,13 @@ export function CharacterCreationWizard({
       case "background":
         return !!selectedBackground;
       case "abilities":
+        if (!isValidIncrease(abilityIncreases)) return false;
+        if (abilityMethod === "roll") return abilityRollId !== null;
+        if (abilityMethod === "standard") {
+          return Object.values(standardArrayAssignments).every(
+            (value) => value !== null,
+          );
+        }
         return Object.values(abilityScores).every((score) => score >= 8);
       case "details":
         return characterName.trim().length > 0;
@@ -319,6 +397,10 @@ export function CharacterCreationWizard({
     selectedClass,
     selectedBackground,
     abilityScores,
+    abilityMethod,
+    abilityRollId,
+    abilityIncreases,
+    standardArrayAssignments,
     characterName,
   ]);
 
@@ -342,7 +424,7 @@ export function CharacterCreationWizard({
         return (
           <BackgroundStep
             selectedBackground={selectedBackground}
-            onSelect={(bg: BackgroundName) => setSelectedBackground(bg)}
+            onSelect={selectBackground}
           />
         );
       case "abilities":
@@ -356,8 +438,12 @@ export function CharacterCreationWizard({
             onStandardArrayAssignment={updateStandardArrayAssignment}
             rolledScores={rolledScores}
             handleRollAll={handleRollAll}
+            isRolling={rollMutation.isPending}
             adjustPointBuy={adjustPointBuy}
             pointsRemaining={pointsRemaining}
+            backgroundAbilities={BACKGROUND_ABILITIES[selectedBackground]}
+            abilityIncreases={abilityIncreases}
+            onAbilityIncreaseChange={updateAbilityIncrease}
           />
         );
       case "details":
@@ -367,14 +453,14 @@ export function CharacterCreationWizard({
             updateCharacterData={updateCharacterData}
             skillProficiencies={skillProficiencies}
             setSkillProficiencies={setSkillProficiencies}
-            abilityScores={abilityScores}
+            abilityScores={finalScores}
           />
         );
       case "review":
         return (
           <ReviewStep
             characterData={characterData}
-            abilityScores={abilityScores}
+            abilityScores={finalScores}
             skillProficiencies={skillProficiencies}
             savingThrowProficiencies={savingThrowProficiencies}
             onEditStep={navigateToStep}
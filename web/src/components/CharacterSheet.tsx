@@ -33,6 +33,9 @@ interface CharacterSheetProps {
   onSaved: () => void;
 }
 
+const sameEntries = (a: readonly string[], b: readonly string[]) =>
+  a.length === b.length && a.every((entry) => b.includes(entry));
+
 const defaultScores: AbilityScores = {
   strength: 10,
   dexterity: 10,
@@ -123,6 +126,8 @@ export function CharacterSheet({
           currentHp: character.currentHp,
           tempHp: character.tempHp,
           armorClass: character.armorClass,
+          armorClassOverride: character.armorClassOverride,
+          armorClassBonus: character.armorClassBonus,
           speed: character.speed,
           hitDice: character.hitDice,
           skillProficiencies: character.skillProficiencies,
@@ -148,6 +153,8 @@ export function CharacterSheet({
           currentHp: 10,
           tempHp: 0,
           armorClass: 10,
+          armorClassOverride: false,
+          armorClassBonus: 0,
           speed: 30,
           hitDice: "1d10",
           skillProficiencies: [],
@@ -177,7 +184,23 @@ export function CharacterSheet({
     (useWatch({ control, name: "alignment" }) as
       | CharacterCreate["alignment"]
       | undefined) || "True Neutral";
+  const armorClassOverride =
+    (useWatch({ control, name: "armorClassOverride" }) as
+      | CharacterCreate["armorClassOverride"]
+      | undefined) ?? false;
   const proficiencyBonus = calculateProficiencyBonus(level);
+  // Show the server's derived numbers until something that feeds them is edited
+  const savedStats =
+    character &&
+    level === character.level &&
+    selectedClass === character.class &&
+    ABILITIES.every(
+      (ability) => abilityScores[ability] === character[ability],
+    ) &&
+    sameEntries(skillProficiencies, character.skillProficiencies) &&
+    sameEntries(savingThrowProficiencies, character.savingThrowProficiencies)
+      ? character
+      : undefined;
   const currentCharacterId = character?.id ?? null;
   const avatarUrl =
     avatarOverride.characterId === currentCharacterId
@@ -313,7 +336,7 @@ export function CharacterSheet({
 
   const updateMutation = useMutation({
     mutationFn: (data: CharacterCreate) =>
-      charactersApi.update(character!.id, data),
+      charactersApi.update(character!.id, data, character!.updatedAt),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ["characters"] });
       onSaved();
@@ -392,6 +415,7 @@ export function CharacterSheet({
           avatarUrl={avatarUrl}
           onAvatarUpload={character ? handleAvatarUpload : undefined}
           isUploadingAvatar={avatarUploadMutation.status === "pending"}
+          isEditing={isEditing}
         />
 
         <AbilityScoresSection
@@ -399,7 +423,6 @@ export function CharacterSheet({
           abilityMethod={abilityMethod}
           onAbilityMethodChange={handleAbilityMethodChange}
           abilityScores={abilityScores}
-          setAbilityScores={setAbilityScores}
           standardArrayAssignments={standardArrayAssignments}
           onStandardArrayAssignment={updateStandardArrayAssignment}
           rolledScores={rolledScores}
@@ -410,9 +433,13 @@ export function CharacterSheet({
 
         <CombatStatsSection
           register={register}
+          isEditing={isEditing}
+          armorClassOverride={armorClassOverride}
           abilityScores={abilityScores}
           proficiencyBonus={proficiencyBonus}
           skillProficiencies={skillProficiencies}
+          initiative={savedStats?.initiative}
+          passivePerception={savedStats?.passivePerception}
         />
 
         <SavingThrowsSection
@@ -420,6 +447,7 @@ export function CharacterSheet({
           savingThrowProficiencies={savingThrowProficiencies}
           setSavingThrowProficiencies={setSavingThrowProficiencies}
           proficiencyBonus={proficiencyBonus}
+          savingThrows={savedStats?.savingThrows}
         />
 
         <SkillsSection
@@ -427,6 +455,8 @@ export function CharacterSheet({
           skillProficiencies={skillProficiencies}
           setSkillProficiencies={setSkillProficiencies}
           proficiencyBonus={proficiencyBonus}
+          skillBonuses={savedStats?.skillBonuses}
+          skillLevels={savedStats?.skillLevels}
         />
 
         {/* Error and Submit */}
@@ -1,6 +1,5 @@
 import {
   Button,
-  Input,
   Listbox,
   ListboxButton,
   ListboxOptions,
@@ -29,9 +28,6 @@ interface AbilityScoresSectionProps {
   abilityMethod: AbilityMethod;
   onAbilityMethodChange: (method: AbilityMethod) => void;
   abilityScores: AbilityScores;
-  setAbilityScores: (
-    updater: AbilityScores | ((prev: AbilityScores) => AbilityScores),
-  ) => void;
   standardArrayAssignments: Record<Ability, number | null>;
   onStandardArrayAssignment: (ability: Ability, value: number | null) => void;
   rolledScores: { rolls: number[]; total: number }[];
@@ -45,7 +41,6 @@ export function AbilityScoresSection({
   abilityMethod,
   onAbilityMethodChange,
   abilityScores,
-  setAbilityScores,
   standardArrayAssignments,
   onStandardArrayAssignment,
   rolledScores,
@@ -210,20 +205,8 @@ export function AbilityScoresSection({
               </div>
 
               {isEditing ? (
-                <Input
-                  type="number"
-                  value={score}
-                  onChange={(event) => {
-                    const nextValue = parseInt(event.target.value, 10) || 8;
-                    setAbilityScores((prev) => ({
-                      ...prev,
-                      [ability]: nextValue,
-                    }));
-                  }}
-                  className="w-full cursor-text rounded border border-slate-600 bg-slate-800 px-2 py-1 text-center text-white shadow-sm transition hover:border-slate-500 focus:ring-2 focus:ring-purple-500 focus:outline-none"
-                  min={1}
-                  max={30}
-                />
+                // Scores only change through ability score improvements at level up
+                <div className="text-lg font-bold text-white">{score}</div>
               ) : abilityMethod === "standard" ? (
                 renderStandardArrayOptions(ability)
               ) : abilityMethod === "pointbuy" ? (
@@ -45,6 +45,8 @@ interface BasicInfoSectionProps {
   avatarUrl?: string;
   onAvatarUpload?: (file: File) => void;
   isUploadingAvatar?: boolean;
+  // Saved characters change level through level up, not the form
+  isEditing?: boolean;
 }
 
 export function BasicInfoSection({
@@ -61,6 +63,7 @@ export function BasicInfoSection({
   avatarUrl,
   onAvatarUpload,
   isUploadingAvatar,
+  isEditing,
 }: BasicInfoSectionProps) {
   const fileInputRef = useRef<HTMLInputElement | null>(null);
   const portraitSrc =
@@ -258,6 +261,7 @@ export function BasicInfoSection({
               className="w-full cursor-text rounded-lg border border-slate-600 bg-slate-900/50 px-3 py-2 text-sm text-white shadow-sm transition hover:border-slate-500 focus:ring-2 focus:ring-purple-500 focus:outline-none"
               min={1}
               max={20}
+              readOnly={isEditing}
             />
           </Field>
 
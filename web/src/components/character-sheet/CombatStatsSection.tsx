@@ -6,22 +6,35 @@ import type { AbilityScores } from "./types";
 
 interface CombatStatsSectionProps {
   register: UseFormRegister<CharacterCreate>;
+  // Max HP of a saved character changes through level up, not the form
+  isEditing?: boolean;
+  // Without the override the server works out armor class from equipped armor
+  armorClassOverride: boolean;
   abilityScores: AbilityScores;
   proficiencyBonus: number;
   skillProficiencies: string[];
+  // Saved values from the server; only passed while the sheet matches the saved character
+  initiative?: number;
+  passivePerception?: number;
 }
 
 export function CombatStatsSection({
   register,
+  isEditing,
+  armorClassOverride,
   abilityScores,
   proficiencyBonus,
   skillProficiencies,
+  initiative,
+  passivePerception,
 }: CombatStatsSectionProps) {
-  const dexterityModifier = calculateModifier(abilityScores.dexterity);
-  const passivePerception =
+  const initiativeBonus =
+    initiative ?? calculateModifier(abilityScores.dexterity);
+  const perception =
+    passivePerception ??
     10 +
-    calculateModifier(abilityScores.wisdom) +
-    (skillProficiencies.includes("Perception") ? proficiencyBonus : 0);
+      calculateModifier(abilityScores.wisdom) +
+      (skillProficiencies.includes("Perception") ? proficiencyBonus : 0);
 
   return (
     <section className="rounded-xl border border-slate-700/50 bg-slate-800/50 p-6 backdrop-blur-sm">
@@ -48,6 +61,7 @@ export function CombatStatsSection({
             {...register("maxHp", { valueAsNumber: true, min: 1 })}
             className="w-full cursor-text rounded border border-slate-600 bg-slate-800 px-2 py-2 text-center text-xl font-bold text-white shadow-sm transition hover:border-slate-500 focus:ring-2 focus:ring-purple-500 focus:outline-none"
             min={1}
+            readOnly={isEditing}
           />
         </div>
 
@@ -72,7 +86,25 @@ export function CombatStatsSection({
             {...register("armorClass", { valueAsNumber: true, min: 0 })}
             className="w-full cursor-text rounded border border-slate-600 bg-slate-800 px-2 py-2 text-center text-xl font-bold text-white shadow-sm transition hover:border-slate-500 focus:ring-2 focus:ring-purple-500 focus:outline-none"
             min={0}
+            readOnly={!armorClassOverride}
           />
+          <label className="mt-2 flex cursor-pointer items-center justify-center gap-1 text-xs text-slate-400">
+            <input
+              type="checkbox"
+              {...register("armorClassOverride")}
+              className="accent-purple-500"
+            />
+            Set by hand
+          </label>
+          <label className="mt-1 flex items-center justify-center gap-1 text-xs text-slate-400">
+            Bonus
+            <Input
+              type="number"
+              {...register("armorClassBonus", { valueAsNumber: true })}
+              readOnly={armorClassOverride}
+              className="w-12 cursor-text rounded border border-slate-600 bg-slate-800 px-1 py-0.5 text-center text-xs text-white shadow-sm transition hover:border-slate-500 focus:ring-2 focus:ring-purple-500 focus:outline-none read-only:opacity-50"
+            />
+          </label>
         </div>
 
         <div className="rounded-xl border border-slate-700/50 bg-slate-900/50 p-4 text-center">
@@ -95,7 +127,7 @@ export function CombatStatsSection({
         <div className="flex items-center justify-between rounded-lg bg-slate-900/30 p-3">
           <span className="cursor-default text-slate-400">Initiative</span>
           <span className="cursor-default text-xl font-bold text-white">
-            {formatModifier(dexterityModifier)}
+            {formatModifier(initiativeBonus)}
           </span>
         </div>
         <div className="flex items-center justify-between rounded-lg bg-slate-900/30 p-3">
@@ -103,7 +135,7 @@ export function CombatStatsSection({
             Passive Perception
           </span>
           <span className="cursor-default text-xl font-bold text-white">
-            {passivePerception}
+            {perception}
           </span>
         </div>
       </div>
@@ -13,6 +13,8 @@ interface SavingThrowsSectionProps {
   savingThrowProficiencies: Ability[];
   setSavingThrowProficiencies: React.Dispatch<React.SetStateAction<Ability[]>>;
   proficiencyBonus: number;
+  // Saved totals from the server; only passed while the sheet matches the saved character
+  savingThrows?: Record<Ability, number>;
 }
 
 export function SavingThrowsSection({
@@ -20,6 +22,7 @@ export function SavingThrowsSection({
   savingThrowProficiencies,
   setSavingThrowProficiencies,
   proficiencyBonus,
+  savingThrows,
 }: SavingThrowsSectionProps) {
   const toggleProficiency = (ability: Ability, checked: boolean) => {
     if (checked) {
@@ -38,7 +41,9 @@ export function SavingThrowsSection({
         {ABILITIES.map((ability) => {
           const isProficient = savingThrowProficiencies.includes(ability);
           const modifier = calculateModifier(abilityScores[ability]);
-          const total = modifier + (isProficient ? proficiencyBonus : 0);
+          const total =
+            savingThrows?.[ability] ??
+            modifier + (isProficient ? proficiencyBonus : 0);
 
           return (
             <Switch
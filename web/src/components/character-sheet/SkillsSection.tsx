@@ -4,8 +4,9 @@ import {
   SKILLS,
   calculateModifier,
   formatModifier,
+  skillKey,
 } from "../../types/character";
-import type { SkillName } from "../../types/character";
+import type { ProficiencyLevel, SkillName } from "../../types/character";
 import type { AbilityScores } from "./types";
 
 interface SkillsSectionProps {
@@ -13,6 +14,9 @@ interface SkillsSectionProps {
   skillProficiencies: SkillName[];
   setSkillProficiencies: React.Dispatch<React.SetStateAction<SkillName[]>>;
   proficiencyBonus: number;
+  // Saved totals from the server; only passed while the sheet matches the saved character
+  skillBonuses?: Record<string, number>;
+  skillLevels?: Record<string, ProficiencyLevel>;
 }
 
 export function SkillsSection({
@@ -20,6 +24,8 @@ export function SkillsSection({
   skillProficiencies,
   setSkillProficiencies,
   proficiencyBonus,
+  skillBonuses,
+  skillLevels,
 }: SkillsSectionProps) {
   const toggleProficiency = (skill: SkillName, checked: boolean) => {
     if (checked) {
@@ -38,7 +44,11 @@ export function SkillsSection({
         {Object.entries(SKILLS).map(([skill, ability]) => {
           const isProficient = skillProficiencies.includes(skill as SkillName);
           const modifier = calculateModifier(abilityScores[ability]);
-          const total = modifier + (isProficient ? proficiencyBonus : 0);
+          const key = skillKey(skill as SkillName);
+          const total =
+            skillBonuses?.[key] ??
+            modifier + (isProficient ? proficiencyBonus : 0);
+          const level = skillLevels?.[key];
 
           return (
             <Switch
@@ -67,7 +77,15 @@ export function SkillsSection({
                   />
                 </svg>
               </span>
-              <span className="flex-1 text-sm text-slate-300">{skill}</span>
+              <span className="flex-1 text-sm text-slate-300">
+                {skill}
+                {level === "expertise" && (
+                  <span className="ml-1 text-xs text-purple-300">(E)</span>
+                )}
+                {level === "half" && (
+                  <span className="ml-1 text-xs text-slate-500">(½)</span>
+                )}
+              </span>
               <span className="text-xs text-slate-500 uppercase">
                 ({ability.slice(0, 3)})
               </span>
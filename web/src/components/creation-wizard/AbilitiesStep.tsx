@@ -28,8 +28,12 @@ interface AbilitiesStepProps {
   onStandardArrayAssignment: (ability: Ability, value: number | null) => void;
   rolledScores: { rolls: number[]; total: number }[];
   handleRollAll: () => void;
+  isRolling?: boolean;
   adjustPointBuy: (ability: Ability, delta: number) => void;
   pointsRemaining: number;
+  backgroundAbilities: Ability[];
+  abilityIncreases: Partial<Record<Ability, number>>;
+  onAbilityIncreaseChange: (ability: Ability, amount: number) => void;
   isTransitioning?: boolean;
 }
 
@@ -60,8 +64,12 @@ export function AbilitiesStep({
   onStandardArrayAssignment,
   rolledScores,
   handleRollAll,
+  isRolling,
   adjustPointBuy,
   pointsRemaining,
+  backgroundAbilities,
+  abilityIncreases,
+  onAbilityIncreaseChange,
 }: AbilitiesStepProps) {
   return (
     <div className="space-y-6">
@@ -135,10 +143,20 @@ export function AbilitiesStep({
             abilityScores={abilityScores}
             rolledScores={rolledScores}
             handleRollAll={handleRollAll}
+            isRolling={isRolling}
           />
         )}
       </div>
 
+      {/* Background Increases */}
+      <div className="rounded-2xl border border-slate-700/50 bg-gradient-to-br from-slate-800/80 to-slate-900/80 p-6">
+        <BackgroundIncreasesUI
+          backgroundAbilities={backgroundAbilities}
+          abilityIncreases={abilityIncreases}
+          onAbilityIncreaseChange={onAbilityIncreaseChange}
+        />
+      </div>
+
       {/* Ability Score Summary */}
       <div className="rounded-2xl border border-slate-700/50 bg-gradient-to-br from-slate-800/80 to-slate-900/80 p-6">
         <h3 className="mb-4 text-lg font-semibold text-white">
@@ -146,7 +164,8 @@ export function AbilitiesStep({
         </h3>
         <div className="grid grid-cols-2 gap-3 sm:grid-cols-3 lg:grid-cols-6">
           {ABILITIES.map((ability) => {
-            const score = abilityScores[ability];
+            const increase = abilityIncreases[ability] ?? 0;
+            const score = abilityScores[ability] + increase;
             const mod = calculateModifier(score);
             const modStr = formatModifier(mod);
 
@@ -161,6 +180,11 @@ export function AbilitiesStep({
                 <div className="mt-2 text-3xl font-bold text-white">
                   {score}
                 </div>
+                {increase > 0 && (
+                  <div className="text-xs text-purple-300">
+                    +{increase} background
+                  </div>
+                )}
                 <div
                   className={`mt-1 text-lg font-semibold ${
                     mod >= 0 ? "text-green-400" : "text-red-400"
@@ -397,15 +421,88 @@ function PointBuyUI({
   );
 }
 
+// Background Increases UI
+function BackgroundIncreasesUI({
+  backgroundAbilities,
+  abilityIncreases,
+  onAbilityIncreaseChange,
+}: {
+  backgroundAbilities: Ability[];
+  abilityIncreases: Partial<Record<Ability, number>>;
+  onAbilityIncreaseChange: (ability: Ability, amount: number) => void;
+}) {
+  const amounts = backgroundAbilities.map(
+    (ability) => abilityIncreases[ability] ?? 0,
+  );
+  const pattern = amounts
+    .filter((amount) => amount > 0)
+    .sort()
+    .join(",");
+  const isValid = pattern === "1,2" || pattern === "1,1,1";
+
+  return (
+    <div className="space-y-4">
+      <div>
+        <h3 className="text-lg font-semibold text-white">
+          Background Increases
+        </h3>
+        <p className="text-sm text-slate-400">
+          Add +2 and +1 to two of your background's abilities, or +1 to all
+          three
+        </p>
+      </div>
+
+      <div className="grid gap-3 sm:grid-cols-3">
+        {backgroundAbilities.map((ability) => {
+          const current = abilityIncreases[ability] ?? 0;
+          return (
+            <div
+              key={ability}
+              className="rounded-lg bg-slate-900/50 p-3 ring-1 ring-slate-700/50"
+            >
+              <div className="mb-2 text-xs font-medium tracking-wide text-slate-400 uppercase">
+                {ABILITY_LABELS[ability]}
+              </div>
+              <div className="flex gap-2">
+                {[0, 1, 2].map((amount) => (
+                  <button
+                    key={amount}
+                    onClick={() => onAbilityIncreaseChange(ability, amount)}
+                    className={`flex-1 rounded-lg py-1 text-sm font-semibold transition ${
+                      current === amount
+                        ? "bg-purple-600 text-white"
+                        : "bg-slate-800 text-slate-400 hover:bg-slate-700"
+                    }`}
+                  >
+                    +{amount}
+                  </button>
+                ))}
+              </div>
+            </div>
+          );
+        })}
+      </div>
+
+      {!isValid && (
+        <p className="text-sm text-red-300">
+          Choose +2 and +1, or +1 for each of the three abilities.
+        </p>
+      )}
+    </div>
+  );
+}
+
 // Roll UI
 function RollUI({
   abilityScores,
   rolledScores,
   handleRollAll,
+  isRolling,
 }: {
   abilityScores: AbilityScores;
   rolledScores: { rolls: number[]; total: number }[];
   handleRollAll: () => void;
+  isRolling?: boolean;
 }) {
   const hasRolled = rolledScores.length > 0;
 
@@ -422,9 +519,10 @@ function RollUI({
         </div>
         <Button
           onClick={handleRollAll}
-          className="flex cursor-pointer items-center gap-2 rounded-lg bg-gradient-to-r from-purple-600 to-pink-600 px-4 py-2 font-semibold text-white shadow-lg transition hover:from-purple-500 hover:to-pink-500"
+          disabled={isRolling || hasRolled}
+          className="flex cursor-pointer items-center gap-2 rounded-lg bg-gradient-to-r from-purple-600 to-pink-600 px-4 py-2 font-semibold text-white shadow-lg transition hover:from-purple-500 hover:to-pink-500 disabled:cursor-not-allowed disabled:opacity-50"
         >
-          🎲 {hasRolled ? "Reroll All" : "Roll Abilities"}
+          🎲 Roll Abilities
         </Button>
       </div>
 
@@ -490,7 +588,9 @@ function RollUI({
         <p className="text-sm text-slate-400">
           <span className="font-medium text-amber-300">Note:</span> Rolling is
           random and may result in very high or very low scores. If you want
-          more control, consider using Standard Array or Point Buy.
+          more control, consider using Standard Array or Point Buy. Scores are
+          rolled on the server once, and rolling again before a character uses
+          them returns the same scores.
         </p>
       </div>
     </div>
@@ -68,6 +68,15 @@ export interface Scene {
   updatedAt: string;
 }
 
+export interface SceneInput {
+  name: string;
+  description?: string;
+}
+
+export interface SceneOrder {
+  sceneIds: number[];
+}
+
 export interface Map {
   id: number;
   sceneId: number;
@@ -76,11 +85,37 @@ export interface Map {
   gridSizeFt: number;
   widthPx?: number | null;
   heightPx?: number | null;
-  lightingMode: string;
-  fogState: string;
+  lightingMode: "none" | "basic";
+  fogState: Fog;
   createdAt: string;
 }
 
+// "gm-only", "all", "user:<id>" or "character:<id>"; empty in player payloads.
+export type TokenAudience = string;
+
+export interface FogPoint {
+  x: number;
+  y: number;
+}
+
+// A map without a mode has no fog. Revealed polygons use the even-odd rule, so a ring
+// inside another is a hole.
+export interface Fog {
+  mode?: "grid" | "polygon";
+  cells?: FogPoint[];
+  revealed?: FogPoint[][];
+}
+
+// Walls always block sight; doors block it unless open. Points are in grid squares.
+export interface Wall {
+  id: number;
+  mapId: number;
+  kind: "wall" | "door";
+  a: FogPoint;
+  b: FogPoint;
+  state?: "open" | "closed" | "locked";
+}
+
 export interface Token {
   id: number;
   mapId: number;
@@ -91,15 +126,29 @@ export interface Token {
   positionX: number;
   positionY: number;
   facingDeg: number;
-  audience: string[];
+  visionFt: number;
+  darkvisionFt: number;
+  audience: TokenAudience[];
   tags: string[];
   notes: string;
+  layer: "map" | "object" | "token" | "gm";
   createdBy?: number | null;
   createdAt: string;
+  conditions: TokenCondition[];
+}
+
+export interface TokenCondition {
+  name: string;
+  level?: number;
+  source?: string;
+  duration?: number;
+  durationUnit?: "rounds" | "minutes";
 }
 
 export interface MapWithTokens extends Map {
   tokens: Token[];
+  // What the player's tokens can see on a map with basic lighting; players only.
+  vision?: FogPoint[][];
 }
 
 export interface SceneWithMaps extends Scene {
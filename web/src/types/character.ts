@@ -5,8 +5,12 @@ export interface Character {
   user_id: number;
   name: string;
   race: Species; // Called "species" in 2024 rules, but keeping "race" for backwards compat
-  class: ClassName;
-  level: number;
+  class: ClassName; // Starting class; classes lists every class for multiclass characters
+  level: number; // Total character level
+  classes: CharacterClass[];
+  classLabel: string;
+  resources: ClassResource[];
+  attacks: Attack[];
   background: BackgroundName;
   alignment: Alignment;
   experiencePoints: number;
@@ -23,7 +27,10 @@ export interface Character {
   maxHp: number;
   currentHp: number;
   tempHp: number;
-  armorClass: number;
+  armorClass: number; // Computed from equipped armor unless armorClassOverride is set
+  armorClassOverride: boolean;
+  armorClassBonus: number;
+  armorClassBreakdown: ModifierPart[];
   speed: number;
   hitDice: string;
 
@@ -44,11 +51,154 @@ export interface Character {
   proficiencyBonus: number;
   initiative: number;
   passivePerception: number;
+  passiveInvestigation: number;
+  passiveInsight: number;
+  skillLevels: Record<string, ProficiencyLevel>;
+  skillBonuses: Record<string, number>;
+  savingThrows: Record<Ability, number>;
+  spellcastingAbility?: Ability; // Of the first spellcasting class; see spellcasting
+  spellSaveDc?: number;
+  spellAttackBonus?: number;
+  spellcasting: ClassSpellcasting[];
+  carriedWeight: number;
+  carryingCapacity: number;
+  encumbrance: Encumbrance;
+  hitDiceSpent: number;
+  hitDiceRemaining: number;
+  deathSaveSuccesses: number;
+  deathSaveFailures: number;
+  damageResistances: DamageType[];
+  damageVulnerabilities: DamageType[];
+  damageImmunities: DamageType[];
+  status: HitPointStatus;
+  conditions: CharacterCondition[];
+  exhaustion: number;
+  currentSpeed: number;
 
   createdAt: string;
   updatedAt: string;
 }
 
+export interface ClassSpellcasting {
+  class: ClassName;
+  ability: Ability;
+  spellSaveDc: number;
+  spellAttackBonus: number;
+}
+
+export interface CharacterClass {
+  id: number;
+  characterId: number;
+  class: ClassName;
+  level: number;
+  position: number;
+  subclass: string;
+  hitDie: number; // From the rules catalog; 0 for classes saved before it was recorded
+  spellcastingAbility: string;
+}
+
+export interface ClassResource {
+  id: number;
+  characterId: number;
+  name: string;
+  maxFormula: string; // e.g. "3", "prof", "cha", "level/2+1" or "monk"
+  used: number;
+  reset: ResourceReset;
+  createdAt: string;
+  max: number;
+  available: number;
+}
+
+export interface Attack {
+  id: number;
+  characterId: number;
+  name: string;
+  kind: "melee" | "ranged" | "spell";
+  ability: Ability | ""; // Empty to pick it from the kind and properties
+  proficient: boolean;
+  damageDice: string;
+  versatileDice: string;
+  damageType: DamageType | "";
+  properties: string; // JSON array of WeaponProperty
+  bonus: number;
+  createdAt: string;
+  attackAbility: Ability;
+  attackBonus: number;
+  damage: string;
+  versatileDamage?: string;
+}
+
+export type WeaponProperty =
+  | "ammunition"
+  | "finesse"
+  | "heavy"
+  | "light"
+  | "loading"
+  | "reach"
+  | "thrown"
+  | "two-handed"
+  | "versatile";
+
+export type ResourceReset = "short" | "long" | "dawn";
+
+export type ArmorType = "light" | "medium" | "heavy" | "shield";
+
+export interface ModifierPart {
+  source: string;
+  value: number;
+}
+
+export type Encumbrance =
+  | "unencumbered"
+  | "encumbered"
+  | "heavily encumbered"
+  | "over capacity";
+
+export interface CharacterCondition {
+  id: number;
+  characterId: number;
+  name: ConditionName;
+  level: number;
+  source: string;
+  duration: number | null;
+  durationUnit: "" | "rounds" | "minutes";
+  createdAt: string;
+}
+
+export type ConditionName =
+  | "blinded"
+  | "charmed"
+  | "deafened"
+  | "exhaustion"
+  | "frightened"
+  | "grappled"
+  | "incapacitated"
+  | "invisible"
+  | "paralyzed"
+  | "petrified"
+  | "poisoned"
+  | "prone"
+  | "restrained"
+  | "stunned"
+  | "unconscious";
+
+export type HitPointStatus = "conscious" | "dying" | "stable" | "dead";
+
+export type DamageType =
+  | "acid"
+  | "bludgeoning"
+  | "cold"
+  | "fire"
+  | "force"
+  | "lightning"
+  | "necrotic"
+  | "piercing"
+  | "poison"
+  | "psychic"
+  | "radiant"
+  | "slashing"
+  | "thunder";
+
 export interface CharacterCreate {
   name: string;
   race: Species;
@@ -57,16 +207,24 @@ export interface CharacterCreate {
   background: BackgroundName;
   alignment: Alignment;
   experiencePoints: number;
+  campaignId?: number; // Makes the campaign's homebrew packs available
   strength: number;
   dexterity: number;
   constitution: number;
   intelligence: number;
   wisdom: number;
   charisma: number;
+  // The scores above are base scores; the server checks them against the method and
+  // adds the background's increases
+  abilityScoreMethod: AbilityScoreMethod;
+  abilityRollId?: number; // From POST /api/characters/ability-rolls, for "rolled"
+  abilityIncreases?: Partial<Record<Ability, number>>;
   maxHp: number;
   currentHp: number;
   tempHp: number;
   armorClass: number;
+  armorClassOverride?: boolean;
+  armorClassBonus?: number;
   speed: number;
   hitDice: string;
   skillProficiencies: SkillName[];
@@ -75,6 +233,51 @@ export interface CharacterCreate {
   equipment: string[];
 }
 
+export type AbilityScoreMethod = "point-buy" | "standard-array" | "rolled";
+
+// Six 4d6-drop-lowest rolls made by the server; a roll can only be used by one character
+export interface AbilityRoll {
+  id: number;
+  characterId: number | null;
+  scores: number[];
+  rolls: { expression: string; total: number; terms: { notation: string; dice?: { value: number; dropped?: boolean }[] }[] }[];
+  createdAt: string;
+}
+
+// GET /api/characters/{id}/ability-scores and /api/campaigns/{id}/ability-scores
+export interface AbilityScoreRecord {
+  characterId: number;
+  characterName?: string;
+  ownerUsername?: string;
+  method: AbilityScoreMethod | "imported" | "manual"; // manual: created before it was removed
+  base: Record<Ability, number>;
+  increases: Partial<Record<Ability, number>>;
+  rollId?: number;
+  roll?: AbilityRoll;
+  createdAt: string;
+}
+
+// 400 response when ability scores break the generation rules, keyed by request field
+export interface AbilityScoreErrorResponse {
+  error: string;
+  fields: Record<string, string>;
+}
+
+// GET /api/characters/{id}/export; POST it to /api/characters/import on any instance
+export interface CharacterExport {
+  schemaVersion: number;
+  exportedAt: string;
+  character: Character;
+  classes: { class: string; level: number; subclass?: string }[];
+  items: Record<string, unknown>[];
+  coins: { cp: number; sp: number; ep: number; gp: number; pp: number };
+  spells: Record<string, unknown>[];
+  resources: ClassResource[];
+  attacks: Attack[];
+  conditions: CharacterCondition[];
+  avatar?: { contentType: string; data: string }; // data is base64
+}
+
 // User types for multi-account support
 export interface User {
   id: number;
@@ -144,6 +347,19 @@ export const SKILLS = {
 
 export type SkillName = keyof typeof SKILLS;
 
+export type ProficiencyLevel = "none" | "half" | "proficient" | "expertise";
+
+// Server-side skill keys are camelCase ("Sleight of Hand" -> "sleightOfHand")
+export const skillKey = (skill: SkillName): string =>
+  skill
+    .split(" ")
+    .map((word, i) =>
+      i === 0
+        ? word.toLowerCase()
+        : word.charAt(0).toUpperCase() + word.slice(1).toLowerCase(),
+    )
+    .join("");
+
 // 2024 Player's Handbook Species (formerly Races)
 export const SPECIES = [
   "Aasimar",
@@ -366,6 +582,26 @@ export const BACKGROUND_DETAILS = {
   }
 >;
 
+// The abilities each background can increase by +2 and +1, or +1 each to all three
+export const BACKGROUND_ABILITIES: Record<BackgroundName, Ability[]> = {
+  Acolyte: ["intelligence", "wisdom", "charisma"],
+  Artisan: ["strength", "dexterity", "intelligence"],
+  Charlatan: ["dexterity", "constitution", "charisma"],
+  Criminal: ["dexterity", "constitution", "intelligence"],
+  Entertainer: ["strength", "dexterity", "charisma"],
+  Farmer: ["strength", "constitution", "wisdom"],
+  Guard: ["strength", "intelligence", "wisdom"],
+  Guide: ["dexterity", "constitution", "wisdom"],
+  Hermit: ["constitution", "wisdom", "charisma"],
+  Merchant: ["constitution", "intelligence", "charisma"],
+  Noble: ["strength", "intelligence", "charisma"],
+  Sage: ["constitution", "intelligence", "wisdom"],
+  Sailor: ["strength", "dexterity", "wisdom"],
+  Scribe: ["dexterity", "intelligence", "wisdom"],
+  Soldier: ["strength", "dexterity", "constitution"],
+  Wayfarer: ["dexterity", "wisdom", "charisma"],
+};
+
 export const ALIGNMENTS = [
   "Lawful Good",
   "Neutral Good",
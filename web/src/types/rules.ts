@@ -0,0 +1,87 @@
+// Rules catalog served by GET /api/rules/{species|classes|subclasses|backgrounds|items|spells}.
+// Options from homebrew packs name the pack in source.
+
+import type { Ability, ArmorType, CreatureSize } from "./character";
+
+export interface SpeciesRules {
+  name: string;
+  size: CreatureSize;
+  speed: number;
+  traits: string[];
+  source?: string;
+}
+
+export interface ClassRules {
+  name: string;
+  hitDie: number;
+  primaryAbilities: Ability[];
+  savingThrows: Ability[];
+  skills: { count: number; from: string[] }; // An empty list means any skill
+  armorTraining: string[];
+  weaponProficiencies: string[];
+  spellcastingAbility?: Ability;
+  features?: { level: number; names: string[] }[];
+  source?: string;
+}
+
+export interface SubclassRules {
+  name: string;
+  class: string;
+  features: string[];
+  source?: string;
+}
+
+export interface BackgroundRules {
+  name: string;
+  abilityScores: Ability[];
+  skillProficiencies: string[];
+  toolProficiency: string;
+  feat: string;
+  source?: string;
+}
+
+export interface ItemRules {
+  name: string;
+  weight: number;
+  valueCp: number;
+  armorType?: ArmorType;
+  armorClass?: number;
+  attunement?: boolean;
+  description?: string;
+  source?: string;
+}
+
+export interface SpellRules {
+  name: string;
+  level: number;
+  school: string;
+  castingTime?: string;
+  range?: string;
+  duration?: string;
+  classes?: string[];
+  description?: string;
+  source?: string;
+}
+
+// Homebrew pack uploaded to POST /api/homebrew or /api/campaigns/{id}/homebrew.
+export interface HomebrewPackContent {
+  name: string;
+  version: string; // major.minor.patch
+  description?: string;
+  species?: SpeciesRules[];
+  classes?: ClassRules[];
+  subclasses?: SubclassRules[];
+  items?: ItemRules[];
+  spells?: SpellRules[];
+}
+
+export interface HomebrewPack {
+  id: number;
+  userId: number;
+  campaignId: number | null;
+  name: string;
+  version: string;
+  content: string; // HomebrewPackContent as JSON
+  createdAt: string;
+  updatedAt: string;
+}